@@ -114,23 +114,24 @@ func MustNewRecomposer(
 	composers map[any]RecomposeFunc,
 	anyComposers ...map[any]RecomposeAnyFunc) *Recomposer {
 
-	r := Recomposer{
-		CreateKey: createKey,
-		composers: map[string]*composer{},
-	}
-	for v, fun := range composers {
-		rt := reflect.TypeOf(v)
-		if _, err := r.registerComposer(rt, fun); err != nil {
-			panic(err)
+	r := Recomposer{CreateKey: createKey}
+	err := r.update(func(cc *composerCache) error {
+		for v, fun := range composers {
+			if _, err := cc.registerComposer(reflect.TypeOf(v), fun); err != nil {
+				return err
+			}
 		}
-	}
-	if 0 < len(anyComposers) {
-		for v, fun := range anyComposers[0] {
-			rt := reflect.TypeOf(v)
-			if _, err := r.registerAnyComposer(rt, fun); err != nil {
-				panic(err)
+		if 0 < len(anyComposers) {
+			for v, fun := range anyComposers[0] {
+				if _, err := cc.registerAnyComposer(reflect.TypeOf(v), fun); err != nil {
+					return err
+				}
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		panic(err)
 	}
 	return &r
 }
@@ -3,55 +3,278 @@
 package alt
 
 import (
+	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 )
 
 type composer struct {
-	fun     RecomposeFunc
-	any     RecomposeAnyFunc
-	short   string
-	full    string
-	rtype   reflect.Type
-	indexes map[string]reflect.StructField
+	fun    RecomposeFunc
+	any    RecomposeAnyFunc
+	short  string
+	full   string
+	rtype  reflect.Type
+	fields []*fieldPlan
+	// known members are all the keys of all the fields.
+	known map[string]bool
 }
 
+// fieldPlan is the precomputed information needed to set a struct field
+// when recomposing.
+type fieldPlan struct {
+	// keys are the member names to look for in order of preference.
+	keys     []string
+	index    []int
+	tag      reflect.StructTag
+	asString bool
+	required bool
+}
+
+// composerCache is a snapshot of the registered composers. Once stored in a
+// Recomposer a cache is never modified. Instead a copy is modified and then
+// replaces the original.
+type composerCache struct {
+	// composers are keyed by both the short and full type names.
+	composers     map[string]*composer
+	types         map[reflect.Type]*composer
+	interfaces    map[reflect.Type]*polymorph
+	discriminants map[string]*discriminant
+}
+
+var emptyComposerCache = composerCache{}
+
+func (cc *composerCache) dup() *composerCache {
+	dup := composerCache{
+		composers:     make(map[string]*composer, len(cc.composers)+2),
+		types:         make(map[reflect.Type]*composer, len(cc.types)+1),
+		interfaces:    make(map[reflect.Type]*polymorph, len(cc.interfaces)),
+		discriminants: make(map[string]*discriminant, len(cc.discriminants)),
+	}
+	for k, c := range cc.composers {
+		dup.composers[k] = c
+	}
+	for k, c := range cc.types {
+		dup.types[k] = c
+	}
+	for k, pm := range cc.interfaces {
+		dup.interfaces[k] = pm
+	}
+	for k, d := range cc.discriminants {
+		dup.discriminants[k] = d
+	}
+	return &dup
+}
+
+// set a composer under all its keys.
+func (cc *composerCache) set(c *composer) {
+	cc.composers[c.short] = c
+	cc.composers[c.full] = c
+	cc.types[c.rtype] = c
+}
+
+func (cc *composerCache) registerComposer(rt reflect.Type, fun RecomposeFunc) (*composer, error) {
+	if rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	// TBD could loosen this up and allow any type as long as a function is provided.
+	if rt.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("only structs can be recomposed. %s is not a struct type", rt)
+	}
+	if c := cc.types[rt]; c != nil {
+		// If already registered then there is no reason to walk the fields
+		// again. Composers may be shared with other caches so a copy is
+		// modified.
+		if fun != nil {
+			dup := *c
+			dup.fun = fun
+			c = &dup
+			cc.set(c)
+		}
+		return c, nil
+	}
+	c := newComposer(rt)
+	c.fun = fun
+	cc.set(c)
+	for i := rt.NumField() - 1; 0 <= i; i-- {
+		f := rt.Field(i)
+		// Private fields should be skipped.
+		if len(f.Name) == 0 || ([]byte(f.Name)[0]&0x20) != 0 {
+			continue
+		}
+		ft := f.Type
+		switch ft.Kind() {
+		case reflect.Array, reflect.Slice, reflect.Map, reflect.Ptr:
+			ft = ft.Elem()
+		}
+		if _, has := cc.types[ft]; has {
+			continue
+		}
+		_, _ = cc.registerComposer(ft, nil)
+	}
+	return c, nil
+}
+
+func (cc *composerCache) registerAnyComposer(rt reflect.Type, fun RecomposeAnyFunc) (*composer, error) {
+	if rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if rt.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("only structs can be recomposed. %s is not a struct type", rt)
+	}
+	var c *composer
+	if orig := cc.types[rt]; orig != nil {
+		dup := *orig
+		c = &dup
+	} else {
+		c = newComposer(rt)
+	}
+	c.any = fun
+	cc.set(c)
+
+	return c, nil
+}
+
+func newComposer(rt reflect.Type) *composer {
+	c := composer{
+		short:  rt.Name(),
+		full:   rt.PkgPath() + "/" + rt.Name(),
+		rtype:  rt,
+		fields: planFields(indexType(rt)),
+		known:  map[string]bool{},
+	}
+	for _, fp := range c.fields {
+		for _, k := range fp.keys {
+			c.known[k] = true
+		}
+	}
+	return &c
+}
+
+// planFields builds the field plans for the indexed fields. The keys for
+// each field are the index key, the field name, the field name with a lower
+// case first letter, and the field name in all lower case.
+func planFields(im map[string]reflect.StructField) []*fieldPlan {
+	keys := make([]string, 0, len(im))
+	for k := range im {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	plans := make([]*fieldPlan, 0, len(keys))
+	for _, k := range keys {
+		sf := im[k]
+		name := []byte(sf.Name)
+		name[0] |= 0x20
+		fp := fieldPlan{
+			index: sf.Index,
+			tag:   sf.Tag,
+		}
+		for _, opt := range strings.Split(sf.Tag.Get("json"), ",")[1:] {
+			switch opt {
+			case "string":
+				fp.asString = true
+			case "required":
+				fp.required = true
+			}
+		}
+		for _, key := range []string{k, sf.Name, string(name), strings.ToLower(string(name))} {
+			dup := false
+			for _, x := range fp.keys {
+				if x == key {
+					dup = true
+					break
+				}
+			}
+			if !dup {
+				fp.keys = append(fp.keys, key)
+			}
+		}
+		plans = append(plans, &fp)
+	}
+	return plans
+}
+
+// indexType returns the fields of a struct type keyed by the member name.
+// Embedded structs are handled in the same way as the json package. The
+// exported fields of untagged embedded structs, even unexported ones, are
+// promoted and when more than one field has the same key the shallowest
+// wins. If more than one is at that depth then a single tagged field wins
+// otherwise none are used.
 func indexType(rt reflect.Type) (im map[string]reflect.StructField) {
-	i := rt.NumField()
-	if 0 < i {
-		im = map[string]reflect.StructField{}
-		for i--; 0 <= i; i-- {
-			f := rt.Field(i)
-			if 0 < len(f.PkgPath) {
+	var cands []*indexCand
+	collectFields(rt, nil, &cands)
+	if len(cands) == 0 {
+		return
+	}
+	byKey := map[string][]*indexCand{}
+	for _, ic := range cands {
+		byKey[ic.key] = append(byKey[ic.key], ic)
+	}
+	im = map[string]reflect.StructField{}
+	for k, list := range byKey {
+		depth := len(list[0].field.Index)
+		for _, ic := range list[1:] {
+			if len(ic.field.Index) < depth {
+				depth = len(ic.field.Index)
+			}
+		}
+		var dom *indexCand
+		cnt := 0
+		tagged := 0
+		for _, ic := range list {
+			if len(ic.field.Index) != depth {
 				continue
 			}
-			if f.Anonymous {
-				fim := indexType(f.Type)
-				// prepend index and add to im
-				for k := range fim {
-					ff := fim[k]
-					ff.Index = append([]int{i}, ff.Index...)
-					im[k] = ff
-				}
-			} else if k, _ := f.Tag.Lookup("json"); 0 < len(k) {
-				parts := strings.Split(k, ",")
-				switch parts[0] {
-				case "":
-					k = strings.ToLower(f.Name)
-				case "-":
-					if 1 < len(parts) {
-						k = "-"
-					} else {
-						continue
-					}
-				default:
-					k = parts[0]
-				}
-				im[k] = f
-			} else {
-				im[f.Name] = f
+			cnt++
+			if ic.tagged {
+				tagged++
+				dom = ic
+			} else if dom == nil {
+				dom = ic
 			}
 		}
+		if cnt == 1 || tagged == 1 {
+			im[k] = dom.field
+		}
 	}
 	return
 }
+
+type indexCand struct {
+	key    string
+	field  reflect.StructField
+	tagged bool
+}
+
+func collectFields(rt reflect.Type, index []int, cands *[]*indexCand) {
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		tag, _ := f.Tag.Lookup("json")
+		if tag == "-" {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		name := parts[0]
+		et := f.Type
+		if et.Kind() == reflect.Ptr {
+			et = et.Elem()
+		}
+		if 0 < len(f.PkgPath) && (!f.Anonymous || et.Kind() != reflect.Struct) {
+			continue
+		}
+		f.Index = append(append([]int{}, index...), i)
+		if et.Kind() == reflect.Struct && inlined(&f, parts, true) {
+			collectFields(et, f.Index, cands)
+			continue
+		}
+		if 0 < len(f.PkgPath) {
+			// A tagged unexported embedded struct can not be set.
+			continue
+		}
+		ic := indexCand{key: name, field: f, tagged: 0 < len(name)}
+		if len(name) == 0 {
+			ic.key = f.Name
+		}
+		*cands = append(*cands, &ic)
+	}
+}
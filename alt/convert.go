@@ -0,0 +1,155 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package alt
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/gen"
+)
+
+// ConvertForWrite returns a copy of the value with the Converter of the
+// options applied. The value is decomposed before being converted so the
+// original is not modified. Times are left as time.Time so that they are
+// encoded according to the options when written. The yaml, msgpack, and
+// cbor writers call this function when the Converter option is set.
+func ConvertForWrite(v any, opt *ojg.Options) any {
+	if opt.Converter == nil {
+		return v
+	}
+	return opt.Converter.Convert(decompose(v, writeDecomposeOptions(opt)))
+}
+
+// ConvertValue applies the Converter of the options to a single value as it
+// is about to be written. The members of maps and slices are not converted
+// or copied since the oj, sen, and pretty writers convert each member as it
+// is written. Values other than the basic types, such as structs, are
+// decomposed first so the converter sees the same maps and slices as
+// ConvertForWrite would. If the converter has Key functions a map is
+// returned as a shallow copy with the keys converted. The value to write is
+// returned along with true if it was converted and should not be converted
+// again.
+func ConvertValue(v any, opt *ojg.Options) (any, bool) {
+	c := opt.Converter
+	switch v.(type) {
+	case nil, bool, string, time.Time, json.Number, []any, map[string]any,
+		int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+	default:
+		v = decompose(v, writeDecomposeOptions(opt))
+	}
+	if cv, ok := c.ConvertValue(v); ok {
+		return cv, true
+	}
+	if m, ok := v.(map[string]any); ok && 0 < len(c.Key) {
+		cm := make(map[string]any, len(m))
+		for k, mv := range m {
+			cm[c.ConvertKey(k)] = mv
+		}
+		v = cm
+	}
+	return v, false
+}
+
+func writeDecomposeOptions(opt *ojg.Options) *ojg.Options {
+	dopt := *opt
+	dopt.Converter = nil
+	dopt.TimeFormat = "time"
+	dopt.TimeMap = false
+	dopt.TimeWrap = ""
+
+	return &dopt
+}
+
+// ConvertNode converts a gen.Node according to the conversion functions of
+// the converter. As with Converter.Convert() the members of Objects and
+// Arrays are replaced in place if converted. The values returned by the
+// conversion functions are converted to gen.Node with Generify().
+func ConvertNode(c *ojg.Converter, n gen.Node) gen.Node {
+	n, _ = convertNode(c, n)
+	return n
+}
+
+func convertNode(c *ojg.Converter, n gen.Node) (gen.Node, bool) {
+	switch tn := n.(type) {
+	case gen.Bool:
+		for _, fun := range c.Bool {
+			if cv, ok := fun(bool(tn)); ok {
+				return Generify(cv), true
+			}
+		}
+	case gen.Int:
+		for _, fun := range c.Int {
+			if cv, ok := fun(int64(tn)); ok {
+				return Generify(cv), true
+			}
+		}
+	case gen.Float:
+		for _, fun := range c.Float {
+			if cv, ok := fun(float64(tn)); ok {
+				return Generify(cv), true
+			}
+		}
+	case gen.String:
+		for _, fun := range c.String {
+			if cv, ok := fun(string(tn)); ok {
+				return Generify(cv), true
+			}
+		}
+	case gen.Time:
+		for _, fun := range c.Time {
+			if cv, ok := fun(time.Time(tn)); ok {
+				return Generify(cv), true
+			}
+		}
+	case gen.Array:
+		if 0 < len(c.Array) {
+			simple, _ := tn.Simplify().([]any)
+			for _, fun := range c.Array {
+				if cv, ok := fun(simple); ok {
+					return Generify(cv), true
+				}
+			}
+		}
+		for i, m := range tn {
+			if cv, ok := convertNode(c, m); ok {
+				tn[i] = cv
+			}
+		}
+	case gen.Object:
+		if 0 < len(c.Map) {
+			simple, _ := tn.Simplify().(map[string]any)
+			for _, fun := range c.Map {
+				if cv, ok := fun(simple); ok {
+					return Generify(cv), true
+				}
+			}
+		}
+		// Collect the changes first so that a converted member is not
+		// visited again.
+		changes := map[string]gen.Node{}
+		for k, m := range tn {
+			if cv, ok := convertNode(c, m); ok {
+				changes[k] = cv
+			}
+		}
+		for k, cv := range changes {
+			tn[k] = cv
+		}
+		if 0 < len(c.Key) {
+			renames := map[string]string{}
+			for k := range tn {
+				if nk := c.ConvertKey(k); nk != k {
+					renames[k] = nk
+				}
+			}
+			for k, nk := range renames {
+				m := tn[k]
+				delete(tn, k)
+				tn[nk] = m
+			}
+		}
+	}
+	return n, false
+}
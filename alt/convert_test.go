@@ -0,0 +1,123 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package alt_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/alt"
+	"github.com/khaf/ojg/gen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestConvertForWrite(t *testing.T) {
+	type Reading struct {
+		Name  string
+		Value float64
+		When  time.Time
+	}
+	tm := time.Date(2021, time.March, 5, 10, 11, 12, 0, time.UTC)
+	c := ojg.Converter{
+		Float: []func(val float64) (any, bool){
+			func(val float64) (any, bool) { return math.Round(val*100) / 100, true },
+		},
+	}
+	src := map[string]any{"r": &Reading{Name: "x", Value: 1.23456, When: tm}, "f": 2.71828}
+	v := alt.ConvertForWrite(src, &ojg.Options{Converter: &c})
+	tt.Equal(t, map[string]any{
+		"r": map[string]any{"name": "x", "value": 1.23, "when": tm},
+		"f": 2.72,
+	}, v)
+	// The original is not modified.
+	tt.Equal(t, 2.71828, src["f"])
+
+	tt.Equal(t, 2.71828, alt.ConvertForWrite(2.71828, &ojg.Options{}))
+}
+
+func TestConvertValue(t *testing.T) {
+	type Reading struct {
+		Name  string
+		Value float64
+	}
+	c := ojg.Converter{
+		Float: []func(val float64) (any, bool){
+			func(val float64) (any, bool) { return math.Round(val*100) / 100, true },
+		},
+		Key: []func(key string) (string, bool){
+			func(key string) (string, bool) { return "_" + key, true },
+		},
+	}
+	opt := ojg.Options{Converter: &c}
+
+	v, converted := alt.ConvertValue(float32(1.23456), &opt)
+	tt.Equal(t, true, converted)
+	tt.Equal(t, 1.23, v)
+
+	// Members are not converted but keys are.
+	src := map[string]any{"f": 2.71828}
+	v, converted = alt.ConvertValue(src, &opt)
+	tt.Equal(t, false, converted)
+	tt.Equal(t, map[string]any{"_f": 2.71828}, v)
+	tt.Equal(t, map[string]any{"f": 2.71828}, src)
+
+	// Structs are decomposed.
+	v, converted = alt.ConvertValue(&Reading{Name: "x", Value: 1.23456}, &opt)
+	tt.Equal(t, false, converted)
+	tt.Equal(t, map[string]any{"_name": "x", "_value": 1.23456}, v)
+}
+
+func TestConvertNode(t *testing.T) {
+	c := ojg.Converter{
+		String: []func(val string) (any, bool){
+			func(val string) (any, bool) { return len(val), val != "keep" },
+		},
+		Int: []func(val int64) (any, bool){
+			func(val int64) (any, bool) { return val * 10, true },
+		},
+		Bool: []func(val bool) (any, bool){
+			func(val bool) (any, bool) { return !val, true },
+		},
+		Time: []func(val time.Time) (any, bool){
+			func(val time.Time) (any, bool) { return val.Year(), true },
+		},
+		Float: []func(val float64) (any, bool){
+			func(val float64) (any, bool) { return nil, true },
+		},
+		Key: []func(key string) (string, bool){
+			func(key string) (string, bool) { return "k_" + key, key == "a" },
+		},
+	}
+	n := alt.ConvertNode(&c, gen.Object{
+		"a": gen.String("abc"),
+		"b": gen.Array{gen.Int(2), gen.Bool(true), gen.String("keep")},
+		"c": gen.Time(time.Date(2021, time.March, 5, 10, 11, 12, 0, time.UTC)),
+		"d": gen.Float(1.5),
+	})
+	tt.Equal(t, gen.Object{
+		"k_a": gen.Int(3),
+		"b":   gen.Array{gen.Int(20), gen.Bool(false), gen.String("keep")},
+		"c":   gen.Int(2021),
+		"d":   nil,
+	}, n)
+
+	c = ojg.Converter{
+		Map: []func(val map[string]any) (any, bool){
+			func(val map[string]any) (any, bool) {
+				v, ok := val["$n"]
+				return v, ok
+			},
+		},
+		Array: []func(val []any) (any, bool){
+			func(val []any) (any, bool) { return len(val), len(val) == 2 },
+		},
+	}
+	n = alt.ConvertNode(&c, gen.Array{
+		gen.Object{"$n": gen.Int(7)},
+		gen.Array{gen.Int(1), gen.Int(2)},
+		gen.Object{"x": gen.Array{}},
+	})
+	tt.Equal(t, gen.Array{gen.Int(7), gen.Int(2), gen.Object{"x": gen.Array{}}}, n)
+}
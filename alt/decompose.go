@@ -17,6 +17,9 @@ import (
 const fracMax = 10000000.0
 
 func decompose(v any, opt *Options) any {
+	if enc := ojg.FindEncoder(v); enc != nil {
+		v = enc(v)
+	}
 	switch tv := v.(type) {
 	case nil, bool, int64, float64, string:
 	case int:
@@ -69,6 +72,8 @@ func decompose(v any, opt *Options) any {
 				a[i] = decompose(m, opt)
 			}
 			v = a
+		case ojg.BytesAsBytes:
+			v = append([]byte{}, tv...)
 		default:
 			v = string(tv)
 		}
@@ -132,6 +137,7 @@ func alter(v any, opt *Options) any {
 				a[i] = decompose(m, opt)
 			}
 			v = a
+		case ojg.BytesAsBytes:
 		default:
 			v = string(tv)
 		}
@@ -154,10 +160,14 @@ func reflectValue(rv reflect.Value, val any, opt *Options) (v any) {
 		v = reflectMap(rv, opt)
 	case reflect.Ptr:
 		elem := rv.Elem()
-		if elem.IsValid() && elem.CanInterface() {
-			v = reflectValue(elem, elem.Interface(), opt)
-		} else {
+		switch {
+		case !elem.IsValid() || !elem.CanInterface():
 			v = nil
+		case elem.Kind() == reflect.Struct && elem.Type() != timeType:
+			// Keep the addressable value so fields can be read directly.
+			v = reflectValue(elem, elem.Interface(), opt)
+		default:
+			v = decompose(elem.Interface(), opt)
 		}
 	case reflect.Slice, reflect.Array:
 		v = reflectArray(rv, opt)
@@ -213,6 +223,9 @@ func reflectStruct(rv reflect.Value, val any, opt *Options) any {
 }
 
 func reflectEmbed(rv reflect.Value, val any, opt *Options) any {
+	if c := ojg.LookupCodec(rv.Type()); c != nil && c.Encode != nil {
+		return decompose(c.Encode(val), opt)
+	}
 	obj := map[string]any{}
 	si := getSinfo(val)
 	t := si.rt
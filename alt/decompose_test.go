@@ -99,6 +99,9 @@ func TestDecomposeStruct(t *testing.T) {
 
 	v = alt.Decompose(&a, &alt.Options{UseTags: true, BytesAs: ojg.BytesAsBase64})
 	tt.Equal(t, map[string]any{"v": 3, "buf": "YWI="}, v)
+
+	v = alt.Decompose(&a, &alt.Options{UseTags: true, BytesAs: ojg.BytesAsBytes})
+	tt.Equal(t, map[string]any{"v": 3, "buf": []byte("ab")}, v)
 }
 
 func TestDecomposeStructWithPointers(t *testing.T) {
@@ -203,6 +206,9 @@ func TestDecomposeSimplifier(t *testing.T) {
 
 	v = alt.Alter([]any{[]byte("abc")}, &alt.Options{UseTags: true, BytesAs: ojg.BytesAsBase64})
 	tt.Equal(t, []any{"YWJj"}, v)
+
+	v = alt.Alter([]any{[]byte("abc")}, &alt.Options{UseTags: true, BytesAs: ojg.BytesAsBytes})
+	tt.Equal(t, []any{[]byte("abc")}, v)
 }
 
 func TestDecomposeTime(t *testing.T) {
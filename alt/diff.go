@@ -3,6 +3,10 @@
 package alt
 
 import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"math/big"
 	"reflect"
 	"time"
 	"unsafe"
@@ -38,72 +42,7 @@ func Compare(v0, v1 any, ignores ...Path) Path {
 // explicit nil in the fingerprint will match either a nil in the target or a
 // missing value in the target.
 func Match(fingerprint, target any) bool {
-	switch fp := fingerprint.(type) {
-	case nil:
-		if target != nil {
-			return false
-		}
-	case bool:
-		if t1, ok := target.(bool); !ok || fp != t1 {
-			return false
-		}
-	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
-		i0, _ := asInt(fp)
-		if i1, ok := asInt(target); !ok || i0 != i1 {
-			return false
-		}
-	case float32, float64:
-		f0, _ := asFloat(fp)
-		if f1, ok := asFloat(target); !ok || f0 != f1 {
-			return false
-		}
-	case string:
-		if t1, ok := target.(string); !ok || fp != t1 {
-			return false
-		}
-	case time.Time:
-		if t1, ok := target.(time.Time); !ok || !fp.Round(TimeTolerance).Equal(t1.Round(TimeTolerance)) {
-			return false
-		}
-	case []any:
-		if t1, ok := target.([]any); ok && len(fp) == len(t1) {
-			for i, v := range fp {
-				if !Match(v, t1[i]) {
-					return false
-				}
-			}
-			return true
-		}
-		return false
-	case map[string]any:
-		if t1, ok := target.(map[string]any); ok {
-			for k, v := range fp {
-				if !Match(v, t1[k]) {
-					return false
-				}
-			}
-			return true
-		}
-		return false
-	default:
-		vt0 := (*[2]uintptr)(unsafe.Pointer(&fingerprint))[0]
-		vt1 := (*[2]uintptr)(unsafe.Pointer(&target))[0]
-		if vt0 == vt1 {
-			if s0, _ := fingerprint.(Simplifier); s0 != nil {
-				if s1, _ := target.(Simplifier); s1 != nil {
-					return Match(s0.Simplify(), s1.Simplify())
-				}
-			}
-			opt := &Options{}
-			fingerprint = reflectValue(reflect.ValueOf(fingerprint), fingerprint, opt)
-			target = reflectValue(reflect.ValueOf(target), target, opt)
-			if fingerprint != nil && target != nil {
-				return Match(fingerprint, target)
-			}
-		}
-		return false
-	}
-	return true
+	return matchValue(Path{}, fingerprint, target) == nil
 }
 
 func diff(v0, v1 any, one bool, ignores ...Path) (diffs []Path) {
@@ -117,8 +56,13 @@ func diff(v0, v1 any, one bool, ignores ...Path) (diffs []Path) {
 			diffs = append(diffs, Path{nil})
 		}
 	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
-		i0, _ := asInt(v0)
-		if i1, ok := asInt(v1); !ok || i0 != i1 {
+		i0, ok0 := asInt(v0)
+		i1, ok1 := asInt(v1)
+		if ok0 && ok1 {
+			if i0 != i1 {
+				diffs = append(diffs, Path{nil})
+			}
+		} else if !ratEqual(v0, v1) {
 			diffs = append(diffs, Path{nil})
 		}
 	case float32, float64:
@@ -126,6 +70,14 @@ func diff(v0, v1 any, one bool, ignores ...Path) (diffs []Path) {
 		if f1, ok := asFloat(v1); !ok || f0 != f1 {
 			diffs = append(diffs, Path{nil})
 		}
+	case json.Number:
+		if !ratEqual(v0, v1) {
+			diffs = append(diffs, Path{nil})
+		}
+	case []byte:
+		if t1, ok := v1.([]byte); !ok || !bytes.Equal(t0, t1) {
+			diffs = append(diffs, Path{nil})
+		}
 	case string:
 		if t1, ok := v1.(string); !ok || t0 != t1 {
 			diffs = append(diffs, Path{nil})
@@ -250,6 +202,7 @@ func asInt(v any) (i int64, ok bool) {
 		i = int64(tv)
 	case uint:
 		i = int64(tv)
+		ok = uint64(tv) <= math.MaxInt64
 	case uint8:
 		i = int64(tv)
 	case uint16:
@@ -258,6 +211,7 @@ func asInt(v any) (i int64, ok bool) {
 		i = int64(tv)
 	case uint64:
 		i = int64(tv)
+		ok = tv <= math.MaxInt64
 	case float32:
 		i = int64(tv)
 		if float32(int64(tv)) != tv {
@@ -312,12 +266,46 @@ func asFloat(v any) (f float64, ok bool) {
 		f = float64(tv)
 	case gen.Int:
 		f = float64(tv)
+	case json.Number:
+		var err error
+		f, err = tv.Float64()
+		ok = err == nil
 	default:
 		ok = false
 	}
 	return
 }
 
+// ratEqual compares two numbers exactly. Numbers that are too large for an
+// int64 or that are json.Numbers are compared as big.Rats.
+func ratEqual(v0, v1 any) bool {
+	r0 := asRat(v0)
+	r1 := asRat(v1)
+
+	return r0 != nil && r1 != nil && r0.Cmp(r1) == 0
+}
+
+func asRat(v any) (r *big.Rat) {
+	switch tv := v.(type) {
+	case uint:
+		r = new(big.Rat).SetUint64(uint64(tv))
+	case uint64:
+		r = new(big.Rat).SetUint64(tv)
+	case json.Number:
+		r, _ = new(big.Rat).SetString(string(tv))
+	case float32, float64:
+		f, _ := asFloat(v)
+		if !math.IsInf(f, 0) && !math.IsNaN(f) {
+			r = new(big.Rat).SetFloat64(f)
+		}
+	default:
+		if i, ok := asInt(v); ok {
+			r = new(big.Rat).SetInt64(i)
+		}
+	}
+	return
+}
+
 func ignoreIndex(i int, ignores []Path) bool {
 	for _, ign := range ignores {
 		if len(ign) == 1 {
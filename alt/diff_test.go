@@ -3,6 +3,8 @@
 package alt_test
 
 import (
+	"encoding/json"
+	"math"
 	"testing"
 	"time"
 
@@ -59,6 +61,23 @@ func TestDiffPrimitive(t *testing.T) {
 
 		{v0: "abc", v1: "abc", expect: true},
 		{v0: "abc", v1: "abx", expect: false},
+
+		{v0: uint64(math.MaxUint64), v1: uint64(math.MaxUint64), expect: true},
+		{v0: uint64(math.MaxUint64), v1: -1, expect: false},
+		{v0: uint64(math.MaxUint64), v1: json.Number("18446744073709551615"), expect: true},
+		{v0: 3, v1: json.Number("3.0"), expect: true},
+		{v0: json.Number("0.1"), v1: json.Number("1e-1"), expect: true},
+		{v0: json.Number("0.1"), v1: json.Number("0.10000000000000000001"), expect: false},
+		{v0: json.Number("1.5"), v1: 1.5, expect: true},
+		{v0: json.Number("x"), v1: json.Number("x"), expect: false},
+		{v0: gen.Uint(3), v1: gen.Uint(3), expect: true},
+		{v0: gen.Decimal("2.50"), v1: gen.Decimal("2.5"), expect: true},
+		{v0: gen.Null{}, v1: gen.Null{}, expect: true},
+
+		{v0: []byte("abc"), v1: []byte("abc"), expect: true},
+		{v0: []byte("abc"), v1: []byte("abx"), expect: false},
+		{v0: []byte("abc"), v1: "abc", expect: false},
+		{v0: gen.Bytes("abc"), v1: gen.Bytes("abc"), expect: true},
 	} {
 		diffs := alt.Diff(p.v0, p.v1)
 		tt.Equal(t, p.expect, len(diffs) == 0, "Diff(", p.v0, p.v1, ")")
@@ -0,0 +1,593 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package alt
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/khaf/ojg"
+)
+
+// ChangeKind identifies the kind of a Change.
+type ChangeKind int
+
+const (
+	// ChangeAdded indicates a value was added.
+	ChangeAdded ChangeKind = iota + 1
+	// ChangeRemoved indicates a value was removed.
+	ChangeRemoved
+	// ChangeModified indicates a value was changed to a different value of
+	// the same type.
+	ChangeModified
+	// ChangeTypeChanged indicates a value was changed to a value of a
+	// different type such as from a string to a number.
+	ChangeTypeChanged
+	// ChangeMoved indicates an array element was moved to a different
+	// index.
+	ChangeMoved
+)
+
+// String returns the name of the change kind.
+func (ck ChangeKind) String() string {
+	switch ck {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeModified:
+		return "modified"
+	case ChangeTypeChanged:
+		return "type-changed"
+	case ChangeMoved:
+		return "moved"
+	}
+	return "unknown"
+}
+
+// Change describes a single difference found by DiffDetailed().
+type Change struct {
+	// Kind of change.
+	Kind ChangeKind
+	// Path to the value. For removed values the path is the location in
+	// the original value otherwise it is the location in the new value.
+	Path Path
+	// From is the original location of a moved array element.
+	From Path
+	// Old value or nil if added.
+	Old any
+	// New value or nil if removed.
+	New any
+}
+
+// String returns a single line description of the change.
+func (c *Change) String() string {
+	return string(c.appendLine(nil, nil))
+}
+
+// DiffOptions are the options for DiffDetailed().
+type DiffOptions struct {
+	// Key is the name of an object member that identifies elements in
+	// arrays of objects. When all the elements in both arrays being
+	// compared are objects that include the key then elements are matched
+	// by the value of the key instead of by their position. Otherwise
+	// arrays are compared using a longest common subsequence.
+	Key string
+
+	// Ignores are paths to values that are ignored. A nil in a path matches
+	// any key or index.
+	Ignores []Path
+}
+
+// DiffDetailed returns the changes needed to go from v0 to v1. Unlike
+// Diff() the kind of change along with the old and new values is
+// included. Arrays are compared so that insertions, removals, and moves are
+// detected instead of reporting every element after an insertion as
+// modified. Values that are not simple types are decomposed before being
+// compared.
+func DiffDetailed(v0, v1 any, options ...*DiffOptions) []*Change {
+	d := detailDiffer{}
+	if 0 < len(options) && options[0] != nil {
+		d.key = options[0].Key
+		d.ignores = options[0].Ignores
+	}
+	opt := Options{TimeFormat: "time"}
+	d.diff(Path{}, Decompose(v0, &opt), Decompose(v1, &opt))
+
+	return d.changes
+}
+
+// DiffReport returns a textual report of changes with one line per change.
+// Lines start with a '+' for added, '-' for removed, '~' for modified, '!'
+// for a type change, and '>' for a moved value. If the options Color is
+// true then added lines use the StringColor, removed lines the NullColor,
+// modified lines the BoolColor, type changes the TimeColor, and moves the
+// KeyColor.
+func DiffReport(changes []*Change, options ...*ojg.Options) string {
+	var opt *ojg.Options
+	if 0 < len(options) && options[0].Color {
+		opt = options[0]
+	}
+	var b []byte
+	for _, c := range changes {
+		b = c.appendLine(b, opt)
+		b = append(b, '\n')
+	}
+	return string(b)
+}
+
+func (c *Change) appendLine(b []byte, opt *ojg.Options) []byte {
+	if opt != nil {
+		switch c.Kind {
+		case ChangeAdded:
+			b = append(b, opt.StringColor...)
+		case ChangeRemoved:
+			b = append(b, opt.NullColor...)
+		case ChangeModified:
+			b = append(b, opt.BoolColor...)
+		case ChangeTypeChanged:
+			b = append(b, opt.TimeColor...)
+		case ChangeMoved:
+			b = append(b, opt.KeyColor...)
+		}
+	}
+	switch c.Kind {
+	case ChangeAdded:
+		b = append(b, "+ "...)
+		b = appendPath(b, c.Path)
+		b = append(b, ": "...)
+		b = appendDiffValue(b, c.New)
+	case ChangeRemoved:
+		b = append(b, "- "...)
+		b = appendPath(b, c.Path)
+		b = append(b, ": "...)
+		b = appendDiffValue(b, c.Old)
+	case ChangeModified, ChangeTypeChanged:
+		if c.Kind == ChangeModified {
+			b = append(b, "~ "...)
+		} else {
+			b = append(b, "! "...)
+		}
+		b = appendPath(b, c.Path)
+		b = append(b, ": "...)
+		b = appendDiffValue(b, c.Old)
+		b = append(b, " => "...)
+		b = appendDiffValue(b, c.New)
+	case ChangeMoved:
+		b = append(b, "> "...)
+		b = appendPath(b, c.From)
+		b = append(b, " => "...)
+		b = appendPath(b, c.Path)
+		b = append(b, ": "...)
+		b = appendDiffValue(b, c.New)
+	}
+	if opt != nil {
+		b = append(b, opt.NoColor...)
+	}
+	return b
+}
+
+// appendDiffValue appends a compact JSON representation of a simple value
+// with object members sorted.
+func appendDiffValue(b []byte, v any) []byte {
+	switch tv := v.(type) {
+	case nil:
+		b = append(b, "null"...)
+	case bool:
+		b = strconv.AppendBool(b, tv)
+	case int64:
+		b = strconv.AppendInt(b, tv, 10)
+	case int, int8, int16, int32, uint8, uint16, uint32:
+		i, _ := asInt(tv)
+		b = strconv.AppendInt(b, i, 10)
+	case uint:
+		b = strconv.AppendUint(b, uint64(tv), 10)
+	case uint64:
+		b = strconv.AppendUint(b, tv, 10)
+	case float64:
+		b = strconv.AppendFloat(b, tv, 'g', -1, 64)
+	case float32:
+		b = strconv.AppendFloat(b, float64(tv), 'g', -1, 32)
+	case string:
+		b = ojg.AppendJSONString(b, tv, false)
+	case time.Time:
+		b = ojg.AppendJSONString(b, tv.Format(time.RFC3339Nano), false)
+	case []any:
+		b = append(b, '[')
+		for i, m := range tv {
+			if 0 < i {
+				b = append(b, ',')
+			}
+			b = appendDiffValue(b, m)
+		}
+		b = append(b, ']')
+	case map[string]any:
+		keys := make([]string, 0, len(tv))
+		for k := range tv {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b = append(b, '{')
+		for i, k := range keys {
+			if 0 < i {
+				b = append(b, ',')
+			}
+			b = ojg.AppendJSONString(b, k, false)
+			b = append(b, ':')
+			b = appendDiffValue(b, tv[k])
+		}
+		b = append(b, '}')
+	default:
+		b = ojg.AppendJSONString(b, fmt.Sprintf("%v", v), false)
+	}
+	return b
+}
+
+type detailDiffer struct {
+	key     string
+	ignores []Path
+	changes []*Change
+}
+
+func (d *detailDiffer) add(kind ChangeKind, path Path, old, nu any) {
+	d.changes = append(d.changes, &Change{
+		Kind: kind,
+		Path: append(Path{}, path...),
+		Old:  old,
+		New:  nu,
+	})
+}
+
+func (d *detailDiffer) ignored(path Path) bool {
+	for _, ign := range d.ignores {
+		if len(ign) != len(path) {
+			continue
+		}
+		match := true
+		for i, k := range ign {
+			if k != nil && k != path[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *detailDiffer) diff(path Path, v0, v1 any) {
+	if d.ignored(path) {
+		return
+	}
+	switch t0 := v0.(type) {
+	case []any:
+		if t1, ok := v1.([]any); ok {
+			d.diffArray(path, t0, t1)
+			return
+		}
+	case map[string]any:
+		if t1, ok := v1.(map[string]any); ok {
+			d.diffObject(path, t0, t1)
+			return
+		}
+	}
+	switch {
+	case diffTypeName(v0) != diffTypeName(v1):
+		d.add(ChangeTypeChanged, path, v0, v1)
+	case !diffEqual(v0, v1):
+		d.add(ChangeModified, path, v0, v1)
+	}
+}
+
+func (d *detailDiffer) diffObject(path Path, m0, m1 map[string]any) {
+	keys := make([]string, 0, len(m0)+len(m1))
+	for k := range m0 {
+		keys = append(keys, k)
+	}
+	for k := range m1 {
+		if _, has := m0[k]; !has {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		p := append(path, k)
+		v0, has0 := m0[k]
+		v1, has1 := m1[k]
+		switch {
+		case d.ignored(p):
+		case !has1:
+			d.add(ChangeRemoved, p, v0, nil)
+		case !has0:
+			d.add(ChangeAdded, p, nil, v1)
+		default:
+			d.diff(p, v0, v1)
+		}
+	}
+}
+
+func (d *detailDiffer) diffArray(path Path, a0, a1 []any) {
+	if 0 < len(d.key) && hasKeyMembers(a0, d.key) && hasKeyMembers(a1, d.key) {
+		d.diffKeyed(path, a0, a1)
+		return
+	}
+	// Elements in the longest common subsequence are unchanged and the
+	// unmatched elements in the gaps between them are the changes.
+	type gap struct {
+		olds []int
+		news []int
+	}
+	var gaps []*gap
+	g := &gap{}
+	j := 0
+	for i, mj := range lcsMatch(a0, a1) {
+		if mj < 0 {
+			g.olds = append(g.olds, i)
+			continue
+		}
+		for ; j < mj; j++ {
+			g.news = append(g.news, j)
+		}
+		gaps = append(gaps, g)
+		g = &gap{}
+		j = mj + 1
+	}
+	for ; j < len(a1); j++ {
+		g.news = append(g.news, j)
+	}
+	gaps = append(gaps, g)
+
+	// An unmatched element that is equal to an unmatched element in another
+	// gap was moved. Moves are not looked for if there are too many
+	// unmatched elements to compare.
+	movedFrom := map[int]int{}
+	movedOld := map[int]bool{}
+	var olds, news int
+	for _, g := range gaps {
+		olds += len(g.olds)
+		news += len(g.news)
+	}
+	if olds == 0 || news <= maxLCSCells/olds {
+		for _, g0 := range gaps {
+			for _, i := range g0.olds {
+				for _, g1 := range gaps {
+					if g0 == g1 {
+						continue
+					}
+					for _, j := range g1.news {
+						if _, has := movedFrom[j]; !has && diffEqual(a0[i], a1[j]) {
+							movedFrom[j] = i
+							movedOld[i] = true
+							break
+						}
+					}
+					if movedOld[i] {
+						break
+					}
+				}
+			}
+		}
+	}
+	for _, g := range gaps {
+		var olds []int
+		for _, i := range g.olds {
+			if !movedOld[i] {
+				olds = append(olds, i)
+			}
+		}
+		for _, j := range g.news {
+			p := append(path, j)
+			if i, has := movedFrom[j]; has {
+				if !d.ignored(p) {
+					d.addMove(path, i, j, a1[j])
+				}
+				continue
+			}
+			if 0 < len(olds) {
+				d.diff(p, a0[olds[0]], a1[j])
+				olds = olds[1:]
+				continue
+			}
+			if !d.ignored(p) {
+				d.add(ChangeAdded, p, nil, a1[j])
+			}
+		}
+		for _, i := range olds {
+			if p := append(path, i); !d.ignored(p) {
+				d.add(ChangeRemoved, p, a0[i], nil)
+			}
+		}
+	}
+}
+
+func (d *detailDiffer) addMove(path Path, from, to int, v any) {
+	d.changes = append(d.changes, &Change{
+		Kind: ChangeMoved,
+		Path: append(append(Path{}, path...), to),
+		From: append(append(Path{}, path...), from),
+		Old:  v,
+		New:  v,
+	})
+}
+
+// diffKeyed matches array elements by the value of the key member.
+func (d *detailDiffer) diffKeyed(path Path, a0, a1 []any) {
+	index := map[string][]int{}
+	for i, v := range a0 {
+		k := string(appendDiffValue(nil, v.(map[string]any)[d.key]))
+		index[k] = append(index[k], i)
+	}
+	match := make([]int, len(a1))
+	matchedOld := make([]bool, len(a0))
+	for j, v := range a1 {
+		match[j] = -1
+		k := string(appendDiffValue(nil, v.(map[string]any)[d.key]))
+		if list := index[k]; 0 < len(list) {
+			match[j] = list[0]
+			matchedOld[list[0]] = true
+			index[k] = list[1:]
+		}
+	}
+	// Matched elements in the longest increasing subsequence of old indexes
+	// kept their order. The rest were moved.
+	inOrder := longestIncreasing(match)
+	for j, i := range match {
+		p := append(path, j)
+		switch {
+		case i < 0:
+			if !d.ignored(p) {
+				d.add(ChangeAdded, p, nil, a1[j])
+			}
+		case inOrder[j]:
+			d.diff(p, a0[i], a1[j])
+		default:
+			if !d.ignored(p) {
+				d.addMove(path, i, j, a1[j])
+			}
+			d.diff(p, a0[i], a1[j])
+		}
+	}
+	for i, matched := range matchedOld {
+		if p := append(path, i); !matched && !d.ignored(p) {
+			d.add(ChangeRemoved, p, a0[i], nil)
+		}
+	}
+}
+
+func hasKeyMembers(a []any, key string) bool {
+	for _, v := range a {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return false
+		}
+		if _, has := obj[key]; !has {
+			return false
+		}
+	}
+	return true
+}
+
+// longestIncreasing returns a flag for each member of seq that is part of
+// the longest strictly increasing subsequence of the non-negative values.
+func longestIncreasing(seq []int) []bool {
+	lens := make([]int, len(seq))
+	prev := make([]int, len(seq))
+	best := -1
+	for j, v := range seq {
+		prev[j] = -1
+		if v < 0 {
+			continue
+		}
+		lens[j] = 1
+		for k := 0; k < j; k++ {
+			if 0 <= seq[k] && seq[k] < v && lens[j] <= lens[k] {
+				lens[j] = lens[k] + 1
+				prev[j] = k
+			}
+		}
+		if best < 0 || lens[best] < lens[j] {
+			best = j
+		}
+	}
+	in := make([]bool, len(seq))
+	for j := best; 0 <= j; j = prev[j] {
+		in[j] = true
+	}
+	return in
+}
+
+// maxLCSCells limits the size of the table used to find the longest common
+// subsequence of two arrays. Arrays that would need a larger table are
+// compared index by index instead.
+const maxLCSCells = 1 << 20
+
+// lcsMatch returns the index in a1 of each element of a0 that is part of
+// the longest common subsequence of the two or -1 if not part of it.
+// Common leading and trailing elements are matched before the longest
+// common subsequence of the rest is found.
+func lcsMatch(a0, a1 []any) []int {
+	match := make([]int, len(a0))
+	start := 0
+	for start < len(a0) && start < len(a1) && diffEqual(a0[start], a1[start]) {
+		match[start] = start
+		start++
+	}
+	end0 := len(a0)
+	end1 := len(a1)
+	for start < end0 && start < end1 && diffEqual(a0[end0-1], a1[end1-1]) {
+		end0--
+		end1--
+		match[end0] = end1
+	}
+	n := end0 - start
+	m := end1 - start
+	if maxLCSCells/(m+1) < n+1 {
+		for i := start; i < end0; i++ {
+			match[i] = -1
+			if i < end1 && diffEqual(a0[i], a1[i]) {
+				match[i] = i
+			}
+		}
+		return match
+	}
+	b0 := a0[start:end0]
+	b1 := a1[start:end1]
+	lens := make([][]int, n+1)
+	for i := range lens {
+		lens[i] = make([]int, m+1)
+	}
+	for i := n - 1; 0 <= i; i-- {
+		for j := m - 1; 0 <= j; j-- {
+			switch {
+			case diffEqual(b0[i], b1[j]):
+				lens[i][j] = lens[i+1][j+1] + 1
+			case lens[i][j+1] < lens[i+1][j]:
+				lens[i][j] = lens[i+1][j]
+			default:
+				lens[i][j] = lens[i][j+1]
+			}
+		}
+	}
+	for i, j := 0, 0; i < n; {
+		switch {
+		case j < m && diffEqual(b0[i], b1[j]) && lens[i][j] == lens[i+1][j+1]+1:
+			match[start+i] = start + j
+			i++
+			j++
+		case j < m && lens[i+1][j] < lens[i][j+1]:
+			j++
+		default:
+			match[start+i] = -1
+			i++
+		}
+	}
+	return match
+}
+
+func diffEqual(v0, v1 any) bool {
+	return len(diff(v0, v1, true)) == 0
+}
+
+func diffTypeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case int64, float64:
+		return "number"
+	case string:
+		return "string"
+	case time.Time:
+		return "time"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return "other"
+}
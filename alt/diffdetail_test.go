@@ -0,0 +1,166 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package alt_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/alt"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func diffLines(changes []*alt.Change) string {
+	lines := make([]string, len(changes))
+	for i, c := range changes {
+		lines[i] = c.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestDiffDetailedObject(t *testing.T) {
+	changes := alt.DiffDetailed(
+		sen.MustParse([]byte(`{a:1 b:{c:x d:true} e:3 f:[1 2]}`)),
+		sen.MustParse([]byte(`{a:2 b:{c:y} e:"3" f:{} g:null}`)),
+	)
+	tt.Equal(t, `~ $.a: 1 => 2
+~ $.b.c: "x" => "y"
+- $.b.d: true
+! $.e: 3 => "3"
+! $.f: [1,2] => {}
++ $.g: null`, diffLines(changes))
+
+	tt.Equal(t, alt.ChangeModified, changes[0].Kind)
+	tt.Equal(t, alt.Path{"a"}, changes[0].Path)
+	tt.Equal(t, int64(1), changes[0].Old)
+	tt.Equal(t, int64(2), changes[0].New)
+	tt.Equal(t, "removed", changes[2].Kind.String())
+	tt.Equal(t, "type-changed", changes[3].Kind.String())
+
+	tt.Equal(t, 0, len(alt.DiffDetailed(
+		map[string]any{"a": 1, "b": []any{1.0, "x"}},
+		map[string]any{"a": 1.0, "b": []any{1, "x"}},
+	)))
+}
+
+func TestDiffDetailedArray(t *testing.T) {
+	changes := alt.DiffDetailed(
+		sen.MustParse([]byte(`[a b c d e]`)),
+		sen.MustParse([]byte(`[a x b c e]`)),
+	)
+	tt.Equal(t, `+ $[1]: "x"
+- $[3]: "d"`, diffLines(changes))
+
+	changes = alt.DiffDetailed(
+		sen.MustParse([]byte(`[a b c]`)),
+		sen.MustParse([]byte(`[a B c]`)),
+	)
+	tt.Equal(t, `~ $[1]: "b" => "B"`, diffLines(changes))
+
+	changes = alt.DiffDetailed(
+		sen.MustParse([]byte(`[a b c d]`)),
+		sen.MustParse([]byte(`[d a b c]`)),
+	)
+	tt.Equal(t, `> $[3] => $[0]: "d"`, diffLines(changes))
+	tt.Equal(t, alt.Path{3}, changes[0].From)
+
+	changes = alt.DiffDetailed(
+		sen.MustParse([]byte(`{list:[{x:1} {x:2}]}`)),
+		sen.MustParse([]byte(`{list:[{x:1} {x:3} {x:4}]}`)),
+	)
+	tt.Equal(t, `~ $.list[1].x: 2 => 3
++ $.list[2]: {"x":4}`, diffLines(changes))
+}
+
+func TestDiffDetailedKeyed(t *testing.T) {
+	v0 := sen.MustParse([]byte(`[{id:1 v:a} {id:2 v:b} {id:3 v:c} {id:4 v:d}]`))
+	v1 := sen.MustParse([]byte(`[{id:3 v:c} {id:1 v:a} {id:2 v:B} {id:5 v:e}]`))
+	changes := alt.DiffDetailed(v0, v1, &alt.DiffOptions{Key: "id"})
+	tt.Equal(t, `> $[2] => $[0]: {"id":3,"v":"c"}
+~ $[2].v: "b" => "B"
++ $[3]: {"id":5,"v":"e"}
+- $[3]: {"id":4,"v":"d"}`, diffLines(changes))
+
+	// Without all elements having the key a longest common subsequence is
+	// used.
+	changes = alt.DiffDetailed(
+		sen.MustParse([]byte(`[{id:1} {x:2}]`)),
+		sen.MustParse([]byte(`[{x:2}]`)),
+		&alt.DiffOptions{Key: "id"},
+	)
+	tt.Equal(t, `- $[0]: {"id":1}`, diffLines(changes))
+}
+
+func TestDiffDetailedIgnore(t *testing.T) {
+	changes := alt.DiffDetailed(
+		sen.MustParse([]byte(`{a:1 b:[{t:1 v:1} {t:2 v:2}] c:3}`)),
+		sen.MustParse([]byte(`{a:2 b:[{t:3 v:1} {t:4 v:3}] c:3 d:4}`)),
+		&alt.DiffOptions{Ignores: []alt.Path{{"a"}, {"b", nil, "t"}, {"d"}}},
+	)
+	tt.Equal(t, `~ $.b[1].v: 2 => 3`, diffLines(changes))
+}
+
+func TestDiffDetailedStruct(t *testing.T) {
+	type Item struct {
+		Name string
+		Tags []string
+	}
+	changes := alt.DiffDetailed(
+		&Item{Name: "one", Tags: []string{"a", "b"}},
+		&Item{Name: "one", Tags: []string{"b"}},
+	)
+	tt.Equal(t, `- $.tags[0]: "a"`, diffLines(changes))
+}
+
+func TestDiffDetailedLargeArray(t *testing.T) {
+	// Arrays too large for a longest common subsequence are compared index
+	// by index after matching the common leading and trailing elements.
+	a0 := make([]any, 2000)
+	a1 := make([]any, 2001)
+	for i := range a0 {
+		a0[i] = int64(i)
+		a1[i] = int64(-i)
+	}
+	a1[0] = int64(0)
+	a1[1999] = int64(7)
+	a1[2000] = int64(1999)
+	a0[1999] = int64(1999)
+	changes := alt.DiffDetailed(a0, a1)
+	tt.Equal(t, 1999, len(changes))
+	tt.Equal(t, "~ $[1]: 1 => -1", changes[0].String())
+	tt.Equal(t, "~ $[1998]: 1998 => -1998", changes[1997].String())
+	tt.Equal(t, "+ $[1999]: 7", changes[1998].String())
+}
+
+func TestDiffReport(t *testing.T) {
+	changes := alt.DiffDetailed(
+		map[string]any{"a": 1, "b": "x", "c": []any{1, 2}},
+		map[string]any{"a": 2, "b": 2, "c": []any{2, 1}, "d": true},
+	)
+	tt.Equal(t, `~ $.a: 1 => 2
+! $.b: "x" => 2
+> $.c[0] => $.c[1]: 1
++ $.d: true
+`, alt.DiffReport(changes))
+
+	opt := ojg.Options{
+		Color:       true,
+		StringColor: "<add>",
+		NullColor:   "<rem>",
+		BoolColor:   "<mod>",
+		TimeColor:   "<type>",
+		KeyColor:    "<move>",
+		NoColor:     "</>",
+	}
+	tt.Equal(t, `<mod>~ $.a: 1 => 2</>
+<type>! $.b: "x" => 2</>
+<move>> $.c[0] => $.c[1]: 1</>
+<add>+ $.d: true</>
+`, alt.DiffReport(changes, &opt))
+	tt.Equal(t, "- $['my key']: null", (&alt.Change{Kind: alt.ChangeRemoved, Path: alt.Path{"my key"}}).String())
+	tt.Equal(t, "~ $.u: 1 => 18446744073709551615",
+		(&alt.Change{Kind: alt.ChangeModified, Path: alt.Path{"u"}, Old: uint(1), New: uint64(math.MaxUint64)}).String())
+}
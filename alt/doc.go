@@ -6,14 +6,15 @@ Package alt contains functions and types for altering values.
 # Conversions
 
 Simple conversion from one to to another include converting to string, bool,
-int64, float64, and time.Time. Each of these functions takes between one and
-three arguments. The first is the value to convert. The second argument is the
-value to return if the value can not be converted. For example, if the value
-is an array then the second argument, the first default would be returned. If
-the third argument is present then any input that is not the correct type will
-cause the third default to be returned. The conversion functions are Int(),
-FLoat(), Bool(), String(), and Time(). The reason for the defaults are to
-allow a single return from a conversion unlike a type assertion.
+int64, float64, time.Time, and time.Duration. Each of these functions takes
+between one and three arguments. The first is the value to convert. The second
+argument is the value to return if the value can not be converted. For
+example, if the value is an array then the second argument, the first default
+would be returned. If the third argument is present then any input that is not
+the correct type will cause the third default to be returned. The conversion
+functions are Int(), FLoat(), Bool(), String(), Time(), and Duration(). The
+reason for the defaults are to allow a single return from a conversion unlike
+a type assertion.
 
 	i := alt.Int("123", 0)
 
@@ -71,6 +72,26 @@ starting with a lower case character.
 	}
 	// sample: {Int: 3, Str: "three"}
 
+Interface typed fields, slices, and maps can be recomposed by registering the
+concrete types along with the member that discriminates between them. The
+Recomposer Decompose() function adds the discriminator when decomposing.
+
+	type Animal interface{ Sound() string }
+	err = r.RegisterInterface((*Animal)(nil), "kind", map[string]any{"dog": &Dog{}, "cat": &Cat{}})
+	var zoo struct{ Animals []Animal }
+	err = oj.Unmarshal([]byte(`{"animals":[{"kind":"dog","name":"Rex"}]}`), &zoo, r)
+	// zoo.Animals: []Animal{&Dog{Name: "Rex"}}
+
+A Recomposer can also be strict. Setting DisallowUnknown rejects object
+members that do not match a field and setting Required rejects objects
+missing fields tagged as required as in `json:"id,required"`. A strict
+Recomposer reports all the problems found, including type mismatches, in a
+single *RecomposeError with the JSONPath of each offending value.
+
+	r := alt.Recomposer{DisallowUnknown: true, Required: true}
+	err = oj.Unmarshal([]byte(`{"name":"x","colour":"red"}`), &sample, &r)
+	// err: $.colour: unknown field for main.Sample
+
 # Alter
 
 The GenAlter() function converts a simple go data element into Node compliant
@@ -80,5 +101,41 @@ Nodes. It modifies the values inplace if possible by altering the original.
 	m := map[string]any{"a": 1, "b": 4, "c": 9}
 	v := alt.GenAlter(m)
 	// v:  gen.Object{"a": gen.Int(1), "b": gen.Int(4), "c": gen.Int(9)}, v)
+
+# Diff
+
+The Diff() function returns the paths to the differences between two values
+while DiffDetailed() describes each change including the kind of change and
+the old and new values. Array elements are matched by a longest common
+subsequence or by a key member so that insertions and moves are detected.
+DiffReport() formats the changes for display.
+
+	changes := alt.DiffDetailed(
+		[]any{map[string]any{"id": 1, "v": "a"}, map[string]any{"id": 2, "v": "b"}},
+		[]any{map[string]any{"id": 2, "v": "c"}, map[string]any{"id": 1, "v": "a"}},
+		&alt.DiffOptions{Key: "id"})
+	fmt.Print(alt.DiffReport(changes))
+	// ~ $[0].v: "b" => "c"
+	// > $[0] => $[1]: {"id":1,"v":"a"}
+
+Match() checks that the values in a fingerprint are in a target. Besides
+exact values a fingerprint can include matchers such as Anything, AnyOf(),
+InRange(), IsType(), ContainsAll(), a *regexp.Regexp, or a jp.Script
+filter. MatchDetail() returns the path and reason of the first mismatch.
+
+	mm := alt.MatchDetail(
+		map[string]any{"age": alt.InRange(0, 120), "name": regexp.MustCompile("^[A-Z]")},
+		map[string]any{"age": 130, "name": "Ann"})
+	// $.age: 130 does not match range 0 to 120
+
+Merge3() combines the changes made to a base value in two other versions.
+Conflicting changes are resolved with a MergeResolver such as MergeOurs or
+MergeTheirs and reported along with the path to each conflict.
+
+	merged, conflicts := alt.Merge3(
+		map[string]any{"a": 1, "b": 2},
+		map[string]any{"a": 3, "b": 2},
+		map[string]any{"a": 1, "b": 4})
+	// merged: map[string]any{"a": 3, "b": 4}, conflicts: []
 */
 package alt
@@ -0,0 +1,210 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package alt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/khaf/ojg/gen"
+)
+
+// Duration converts the value provided to a time.Duration. Integers are
+// nanoseconds, floats are seconds, and strings are either golang durations
+// such as "1h30m" or ISO 8601 durations such as "PT1H30M". ISO 8601 years,
+// months, and days are approximated as 365, 30, and 1 days of 24 hours. If
+// conversion is not possible then the first option default value is returned
+// or if not provided zero is returned. If the type is not a time.Duration
+// and there is a second optional default then that second default value is
+// returned.
+func Duration(v any, defaults ...time.Duration) (d time.Duration) {
+	if td, ok := v.(time.Duration); ok {
+		return td
+	}
+	if 1 < len(defaults) {
+		return defaults[1]
+	}
+	switch tv := v.(type) {
+	case int64:
+		d = time.Duration(tv)
+	case int:
+		d = time.Duration(tv)
+	case int32:
+		d = time.Duration(tv)
+	case uint:
+		d = time.Duration(tv)
+	case uint64:
+		d = time.Duration(tv)
+	case uint32:
+		d = time.Duration(tv)
+	case float64:
+		d = time.Duration(tv * float64(time.Second))
+	case float32:
+		d = time.Duration(float64(tv) * float64(time.Second))
+	case gen.Int:
+		d = time.Duration(tv)
+	case gen.Float:
+		d = time.Duration(float64(tv) * float64(time.Second))
+	case string:
+		d = parseDuration(tv, defaults)
+	case gen.String:
+		d = parseDuration(string(tv), defaults)
+	default:
+		if 0 < len(defaults) {
+			d = defaults[0]
+		}
+	}
+	return
+}
+
+func parseDuration(s string, defaults []time.Duration) time.Duration {
+	if d, err := time.ParseDuration(s); err == nil {
+		return d
+	}
+	if iso, err := ParseISODuration(s); err == nil {
+		return iso.Approx()
+	}
+	if 0 < len(defaults) {
+		return defaults[0]
+	}
+	return 0
+}
+
+// ISODuration is an ISO 8601 duration. The years, months, and days are kept
+// separate from the fixed length portion of the duration since the length
+// of each depends on the time they are added to. Weeks are converted to
+// days.
+type ISODuration struct {
+	Years  int
+	Months int
+	Days   int
+	Dur    time.Duration
+}
+
+// ParseISODuration parses an ISO 8601 duration such as P1Y2M3DT4H5M6.5S or
+// P2W. A leading minus sign negates all the components.
+func ParseISODuration(s string) (iso ISODuration, err error) {
+	str := s
+	neg := false
+	switch {
+	case strings.HasPrefix(str, "-"):
+		neg = true
+		str = str[1:]
+	case strings.HasPrefix(str, "+"):
+		str = str[1:]
+	}
+	if len(str) < 2 || str[0] != 'P' {
+		return iso, fmt.Errorf("%q is not an ISO 8601 duration", s)
+	}
+	str = str[1:]
+	inTime := false
+	for 0 < len(str) {
+		if str[0] == 'T' {
+			if inTime || len(str) == 1 {
+				return iso, fmt.Errorf("%q is not an ISO 8601 duration", s)
+			}
+			inTime = true
+			str = str[1:]
+			continue
+		}
+		end := strings.IndexAny(str, "YMWDHS")
+		if end <= 0 {
+			return iso, fmt.Errorf("%q is not an ISO 8601 duration", s)
+		}
+		num := strings.Replace(str[:end], ",", ".", 1)
+		unit := str[end]
+		str = str[end+1:]
+		if inTime {
+			var f float64
+			if f, err = strconv.ParseFloat(num, 64); err != nil {
+				return iso, fmt.Errorf("%q is not an ISO 8601 duration", s)
+			}
+			switch unit {
+			case 'H':
+				iso.Dur += time.Duration(f * float64(time.Hour))
+			case 'M':
+				iso.Dur += time.Duration(f * float64(time.Minute))
+			case 'S':
+				iso.Dur += time.Duration(f * float64(time.Second))
+			default:
+				return iso, fmt.Errorf("%q is not an ISO 8601 duration", s)
+			}
+			continue
+		}
+		var i int
+		if i, err = strconv.Atoi(num); err != nil {
+			return iso, fmt.Errorf("%q is not an ISO 8601 duration, fractional dates are not supported", s)
+		}
+		switch unit {
+		case 'Y':
+			iso.Years += i
+		case 'M':
+			iso.Months += i
+		case 'W':
+			iso.Days += i * 7
+		case 'D':
+			iso.Days += i
+		default:
+			return iso, fmt.Errorf("%q is not an ISO 8601 duration", s)
+		}
+	}
+	if neg {
+		iso.Years = -iso.Years
+		iso.Months = -iso.Months
+		iso.Days = -iso.Days
+		iso.Dur = -iso.Dur
+	}
+	return
+}
+
+// AddTo returns the time t plus the duration. The years, months, and days
+// are added using calendar arithmetic.
+func (iso ISODuration) AddTo(t time.Time) time.Time {
+	return t.AddDate(iso.Years, iso.Months, iso.Days).Add(iso.Dur)
+}
+
+// Approx returns the duration as a time.Duration assuming years are 365
+// days, months are 30 days, and days are 24 hours.
+func (iso ISODuration) Approx() time.Duration {
+	days := time.Duration(iso.Years*365 + iso.Months*30 + iso.Days)
+	return days*24*time.Hour + iso.Dur
+}
+
+// String returns the ISO 8601 representation of the duration. If all the
+// components are negative a leading minus sign is used.
+func (iso ISODuration) String() string {
+	if iso.Years <= 0 && iso.Months <= 0 && iso.Days <= 0 && iso.Dur <= 0 &&
+		(iso.Years < 0 || iso.Months < 0 || iso.Days < 0 || iso.Dur < 0) {
+		return "-" + ISODuration{Years: -iso.Years, Months: -iso.Months, Days: -iso.Days, Dur: -iso.Dur}.String()
+	}
+	var b []byte
+	b = append(b, 'P')
+	if iso.Years != 0 {
+		b = append(strconv.AppendInt(b, int64(iso.Years), 10), 'Y')
+	}
+	if iso.Months != 0 {
+		b = append(strconv.AppendInt(b, int64(iso.Months), 10), 'M')
+	}
+	if iso.Days != 0 {
+		b = append(strconv.AppendInt(b, int64(iso.Days), 10), 'D')
+	}
+	if iso.Dur != 0 || len(b) == 1 {
+		b = append(b, 'T')
+		d := iso.Dur
+		if h := d / time.Hour; h != 0 {
+			b = append(strconv.AppendInt(b, int64(h), 10), 'H')
+			d -= h * time.Hour
+		}
+		if m := d / time.Minute; m != 0 {
+			b = append(strconv.AppendInt(b, int64(m), 10), 'M')
+			d -= m * time.Minute
+		}
+		if d != 0 || len(b) == 2 {
+			b = strconv.AppendFloat(b, d.Seconds(), 'f', -1, 64)
+			b = append(b, 'S')
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package alt_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/khaf/ojg/alt"
+	"github.com/khaf/ojg/gen"
+	"github.com/khaf/ojg/tt"
+)
+
+type durationData struct {
+	value    any
+	defaults []time.Duration
+	expect   time.Duration
+}
+
+func TestDuration(t *testing.T) {
+	for _, d := range []durationData{
+		{value: 3 * time.Second, expect: 3 * time.Second},
+		{value: 3 * time.Second, expect: 3 * time.Second, defaults: []time.Duration{1, 2}},
+
+		{value: int64(1500), expect: 1500},
+		{value: 1500, expect: 1500},
+		{value: int32(1500), expect: 1500},
+		{value: uint(1500), expect: 1500},
+		{value: uint64(1500), expect: 1500},
+		{value: uint32(1500), expect: 1500},
+		{value: gen.Int(1500), expect: 1500},
+		{value: 1500, expect: 2, defaults: []time.Duration{1, 2}},
+
+		{value: 1.5, expect: 1500 * time.Millisecond},
+		{value: float32(1.5), expect: 1500 * time.Millisecond},
+		{value: gen.Float(1.5), expect: 1500 * time.Millisecond},
+
+		{value: "1h30m", expect: 90 * time.Minute},
+		{value: "PT1H30M", expect: 90 * time.Minute},
+		{value: "P1DT1S", expect: 24*time.Hour + time.Second},
+		{value: gen.String("PT0.5S"), expect: 500 * time.Millisecond},
+		{value: "x", expect: 0},
+		{value: "x", expect: 1, defaults: []time.Duration{1}},
+
+		{value: true, expect: 0},
+		{value: true, expect: 1, defaults: []time.Duration{1}},
+	} {
+		result := alt.Duration(d.value, d.defaults...)
+		tt.Equal(t, d.expect, result, "Duration(", d.value, d.defaults, ")")
+	}
+}
+
+func TestParseISODuration(t *testing.T) {
+	for _, d := range []struct {
+		src    string
+		expect alt.ISODuration
+		str    string
+	}{
+		{src: "P1Y2M3DT4H5M6.5S", expect: alt.ISODuration{Years: 1, Months: 2, Days: 3, Dur: 4*time.Hour + 5*time.Minute + 6500*time.Millisecond}},
+		{src: "P2W", expect: alt.ISODuration{Days: 14}, str: "P14D"},
+		{src: "PT0,25S", expect: alt.ISODuration{Dur: 250 * time.Millisecond}, str: "PT0.25S"},
+		{src: "-P1DT2H", expect: alt.ISODuration{Days: -1, Dur: -2 * time.Hour}},
+		{src: "+PT36H", expect: alt.ISODuration{Dur: 36 * time.Hour}, str: "PT36H"},
+		{src: "PT0S", expect: alt.ISODuration{}},
+	} {
+		iso, err := alt.ParseISODuration(d.src)
+		tt.Nil(t, err, d.src)
+		tt.Equal(t, d.expect, iso, d.src)
+		str := d.str
+		if len(str) == 0 {
+			str = d.src
+		}
+		tt.Equal(t, str, iso.String(), d.src)
+	}
+	for _, src := range []string{"", "P", "1D", "PT", "P1H", "PT1D", "P1.5D", "PxD", "PT1HT2M", "P1DT"} {
+		_, err := alt.ParseISODuration(src)
+		tt.NotNil(t, err, src)
+	}
+}
+
+func TestISODurationAddTo(t *testing.T) {
+	tm := time.Date(2023, time.January, 31, 12, 0, 0, 0, time.UTC)
+	iso, err := alt.ParseISODuration("P1Y1M1DT1H")
+	tt.Nil(t, err)
+	tt.Equal(t, time.Date(2024, time.March, 3, 13, 0, 0, 0, time.UTC), iso.AddTo(tm))
+	tt.Equal(t, (365+30+1)*24*time.Hour+time.Hour, iso.Approx())
+}
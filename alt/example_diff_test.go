@@ -4,6 +4,7 @@ package alt_test
 
 import (
 	"fmt"
+	"regexp"
 	"sort"
 	"strings"
 
@@ -43,3 +44,47 @@ func ExampleMatch() {
 
 	// Output: match: true
 }
+
+func ExampleMatchDetail() {
+	fingerprint := map[string]any{
+		"name": regexp.MustCompile("^[A-Z]"),
+		"age":  alt.InRange(0, 120),
+		"tags": alt.ContainsAll("admin"),
+	}
+	mm := alt.MatchDetail(
+		fingerprint,
+		map[string]any{"name": "Ann", "age": 130, "tags": []any{"user", "admin"}},
+	)
+	fmt.Println(mm)
+
+	// Output: $.age: 130 does not match range 0 to 120
+}
+
+func ExampleDiffDetailed() {
+	changes := alt.DiffDetailed(
+		map[string]any{"x": 1, "y": 2, "z": []any{1, 2, 3}},
+		map[string]any{"x": 1, "y": "2", "z": []any{1, 4, 2, 3}},
+	)
+	fmt.Print(alt.DiffReport(changes))
+
+	// Output:
+	// ! $.y: 2 => "2"
+	// + $.z[1]: 4
+}
+
+func ExampleMerge3() {
+	merged, conflicts := alt.Merge3(
+		map[string]any{"a": 1, "b": 2, "c": 3},
+		map[string]any{"a": 10, "b": 2, "c": 30},
+		map[string]any{"a": 1, "b": 20, "c": 31},
+		alt.MergeTheirs,
+	)
+	fmt.Printf("merged: %v\n", merged)
+	for _, c := range conflicts {
+		fmt.Printf("conflict at %s: %v or %v\n", c.JSONPath(), c.Ours, c.Theirs)
+	}
+
+	// Output:
+	// merged: map[a:10 b:20 c:31]
+	// conflict at $.c: 30 or 31
+}
@@ -3,8 +3,12 @@
 package alt
 
 import (
+	"encoding/json"
 	"reflect"
+	"strconv"
 	"unsafe"
+
+	"github.com/khaf/ojg"
 )
 
 const (
@@ -24,6 +28,67 @@ type finfo struct {
 	ivalue valFunc
 	index  []int
 	offset uintptr
+	tagged bool
+	// zvalue and zivalue are the value functions used when omitzero
+	// applies and the field is not zero.
+	zvalue  valFunc
+	zivalue valFunc
+	// evalue is the value function used once a field reached through an
+	// embedded struct pointer is known to not be behind a nil pointer.
+	evalue valFunc
+}
+
+// viaPtr is called when a field is reached through an embedded struct
+// pointer. The address based value function can no longer be used and a nil
+// embedded pointer must result in the field being omitted.
+func (fi *finfo) viaPtr() {
+	if fi.evalue == nil {
+		fi.evalue = fi.ivalue
+		fi.ivalue = valEmbedded
+	}
+	fi.value = fi.ivalue
+}
+
+func valEmbedded(fi *finfo, rv reflect.Value, addr uintptr) (any, reflect.Value, bool) {
+	if _, err := rv.FieldByIndexErr(fi.index); err != nil {
+		return nil, nilValue, true
+	}
+	return fi.evalue(fi, rv, addr)
+}
+
+func valOmitZero(fi *finfo, rv reflect.Value, addr uintptr) (any, reflect.Value, bool) {
+	if isZeroField(rv, fi.index) {
+		return nil, nilValue, true
+	}
+	return fi.zvalue(fi, rv, addr)
+}
+
+func ivalOmitZero(fi *finfo, rv reflect.Value, addr uintptr) (any, reflect.Value, bool) {
+	if isZeroField(rv, fi.index) {
+		return nil, nilValue, true
+	}
+	return fi.zivalue(fi, rv, addr)
+}
+
+// isZeroField returns true if the field is the zero value or has an
+// IsZero() method that returns true as with the json omitzero option.
+func isZeroField(rv reflect.Value, index []int) bool {
+	fv, err := rv.FieldByIndexErr(index)
+	if err != nil {
+		return true
+	}
+	if fv.Kind() == reflect.Ptr && fv.IsNil() {
+		return true
+	}
+	if z, ok := fv.Interface().(interface{ IsZero() bool }); ok {
+		return z.IsZero()
+	}
+	if fv.CanAddr() {
+		if z, ok := fv.Addr().Interface().(interface{ IsZero() bool }); ok {
+			return z.IsZero()
+		}
+	}
+	return fv.IsZero()
 }
 
 func valString(fi *finfo, rv reflect.Value, addr uintptr) (any, reflect.Value, bool) {
@@ -93,6 +158,119 @@ func valGenericerAddr(fi *finfo, rv reflect.Value, addr uintptr) (any, reflect.V
 	return nil, nilValue, false
 }
 
+func valStringAsString(fi *finfo, rv reflect.Value, addr uintptr) (any, reflect.Value, bool) {
+	return string(ojg.AppendJSONString(nil, rv.FieldByIndex(fi.index).String(), false)), nilValue, false
+}
+
+func valStringNotEmptyAsString(fi *finfo, rv reflect.Value, addr uintptr) (any, reflect.Value, bool) {
+	s := rv.FieldByIndex(fi.index).String()
+	if len(s) == 0 {
+		return s, nilValue, true
+	}
+	return string(ojg.AppendJSONString(nil, s, false)), nilValue, false
+}
+
+// valNumber is used for json.Number fields which are numbers as with the
+// json package. An empty json.Number is 0.
+func valNumber(fi *finfo, rv reflect.Value, addr uintptr) (any, reflect.Value, bool) {
+	return json.Number(numberString(rv.FieldByIndex(fi.index).String())), nilValue, false
+}
+
+func valNumberNotEmpty(fi *finfo, rv reflect.Value, addr uintptr) (any, reflect.Value, bool) {
+	s := rv.FieldByIndex(fi.index).String()
+	return json.Number(s), nilValue, len(s) == 0
+}
+
+func valNumberAsString(fi *finfo, rv reflect.Value, addr uintptr) (any, reflect.Value, bool) {
+	return numberString(rv.FieldByIndex(fi.index).String()), nilValue, false
+}
+
+func valNumberNotEmptyAsString(fi *finfo, rv reflect.Value, addr uintptr) (any, reflect.Value, bool) {
+	s := rv.FieldByIndex(fi.index).String()
+	return s, nilValue, len(s) == 0
+}
+
+func numberString(s string) string {
+	if len(s) == 0 {
+		return "0"
+	}
+	return s
+}
+
+// valPtrAsString is used for pointers to scalar types with the json string
+// option.
+func valPtrAsString(fi *finfo, rv reflect.Value, addr uintptr) (any, reflect.Value, bool) {
+	fv := rv.FieldByIndex(fi.index)
+	if fv.IsNil() {
+		return nil, nilValue, false
+	}
+	return scalarAsString(fv.Elem()), nilValue, false
+}
+
+func valPtrNotEmptyAsString(fi *finfo, rv reflect.Value, addr uintptr) (any, reflect.Value, bool) {
+	fv := rv.FieldByIndex(fi.index)
+	if fv.IsNil() {
+		return nil, nilValue, true
+	}
+	return scalarAsString(fv.Elem()), nilValue, false
+}
+
+func scalarAsString(rv reflect.Value) string {
+	if rv.Type() == jsonNumberType {
+		return numberString(rv.String())
+	}
+	switch rv.Kind() {
+	case reflect.String:
+		return string(ojg.AppendJSONString(nil, rv.String(), false))
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, 32)
+	}
+	return strconv.FormatFloat(rv.Float(), 'g', -1, 64)
+}
+
+func isScalarKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func valCodec(fi *finfo, rv reflect.Value, addr uintptr) (any, reflect.Value, bool) {
+	// The encoded value is returned as a reflect.Value as well so that it is
+	// decomposed.
+	v := encodeField(fi, rv.FieldByIndex(fi.index).Interface())
+	return v, reflect.ValueOf(v), false
+}
+
+func valCodecNotEmpty(fi *finfo, rv reflect.Value, addr uintptr) (any, reflect.Value, bool) {
+	fv := rv.FieldByIndex(fi.index)
+	if fv.IsZero() {
+		return nil, nilValue, true
+	}
+	v := encodeField(fi, fv.Interface())
+	return v, reflect.ValueOf(v), v == nil
+}
+
+// encodeField encodes a field value with the codec for the field type. If
+// the codec has been removed since the field information was cached then the
+// value is returned as is.
+func encodeField(fi *finfo, v any) any {
+	if encode := ojg.LookupEncoder(fi.rt); encode != nil {
+		return encode(v)
+	}
+	return v
+}
+
 func newFinfo(f *reflect.StructField, key string, fx byte) *finfo {
 	fi := finfo{
 		rt:     f.Type,
@@ -102,6 +280,16 @@ func newFinfo(f *reflect.StructField, key string, fx byte) *finfo {
 		ivalue: valJustVal, // replace as necessary later
 		offset: f.Offset,
 	}
+	if ojg.LookupEncoder(fi.rt) != nil {
+		if (fx & omitMask) != 0 {
+			fi.value = valCodecNotEmpty
+			fi.ivalue = valCodecNotEmpty
+		} else {
+			fi.value = valCodec
+			fi.ivalue = valCodec
+		}
+		return &fi
+	}
 	// Check for interfaces first since almost any type can implement one of
 	// the supported interfaces.
 	vp := reflect.New(fi.rt).Interface()
@@ -171,10 +359,34 @@ func newFinfo(f *reflect.StructField, key string, fx byte) *finfo {
 		fi.ivalue = float64ValFuncs[fx|embedMask]
 
 	case reflect.String:
-		if (fx & omitMask) != 0 {
+		if fi.rt == jsonNumberType {
+			switch fx & (omitMask | strMask) {
+			case omitMask | strMask:
+				fi.value = valNumberNotEmptyAsString
+				fi.ivalue = valNumberNotEmptyAsString
+			case omitMask:
+				fi.value = valNumberNotEmpty
+				fi.ivalue = valNumberNotEmpty
+			case strMask:
+				fi.value = valNumberAsString
+				fi.ivalue = valNumberAsString
+			default:
+				fi.value = valNumber
+				fi.ivalue = valNumber
+			}
+			break
+		}
+		switch fx & (omitMask | strMask) {
+		case omitMask | strMask:
+			fi.value = valStringNotEmptyAsString
+			fi.ivalue = valStringNotEmptyAsString
+		case omitMask:
 			fi.value = valStringNotEmpty
 			fi.ivalue = valStringNotEmpty
-		} else {
+		case strMask:
+			fi.value = valStringAsString
+			fi.ivalue = valStringAsString
+		default:
 			fi.value = valString
 			fi.ivalue = valString
 		}
@@ -182,10 +394,19 @@ func newFinfo(f *reflect.StructField, key string, fx byte) *finfo {
 		fi.value = valJustVal
 		fi.ivalue = valJustVal
 	case reflect.Ptr:
-		if (fx & omitMask) != 0 {
+		switch {
+		case (fx&strMask) != 0 && isScalarKind(f.Type.Elem().Kind()):
+			if (fx & omitMask) != 0 {
+				fi.value = valPtrNotEmptyAsString
+				fi.ivalue = valPtrNotEmptyAsString
+			} else {
+				fi.value = valPtrAsString
+				fi.ivalue = valPtrAsString
+			}
+		case (fx & omitMask) != 0:
 			fi.value = valPtrNotEmpty
 			fi.ivalue = valPtrNotEmpty
-		} else {
+		default:
 			fi.value = valJustVal
 			fi.ivalue = valJustVal
 		}
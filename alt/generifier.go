@@ -3,11 +3,14 @@
 package alt
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
 	"time"
 	"unsafe"
 
+	"github.com/khaf/ojg"
 	"github.com/khaf/ojg/gen"
 )
 
@@ -28,6 +31,9 @@ func Generify(v any, options ...*Options) (n gen.Node) {
 	if 0 < len(options) {
 		opt = options[0]
 	}
+	if enc := ojg.FindEncoder(v); enc != nil {
+		v = enc(v)
+	}
 	if v != nil {
 		switch tv := v.(type) {
 		case bool:
@@ -45,7 +51,7 @@ func Generify(v any, options ...*Options) (n gen.Node) {
 		case int64:
 			n = gen.Int(tv)
 		case uint:
-			n = gen.Int(int64(tv))
+			n = genUint(uint64(tv))
 		case uint8:
 			n = gen.Int(int64(tv))
 		case uint16:
@@ -53,9 +59,11 @@ func Generify(v any, options ...*Options) (n gen.Node) {
 		case uint32:
 			n = gen.Int(int64(tv))
 		case uint64:
-			n = gen.Int(int64(tv))
+			n = genUint(tv)
 		case gen.Int:
 			n = tv
+		case json.Number:
+			n = gen.Decimal(tv)
 		case float32:
 			n = gen.Float(float64(tv))
 		case float64:
@@ -70,6 +78,8 @@ func Generify(v any, options ...*Options) (n gen.Node) {
 			n = gen.Time(tv)
 		case gen.Time:
 			n = tv
+		case []byte:
+			n = gen.Bytes(append([]byte{}, tv...))
 		case []any:
 			a := make(gen.Array, len(tv))
 			for i, m := range tv {
@@ -127,7 +137,7 @@ func GenAlter(v any, options ...*Options) (n gen.Node) {
 		case int64:
 			n = gen.Int(tv)
 		case uint:
-			n = gen.Int(int64(tv))
+			n = genUint(uint64(tv))
 		case uint8:
 			n = gen.Int(int64(tv))
 		case uint16:
@@ -135,9 +145,11 @@ func GenAlter(v any, options ...*Options) (n gen.Node) {
 		case uint32:
 			n = gen.Int(int64(tv))
 		case uint64:
-			n = gen.Int(int64(tv))
+			n = genUint(tv)
 		case gen.Int:
 			n = tv
+		case json.Number:
+			n = gen.Decimal(tv)
 		case float32:
 			n = gen.Float(float64(tv))
 		case float64:
@@ -150,6 +162,8 @@ func GenAlter(v any, options ...*Options) (n gen.Node) {
 			n = tv
 		case time.Time:
 			n = gen.Time(tv)
+		case []byte:
+			n = gen.Bytes(tv)
 		case []any:
 			a := *(*gen.Array)(unsafe.Pointer(&tv))
 			for i, m := range tv {
@@ -188,6 +202,15 @@ func GenAlter(v any, options ...*Options) (n gen.Node) {
 	return
 }
 
+// genUint returns an Int if the value fits in an int64 and a Uint
+// otherwise.
+func genUint(u uint64) gen.Node {
+	if u <= math.MaxInt64 {
+		return gen.Int(int64(u))
+	}
+	return gen.Uint(u)
+}
+
 func reflectGenData(data any, opt *Options) gen.Node {
 	return reflectGenValue(reflect.ValueOf(data), opt)
 }
@@ -201,7 +224,9 @@ func reflectGenValue(rv reflect.Value, opt *Options) (v gen.Node) {
 	case reflect.Map:
 		v = reflectGenMap(rv, opt)
 	case reflect.Ptr:
-		v = reflectGenValue(rv.Elem(), opt)
+		if elem := rv.Elem(); elem.IsValid() && elem.CanInterface() {
+			v = Generify(elem.Interface(), opt)
+		}
 	case reflect.Slice, reflect.Array:
 		v = reflectGenArray(rv, opt)
 	case reflect.Struct:
@@ -220,6 +245,9 @@ func reflectGenStruct(rv reflect.Value, opt *Options) gen.Node {
 			obj[opt.CreateKey] = gen.String(t.Name())
 		}
 	}
+	if opt.UseTags {
+		return reflectGenTagged(rv, obj, opt)
+	}
 	for i := rv.NumField() - 1; 0 <= i; i-- {
 		name := []byte(t.Field(i).Name)
 		if len(name) == 0 || 'a' <= name[0] {
@@ -235,6 +263,21 @@ func reflectGenStruct(rv reflect.Value, opt *Options) gen.Node {
 	return obj
 }
 
+// reflectGenTagged uses the json tags on the struct fields in the same way
+// as Decompose does.
+func reflectGenTagged(rv reflect.Value, obj gen.Object, opt *Options) gen.Node {
+	si := getSinfo(rv.Interface())
+	for _, fi := range si.getFields(opt) {
+		if v, _, omit := fi.ivalue(fi, rv, 0); !omit {
+			g := Generify(v, opt)
+			if g != nil || !opt.OmitNil {
+				obj[fi.key] = g
+			}
+		}
+	}
+	return obj
+}
+
 func reflectGenComplex(rv reflect.Value, opt *Options) gen.Node {
 	c := rv.Complex()
 	obj := gen.Object{
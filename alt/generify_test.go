@@ -3,6 +3,10 @@
 package alt_test
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
 	"testing"
 	"time"
 
@@ -127,6 +131,39 @@ func TestGenerifyNode(t *testing.T) {
 	tt.Equal(t, gen.Array{gen.Int(1)}, v)
 }
 
+func TestGenerifyExtended(t *testing.T) {
+	b := []byte("abc")
+	v := alt.Generify([]any{
+		uint64(math.MaxUint64),
+		uint(math.MaxInt64 + 1),
+		json.Number("1.50"),
+		b,
+		gen.Uint(7),
+		gen.Decimal("2.5"),
+		gen.Bytes("xyz"),
+		gen.Null{},
+	})
+	var types []string
+	for _, n := range v.(gen.Array) {
+		types = append(types, fmt.Sprintf("%T %v", n, n))
+	}
+	tt.Equal(t, `gen.Uint 18446744073709551615
+gen.Uint 9223372036854775808
+gen.Decimal 1.50
+gen.Bytes "YWJj"
+gen.Uint 7
+gen.Decimal 2.5
+gen.Bytes "eHl6"
+gen.Null null`, strings.Join(types, "\n"))
+
+	// Generify copies the bytes.
+	b[0] = 'x'
+	tt.Equal(t, "abc", string(v.(gen.Array)[3].(gen.Bytes)))
+
+	v = alt.GenAlter([]any{uint64(math.MaxUint64), json.Number("1.50"), b})
+	tt.Equal(t, gen.Array{gen.Uint(math.MaxUint64), gen.Decimal("1.50"), gen.Bytes("xbc")}, v)
+}
+
 func TestGenAlterBase(t *testing.T) {
 	tm := time.Date(2020, time.April, 12, 16, 34, 04, 123456789, time.UTC)
 	a := []any{
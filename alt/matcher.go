@@ -0,0 +1,337 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package alt
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+// Matcher is implemented by fingerprint values that match a target value by
+// some rule other than equality. A jp.Script is a Matcher so filters such
+// as jp.MustNewScript("(@.age > 21)") can be used in a fingerprint. If a
+// Matcher is also a fmt.Stringer the string is used to describe a
+// mismatch.
+type Matcher interface {
+	// Match returns true if the target value matches.
+	Match(target any) bool
+}
+
+// MatchFunc adapts a function to the Matcher interface.
+type MatchFunc func(target any) bool
+
+// Match returns the result of calling the function with the target.
+func (f MatchFunc) Match(target any) bool {
+	return f(target)
+}
+
+// Anything matches any value including a missing value.
+var Anything Matcher = anything{}
+
+// Mismatch describes the first difference found by MatchDetail().
+type Mismatch struct {
+	// Path to the value in the target that did not match.
+	Path Path
+	// Reason the value did not match.
+	Reason string
+}
+
+// JSONPath returns the path to the mismatch in the same format as a jp.Expr
+// such as $.servers[1].port.
+func (m *Mismatch) JSONPath() string {
+	return string(appendPath(nil, m.Path))
+}
+
+// String returns the path followed by the reason for the mismatch.
+func (m *Mismatch) String() string {
+	return m.JSONPath() + ": " + m.Reason
+}
+
+// MatchDetail is the same as Match() except that it returns a description
+// of the first mismatch encountered or nil if the target matches the
+// fingerprint.
+func MatchDetail(fingerprint, target any) *Mismatch {
+	return matchValue(Path{}, fingerprint, target)
+}
+
+// AnyOf returns a Matcher that matches if any of the values match the
+// target. The values can be matchers themselves.
+func AnyOf(values ...any) Matcher {
+	return anyOf(values)
+}
+
+// InRange returns a Matcher that matches numbers between min and max
+// inclusive. A nil min or max leaves that end of the range unbounded.
+func InRange(min, max any) Matcher {
+	r := numRange{}
+	if min != nil {
+		if f, ok := asFloat(min); ok {
+			r.min = &f
+		}
+	}
+	if max != nil {
+		if f, ok := asFloat(max); ok {
+			r.max = &f
+		}
+	}
+	return &r
+}
+
+// IsType returns a Matcher that matches values of any of the named JSON
+// types. The recognized names are null, boolean, number, integer, string,
+// time, array, and object.
+func IsType(names ...string) Matcher {
+	return isType(names)
+}
+
+// ContainsAll returns a Matcher that matches arrays that include an element
+// matching each of the values in any order. Each value must match a
+// different element. Other elements in the target array are ignored.
+func ContainsAll(values ...any) Matcher {
+	return containsAll(values)
+}
+
+func matchValue(path Path, fingerprint, target any) *Mismatch {
+	switch fp := fingerprint.(type) {
+	case nil:
+		if target != nil {
+			return mismatch(path, "expected null but was %s", describeMatch(target))
+		}
+	case bool:
+		if t1, ok := target.(bool); !ok || fp != t1 {
+			return mismatchValue(path, fp, target)
+		}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		i0, _ := asInt(fp)
+		if i1, ok := asInt(target); !ok || i0 != i1 {
+			return mismatchValue(path, fp, target)
+		}
+	case float32, float64:
+		f0, _ := asFloat(fp)
+		if f1, ok := asFloat(target); !ok || f0 != f1 {
+			return mismatchValue(path, fp, target)
+		}
+	case string:
+		if t1, ok := target.(string); !ok || fp != t1 {
+			return mismatchValue(path, fp, target)
+		}
+	case time.Time:
+		if t1, ok := target.(time.Time); !ok || !fp.Round(TimeTolerance).Equal(t1.Round(TimeTolerance)) {
+			return mismatchValue(path, fp, target)
+		}
+	case []any:
+		t1, ok := target.([]any)
+		if !ok {
+			return mismatch(path, "expected an array but was %s", describeMatch(target))
+		}
+		if len(fp) != len(t1) {
+			return mismatch(path, "expected an array of length %d but the length was %d", len(fp), len(t1))
+		}
+		for i, v := range fp {
+			if mm := matchValue(append(path, i), v, t1[i]); mm != nil {
+				return mm
+			}
+		}
+	case map[string]any:
+		t1, ok := target.(map[string]any)
+		if !ok {
+			return mismatch(path, "expected an object but was %s", describeMatch(target))
+		}
+		keys := make([]string, 0, len(fp))
+		for k := range fp {
+			keys = append(keys, k)
+		}
+		// Sort so the first mismatch is consistent.
+		sort.Strings(keys)
+		for _, k := range keys {
+			tv, has := t1[k]
+			if mm := matchValue(append(path, k), fp[k], tv); mm != nil {
+				if !has {
+					mm.Reason = "missing, expected " + describeMatch(fp[k])
+				}
+				return mm
+			}
+		}
+	case *regexp.Regexp:
+		if s, ok := target.(string); !ok || !fp.MatchString(s) {
+			return mismatch(path, "%s does not match /%s/", describeMatch(target), fp)
+		}
+	case Matcher:
+		if !fp.Match(target) {
+			return mismatch(path, "%s does not match %s", describeMatch(target), describeMatch(fp))
+		}
+	default:
+		vt0 := (*[2]uintptr)(unsafe.Pointer(&fingerprint))[0]
+		vt1 := (*[2]uintptr)(unsafe.Pointer(&target))[0]
+		if vt0 == vt1 {
+			if s0, _ := fingerprint.(Simplifier); s0 != nil {
+				if s1, _ := target.(Simplifier); s1 != nil {
+					return matchValue(path, s0.Simplify(), s1.Simplify())
+				}
+			}
+			opt := &Options{}
+			fv := reflectValue(reflect.ValueOf(fingerprint), fingerprint, opt)
+			tv := reflectValue(reflect.ValueOf(target), target, opt)
+			if fv != nil && tv != nil {
+				return matchValue(path, fv, tv)
+			}
+		}
+		return mismatch(path, "expected %v but was %v", fingerprint, target)
+	}
+	return nil
+}
+
+func mismatch(path Path, format string, args ...any) *Mismatch {
+	return &Mismatch{Path: append(Path{}, path...), Reason: fmt.Sprintf(format, args...)}
+}
+
+func mismatchValue(path Path, fingerprint, target any) *Mismatch {
+	return mismatch(path, "expected %s but was %s", describeMatch(fingerprint), describeMatch(target))
+}
+
+func describeMatch(v any) string {
+	switch tv := v.(type) {
+	case *regexp.Regexp:
+		return "/" + tv.String() + "/"
+	case fmt.Stringer:
+		if _, ok := tv.(Matcher); ok {
+			return tv.String()
+		}
+	case Matcher:
+		return "matcher"
+	}
+	return string(appendDiffValue(nil, v))
+}
+
+type anything struct{}
+
+func (anything) Match(target any) bool {
+	return true
+}
+
+func (anything) String() string {
+	return "anything"
+}
+
+type anyOf []any
+
+func (m anyOf) Match(target any) bool {
+	for _, v := range m {
+		if matchValue(nil, v, target) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (m anyOf) String() string {
+	strs := make([]string, len(m))
+	for i, v := range m {
+		strs[i] = describeMatch(v)
+	}
+	return "any of " + strings.Join(strs, ", ")
+}
+
+type numRange struct {
+	min *float64
+	max *float64
+}
+
+func (m *numRange) Match(target any) bool {
+	f, ok := asFloat(target)
+	if !ok {
+		return false
+	}
+	return (m.min == nil || *m.min <= f) && (m.max == nil || f <= *m.max)
+}
+
+func (m *numRange) String() string {
+	switch {
+	case m.min != nil && m.max != nil:
+		return fmt.Sprintf("range %g to %g", *m.min, *m.max)
+	case m.min != nil:
+		return fmt.Sprintf("range %g or more", *m.min)
+	case m.max != nil:
+		return fmt.Sprintf("range %g or less", *m.max)
+	}
+	return "range of any number"
+}
+
+type isType []string
+
+func (m isType) Match(target any) bool {
+	for _, name := range m {
+		switch name {
+		case "null":
+			if target == nil {
+				return true
+			}
+		case "integer":
+			if _, ok := asInt(target); ok {
+				return true
+			}
+		case "number":
+			if _, ok := asFloat(target); ok {
+				return true
+			}
+		default:
+			if name == diffTypeName(target) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (m isType) String() string {
+	return "type " + strings.Join(m, " or ")
+}
+
+type containsAll []any
+
+func (m containsAll) Match(target any) bool {
+	list, ok := target.([]any)
+	if !ok {
+		return false
+	}
+	// Assign each value to a different element using augmenting paths so
+	// that a value that matches many elements does not take the only
+	// element another value matches.
+	owner := make([]int, len(list))
+	for i := range owner {
+		owner[i] = -1
+	}
+	for vi := range m {
+		if !m.assign(vi, list, owner, make([]bool, len(list))) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m containsAll) assign(vi int, list []any, owner []int, seen []bool) bool {
+	for i, v := range list {
+		if seen[i] || matchValue(nil, m[vi], v) != nil {
+			continue
+		}
+		seen[i] = true
+		if owner[i] < 0 || m.assign(owner[i], list, owner, seen) {
+			owner[i] = vi
+			return true
+		}
+	}
+	return false
+}
+
+func (m containsAll) String() string {
+	strs := make([]string, len(m))
+	for i, v := range m {
+		strs[i] = describeMatch(v)
+	}
+	return "contains all of " + strings.Join(strs, ", ")
+}
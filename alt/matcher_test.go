@@ -0,0 +1,123 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package alt_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/khaf/ojg/alt"
+	"github.com/khaf/ojg/jp"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestMatchAnything(t *testing.T) {
+	fp := map[string]any{"x": alt.Anything}
+	tt.Equal(t, true, alt.Match(fp, map[string]any{"x": 1}))
+	tt.Equal(t, true, alt.Match(fp, map[string]any{"x": []any{1, 2}}))
+	tt.Equal(t, true, alt.Match(fp, map[string]any{}))
+}
+
+func TestMatchAnyOf(t *testing.T) {
+	fp := map[string]any{"x": alt.AnyOf("a", 2, nil)}
+	tt.Equal(t, true, alt.Match(fp, map[string]any{"x": "a"}))
+	tt.Equal(t, true, alt.Match(fp, map[string]any{"x": int64(2)}))
+	tt.Equal(t, true, alt.Match(fp, map[string]any{}))
+	tt.Equal(t, false, alt.Match(fp, map[string]any{"x": "b"}))
+	tt.Equal(t, `$.x: "b" does not match any of "a", 2, null`,
+		alt.MatchDetail(fp, map[string]any{"x": "b"}).String())
+}
+
+func TestMatchInRange(t *testing.T) {
+	fp := map[string]any{"x": alt.InRange(1, 10), "y": alt.InRange(nil, 2.5), "z": alt.InRange(0, nil)}
+	tt.Equal(t, true, alt.Match(fp, map[string]any{"x": 1, "y": -3, "z": 7.5}))
+	tt.Equal(t, true, alt.Match(fp, map[string]any{"x": 10.0, "y": 2.5, "z": int64(0)}))
+	tt.Equal(t, false, alt.Match(fp, map[string]any{"x": 11, "y": 0, "z": 0}))
+	tt.Equal(t, false, alt.Match(fp, map[string]any{"x": 5, "y": 0, "z": -1}))
+	tt.Equal(t, false, alt.Match(fp, map[string]any{"x": "5", "y": 0, "z": 0}))
+	tt.Equal(t, "$.y: 3 does not match range 2.5 or less",
+		alt.MatchDetail(fp, map[string]any{"x": 5, "y": 3, "z": 0}).String())
+	tt.Equal(t, "$.x: 0 does not match range 1 to 10",
+		alt.MatchDetail(fp, map[string]any{"x": 0}).String())
+	tt.Equal(t, "$.z: -1 does not match range 0 or more",
+		alt.MatchDetail(fp, map[string]any{"x": 1, "y": 1, "z": -1}).String())
+}
+
+func TestMatchRegexp(t *testing.T) {
+	fp := map[string]any{"name": regexp.MustCompile("^a.c$")}
+	tt.Equal(t, true, alt.Match(fp, map[string]any{"name": "abc"}))
+	tt.Equal(t, false, alt.Match(fp, map[string]any{"name": "abcd"}))
+	tt.Equal(t, false, alt.Match(fp, map[string]any{"name": 3}))
+	tt.Equal(t, `$.name: "abcd" does not match /^a.c$/`,
+		alt.MatchDetail(fp, map[string]any{"name": "abcd"}).String())
+}
+
+func TestMatchIsType(t *testing.T) {
+	fp := []any{
+		alt.IsType("integer"),
+		alt.IsType("number"),
+		alt.IsType("string", "null"),
+		alt.IsType("array"),
+		alt.IsType("object"),
+		alt.IsType("boolean"),
+	}
+	tt.Equal(t, true, alt.Match(fp, []any{3, 1.5, nil, []any{}, map[string]any{}, true}))
+	tt.Equal(t, true, alt.Match(fp, []any{3.0, int8(1), "x", []any{1}, map[string]any{"a": 1}, false}))
+	tt.Equal(t, "$[0]: 1.5 does not match type integer",
+		alt.MatchDetail(fp, []any{1.5, 1.5, nil, []any{}, map[string]any{}, true}).String())
+	tt.Equal(t, "$[2]: 7 does not match type string or null",
+		alt.MatchDetail(fp, []any{1, 1.5, 7, []any{}, map[string]any{}, true}).String())
+}
+
+func TestMatchContainsAll(t *testing.T) {
+	fp := map[string]any{"tags": alt.ContainsAll("b", alt.AnyOf("a", "b"))}
+	tt.Equal(t, true, alt.Match(fp, map[string]any{"tags": []any{"c", "b", "a"}}))
+	// Each value must match a different element.
+	tt.Equal(t, true, alt.Match(fp, map[string]any{"tags": []any{"b", "b"}}))
+	tt.Equal(t, false, alt.Match(fp, map[string]any{"tags": []any{"b", "c"}}))
+	tt.Equal(t, false, alt.Match(fp, map[string]any{"tags": "b"}))
+	tt.Equal(t, `$.tags: ["b","c"] does not match contains all of "b", any of "a", "b"`,
+		alt.MatchDetail(fp, map[string]any{"tags": []any{"b", "c"}}).String())
+
+	tt.Equal(t, true, alt.Match(
+		alt.ContainsAll(map[string]any{"id": 2}, map[string]any{"id": 1}),
+		sen.MustParse([]byte(`[{id:1 v:a} {id:2 v:b} {id:3 v:c}]`)),
+	))
+}
+
+func TestMatchScript(t *testing.T) {
+	fp := map[string]any{
+		"user": jp.MustNewScript("(@.age >= 21 && @.name == 'Bob')"),
+	}
+	tt.Equal(t, true, alt.Match(fp, sen.MustParse([]byte(`{user:{name:Bob age:30}}`))))
+	tt.Equal(t, false, alt.Match(fp, sen.MustParse([]byte(`{user:{name:Bob age:20}}`))))
+	tt.Equal(t, `$.user: {"age":20,"name":"Bob"} does not match (@.age >= 21 && @.name == 'Bob')`,
+		alt.MatchDetail(fp, sen.MustParse([]byte(`{user:{name:Bob age:20}}`))).String())
+
+	fp = map[string]any{"n": alt.MatchFunc(func(v any) bool { return v == "odd" || v == "even" })}
+	tt.Equal(t, true, alt.Match(fp, map[string]any{"n": "odd"}))
+	tt.Equal(t, `$.n: "one" does not match matcher`, alt.MatchDetail(fp, map[string]any{"n": "one"}).String())
+}
+
+func TestMatchDetail(t *testing.T) {
+	fp := sen.MustParse([]byte(`{a:1 b:{c:[x y]} d:null}`))
+	tt.Nil(t, alt.MatchDetail(fp, sen.MustParse([]byte(`{a:1 b:{c:[x y] e:3}}`))))
+
+	mm := alt.MatchDetail(fp, sen.MustParse([]byte(`{a:1 b:{c:[x z]}}`)))
+	tt.Equal(t, alt.Path{"b", "c", 1}, mm.Path)
+	tt.Equal(t, "$.b.c[1]", mm.JSONPath())
+	tt.Equal(t, `expected "y" but was "z"`, mm.Reason)
+
+	tt.Equal(t, "$.b.c: expected an array of length 2 but the length was 1",
+		alt.MatchDetail(fp, sen.MustParse([]byte(`{a:1 b:{c:[x]}}`))).String())
+	tt.Equal(t, `$.b: missing, expected {"c":["x","y"]}`,
+		alt.MatchDetail(fp, sen.MustParse([]byte(`{a:1}`))).String())
+	tt.Equal(t, "$.d: expected null but was true",
+		alt.MatchDetail(fp, sen.MustParse([]byte(`{a:1 b:{c:[x y]} d:true}`))).String())
+	tt.Equal(t, "$.b: expected an object but was 2",
+		alt.MatchDetail(fp, sen.MustParse([]byte(`{a:1 b:2}`))).String())
+	tt.Equal(t, "$.b.c: expected an array but was null",
+		alt.MatchDetail(fp, sen.MustParse([]byte(`{a:1 b:{c:null}}`))).String())
+	tt.Equal(t, "$: expected an object but was [1]", alt.MatchDetail(fp, []any{1}).String())
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package alt
+
+import (
+	"sort"
+)
+
+// Conflict describes a value that was changed differently in both ours and
+// theirs when merging with Merge3().
+type Conflict struct {
+	// Path to the conflicting value in the merged result.
+	Path Path
+	// Base is the original value.
+	Base any
+	// Ours is our version of the value.
+	Ours any
+	// Theirs is their version of the value.
+	Theirs any
+	// BaseMissing is true if the value was not present in the base.
+	BaseMissing bool
+	// OursRemoved is true if the value was removed in ours.
+	OursRemoved bool
+	// TheirsRemoved is true if the value was removed in theirs.
+	TheirsRemoved bool
+}
+
+// JSONPath returns the path to the conflict in the same format as a
+// jp.Expr such as $.servers[1].port.
+func (c *Conflict) JSONPath() string {
+	return string(appendPath(nil, c.Path))
+}
+
+// MergeResolver determines the value to use in the merged result for a
+// conflict. If keep is false the value is left out of the result.
+type MergeResolver func(c *Conflict) (v any, keep bool)
+
+// MergeOurs is a MergeResolver that uses our version of a conflicting value.
+func MergeOurs(c *Conflict) (any, bool) {
+	return c.Ours, !c.OursRemoved
+}
+
+// MergeTheirs is a MergeResolver that uses their version of a conflicting
+// value.
+func MergeTheirs(c *Conflict) (any, bool) {
+	return c.Theirs, !c.TheirsRemoved
+}
+
+// Merge3 combines the changes made to base in ours and theirs. Changes made
+// in only one of ours and theirs are applied as are identical changes made
+// in both. Objects are merged member by member. Arrays are merged by
+// aligning each version with the base so that insertions and removals at
+// different locations are combined and elements modified in place are
+// merged recursively. When the same value was changed differently, or the
+// changes to an array overlap, the resolver determines the result and the
+// conflict is included in the returned conflicts. If no resolver is
+// provided then MergeOurs is used. Values that are not simple types are
+// decomposed before being merged and the result does not share values with
+// the arguments.
+func Merge3(base, ours, theirs any, resolver ...MergeResolver) (merged any, conflicts []*Conflict) {
+	m := merger{resolve: MergeOurs}
+	if 0 < len(resolver) && resolver[0] != nil {
+		m.resolve = resolver[0]
+	}
+	opt := Options{TimeFormat: "time"}
+	merged, _ = m.merge(Path{},
+		mergeValue{v: Decompose(base, &opt), has: true},
+		mergeValue{v: Decompose(ours, &opt), has: true},
+		mergeValue{v: Decompose(theirs, &opt), has: true})
+
+	return merged, m.conflicts
+}
+
+type mergeValue struct {
+	v   any
+	has bool
+}
+
+func (mv mergeValue) equal(other mergeValue) bool {
+	if mv.has != other.has {
+		return false
+	}
+	return !mv.has || diffEqual(mv.v, other.v)
+}
+
+type merger struct {
+	resolve   MergeResolver
+	conflicts []*Conflict
+}
+
+func (m *merger) merge(path Path, base, ours, theirs mergeValue) (any, bool) {
+	switch {
+	case ours.equal(theirs), base.equal(theirs):
+		return ours.v, ours.has
+	case base.equal(ours):
+		return theirs.v, theirs.has
+	}
+	// Both changed and differently.
+	if o0, ok := ours.v.(map[string]any); ok {
+		if o1, ok := theirs.v.(map[string]any); ok {
+			if !base.has {
+				return m.mergeObjects(path, map[string]any{}, o0, o1), true
+			}
+			if b, ok := base.v.(map[string]any); ok {
+				return m.mergeObjects(path, b, o0, o1), true
+			}
+		}
+	}
+	if a0, ok := ours.v.([]any); ok {
+		if a1, ok := theirs.v.([]any); ok {
+			if b, ok := base.v.([]any); ok && base.has {
+				if merged, ok := m.mergeArrays(path, b, a0, a1); ok {
+					return merged, true
+				}
+			}
+		}
+	}
+	return m.conflict(path, base, ours, theirs)
+}
+
+func (m *merger) conflict(path Path, base, ours, theirs mergeValue) (any, bool) {
+	c := Conflict{
+		Path:          append(Path{}, path...),
+		Base:          base.v,
+		Ours:          ours.v,
+		Theirs:        theirs.v,
+		BaseMissing:   !base.has,
+		OursRemoved:   !ours.has,
+		TheirsRemoved: !theirs.has,
+	}
+	m.conflicts = append(m.conflicts, &c)
+
+	return m.resolve(&c)
+}
+
+func (m *merger) mergeObjects(path Path, base, ours, theirs map[string]any) map[string]any {
+	keys := make([]string, 0, len(base)+len(ours)+len(theirs))
+	seen := map[string]bool{}
+	for _, obj := range []map[string]any{base, ours, theirs} {
+		for k := range obj {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	merged := map[string]any{}
+	for _, k := range keys {
+		var b, o, t mergeValue
+		b.v, b.has = base[k]
+		o.v, o.has = ours[k]
+		t.v, t.has = theirs[k]
+		if v, keep := m.merge(append(path, k), b, o, t); keep {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// mergeArrays merges arrays by aligning ours and theirs with the base. The
+// base elements that are unchanged in both are anchors and the chunks
+// between the anchors are merged. False is returned if the changes
+// overlap.
+func (m *merger) mergeArrays(path Path, base, ours, theirs []any) ([]any, bool) {
+	om := lcsMatch(base, ours)
+	tm := lcsMatch(base, theirs)
+	var merged []any
+	var conflicts []*Conflict
+	bStart, oStart, tStart := 0, 0, 0
+	for i := 0; i <= len(base); i++ {
+		if i < len(base) && (om[i] < 0 || tm[i] < 0) {
+			continue
+		}
+		oEnd, tEnd := len(ours), len(theirs)
+		if i < len(base) {
+			oEnd, tEnd = om[i], tm[i]
+		}
+		b := base[bStart:i]
+		o := ours[oStart:oEnd]
+		t := theirs[tStart:tEnd]
+		switch {
+		case chunkEqual(o, t), chunkEqual(b, t):
+			merged = append(merged, o...)
+		case chunkEqual(b, o):
+			merged = append(merged, t...)
+		case len(b) == len(o) && len(b) == len(t):
+			// Modified in place so merge the elements.
+			save := m.conflicts
+			m.conflicts = nil
+			for k := range b {
+				v, keep := m.merge(append(path, len(merged)),
+					mergeValue{v: b[k], has: true},
+					mergeValue{v: o[k], has: true},
+					mergeValue{v: t[k], has: true})
+				if keep {
+					merged = append(merged, v)
+				}
+			}
+			conflicts = append(conflicts, m.conflicts...)
+			m.conflicts = save
+		default:
+			return nil, false
+		}
+		if i < len(base) {
+			merged = append(merged, ours[oEnd])
+		}
+		bStart, oStart, tStart = i+1, oEnd+1, tEnd+1
+	}
+	m.conflicts = append(m.conflicts, conflicts...)
+	if merged == nil {
+		merged = []any{}
+	}
+	return merged, true
+}
+
+func chunkEqual(c0, c1 []any) bool {
+	if len(c0) != len(c1) {
+		return false
+	}
+	for i, v := range c0 {
+		if !diffEqual(v, c1[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package alt_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/alt"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func merge3(t *testing.T, base, ours, theirs string, resolver ...alt.MergeResolver) (string, []*alt.Conflict) {
+	t.Helper()
+	merged, conflicts := alt.Merge3(
+		sen.MustParse([]byte(base)),
+		sen.MustParse([]byte(ours)),
+		sen.MustParse([]byte(theirs)),
+		resolver...,
+	)
+	return sen.String(merged, &ojg.Options{Sort: true}), conflicts
+}
+
+func TestMerge3Object(t *testing.T) {
+	merged, conflicts := merge3(t,
+		`{a:1 b:2 c:3 d:{x:1 y:2} e:5}`,
+		`{a:1 b:20 c:3 d:{x:10 y:2} f:6}`,
+		`{a:1 b:2 d:{x:1 y:20} e:5 g:7}`,
+	)
+	tt.Equal(t, `{a:1 b:20 d:{x:10 y:20} f:6 g:7}`, merged)
+	tt.Equal(t, 0, len(conflicts))
+
+	// Same change on both sides is not a conflict.
+	merged, conflicts = merge3(t, `{a:1}`, `{a:2 b:3}`, `{a:2 b:3}`)
+	tt.Equal(t, `{a:2 b:3}`, merged)
+	tt.Equal(t, 0, len(conflicts))
+
+	// Added on both sides is merged.
+	merged, conflicts = merge3(t, `{}`, `{a:{x:1}}`, `{a:{y:2}}`)
+	tt.Equal(t, `{a:{x:1 y:2}}`, merged)
+	tt.Equal(t, 0, len(conflicts))
+}
+
+func TestMerge3Conflict(t *testing.T) {
+	base := `{a:1 b:{c:2} d:3}`
+	ours := `{a:10 b:{c:20} d:3}`
+	theirs := `{a:11 b:{c:21}}`
+	merged, conflicts := merge3(t, base, ours, theirs)
+	tt.Equal(t, `{a:10 b:{c:20}}`, merged)
+	tt.Equal(t, 2, len(conflicts))
+	tt.Equal(t, "$.a", conflicts[0].JSONPath())
+	tt.Equal(t, int64(1), conflicts[0].Base)
+	tt.Equal(t, int64(10), conflicts[0].Ours)
+	tt.Equal(t, int64(11), conflicts[0].Theirs)
+	tt.Equal(t, "$.b.c", conflicts[1].JSONPath())
+
+	merged, _ = merge3(t, base, ours, theirs, alt.MergeTheirs)
+	tt.Equal(t, `{a:11 b:{c:21}}`, merged)
+
+	// A removal on one side and a change on the other.
+	merged, conflicts = merge3(t, `{a:1}`, `{a:2}`, `{}`, alt.MergeTheirs)
+	tt.Equal(t, `{}`, merged)
+	tt.Equal(t, 1, len(conflicts))
+	tt.Equal(t, true, conflicts[0].TheirsRemoved)
+	tt.Equal(t, false, conflicts[0].OursRemoved)
+
+	// A custom resolver.
+	merged, _ = merge3(t, `{a:1 b:x}`, `{a:5 b:y}`, `{a:3 b:z}`, func(c *alt.Conflict) (any, bool) {
+		if o, ok := c.Ours.(int64); ok {
+			return o + c.Theirs.(int64), true
+		}
+		return nil, false
+	})
+	tt.Equal(t, `{a:8}`, merged)
+
+	// Type changes are conflicts too.
+	_, conflicts = merge3(t, `{a:[1]}`, `{a:{}}`, `{a:2}`)
+	tt.Equal(t, 1, len(conflicts))
+	tt.Equal(t, true, conflicts[0].Path[0] == "a")
+}
+
+func TestMerge3Array(t *testing.T) {
+	merged, conflicts := merge3(t, `[a b c d]`, `[x a b c d]`, `[a b c d y]`)
+	tt.Equal(t, `[x a b c d y]`, merged)
+	tt.Equal(t, 0, len(conflicts))
+
+	merged, conflicts = merge3(t, `[a b c d]`, `[a c d]`, `[a b c e d]`)
+	tt.Equal(t, `[a c e d]`, merged)
+	tt.Equal(t, 0, len(conflicts))
+
+	// Elements modified in place are merged.
+	merged, conflicts = merge3(t,
+		`[{id:1 v:a w:a} {id:2 v:b}]`,
+		`[{id:1 v:A w:a} {id:2 v:b}]`,
+		`[{id:1 v:a w:W} {id:2 v:b} {id:3}]`,
+	)
+	tt.Equal(t, `[{id:1 v:A w:W}{id:2 v:b}{id:3}]`, merged)
+	tt.Equal(t, 0, len(conflicts))
+
+	merged, conflicts = merge3(t, `[{v:a}]`, `[{v:b}]`, `[{v:c}]`)
+	tt.Equal(t, `[{v:b}]`, merged)
+	tt.Equal(t, 1, len(conflicts))
+	tt.Equal(t, "$[0].v", conflicts[0].JSONPath())
+
+	// Overlapping changes conflict on the whole array.
+	merged, conflicts = merge3(t, `{list:[a b c]}`, `{list:[a x c]}`, `{list:[a c]}`, alt.MergeTheirs)
+	tt.Equal(t, `{list:[a c]}`, merged)
+	tt.Equal(t, 1, len(conflicts))
+	tt.Equal(t, "$.list", conflicts[0].JSONPath())
+}
+
+func TestMerge3Struct(t *testing.T) {
+	type Config struct {
+		Name string
+		Port int
+		Tags []string
+	}
+	merged, conflicts := alt.Merge3(
+		&Config{Name: "a", Port: 80, Tags: []string{"x"}},
+		&Config{Name: "a", Port: 8080, Tags: []string{"x"}},
+		&Config{Name: "b", Port: 80, Tags: []string{"x", "y"}},
+	)
+	tt.Equal(t, 0, len(conflicts))
+	tt.Equal(t, `{name:b port:8080 tags:[x y]}`, sen.String(merged, &ojg.Options{Sort: true}))
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package alt
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/gen"
+)
+
+// typeKey is the create key used internally by the Recomposer Decompose()
+// to identify the type of decomposed structs. It is not a valid JSON or SEN
+// key that would be found in normal data.
+const typeKey = "\x00type"
+
+// polymorph describes the concrete types that can be recomposed into an
+// interface type.
+type polymorph struct {
+	key   string
+	types map[string]reflect.Type
+}
+
+// discriminant is the reverse of a polymorph entry and is used to identify
+// the concrete type when decomposing.
+type discriminant struct {
+	key   string
+	value string
+}
+
+// RegisterInterface registers the concrete types that can be used to
+// recompose values of an interface type. The iface argument must be a
+// pointer to the interface type such as (*Animal)(nil). The key is the name
+// of the member that holds the discriminator value and the types map
+// associates each discriminator value with a sample of the concrete type
+// such as &Dog{} or Dog{}. A pointer sample results in pointers being
+// assigned to the interface. Interface fields, slices, and maps are then
+// recomposed according to the discriminator value of each object.
+func (r *Recomposer) RegisterInterface(iface any, key string, types map[string]any) error {
+	it := reflect.TypeOf(iface)
+	if it == nil || it.Kind() != reflect.Ptr || it.Elem().Kind() != reflect.Interface {
+		return fmt.Errorf("a %T is not a pointer to an interface", iface)
+	}
+	it = it.Elem()
+	if len(key) == 0 {
+		return fmt.Errorf("a discriminator key is required for %s", it)
+	}
+	return r.update(func(cc *composerCache) error {
+		pm := polymorph{key: key, types: map[string]reflect.Type{}}
+		for dv, sample := range types {
+			rt := reflect.TypeOf(sample)
+			if rt == nil || !rt.Implements(it) {
+				return fmt.Errorf("a %T does not implement %s", sample, it)
+			}
+			if _, err := cc.registerComposer(rt, nil); err != nil {
+				return err
+			}
+			pm.types[dv] = rt
+		}
+		cc.interfaces[it] = &pm
+		for dv, rt := range pm.types {
+			if rt.Kind() == reflect.Ptr {
+				rt = rt.Elem()
+			}
+			cc.discriminants[rt.PkgPath()+"/"+rt.Name()] = &discriminant{key: key, value: dv}
+		}
+		return nil
+	})
+}
+
+// recompInterface recomposes a value into a registered interface type. If
+// the interface type has not been registered then false is returned.
+func (r *Recomposer) recompInterface(v any, rv reflect.Value, st *strictState) bool {
+	pm := r.load().interfaces[rv.Type()]
+	if pm == nil {
+		return false
+	}
+	if v == nil {
+		return true
+	}
+	if obj, ok := v.(gen.Object); ok {
+		v = obj.Simplify()
+	}
+	vm, ok := v.(map[string]any)
+	if !ok {
+		panic(fmt.Errorf("can only recompose a %s from a map[string]any, not a %T", rv.Type(), v))
+	}
+	dv, _ := vm[pm.key].(string)
+	rt := pm.types[dv]
+	if rt == nil {
+		panic(fmt.Errorf("%q is not a registered %s value for %s", dv, pm.key, rv.Type()))
+	}
+	if st != nil && st.r.DisallowUnknown {
+		// The discriminator is not an unknown member even if there is no
+		// matching field.
+		et := rt
+		if et.Kind() == reflect.Ptr {
+			et = et.Elem()
+		}
+		if c := r.composerFor(et); !c.known[pm.key] {
+			dup := make(map[string]any, len(vm))
+			for k, m := range vm {
+				if k != pm.key {
+					dup[k] = m
+				}
+			}
+			vm = dup
+		}
+	}
+	if rt.Kind() == reflect.Ptr {
+		ev := reflect.New(rt.Elem())
+		r.recomp(vm, ev, st)
+		rv.Set(ev)
+	} else {
+		ev := reflect.New(rt)
+		r.recomp(vm, ev, st)
+		rv.Set(ev.Elem())
+	}
+	return true
+}
+
+// Decompose creates simple data from the value provided in the same way as
+// the package Decompose() function except that objects created from a
+// struct with a type registered with RegisterInterface() include the
+// discriminator member. The result can then be recomposed into interface
+// typed fields by the same Recomposer.
+func (r *Recomposer) Decompose(v any, options ...*ojg.Options) any {
+	opt := DefaultOptions
+	if 0 < len(options) {
+		opt = *options[0]
+	}
+	createKey := opt.CreateKey
+	fullPath := opt.FullTypePath
+	opt.CreateKey = typeKey
+	opt.FullTypePath = true
+
+	return r.load().addDiscriminants(Decompose(v, &opt), createKey, fullPath)
+}
+
+func (cc *composerCache) addDiscriminants(v any, createKey string, fullPath bool) any {
+	switch tv := v.(type) {
+	case []any:
+		for i, m := range tv {
+			tv[i] = cc.addDiscriminants(m, createKey, fullPath)
+		}
+	case map[string]any:
+		for k, m := range tv {
+			tv[k] = cc.addDiscriminants(m, createKey, fullPath)
+		}
+		if tn, ok := tv[typeKey].(string); ok {
+			delete(tv, typeKey)
+			if d := cc.discriminants[tn]; d != nil {
+				tv[d.key] = d.value
+			}
+			if 0 < len(createKey) {
+				if !fullPath {
+					for i := len(tn) - 1; 0 <= i; i-- {
+						if tn[i] == '/' {
+							tn = tn[i+1:]
+							break
+						}
+					}
+				}
+				tv[createKey] = tn
+			}
+		}
+	}
+	return v
+}
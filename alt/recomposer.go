@@ -3,24 +3,22 @@
 package alt
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"math"
 	"reflect"
 	"strconv"
-	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/khaf/ojg"
 	"github.com/khaf/ojg/gen"
 )
 
-// DefaultRecomposer provides a shared Recomposer. Note that this should not
-// be shared across go routines unless all types that will be used are
-// registered first. That can be done explicitly or with a warm up run.
-var DefaultRecomposer = Recomposer{
-	composers: map[string]*composer{},
-}
+// DefaultRecomposer provides a shared Recomposer. It is safe for concurrent
+// use.
+var DefaultRecomposer = Recomposer{}
 
 // RecomposeFunc should build an object from data in a map returning the
 // recomposed object or an error.
@@ -30,16 +28,39 @@ type RecomposeFunc func(map[string]any) (any, error)
 // returning the recomposed object or an error.
 type RecomposeAnyFunc func(any) (any, error)
 
-// Recomposer is used to recompose simple data into structs.
+// Recomposer is used to recompose simple data into structs. A Recomposer is
+// safe for concurrent use. Registered composers are kept in an immutable
+// cache that is replaced, using copy-on-write, whenever a composer is
+// registered so that recomposing never blocks.
 type Recomposer struct {
 
 	// CreateKey identifies the creation key in decomposed objects.
 	CreateKey string
 
-	composers map[string]*composer
+	// DisallowUnknown if true causes an error to be reported for each object
+	// member that does not match a field of the struct being recomposed.
+	DisallowUnknown bool
+
+	// Required if true causes an error to be reported for each required
+	// field that has no matching object member. A field is required if the
+	// json tag includes the required option such as `json:"id,required"` or
+	// if RequiredTag is not empty and the field has a tag with that name
+	// that includes "required" or "true" such as `validate:"required"`.
+	Required bool
+
+	// RequiredTag is the name of an optional tag that marks a field as
+	// required.
+	RequiredTag string
+
+	// cache holds a *composerCache.
+	cache atomic.Value
 }
 
-var jsonUnmarshalerType reflect.Type
+var (
+	jsonUnmarshalerType reflect.Type
+	timeType            = reflect.TypeOf(time.Time{})
+	jsonNumberType      = reflect.TypeOf(json.Number(""))
+)
 
 func init() {
 	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
@@ -49,107 +70,83 @@ func init() {
 // RegisterComposer regsiters a composer function for a value type. A nil
 // function will still register the default composer which uses reflection.
 func (r *Recomposer) RegisterComposer(val any, fun RecomposeFunc) error {
-	_, err := r.registerComposer(reflect.TypeOf(val), fun)
-
-	return err
+	return r.update(func(cc *composerCache) (err error) {
+		_, err = cc.registerComposer(reflect.TypeOf(val), fun)
+		return
+	})
 }
 
 // RegisterAnyComposer regsiters a composer function for a value type. A nil
 // function will still register the default composer which uses reflection.
 func (r *Recomposer) RegisterAnyComposer(val any, fun RecomposeAnyFunc) error {
-	_, err := r.registerAnyComposer(reflect.TypeOf(val), fun)
-
-	return err
+	return r.update(func(cc *composerCache) (err error) {
+		_, err = cc.registerAnyComposer(reflect.TypeOf(val), fun)
+		return
+	})
 }
 
 // RegisterUnmarshalerComposer regsiters a composer function for a named
 // value. This is only used to register cross package json.Unmarshaler
 // composer which returns []byte.
 func (r *Recomposer) RegisterUnmarshalerComposer(fun RecomposeAnyFunc) {
-	name := "json.Unmarshaler"
-	r.composers[name] = &composer{
-		any:   fun,
-		short: name,
-		full:  name,
-	}
+	_ = r.update(func(cc *composerCache) error {
+		name := "json.Unmarshaler"
+		cc.composers[name] = &composer{
+			any:   fun,
+			short: name,
+			full:  name,
+		}
+		return nil
+	})
 }
 
-func (r *Recomposer) registerComposer(rt reflect.Type, fun RecomposeFunc) (*composer, error) {
-	if rt.Kind() == reflect.Ptr {
-		rt = rt.Elem()
+// load returns the current composer cache. The returned cache must not be
+// modified.
+func (r *Recomposer) load() *composerCache {
+	if cc, _ := r.cache.Load().(*composerCache); cc != nil {
+		return cc
 	}
-	full := rt.PkgPath() + "/" + rt.Name()
-	// TBD could loosen this up and allow any type as long as a function is provided.
-	if rt.Kind() != reflect.Struct {
-		return nil, fmt.Errorf("only structs can be recomposed. %s is not a struct type", rt)
-	}
-	c := r.composers[full]
-	if c == nil {
-		c = &composer{
-			fun:   fun,
-			short: rt.Name(),
-			full:  full,
-			rtype: rt,
-		}
-		c.indexes = indexType(c.rtype)
-		r.composers[c.short] = c
-		r.composers[c.full] = c
-	} else {
-		if fun != nil {
-			c.fun = fun
-		}
-		// If already registered then there is no reason to walk the fields again.
-		return c, nil
-	}
-	for i := rt.NumField() - 1; 0 <= i; i-- {
-		f := rt.Field(i)
-		// Private fields should be skipped.
-		if len(f.Name) == 0 || ([]byte(f.Name)[0]&0x20) != 0 {
-			continue
-		}
-		ft := f.Type
-		switch ft.Kind() {
-		case reflect.Array, reflect.Slice, reflect.Map, reflect.Ptr:
-			ft = ft.Elem()
+	return &emptyComposerCache
+}
+
+// update applies the function to a copy of the current composer cache and
+// then replaces the current cache with the copy. If another update replaced
+// the cache in the meantime the function is applied again to a copy of the
+// newer cache.
+func (r *Recomposer) update(fun func(cc *composerCache) error) error {
+	for {
+		old := r.cache.Load()
+		cc := r.load().dup()
+		if err := fun(cc); err != nil {
+			return err
 		}
-		if _, has := r.composers[ft.Name()]; has {
-			continue
+		if r.cache.CompareAndSwap(old, cc) {
+			return nil
 		}
-		_, _ = r.registerComposer(ft, nil)
 	}
-	return c, nil
 }
 
-func (r *Recomposer) registerAnyComposer(rt reflect.Type, fun RecomposeAnyFunc) (*composer, error) {
-	if rt.Kind() == reflect.Ptr {
-		rt = rt.Elem()
-	}
-	full := rt.PkgPath() + "/" + rt.Name()
-	if rt.Kind() != reflect.Struct {
-		return nil, fmt.Errorf("only structs can be recomposed. %s is not a struct type", rt)
-	}
-	c := r.composers[full]
-	if c == nil {
-		c = &composer{
-			any:   fun,
-			short: rt.Name(),
-			full:  full,
-			rtype: rt,
-		}
-		c.indexes = indexType(c.rtype)
-		r.composers[c.short] = c
-		r.composers[c.full] = c
-	} else {
-		c.any = fun
+// composerFor returns the composer for a struct type, registering a default
+// composer if one is not already registered.
+func (r *Recomposer) composerFor(rt reflect.Type) (c *composer) {
+	if c = r.load().types[rt]; c == nil {
+		_ = r.update(func(cc *composerCache) (err error) {
+			c, err = cc.registerComposer(rt, nil)
+			return
+		})
 	}
-	return c, nil
+	return
 }
 
 // Recompose simple data into more complex go types.
 func (r *Recomposer) Recompose(v any, tv ...any) (out any, err error) {
 	defer func() {
 		if rec := recover(); rec != nil {
-			err = ojg.NewError(rec)
+			if re, ok := rec.(*RecomposeError); ok {
+				err = re
+			} else {
+				err = ojg.NewError(rec)
+			}
 			out = nil
 		}
 	}()
@@ -157,11 +154,24 @@ func (r *Recomposer) Recompose(v any, tv ...any) (out any, err error) {
 	return
 }
 
-// MustRecompose simple data into more complex go types.
+// MustRecompose simple data into more complex go types. If either
+// DisallowUnknown or Required are true then all the errors found are
+// collected and a *RecomposeError is raised. Values of the wrong type and
+// numbers that would be truncated or overflow a field, such as 1.5 or 300
+// for a uint8, are also reported.
 func (r *Recomposer) MustRecompose(v any, tv ...any) (out any) {
+	var st *strictState
+	if r.DisallowUnknown || r.Required {
+		st = &strictState{r: r}
+		defer func() {
+			if 0 < len(st.errs) {
+				panic(&RecomposeError{Errors: st.errs})
+			}
+		}()
+	}
 	if 0 < len(tv) {
 		if um, ok := tv[0].(json.Unmarshaler); ok {
-			if comp := r.composers["json.Unmarshaler"]; comp != nil {
+			if comp := r.load().composers["json.Unmarshaler"]; comp != nil {
 				b, _ := comp.any(v) // Special case. Must return []byte.
 				if err := um.UnmarshalJSON(b.([]byte)); err != nil {
 					panic(err)
@@ -174,12 +184,12 @@ func (r *Recomposer) MustRecompose(v any, tv ...any) (out any) {
 		switch rv.Kind() {
 		case reflect.Array, reflect.Slice:
 			rv = reflect.New(rv.Type())
-			r.recomp(v, rv)
+			r.recomp(v, rv, st)
 			out = rv.Elem().Interface()
 		case reflect.Map:
-			r.recomp(v, rv)
+			r.recomp(v, rv, st)
 		case reflect.Ptr:
-			r.recomp(v, rv)
+			r.recomp(v, rv, st)
 			switch rv.Elem().Kind() {
 			case reflect.Slice, reflect.Array, reflect.Map, reflect.Interface:
 				out = rv.Elem().Interface()
@@ -188,12 +198,12 @@ func (r *Recomposer) MustRecompose(v any, tv ...any) (out any) {
 			panic(fmt.Errorf("only a slice, map, or pointer is allowed as an optional argument"))
 		}
 	} else {
-		out = r.recompAny(v)
+		out = r.recompAny(v, st)
 	}
 	return
 }
 
-func (r *Recomposer) recompAny(v any) any {
+func (r *Recomposer) recompAny(v any, st *strictState) any {
 	switch tv := v.(type) {
 	case nil, bool, int64, float64, string, time.Time:
 	case int:
@@ -223,13 +233,13 @@ func (r *Recomposer) recompAny(v any) any {
 	case []any:
 		a := make([]any, len(tv))
 		for i, m := range tv {
-			a[i] = r.recompAny(m)
+			a[i] = r.recompAny(m, st)
 		}
 		v = a
 	case map[string]any:
 		if cv := tv[r.CreateKey]; cv != nil {
 			tn, _ := cv.(string)
-			if c := r.composers[tn]; c != nil {
+			if c := r.load().composers[tn]; c != nil {
 				if c.fun != nil {
 					val, err := c.fun(tv)
 					if err != nil {
@@ -238,13 +248,13 @@ func (r *Recomposer) recompAny(v any) any {
 					return val
 				}
 				rv := reflect.New(c.rtype)
-				r.recomp(v, rv)
+				r.recomp(v, rv, st)
 				return rv.Interface()
 			}
 		}
 		o := map[string]any{}
 		for k, m := range tv {
-			o[k] = r.recompAny(m)
+			o[k] = r.recompAny(m, st)
 		}
 		v = o
 
@@ -263,14 +273,14 @@ func (r *Recomposer) recompAny(v any) any {
 	case gen.Array:
 		a := make([]any, len(tv))
 		for i, m := range tv {
-			a[i] = r.recompAny(m)
+			a[i] = r.recompAny(m, st)
 		}
 		v = a
 	case gen.Object:
 		if cv := tv[r.CreateKey]; cv != nil {
 			gn, _ := cv.(gen.String)
 			tn := string(gn)
-			if c := r.composers[tn]; c != nil {
+			if c := r.load().composers[tn]; c != nil {
 				simple, _ := tv.Simplify().(map[string]any)
 				if c.fun != nil {
 					val, err := c.fun(simple)
@@ -280,13 +290,13 @@ func (r *Recomposer) recompAny(v any) any {
 					return val
 				}
 				rv := reflect.New(c.rtype)
-				r.recomp(simple, rv)
+				r.recomp(simple, rv, st)
 				return rv.Interface()
 			}
 		}
 		o := map[string]any{}
 		for k, m := range tv {
-			o[k] = r.recompAny(m)
+			o[k] = r.recompAny(m, st)
 		}
 		v = o
 
@@ -296,7 +306,10 @@ func (r *Recomposer) recompAny(v any) any {
 	return v
 }
 
-func (r *Recomposer) recomp(v any, rv reflect.Value) {
+func (r *Recomposer) recomp(v any, rv reflect.Value, st *strictState) {
+	if st != nil {
+		defer st.recover(len(st.path))
+	}
 	as, _ := rv.Interface().(AttrSetter)
 	if rv.Kind() == reflect.Ptr {
 		if v == nil {
@@ -304,6 +317,9 @@ func (r *Recomposer) recomp(v any, rv reflect.Value) {
 		}
 		rv = rv.Elem()
 	}
+	if decodeValue(v, rv) || decodeBytes(v, rv) {
+		return
+	}
 	switch rv.Kind() {
 	case reflect.Array, reflect.Slice:
 		va, ok := (v).([]any)
@@ -324,12 +340,16 @@ func (r *Recomposer) recomp(v any, rv reflect.Value) {
 			et = et.Elem()
 			for i := 0; i < size; i++ {
 				ev := reflect.New(et)
-				r.recomp(va[i], ev)
+				st.push(i)
+				r.recomp(va[i], ev, st)
+				st.pop()
 				av.Index(i).Set(ev)
 			}
 		} else {
 			for i := 0; i < size; i++ {
-				r.setValue(va[i], av.Index(i), nil)
+				st.push(i)
+				r.setValue(va[i], av.Index(i), false, st)
+				st.pop()
 			}
 		}
 		rv.Set(av)
@@ -355,28 +375,40 @@ func (r *Recomposer) recomp(v any, rv reflect.Value) {
 			rv.Set(reflect.MakeMapWithSize(rv.Type(), len(vm)))
 		}
 		switch {
+		case et.Kind() == reflect.Interface && r.load().interfaces[et] != nil:
+			for k, m := range vm {
+				ev := reflect.New(et).Elem()
+				st.push(k)
+				r.recompInterface(m, ev, st)
+				st.pop()
+				rv.SetMapIndex(reflect.ValueOf(k), ev)
+			}
 		case et.Kind() == reflect.Interface:
 			for k, m := range vm {
-				rv.SetMapIndex(reflect.ValueOf(k), reflect.ValueOf(r.recompAny(m)))
+				rv.SetMapIndex(reflect.ValueOf(k), reflect.ValueOf(r.recompAny(m, st)))
 			}
 		case et.Kind() == reflect.Ptr:
 			et = et.Elem()
 			for k, m := range vm {
 				ev := reflect.New(et)
-				r.recomp(m, ev)
+				st.push(k)
+				r.recomp(m, ev, st)
+				st.pop()
 				rv.SetMapIndex(reflect.ValueOf(k), ev)
 			}
 		default:
 			for k, m := range vm {
 				ev := reflect.New(et)
-				r.recomp(m, ev)
+				st.push(k)
+				r.recomp(m, ev, st)
+				st.pop()
 				rv.SetMapIndex(reflect.ValueOf(k), ev.Elem())
 			}
 		}
 	case reflect.Struct:
 		vm, ok := (v).(map[string]any)
 		if !ok {
-			if c := r.composers[rv.Type().Name()]; c != nil && c.any != nil {
+			if c := r.load().types[rv.Type()]; c != nil && c.any != nil {
 				if val, err := c.any(v); err == nil {
 					if val == nil {
 						break
@@ -392,6 +424,9 @@ func (r *Recomposer) recomp(v any, rv reflect.Value) {
 				break
 			}
 
+			if setTime(v, rv) {
+				break
+			}
 			vv := reflect.ValueOf(v)
 			if vv.Kind() != reflect.Map {
 				panic(fmt.Errorf("can only recompose a %s from a map[string]any, not a %T", rv.Type(), v))
@@ -414,53 +449,68 @@ func (r *Recomposer) recomp(v any, rv reflect.Value) {
 			}
 			return
 		}
-		var im map[string]reflect.StructField
-		if c := r.composers[rv.Type().Name()]; c != nil {
-			if c.fun != nil {
-				if val, err := c.fun(vm); err == nil {
-					vv := reflect.ValueOf(val)
-					if vv.Type().Kind() == reflect.Ptr {
-						vv = vv.Elem()
-					}
-					rv.Set(vv)
-				} else {
-					panic(err)
+		c := r.composerFor(rv.Type())
+		if c.fun != nil {
+			if val, err := c.fun(vm); err == nil {
+				vv := reflect.ValueOf(val)
+				if vv.Type().Kind() == reflect.Ptr {
+					vv = vv.Elem()
 				}
-				break
+				rv.Set(vv)
+			} else {
+				panic(err)
 			}
-			im = c.indexes
-		} else {
-			c, _ = r.registerComposer(rv.Type(), nil)
-			im = c.indexes
-		}
-		for k := range im {
-			sf := im[k]
-			f := rv.FieldByIndex(sf.Index)
-			var m any
-			var has bool
-			if m, has = vm[k]; !has {
-				if m, has = vm[sf.Name]; !has {
-					name := []byte(sf.Name)
-					name[0] |= 0x20
-					if m, has = vm[string(name)]; !has {
-						m, has = vm[strings.ToLower(string(name))]
-					}
+			break
+		}
+		var found []bool
+		if st != nil {
+			found = make([]bool, len(c.fields))
+		}
+		for i, fp := range c.fields {
+			var (
+				k   string
+				m   any
+				has bool
+			)
+			for _, k = range fp.keys {
+				if m, has = vm[k]; has {
+					break
 				}
 			}
 			if has && m != nil {
-				r.setValue(m, f, &sf)
+				st.push(k)
+				r.setValue(m, fieldByIndex(rv, fp.index), fp.asString, st)
+				st.pop()
 			}
+			if found != nil {
+				found[i] = has
+			}
+		}
+		if st != nil {
+			st.checkFields(c, vm, found)
 		}
 	case reflect.Interface:
-		v = r.recompAny(v)
+		if r.recompInterface(v, rv, st) {
+			break
+		}
+		v = r.recompAny(v, st)
 		rv.Set(reflect.ValueOf(v))
 
 	case reflect.Bool:
+		if st != nil {
+			st.checkType(v, rv.Type())
+		}
 		rv.Set(reflect.ValueOf(v))
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
 		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
 		reflect.Float32, reflect.Float64,
 		reflect.String:
+		if setNumber(v, rv) {
+			break
+		}
+		if st != nil {
+			st.checkType(v, rv.Type())
+		}
 		rv.Set(reflect.ValueOf(v).Convert(rv.Type()))
 
 	default:
@@ -468,52 +518,111 @@ func (r *Recomposer) recomp(v any, rv reflect.Value) {
 	}
 }
 
-func (r *Recomposer) setValue(v any, rv reflect.Value, sf *reflect.StructField) {
+func (r *Recomposer) setValue(v any, rv reflect.Value, asString bool, st *strictState) {
+	if st != nil {
+		defer st.recover(len(st.path))
+	}
+	if decodeValue(v, rv) {
+		return
+	}
 	switch rv.Kind() {
 	case reflect.Bool:
-		if s, ok := v.(string); ok && sf != nil && strings.Contains(sf.Tag.Get("json"), ",string") {
+		if s, ok := v.(string); ok && asString {
 			if b, err := strconv.ParseBool(s); err == nil {
 				rv.Set(reflect.ValueOf(b))
 			} else {
 				panic(err)
 			}
 		} else {
+			if st != nil {
+				st.checkType(v, rv.Type())
+			}
 			rv.Set(reflect.ValueOf(v))
 		}
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		if s, ok := v.(string); ok && sf != nil && strings.Contains(sf.Tag.Get("json"), ",string") {
-			if i, err := strconv.Atoi(s); err == nil {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if s, ok := v.(string); ok && asString {
+			if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+				if st != nil {
+					st.checkType(i, rv.Type())
+				}
 				rv.Set(reflect.ValueOf(i).Convert(rv.Type()))
 			} else {
 				panic(err)
 			}
 		} else {
+			if st != nil {
+				st.checkType(v, rv.Type())
+			}
+			rv.Set(reflect.ValueOf(v).Convert(rv.Type()))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if s, ok := v.(string); ok && asString {
+			if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+				if st != nil {
+					st.checkType(u, rv.Type())
+				}
+				rv.Set(reflect.ValueOf(u).Convert(rv.Type()))
+			} else {
+				panic(err)
+			}
+		} else {
+			if st != nil {
+				st.checkType(v, rv.Type())
+			}
 			rv.Set(reflect.ValueOf(v).Convert(rv.Type()))
 		}
 	case reflect.Float32, reflect.Float64:
-		if s, ok := v.(string); ok && sf != nil && strings.Contains(sf.Tag.Get("json"), ",string") {
+		if s, ok := v.(string); ok && asString {
 			if f, err := strconv.ParseFloat(s, 64); err == nil {
+				if st != nil {
+					st.checkType(f, rv.Type())
+				}
 				rv.Set(reflect.ValueOf(f).Convert(rv.Type()))
 			} else {
 				panic(err)
 			}
 		} else {
+			if st != nil {
+				st.checkType(v, rv.Type())
+			}
 			rv.Set(reflect.ValueOf(v).Convert(rv.Type()))
 		}
 	case reflect.String:
+		if setNumber(v, rv) {
+			return
+		}
+		if s, ok := v.(string); ok && asString {
+			var us string
+			if err := json.Unmarshal([]byte(s), &us); err != nil {
+				panic(fmt.Errorf("invalid quoted string %q", s))
+			}
+			v = us
+		}
+		if st != nil {
+			st.checkType(v, rv.Type())
+		}
 		rv.Set(reflect.ValueOf(v).Convert(rv.Type()))
 	case reflect.Interface:
-		v = r.recompAny(v)
+		if r.recompInterface(v, rv, st) {
+			return
+		}
+		v = r.recompAny(v, st)
 		rv.Set(reflect.ValueOf(v))
 	case reflect.Ptr:
 		ev := reflect.New(rv.Type().Elem())
-		r.recomp(v, ev)
+		if asString {
+			r.setValue(v, ev.Elem(), asString, st)
+		} else {
+			r.recomp(v, ev, st)
+		}
 		rv.Set(ev)
 	default:
+		if setTime(v, rv) {
+			return
+		}
 		if reflect.PtrTo(rv.Type()).Implements(jsonUnmarshalerType) {
 			ev := rv.Addr().Interface().(json.Unmarshaler)
-			if comp := r.composers["json.Unmarshaler"]; comp != nil {
+			if comp := r.load().composers["json.Unmarshaler"]; comp != nil {
 				b, _ := comp.any(v) // Special case. Must return []byte.
 				if err := ev.UnmarshalJSON(b.([]byte)); err != nil {
 					panic(err)
@@ -521,6 +630,135 @@ func (r *Recomposer) setValue(v any, rv reflect.Value, sf *reflect.StructField)
 				return
 			}
 		}
-		r.recomp(v, rv)
+		r.recomp(v, rv, st)
+	}
+}
+
+// setTime sets a time.Time value from a time.Time or from a string in one
+// of the formats recognized by the TimeRFC3339Converter, which includes
+// dates without a time. True is returned if the value was set.
+func setTime(v any, rv reflect.Value) bool {
+	if rv.Type() != timeType {
+		return false
+	}
+	switch tv := v.(type) {
+	case time.Time:
+		rv.Set(reflect.ValueOf(tv))
+		return true
+	case string:
+		if t, ok := TimeRFC3339Converter.Convert(tv).(time.Time); ok {
+			rv.Set(reflect.ValueOf(t))
+			return true
+		}
+	}
+	return false
+}
+
+// setNumber sets a json.Number value from a number or from a string that is
+// a valid number as the json package does, with or without the json string
+// option. True is returned if the value was a json.Number.
+func setNumber(v any, rv reflect.Value) bool {
+	if rv.Type() != jsonNumberType {
+		return false
+	}
+	var s string
+	switch tv := v.(type) {
+	case json.Number:
+		s = string(tv)
+	case string:
+		if !isNumber(tv) {
+			panic(fmt.Errorf("invalid number %q for a json.Number", tv))
+		}
+		s = tv
+	case float32:
+		s = strconv.FormatFloat(float64(tv), 'g', -1, 32)
+	case float64:
+		s = strconv.FormatFloat(tv, 'g', -1, 64)
+	default:
+		vv := reflect.ValueOf(v)
+		switch vv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			s = strconv.FormatInt(vv.Int(), 10)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			s = strconv.FormatUint(vv.Uint(), 10)
+		default:
+			panic(fmt.Errorf("expected a json.Number, not a %T", v))
+		}
+	}
+	rv.SetString(s)
+	return true
+}
+
+// isNumber returns true if the string is a valid JSON number.
+func isNumber(s string) bool {
+	if len(s) == 0 || !(s[0] == '-' || ('0' <= s[0] && s[0] <= '9')) {
+		return false
+	}
+	if c := s[len(s)-1]; c < '0' || '9' < c {
+		return false
+	}
+	return json.Valid([]byte(s))
+}
+
+// fieldByIndex returns the field at the index, allocating any nil embedded
+// struct pointers along the way.
+func fieldByIndex(rv reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if 0 < i && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				if !rv.CanSet() {
+					panic(fmt.Errorf("can not set embedded pointer to unexported struct %s", rv.Type().Elem()))
+				}
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv
+}
+
+// decodeBytes sets a byte slice from a base64 string as the json package
+// does. True is returned if the value was set.
+func decodeBytes(v any, rv reflect.Value) bool {
+	s, ok := v.(string)
+	if !ok || rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() != reflect.Uint8 {
+		return false
+	}
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	rv.SetBytes(b)
+
+	return true
+}
+
+// decodeValue sets the value using the codec registered for the type of the
+// value if there is one. True is returned if the value was set.
+func decodeValue(v any, rv reflect.Value) bool {
+	c := ojg.LookupCodec(rv.Type())
+	if c == nil || c.Decode == nil {
+		return false
+	}
+	dv, err := c.Decode(v)
+	if err != nil {
+		panic(err)
+	}
+	if dv == nil {
+		rv.Set(reflect.Zero(rv.Type()))
+		return true
+	}
+	vv := reflect.ValueOf(dv)
+	switch {
+	case vv.Type() == rv.Type():
+	case vv.Kind() == reflect.Ptr && vv.Type().Elem() == rv.Type():
+		vv = vv.Elem()
+	case vv.Type().ConvertibleTo(rv.Type()):
+		vv = vv.Convert(rv.Type())
+	default:
+		panic(fmt.Errorf("codec for %s decoded a %T", rv.Type(), dv))
 	}
+	rv.Set(vv)
+	return true
 }
@@ -4,6 +4,7 @@ package alt_test
 
 import (
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/khaf/ojg/gen"
 	"github.com/khaf/ojg/jp"
 	"github.com/khaf/ojg/oj"
+	"github.com/khaf/ojg/sen"
 	"github.com/khaf/ojg/tt"
 )
 
@@ -778,3 +780,194 @@ func TestRecomposeUnmarshallerList(t *testing.T) {
 	tt.Equal(t, 1, len(list[0]))
 	tt.Equal(t, 1, list[0]["k1"])
 }
+
+type Pet interface {
+	Sound() string
+}
+
+type Hound struct {
+	Name  string
+	Breed string
+}
+
+func (d *Hound) Sound() string {
+	return "woof"
+}
+
+type Tabby struct {
+	Name  string
+	Lives int
+}
+
+func (c Tabby) Sound() string {
+	return "meow"
+}
+
+type Kennel struct {
+	Star    Pet
+	Pets    []Pet
+	Keepers map[string]Pet
+}
+
+func polyRecomposer(t *testing.T) *alt.Recomposer {
+	r, err := alt.NewRecomposer("", nil)
+	tt.Nil(t, err)
+	err = r.RegisterInterface((*Pet)(nil), "kind", map[string]any{"dog": &Hound{}, "cat": Tabby{}})
+	tt.Nil(t, err)
+	return r
+}
+
+func TestRecomposePolymorph(t *testing.T) {
+	r := polyRecomposer(t)
+	src := map[string]any{
+		"star": map[string]any{"kind": "dog", "name": "Rex", "breed": "collie"},
+		"pets": []any{
+			map[string]any{"kind": "cat", "name": "Tom", "lives": 9},
+			map[string]any{"kind": "dog", "name": "Spot"},
+			nil,
+		},
+		"keepers": map[string]any{"bob": map[string]any{"kind": "cat", "name": "Felix", "lives": 3}},
+	}
+	v, err := r.Recompose(src, &Kennel{})
+	tt.Nil(t, err)
+	kennel, _ := v.(*Kennel)
+	tt.NotNil(t, kennel)
+	tt.Equal(t, &Hound{Name: "Rex", Breed: "collie"}, kennel.Star)
+	tt.Equal(t, 3, len(kennel.Pets))
+	tt.Equal(t, Tabby{Name: "Tom", Lives: 9}, kennel.Pets[0])
+	tt.Equal(t, &Hound{Name: "Spot"}, kennel.Pets[1])
+	tt.Nil(t, kennel.Pets[2])
+	tt.Equal(t, Tabby{Name: "Felix", Lives: 3}, kennel.Keepers["bob"])
+
+	var list []Pet
+	v, err = r.Recompose([]any{gen.Object{"kind": gen.String("cat"), "name": gen.String("Kit")}}, &list)
+	tt.Nil(t, err)
+	tt.Equal(t, []Pet{Tabby{Name: "Kit"}}, v)
+
+	var a Pet
+	_, err = r.Recompose(map[string]any{"kind": "dog", "name": "Max"}, &a)
+	tt.Nil(t, err)
+	tt.Equal(t, &Hound{Name: "Max"}, a)
+}
+
+func TestRecomposePolymorphUnmarshal(t *testing.T) {
+	r := polyRecomposer(t)
+	var kennel Kennel
+	err := oj.Unmarshal([]byte(`{"star":{"kind":"cat","name":"Tom","lives":9},"pets":[{"kind":"dog","name":"Rex"}]}`), &kennel, r)
+	tt.Nil(t, err)
+	tt.Equal(t, Tabby{Name: "Tom", Lives: 9}, kennel.Star)
+	tt.Equal(t, []Pet{&Hound{Name: "Rex"}}, kennel.Pets)
+
+	kennel = Kennel{}
+	err = sen.Unmarshal([]byte(`{star:{kind:dog name:Rex} pets:[{kind:cat name:Tom}]}`), &kennel, r)
+	tt.Nil(t, err)
+	tt.Equal(t, &Hound{Name: "Rex"}, kennel.Star)
+	tt.Equal(t, []Pet{Tabby{Name: "Tom"}}, kennel.Pets)
+
+	kennel = Kennel{}
+	err = (&oj.Parser{}).Unmarshal([]byte(`{"star":{"kind":"dog","name":"Rex"}}`), &kennel, *r)
+	tt.Nil(t, err)
+	tt.Equal(t, &Hound{Name: "Rex"}, kennel.Star)
+}
+
+func TestRecomposePolymorphDecompose(t *testing.T) {
+	r := polyRecomposer(t)
+	kennel := Kennel{
+		Star:    &Hound{Name: "Rex", Breed: "collie"},
+		Pets:    []Pet{Tabby{Name: "Tom", Lives: 9}},
+		Keepers: map[string]Pet{},
+	}
+	simple := r.Decompose(&kennel, &alt.Options{OmitNil: true})
+	tt.Equal(t,
+		`{"keepers":{},"pets":[{"kind":"cat","lives":9,"name":"Tom"}],"star":{"breed":"collie","kind":"dog","name":"Rex"}}`,
+		oj.JSON(simple, &oj.Options{Sort: true}))
+
+	simple = r.Decompose(&kennel)
+	tt.Equal(t, "Kennel", jp.C("type").First(simple))
+	tt.Equal(t, "dog", jp.C("star").C("kind").First(simple))
+	tt.Equal(t, "Hound", jp.C("star").C("type").First(simple))
+
+	var kennel2 Kennel
+	_, err := r.Recompose(r.Decompose(&kennel, &alt.Options{}), &kennel2)
+	tt.Nil(t, err)
+	tt.Equal(t, kennel.Star, kennel2.Star)
+	tt.Equal(t, kennel.Pets, kennel2.Pets)
+}
+
+func TestRecomposePolymorphErrors(t *testing.T) {
+	r := polyRecomposer(t)
+	var kennel Kennel
+	_, err := r.Recompose(map[string]any{"star": map[string]any{"kind": "cow"}}, &kennel)
+	tt.NotNil(t, err)
+	_, err = r.Recompose(map[string]any{"star": "dog"}, &kennel)
+	tt.NotNil(t, err)
+
+	err = r.RegisterInterface(Tabby{}, "kind", nil)
+	tt.NotNil(t, err)
+	err = r.RegisterInterface((*Pet)(nil), "", nil)
+	tt.NotNil(t, err)
+	err = r.RegisterInterface((*Pet)(nil), "kind", map[string]any{"dog": Hound{}})
+	tt.NotNil(t, err)
+}
+
+func TestRecomposeConcurrent(t *testing.T) {
+	var r alt.Recomposer
+	err := r.RegisterInterface((*Pet)(nil), "kind", map[string]any{"dog": &Hound{}, "cat": Tabby{}})
+	tt.Nil(t, err)
+	src := map[string]any{
+		"star": map[string]any{"kind": "dog", "name": "Rex"},
+		"pets": []any{map[string]any{"kind": "cat", "name": "Tom", "lives": 9}},
+	}
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var err error
+			if i%2 == 0 {
+				_, err = r.Recompose(src, &Kennel{})
+			} else {
+				_, err = r.Recompose(map[string]any{"num": 3, "children": []any{map[string]any{"name": "x"}}}, &Parent{})
+				if err == nil {
+					err = r.RegisterComposer(&Dummy{}, nil)
+				}
+			}
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		tt.Nil(t, err)
+	}
+}
+
+func TestRecomposeTimeString(t *testing.T) {
+	type Dates struct {
+		Day  time.Time
+		At   *time.Time
+		List []time.Time
+	}
+	var d Dates
+	_, err := alt.Recompose(map[string]any{
+		"day":  "2023-01-02",
+		"at":   "2023-01-02T03:04:05.123Z",
+		"list": []any{"2023-01-02T03:04:05Z"},
+	}, &d)
+	tt.Nil(t, err)
+	tt.Equal(t, "2023-01-02T00:00:00Z", d.Day.Format(time.RFC3339Nano))
+	tt.Equal(t, "2023-01-02T03:04:05.123Z", d.At.Format(time.RFC3339Nano))
+	tt.Equal(t, "2023-01-02T03:04:05Z", d.List[0].Format(time.RFC3339Nano))
+
+	_, err = alt.Recompose(map[string]any{"day": "yesterday"}, &d)
+	tt.NotNil(t, err)
+
+	// Parsers for binary formats return time.Time values.
+	when := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	_, err = alt.Recompose(map[string]any{"day": when, "at": when, "list": []any{when}}, &d)
+	tt.Nil(t, err)
+	tt.Equal(t, when, d.Day)
+	tt.Equal(t, when, *d.At)
+	tt.Equal(t, when, d.List[0])
+}
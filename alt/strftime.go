@@ -0,0 +1,214 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package alt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var strftimeLayouts = map[byte]string{
+	'a': "Mon",
+	'A': "Monday",
+	'b': "Jan",
+	'B': "January",
+	'd': "02",
+	'e': "_2",
+	'f': "000000",
+	'h': "Jan",
+	'H': "15",
+	'I': "03",
+	'L': "000",
+	'm': "01",
+	'M': "04",
+	'n': "\n",
+	'N': "000000000",
+	'p': "PM",
+	'S': "05",
+	't': "\t",
+	'y': "06",
+	'Y': "2006",
+	'z': "-0700",
+	'Z': "MST",
+	'%': "%",
+}
+
+// strftimeComposites are the directives that are replaced by a sequence of
+// other directives.
+var strftimeComposites = map[byte]string{
+	'c': "%a %b %e %H:%M:%S %Y",
+	'D': "%m/%d/%y",
+	'F': "%Y-%m-%d",
+	'r': "%I:%M:%S %p",
+	'R': "%H:%M",
+	'T': "%H:%M:%S",
+}
+
+// strftimePiece is either a literal run of text or a single directive.
+type strftimePiece struct {
+	lit string
+	dir byte
+}
+
+// strftimePieces splits a strftime format into literal runs and
+// directives. Composite directives are expanded and unknown directives are
+// treated as literal text.
+func strftimePieces(format string) (pieces []strftimePiece) {
+	var lit []byte
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		if c != '%' || len(format) <= i+1 {
+			lit = append(lit, c)
+			continue
+		}
+		i++
+		c = format[i]
+		if composite, has := strftimeComposites[c]; has {
+			format = format[:i-1] + composite + format[i+1:]
+			i -= 2
+			continue
+		}
+		switch c {
+		case '%', 'n', 't':
+			lit = append(lit, strftimeLayouts[c]...)
+			continue
+		}
+		if _, has := strftimeLayouts[c]; !has {
+			lit = append(lit, '%', c)
+			continue
+		}
+		if 0 < len(lit) {
+			pieces = append(pieces, strftimePiece{lit: string(lit)})
+			lit = lit[:0]
+		}
+		pieces = append(pieces, strftimePiece{dir: c})
+	}
+	if 0 < len(lit) {
+		pieces = append(pieces, strftimePiece{lit: string(lit)})
+	}
+	return
+}
+
+// Strftime formats a time according to a strftime style format such as
+// "%Y-%m-%d". Text outside of the directives is copied as is and unknown
+// directives are left unchanged.
+func Strftime(t time.Time, format string) string {
+	var b []byte
+	for _, p := range strftimePieces(format) {
+		switch p.dir {
+		case 0:
+			b = append(b, p.lit...)
+		case 'f':
+			b = appendPadded(b, t.Nanosecond()/1000, 6)
+		case 'L':
+			b = appendPadded(b, t.Nanosecond()/1000000, 3)
+		case 'N':
+			b = appendPadded(b, t.Nanosecond(), 9)
+		default:
+			b = t.AppendFormat(b, strftimeLayouts[p.dir])
+		}
+	}
+	return string(b)
+}
+
+// ParseStrftime parses a time formatted according to a strftime style
+// format. Literal text in the format must match the value exactly. A
+// fractional second directive (%f, %L, or %N) must follow a period or
+// comma.
+func ParseStrftime(format, value string) (time.Time, error) {
+	pieces := strftimePieces(format)
+	// Consecutive directives are parsed together with a golang layout and
+	// the literal runs are matched and removed so the golang time package
+	// never sees them. The directive groups are separated by a character
+	// that is not part of any golang layout element.
+	var (
+		layout []byte
+		val    []byte
+		group  []byte
+		pos    int
+	)
+	flush := func(end int) {
+		if 0 < len(layout) {
+			layout = append(layout, 0)
+			val = append(val, 0)
+		}
+		layout = append(layout, group...)
+		val = append(val, value[pos:end]...)
+		group = group[:0]
+	}
+	for i, p := range pieces {
+		if p.dir != 0 {
+			switch p.dir {
+			case 'f', 'L', 'N':
+				sep := fractionSep(pieces, i)
+				if sep == 0 {
+					return time.Time{}, fmt.Errorf("%%%c must follow a period or comma", p.dir)
+				}
+				// The separator is moved from the preceding literal.
+				group = append(group, sep)
+			}
+			group = append(group, strftimeLayouts[p.dir]...)
+			continue
+		}
+		lit := p.lit
+		if 0 < fractionSep(pieces, i+1) {
+			if lit = lit[:len(lit)-1]; len(lit) == 0 {
+				continue
+			}
+		}
+		if len(group) == 0 {
+			if !strings.HasPrefix(value[pos:], lit) {
+				return time.Time{}, fmt.Errorf("%q does not match %q", value, format)
+			}
+			pos += len(lit)
+			continue
+		}
+		// Directives always produce at least one character so start
+		// looking for the literal after the first.
+		end := -1
+		if pos < len(value) {
+			if end = strings.Index(value[pos+1:], lit); 0 <= end {
+				end += pos + 1
+			}
+		}
+		if end < 0 {
+			return time.Time{}, fmt.Errorf("%q does not match %q", value, format)
+		}
+		flush(end)
+		pos = end + len(lit)
+	}
+	switch {
+	case 0 < len(group):
+		flush(len(value))
+	case pos < len(value):
+		return time.Time{}, fmt.Errorf("%q does not match %q", value, format)
+	}
+	return time.Parse(string(layout), string(val))
+}
+
+// fractionSep returns the period or comma that precedes the fractional
+// second directive at index i or 0 if there is not one.
+func fractionSep(pieces []strftimePiece, i int) byte {
+	if i <= 0 || len(pieces) <= i {
+		return 0
+	}
+	switch pieces[i].dir {
+	case 'f', 'L', 'N':
+		if lit := pieces[i-1].lit; 0 < len(lit) {
+			if c := lit[len(lit)-1]; c == '.' || c == ',' {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+func appendPadded(b []byte, i, width int) []byte {
+	s := strconv.Itoa(i)
+	for n := len(s); n < width; n++ {
+		b = append(b, '0')
+	}
+	return append(b, s...)
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package alt_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khaf/ojg/alt"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestStrftime(t *testing.T) {
+	tm := time.Date(2023, time.February, 5, 14, 7, 9, 123456789, time.UTC)
+	for _, d := range []struct {
+		format string
+		expect string
+	}{
+		{format: "%Y-%m-%d %H:%M:%S", expect: "2023-02-05 14:07:09"},
+		{format: "%F %T.%f", expect: "2023-02-05 14:07:09.123456"},
+		{format: "%S.%L %S.%N", expect: "09.123 09.123456789"},
+		{format: "%a %A %b %B %e %y", expect: "Sun Sunday Feb February  5 23"},
+		{format: "%I:%M %p %Z %z", expect: "02:07 PM UTC +0000"},
+		{format: "%D %R %r", expect: "02/05/23 14:07 02:07:09 PM"},
+		{format: "at %% %q %", expect: "at % %q %"},
+		{format: "Day 1 of Jan: %d Monday %Y PM", expect: "Day 1 of Jan: 05 Monday 2023 PM"},
+		{format: "%S%L%n%t", expect: "09123\n\t"},
+	} {
+		tt.Equal(t, d.expect, alt.Strftime(tm, d.format), d.format)
+	}
+}
+
+func TestParseStrftime(t *testing.T) {
+	tm := time.Date(2023, time.February, 5, 14, 7, 9, 123456789, time.UTC)
+	for _, format := range []string{
+		"%Y-%m-%dT%H:%M:%S.%N%z",
+		"%F %T,%f",
+		"Day 1 of Jan: %d %B %Y at %H%M%S.%N",
+		"on %e %b %Y %I:%M:%S.%N %p %Z",
+		"%D %R:%S.%N",
+		"%c.%N",
+	} {
+		str := alt.Strftime(tm, format)
+		parsed, err := alt.ParseStrftime(format, str)
+		tt.Nil(t, err, format)
+		expect := tm
+		if strings.Contains(format, "%f") {
+			expect = expect.Truncate(time.Microsecond)
+		}
+		tt.Equal(t, expect.Format(time.RFC3339Nano), parsed.Format(time.RFC3339Nano), format)
+	}
+	for _, d := range []struct {
+		format string
+		value  string
+	}{
+		{format: "Jan %d", value: "Feb 05"},
+		{format: "%d Jan", value: "05 Feb"},
+		{format: "%d", value: "05 Jan"},
+		{format: "%d days", value: "05"},
+		{format: "%S%L", value: "09123"},
+	} {
+		_, err := alt.ParseStrftime(d.format, d.value)
+		tt.NotNil(t, err, d.format)
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package alt
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// PathError is a recompose error for the value at a specific location in
+// the data being recomposed. The Path is a JSONPath such as $.pets[1].name.
+type PathError struct {
+	Path    string
+	Message string
+}
+
+// Error returns the path and message of the error.
+func (pe *PathError) Error() string {
+	return pe.Path + ": " + pe.Message
+}
+
+// RecomposeError is returned by a strict Recomposer and includes every
+// problem found while recomposing.
+type RecomposeError struct {
+	Errors []*PathError
+}
+
+// Error returns all the path errors, one per line.
+func (re *RecomposeError) Error() string {
+	var b strings.Builder
+	for i, pe := range re.Errors {
+		if 0 < i {
+			b.WriteByte('\n')
+		}
+		b.WriteString(pe.Error())
+	}
+	return b.String()
+}
+
+// strictState tracks the location in the data being recomposed and the
+// errors found when a Recomposer is strict. A nil strictState is used when
+// not strict.
+type strictState struct {
+	r    *Recomposer
+	path []any
+	errs []*PathError
+}
+
+func (st *strictState) push(key any) {
+	if st != nil {
+		st.path = append(st.path, key)
+	}
+}
+
+func (st *strictState) pop() {
+	if st != nil {
+		st.path = st.path[:len(st.path)-1]
+	}
+}
+
+// recover is deferred so that a panic while recomposing a value is recorded
+// as an error and recomposing continues with the next value. The depth is
+// the path length when the recover was deferred.
+func (st *strictState) recover(depth int) {
+	if rec := recover(); rec != nil {
+		st.fail(fmt.Sprintf("%v", rec))
+		st.path = st.path[:depth]
+	}
+}
+
+func (st *strictState) fail(msg string) {
+	st.errs = append(st.errs, &PathError{Path: st.pathString(), Message: msg})
+}
+
+// pathString returns the current path in the same format as a jp.Expr.
+func (st *strictState) pathString() string {
+	return string(appendPath(nil, st.path))
+}
+
+// appendPath appends a path of string keys and int indexes in the same
+// format as a jp.Expr.
+func appendPath(b []byte, path []any) []byte {
+	b = append(b, '$')
+	for _, key := range path {
+		switch tk := key.(type) {
+		case int:
+			b = append(b, '[')
+			b = strconv.AppendInt(b, int64(tk), 10)
+			b = append(b, ']')
+		case string:
+			if isToken(tk) {
+				b = append(b, '.')
+				b = append(b, tk...)
+			} else {
+				b = append(b, "['"...)
+				b = append(b, tk...)
+				b = append(b, "']"...)
+			}
+		}
+	}
+	return b
+}
+
+func isToken(key string) bool {
+	if len(key) == 0 {
+		return false
+	}
+	for _, r := range key {
+		if !(r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') || 0x7f < r) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkType panics if a value can not be assigned to a field of the
+// provided type without a lossy conversion.
+func (st *strictState) checkType(v any, rt reflect.Type) {
+	vt := reflect.TypeOf(v)
+	ok := false
+	if vt != nil {
+		switch rt.Kind() {
+		case reflect.Bool:
+			ok = vt.Kind() == reflect.Bool
+		case reflect.String:
+			ok = vt.Kind() == reflect.String
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			switch vt.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+				reflect.Float32, reflect.Float64:
+				checkNumber(reflect.ValueOf(v), rt)
+				ok = true
+			}
+		default:
+			ok = true
+		}
+	}
+	if !ok {
+		panic(fmt.Errorf("expected a %s, not a %T", rt, v))
+	}
+}
+
+// checkNumber panics if a number would be truncated or would overflow when
+// converted to the provided type.
+func checkNumber(vv reflect.Value, rt reflect.Type) {
+	z := reflect.New(rt).Elem()
+	fits := true
+	switch rt.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch vv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			fits = !z.OverflowInt(vv.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			fits = vv.Uint() <= math.MaxInt64 && !z.OverflowInt(int64(vv.Uint()))
+		default:
+			f := vv.Float()
+			if f != math.Trunc(f) {
+				panic(fmt.Errorf("%v is not an integer so can not be a %s", vv, rt))
+			}
+			fits = math.MinInt64 <= f && f < math.MaxInt64 && !z.OverflowInt(int64(f))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch vv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			fits = 0 <= vv.Int() && !z.OverflowUint(uint64(vv.Int()))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			fits = !z.OverflowUint(vv.Uint())
+		default:
+			f := vv.Float()
+			if f != math.Trunc(f) {
+				panic(fmt.Errorf("%v is not an integer so can not be a %s", vv, rt))
+			}
+			fits = 0 <= f && f < math.MaxUint64 && !z.OverflowUint(uint64(f))
+		}
+	default:
+		if vv.Kind() == reflect.Float32 || vv.Kind() == reflect.Float64 {
+			fits = !z.OverflowFloat(vv.Float())
+		}
+	}
+	if !fits {
+		panic(fmt.Errorf("%v is out of range for a %s", vv, rt))
+	}
+}
+
+// checkFields reports missing required fields and, if unknown members are
+// not allowed, object members that do not match a field.
+func (st *strictState) checkFields(c *composer, vm map[string]any, found []bool) {
+	if st.r.Required {
+		for i, fp := range c.fields {
+			if !found[i] && fp.isRequired(st.r.RequiredTag) {
+				st.push(fp.keys[0])
+				st.fail("required field missing")
+				st.pop()
+			}
+		}
+	}
+	if st.r.DisallowUnknown {
+		for k := range vm {
+			if !c.known[k] && k != st.r.CreateKey {
+				st.push(k)
+				st.fail(fmt.Sprintf("unknown field for %s", c.rtype))
+				st.pop()
+			}
+		}
+	}
+}
+
+// isRequired returns true if the json tag includes the required option or
+// if the tag named by requiredTag includes "required" or "true".
+func (fp *fieldPlan) isRequired(requiredTag string) bool {
+	if fp.required {
+		return true
+	}
+	if 0 < len(requiredTag) {
+		for _, s := range strings.Split(fp.tag.Get(requiredTag), ",") {
+			if s == "required" || s == "true" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package alt_test
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/khaf/ojg/alt"
+	"github.com/khaf/ojg/oj"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+type strictItem struct {
+	ID    int    `json:"id,required"`
+	Label string `json:"label" validate:"required"`
+	Flag  bool   `json:"flag"`
+}
+
+type strictOrder struct {
+	Name  string        `json:"name,required"`
+	Items []*strictItem `json:"items"`
+	Tags  map[string]int
+}
+
+func strictErrors(t *testing.T, err error) []string {
+	var re *alt.RecomposeError
+	tt.Equal(t, true, errors.As(err, &re), "expected a RecomposeError, not %v", err)
+	var list []string
+	for _, pe := range re.Errors {
+		list = append(list, pe.Error())
+	}
+	sort.Strings(list)
+	return list
+}
+
+func TestRecomposeStrict(t *testing.T) {
+	r := alt.Recomposer{DisallowUnknown: true, Required: true, RequiredTag: "validate"}
+	src := map[string]any{
+		"items": []any{
+			map[string]any{"id": 1, "label": "one", "flag": "yes"},
+			map[string]any{"label": "two", "extra": true},
+		},
+		"Tags":     map[string]any{"x": 1, "y": "two"},
+		"my color": "blue",
+	}
+	_, err := r.Recompose(src, &strictOrder{})
+	tt.Equal(t, []string{
+		"$.Tags.y: expected a int, not a string",
+		"$.items[0].flag: expected a bool, not a string",
+		"$.items[1].extra: unknown field for alt_test.strictItem",
+		"$.items[1].id: required field missing",
+		"$.name: required field missing",
+		"$['my color']: unknown field for alt_test.strictOrder",
+	}, strictErrors(t, err))
+
+	var order strictOrder
+	_, err = r.Recompose(map[string]any{"name": "x", "items": []any{map[string]any{"id": 2, "label": "b"}}}, &order)
+	tt.Nil(t, err)
+	tt.Equal(t, "x", order.Name)
+	tt.Equal(t, 2, order.Items[0].ID)
+
+	// Not strict so unknown and missing fields are ignored.
+	var lenient alt.Recomposer
+	_, err = lenient.Recompose(map[string]any{"items": []any{map[string]any{"label": "two", "extra": true}}}, &order)
+	tt.Nil(t, err)
+}
+
+type strictNumbers struct {
+	I    int     `json:"i"`
+	I8   int8    `json:"i8"`
+	U8   uint8   `json:"u8"`
+	U    uint    `json:"u"`
+	F32  float32 `json:"f32"`
+	S8   int8    `json:"s8,string"`
+	List []uint8 `json:"list"`
+}
+
+func TestRecomposeStrictNumbers(t *testing.T) {
+	r := alt.Recomposer{DisallowUnknown: true}
+	_, err := r.Recompose(map[string]any{
+		"i":    1.5,
+		"i8":   int64(128),
+		"u8":   300,
+		"u":    -1,
+		"f32":  1e300,
+		"s8":   "-200",
+		"list": []any{1, -1, 2.5, uint64(256)},
+	}, &strictNumbers{})
+	tt.Equal(t, []string{
+		"$.f32: 1e+300 is out of range for a float32",
+		"$.i8: 128 is out of range for a int8",
+		"$.i: 1.5 is not an integer so can not be a int",
+		"$.list[1]: -1 is out of range for a uint8",
+		"$.list[2]: 2.5 is not an integer so can not be a uint8",
+		"$.list[3]: 256 is out of range for a uint8",
+		"$.s8: -200 is out of range for a int8",
+		"$.u8: 300 is out of range for a uint8",
+		"$.u: -1 is out of range for a uint",
+	}, strictErrors(t, err))
+
+	var sn strictNumbers
+	_, err = r.Recompose(map[string]any{
+		"i":    2.0,
+		"i8":   -128,
+		"u8":   uint64(255),
+		"u":    3.0,
+		"f32":  1.5,
+		"s8":   "-7",
+		"list": []any{0, 255.0},
+	}, &sn)
+	tt.Nil(t, err)
+	tt.Equal(t, strictNumbers{I: 2, I8: -128, U8: 255, U: 3, F32: 1.5, S8: -7, List: []uint8{0, 255}}, sn)
+
+	// Not strict so numbers are converted as Go converts them.
+	var lenient alt.Recomposer
+	_, err = lenient.Recompose(map[string]any{"i": 1.5, "u8": 300}, &sn)
+	tt.Nil(t, err)
+	tt.Equal(t, 1, sn.I)
+	tt.Equal(t, 44, sn.U8)
+}
+
+func TestRecomposeStrictOptions(t *testing.T) {
+	src := map[string]any{"name": "x", "items": []any{map[string]any{"id": 1}}, "other": 3}
+
+	_, err := (&alt.Recomposer{DisallowUnknown: true}).Recompose(src, &strictOrder{})
+	tt.Equal(t, []string{"$.other: unknown field for alt_test.strictOrder"}, strictErrors(t, err))
+
+	_, err = (&alt.Recomposer{Required: true}).Recompose(src, &strictOrder{})
+	tt.Nil(t, err)
+
+	_, err = (&alt.Recomposer{Required: true, RequiredTag: "validate"}).Recompose(src, &strictOrder{})
+	tt.Equal(t, []string{"$.items[0].label: required field missing"}, strictErrors(t, err))
+}
+
+func TestRecomposeStrictPolymorph(t *testing.T) {
+	r := alt.Recomposer{DisallowUnknown: true}
+	err := r.RegisterInterface((*Pet)(nil), "kind", map[string]any{"dog": &Hound{}, "cat": Tabby{}})
+	tt.Nil(t, err)
+	var kennel Kennel
+	_, err = r.Recompose(map[string]any{
+		"star": map[string]any{"kind": "dog", "name": "Rex"},
+		"pets": []any{map[string]any{"kind": "cat", "name": "Tom", "color": "gray"}},
+	}, &kennel)
+	tt.Equal(t, []string{"$.pets[0].color: unknown field for alt_test.Tabby"}, strictErrors(t, err))
+}
+
+func TestRecomposeStrictUnmarshal(t *testing.T) {
+	r := alt.Recomposer{DisallowUnknown: true, Required: true}
+	var order strictOrder
+	err := oj.Unmarshal([]byte(`{"items":[{"id":1,"x":2}]}`), &order, &r)
+	tt.Equal(t, []string{
+		"$.items[0].x: unknown field for alt_test.strictItem",
+		"$.name: required field missing",
+	}, strictErrors(t, err))
+
+	err = sen.Unmarshal([]byte(`{name: abc items: [{label: a}]}`), &order, &r)
+	tt.Equal(t, []string{"$.items[0].id: required field missing"}, strictErrors(t, err))
+}
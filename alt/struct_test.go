@@ -222,7 +222,7 @@ func TestDecomposeTagAsString(t *testing.T) {
 			"f64": "12.5",
 			"no":  "false",
 			"yes": "true",
-			"z":   "abc",
+			"z":   `"abc"`,
 		}, out)
 	out = alt.Decompose(sample, &opt)
 	tt.Equal(t,
@@ -241,7 +241,7 @@ func TestDecomposeTagAsString(t *testing.T) {
 			"f64": "12.5",
 			"no":  "false",
 			"yes": "true",
-			"z":   "abc",
+			"z":   `"abc"`,
 		}, out)
 }
 
@@ -379,7 +379,7 @@ func TestDecomposeTagOmitEmptyAsString(t *testing.T) {
 			"f32": "11.5",
 			"f64": "12.5",
 			"yes": "true",
-			"z":   "abc",
+			"z":   `"abc"`,
 		}, out)
 	out = alt.Decompose(sample, &opt)
 	tt.Equal(t,
@@ -397,7 +397,7 @@ func TestDecomposeTagOmitEmptyAsString(t *testing.T) {
 			"f32": "11.5",
 			"f64": "12.5",
 			"yes": "true",
-			"z":   "abc",
+			"z":   `"abc"`,
 		}, out)
 
 	out = alt.Decompose(&Sample{}, &opt)
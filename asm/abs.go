@@ -0,0 +1,40 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+func init() {
+	Define(&Fn{
+		Name: "abs",
+		Eval: abs,
+		Desc: `Returns the absolute value of the single number argument. An
+error is raised if the argument is not a number.`,
+	})
+}
+
+func abs(root map[string]any, at any, args ...any) any {
+	if len(args) != 1 {
+		panic(fmt.Errorf("abs expects exactly one argument. %d given", len(args)))
+	}
+	n := numberArg("abs", root, at, args[0])
+	switch n.kind {
+	case intNum:
+		switch {
+		case n.i == math.MinInt64:
+			n = n.promote(bigNum)
+			n.b.Abs(n.b)
+		case n.i < 0:
+			n.i = -n.i
+		}
+	case floatNum:
+		n.f = math.Abs(n.f)
+	default:
+		n.b = new(big.Float).Abs(n.b)
+	}
+	return n.value()
+}
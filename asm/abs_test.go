@@ -0,0 +1,46 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestAbs(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [abs -3]]
+           [set $.asm.b [abs -1.5]]
+           [set $.asm.c [abs 2]]
+         ]`,
+		"{}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: 3
+  b: 1.5
+  c: 2
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestAbsArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"abs", 1, 2},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestAbsArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"abs", "x"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/khaf/ojg/alt"
+)
+
+// stringArg evaluates an argument and returns it as a string or raises an
+// error that names the function and the position of the argument.
+func stringArg(name, pos string, root map[string]any, at, arg any) string {
+	v := evalArg(root, at, arg)
+	s, ok := v.(string)
+	if !ok {
+		panic(fmt.Errorf("%s expects a string %sargument, not a %T", name, pos, v))
+	}
+	return s
+}
+
+// compileRegex compiles the arguments of a function that takes a regular
+// expression as the second argument. A string pattern is always a literal,
+// even if it starts with a '$' or '@' as "$^" does, and is compiled once
+// here instead of on every evaluation. A pattern from the data must be
+// provided by a function such as get. If the pattern is not valid it is
+// left as a string so the error is raised when evaluated.
+func compileRegex(f *Fn) {
+	var pattern any
+	if 1 < len(f.Args) {
+		pattern = f.Args[1]
+	}
+	compileArgs(f)
+	if s, ok := pattern.(string); ok {
+		if rx, err := regexp.Compile(s); err == nil {
+			f.Args[1] = rx
+		} else {
+			f.Args[1] = s
+		}
+	}
+}
+
+// regexArg evaluates an argument and compiles it as a regular expression
+// unless it was already compiled by compileRegex.
+func regexArg(name string, root map[string]any, at, arg any) *regexp.Regexp {
+	if rx, ok := arg.(*regexp.Regexp); ok {
+		return rx
+	}
+	rx, err := regexp.Compile(stringArg(name, "pattern ", root, at, arg))
+	if err != nil {
+		panic(err)
+	}
+	return rx
+}
+
+// bytesArg evaluates an argument that must be either a string or a []byte
+// and returns it as a string.
+func bytesArg(name string, root map[string]any, at, arg any) string {
+	switch v := evalArg(root, at, arg).(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		panic(fmt.Errorf("%s expects a string argument, not a %T", name, v))
+	}
+}
+
+// noTime is the default returned by alt.Time when a value can not be
+// converted so that conversion failures can be detected.
+var noTime = time.Date(-9999, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// timeArg evaluates an argument and converts it to a time using
+// alt.Time. A time, integer nanoseconds since 1970-01-01 UTC, float seconds,
+// or RFC 3339 string are all valid.
+func timeArg(name string, root map[string]any, at, arg any) time.Time {
+	v := evalArg(root, at, arg)
+	t := alt.Time(v, noTime)
+	if t.Equal(noTime) {
+		panic(fmt.Errorf("%s expects a time argument, not a %T", name, v))
+	}
+	return t
+}
+
+var namedLayouts = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RubyDate":    time.RubyDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"Stamp":       time.Stamp,
+	"StampMilli":  time.StampMilli,
+	"StampMicro":  time.StampMicro,
+	"StampNano":   time.StampNano,
+}
+
+// formatTime formats a time with a layout argument that can be the name of
+// one of the golang time layout constants such as RFC3339, a strftime
+// format such as "%Y-%m-%d", or a golang layout.
+func formatTime(t time.Time, layout string) string {
+	if named, has := namedLayouts[layout]; has {
+		return t.Format(named)
+	}
+	if strings.ContainsRune(layout, '%') {
+		return alt.Strftime(t, layout)
+	}
+	return t.Format(layout)
+}
+
+// parseTime parses a time with a layout argument as described for
+// formatTime.
+func parseTime(layout, value string) (time.Time, error) {
+	if named, has := namedLayouts[layout]; has {
+		return time.Parse(named, value)
+	}
+	if strings.ContainsRune(layout, '%') {
+		return alt.ParseStrftime(layout, value)
+	}
+	return time.Parse(layout, value)
+}
+
+// durationArg evaluates an argument and converts it to an ISO 8601 duration.
+// ISO 8601 strings keep the calendar components while integer nanoseconds,
+// float seconds, and golang duration strings are converted with
+// alt.Duration.
+func durationArg(name string, root map[string]any, at, arg any) alt.ISODuration {
+	v := evalArg(root, at, arg)
+	if s, ok := v.(string); ok {
+		if iso, err := alt.ParseISODuration(s); err == nil {
+			return iso
+		}
+	}
+	d := alt.Duration(v, math.MinInt64)
+	if d == math.MinInt64 {
+		panic(fmt.Errorf("%s expects a duration argument, not %v", name, v))
+	}
+	return alt.ISODuration{Dur: d}
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"math/big"
+)
+
+func init() {
+	Define(&Fn{
+		Name: "avg",
+		Eval: avg,
+		Desc: `Returns the average (arithmetic mean) of all arguments as a
+float. All arguments must be numbers or arrays of numbers. If
+no numbers are given nil is returned.`,
+	})
+}
+
+func avg(root map[string]any, at any, args ...any) any {
+	nums := numberArgs("avg", root, at, args)
+	if len(nums) == 0 {
+		return nil
+	}
+	return mean(nums).value()
+}
+
+// mean returns the mean of a non-empty list of numbers as either a float or
+// a big number.
+func mean(nums []number) number {
+	if widest(nums) == bigNum {
+		sum := new(big.Float).SetPrec(bigPrec)
+		for _, n := range nums {
+			sum.Add(sum, n.bigFloat())
+		}
+		return number{kind: bigNum, b: sum.Quo(sum, new(big.Float).SetInt64(int64(len(nums))))}
+	}
+	var sum float64
+	for _, n := range nums {
+		sum += n.asFloat64()
+	}
+	return number{kind: floatNum, f: sum / float64(len(nums))}
+}
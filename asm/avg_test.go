@@ -0,0 +1,38 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestAvg(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [avg 1 2 3 4]]
+           [set $.asm.b [avg [list 1.5 2.5]]]
+           [set $.asm.d [avg]]
+         ]`,
+		"{}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: 2.5
+  b: 2
+  d: null
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestAvgArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"avg", "x"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+func init() {
+	Define(&Fn{
+		Name: "b64encode",
+		Eval: b64encode,
+		Desc: `Returns the base64 encoding of the first string argument. The
+optional second argument selects the encoding and must be one
+of std (the default), url, rawstd, or rawurl.`,
+	})
+	Define(&Fn{
+		Name: "b64decode",
+		Eval: b64decode,
+		Desc: `Returns the decoded string of the first base64 encoded string
+argument. The optional second argument selects the encoding and
+must be one of std (the default), url, rawstd, or rawurl. An
+error is raised if the string is not valid base64.`,
+	})
+}
+
+func b64encode(root map[string]any, at any, args ...any) any {
+	s, enc := b64Args("b64encode", root, at, args)
+	return enc.EncodeToString([]byte(s))
+}
+
+func b64decode(root map[string]any, at any, args ...any) any {
+	s, enc := b64Args("b64decode", root, at, args)
+	b, err := enc.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
+
+func b64Args(name string, root map[string]any, at any, args []any) (s string, enc *base64.Encoding) {
+	if len(args) < 1 || 2 < len(args) {
+		panic(fmt.Errorf("%s expects one or two arguments. %d given", name, len(args)))
+	}
+	s = bytesArg(name, root, at, args[0])
+	enc = base64.StdEncoding
+	if 1 < len(args) {
+		switch kind := stringArg(name, "encoding ", root, at, args[1]); kind {
+		case "std":
+		case "url":
+			enc = base64.URLEncoding
+		case "rawstd":
+			enc = base64.RawStdEncoding
+		case "rawurl":
+			enc = base64.RawURLEncoding
+		default:
+			panic(fmt.Errorf("%s encoding must be std, url, rawstd, or rawurl, not %s", name, kind))
+		}
+	}
+	return
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestBase64(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [b64encode "hello?~"]]
+           [set $.asm.b [b64encode "hello?~" url]]
+           [set $.asm.c [b64encode "hello?~" rawstd]]
+           [set $.asm.d [b64encode "hello?~" rawurl]]
+           [set $.asm.e [b64decode "aGVsbG8/fg=="]]
+           [set $.asm.f [b64decode "aGVsbG8_fg" rawurl]]
+           [set $.asm.g [b64encode [b64decode "aGVsbG8/fg==" std]]]
+         ]`,
+		"{src: []}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: "aGVsbG8/fg=="
+  b: "aGVsbG8_fg=="
+  c: "aGVsbG8/fg"
+  d: aGVsbG8_fg
+  e: hello?~
+  f: hello?~
+  g: "aGVsbG8/fg=="
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestBase64ArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"b64encode"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestBase64ArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"b64encode", 1},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestBase64Encoding(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"b64encode", "x", "bad"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestBase64Decode(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"b64decode", "@@@"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+func init() {
+	Define(&Fn{
+		Name: "sha256",
+		Eval: sha256Eval,
+		Desc: `Returns the SHA-256 digest of the single string argument as a
+lowercase hexadecimal string.`,
+	})
+	Define(&Fn{
+		Name: "md5",
+		Eval: md5Eval,
+		Desc: `Returns the MD5 digest of the single string argument as a
+lowercase hexadecimal string.`,
+	})
+}
+
+func sha256Eval(root map[string]any, at any, args ...any) any {
+	if len(args) != 1 {
+		panic(fmt.Errorf("sha256 expects exactly one argument. %d given", len(args)))
+	}
+	sum := sha256.Sum256([]byte(bytesArg("sha256", root, at, args[0])))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func md5Eval(root map[string]any, at any, args ...any) any {
+	if len(args) != 1 {
+		panic(fmt.Errorf("md5 expects exactly one argument. %d given", len(args)))
+	}
+	sum := md5.Sum([]byte(bytesArg("md5", root, at, args[0])))
+
+	return hex.EncodeToString(sum[:])
+}
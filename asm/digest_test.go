@@ -0,0 +1,44 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestDigest(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [sha256 abc]]
+           [set $.asm.b [md5 abc]]
+         ]`,
+		"{src: []}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad
+  b: "900150983cd24fb0d6963f7d28e17f72"
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestDigestArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"sha256", "x", "y"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestDigestArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"sha256", 1},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
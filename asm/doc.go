@@ -18,6 +18,11 @@ An example of a plan in SEN format is (the first asm is optional):
 	  [set $.asm.hello world]  // output is now {good: bad, hello: world}
 	]
 
+The math and statistics functions accept integers, floats, gen.Big, and
+json.Number values. Results that do not fit in an int64 or float64 without
+losing precision are returned as a json.Number. Unsigned integers too large
+for an int64 are treated as big numbers.
+
 The functions available are:
 
 	      !=: Returns true if any the argument are not equal. An alias is !==.
@@ -55,6 +60,9 @@ The functions available are:
 	      >=: Returns true if each argument is greater than or equal to any
 	          subsequent argument. An alias is gte.
 
+	     abs: Returns the absolute value of the single number argument. An
+	          error is raised if the argument is not a number.
+
 	     and: Returns true if all argument evaluate to true. Any arguments
 	          that do not evaluate to a boolean or null (false) raise an error.
 
@@ -70,9 +78,27 @@ The functions available are:
 	      at: Forms a path starting with @. The remaining string arguments are
 	          joined with a '.' and parsed to form a jp.Expr.
 
+	     avg: Returns the average (arithmetic mean) of all arguments as a
+	          float. All arguments must be numbers or arrays of numbers. If
+	          no numbers are given nil is returned.
+
+	b64decode: Returns the decoded string of the first base64 encoded string
+	          argument. The optional second argument selects the encoding and
+	          must be one of std (the default), url, rawstd, or rawurl. An
+	          error is raised if the string is not valid base64.
+
+	b64encode: Returns the base64 encoding of the first string argument. The
+	          optional second argument selects the encoding and must be one
+	          of std (the default), url, rawstd, or rawurl.
+
 	   bool?: Returns true if the single required argumement is a boolean
 	          otherwise false is returned.
 
+	    ceil: Returns the least value greater than or equal to the first
+	          number argument with the number of decimal places given by the
+	          optional second integer argument. The result has the same type
+	          as the first argument.
+
 	    cond: A conditional construct modeled after the LISP cond. All
 	          arguments must be array of two elements. The first element must
 	          evaluate to a boolean and the second can be any value. The value
@@ -93,17 +119,47 @@ The functions available are:
 	          numbers. If any of the arguments are not a number an error is
 	          raised.
 
+	duration: Converts the first argument to a duration in integer
+	          nanoseconds. The argument can be integer nanoseconds, float
+	          seconds, a golang duration string such as "1h30m", or an ISO
+	          8601 duration such as "PT1H30M" where years, months, and days
+	          are taken to be 365, 30, and 1 days of 24 hours. The optional
+	          second argument changes the result as with timediff.
+
 	    each: Each .
 
+	endswith: Returns true if the first string argument ends with the second
+	          string argument.
+
 	      eq: Returns true if all the argument are equal. Aliases are eq, ==,
 	          and equal.
 
 	   equal: Returns true if all the argument are equal. Aliases are eq, ==,
 	          and equal.
 
+	 extract: Returns the capture groups of the first match of the regular
+	          expression second argument against the first string argument
+	          as an array. If the regular expression has no capture groups
+	          then the whole match is the only element. If the optional third
+	          argument is an integer then only that group is returned with 0
+	          being the whole match. If there is no match nil is returned.
+
 	   float: Converts a value into a float if possible. I no conversion is
 	          possible nil is returned.
 
+	   floor: Returns the greatest value less than or equal to the first
+	          number argument with the number of decimal places given by the
+	          optional second integer argument. The result has the same type
+	          as the first argument.
+
+	  format: Formats the remaining arguments according to the first string
+	          argument which is a golang fmt package format such as
+	          [format "%s-%04d" abc 12] which returns "abc-0012".
+
+	fromentries: Returns an object built from the single array argument. Each
+	          element of the array must be either an object with a key and
+	          value member or an array of a key and a value.
+
 	     get: Gets the first matching value in either the root ($), local (@),
 	          or if present, the second argument. The required first argument
 	          must be a path and the option second argument is the
@@ -116,12 +172,23 @@ The functions available are:
 	          data to apply the path to. The jp.Get() function is used to get
 	          the results
 
+	    gsub: Replaces all matches of the regular expression second argument
+	          in the first string argument with the third string argument.
+	          The replacement can refer to capture groups with $1 or ${name}.
+
 	      gt: Returns true if each argument is greater than any subsequent
 	          argument. An alias is >.
 
 	     gte: Returns true if each argument is greater than or equal to any
 	          subsequent argument. An alias is >=.
 
+	hexdecode: Returns the decoded string of the single hexadecimal encoded
+	          string argument. An error is raised if the string is not valid
+	          hexadecimal.
+
+	hexencode: Returns the lowercase hexadecimal encoding of the single string
+	          argument.
+
 	 include: Returns true if a list first argument includes the second
 	          argument. It will also return true if the first argument is a
 	          string and the second string argument is included in the first.
@@ -139,6 +206,10 @@ The functions available are:
 
 	    list: Creates a list from all the argument and return that list.
 
+	    lpad: Pads the left side of the first string argument until it is as
+	          long as the second integer argument. The optional third string
+	          argument is used for padding instead of a space.
+
 	      lt: Returns true if each argument is less than any subsequent
 	          argument. An alias is <.
 
@@ -148,6 +219,34 @@ The functions available are:
 	    map?: Returns true if the single required argumement is a map
 	          otherwise false is returned.
 
+	   match: Returns true if the first string argument matches the regular
+	          expression that is the second argument. The regular expression
+	          syntax is that of the golang regexp package.
+
+	     max: Returns the maximum of all arguments. All arguments must be
+	          numbers or arrays of numbers. If any argument is a float then
+	          the result will be a float. Big numbers are compared exactly.
+	          If no numbers are given nil is returned.
+
+	     md5: Returns the MD5 digest of the single string argument as a
+	          lowercase hexadecimal string.
+
+	  median: Returns the median of all arguments. All arguments must be
+	          numbers or arrays of numbers. If there are an even number of
+	          values the result is the average of the middle two values as
+	          a float. If no numbers are given nil is returned.
+
+	   merge: Returns a new object that is the deep merge of all the object
+	          arguments. Values from later arguments replace those from
+	          earlier arguments unless both values are objects in which case
+	          they are merged. Null arguments are ignored. The arguments are
+	          not modified.
+
+	     min: Returns the minimum of all arguments. All arguments must be
+	          numbers or arrays of numbers. If any argument is a float then
+	          the result will be a float. Big numbers are compared exactly.
+	          If no numbers are given nil is returned.
+
 	     mod: Returns the remainer of a modulo operation on the first two
 	          argument. Both arguments must be integers and are both required.
 	          An error is raised if the wrong argument types are given.
@@ -171,10 +270,40 @@ The functions available are:
 	    num?: Returns true if the single required argumement is number
 	          otherwise false is returned.
 
+	  numfmt: Formats a number as a string. The optional second integer
+	          argument is the number of decimal places to include. If not
+	          provided or negative the minimum number of digits needed are
+	          used. The optional third string argument is inserted between
+	          groups of three digits in the integer part of the number as
+	          in [numfmt 1234.5 2 ","] which returns "1,234.50".
+
+	    omit: Returns a copy of the first object argument without the members
+	          selected by the remaining arguments. The remaining arguments
+	          can be keys, arrays of keys, or local (@) paths relative to
+	          the first argument such as @.a[*].b which are removed using the
+	          jp.Remove() function.
+
 	      or: Returns true if any of the argument evaluate to true. Any
 	          arguments that do not evaluate to a boolean or null (false)
 	          raise an error.
 
+	percentile: Returns the value at the percentile given by the second
+	          argument of the array of numbers that is the first argument.
+	          The percentile must be a number from 0 to 100. When the
+	          percentile falls between two values the result is linearly
+	          interpolated as a float. If the array is empty nil is returned.
+
+	    pick: Returns a new object with only the selected members of the first
+	          object argument. The remaining arguments select members and can
+	          be keys, arrays of keys, or local (@) paths that are relative
+	          to the first argument such as @.a.b. Values selected by a path
+	          are placed at the same path in the new object.
+
+	     pow: Returns the first argument raised to the power of the second
+	          argument. Both arguments must be numbers. An integer raised to
+	          a non-negative integer power is an integer or a big number if
+	          it does not fit in an integer. Otherwise the result is a float.
+
 	 product: Returns the product of all arguments. All arguments must be
 	          numbers. If any of the arguments are not a number an error is
 	          raised.
@@ -189,6 +318,11 @@ The functions available are:
 	          raised. If an attempt is made to divide by zero and error will
 	          be raised.
 
+	  rename: Returns a copy of the first object argument with keys renamed
+	          according to the second argument which must be an object that
+	          maps old key names to new key names such as {old: new}. Keys
+	          not in the mapping are unchanged.
+
 	 replace: Replace an occurrences the second argument with the third
 	          argument. All three arguments must be strings.
 
@@ -197,6 +331,16 @@ The functions available are:
 	    root: Forms a path starting with @. The remaining string arguments are
 	          joined with a '.' and parsed to form a jp.Expr.
 
+	   round: Rounds the first number argument to the nearest value with the
+	          number of decimal places given by the optional second integer
+	          argument. Halfway values are rounded away from zero. A negative
+	          precision rounds to tens, hundreds, and so on. The result has
+	          the same type as the first argument.
+
+	    rpad: Pads the right side of the first string argument until it is as
+	          long as the second integer argument. The optional third string
+	          argument is used for padding instead of a space.
+
 	     set: Sets a single value in either the root ($) or local (@) data. Two
 	          arguments are required, the first must be a path and the second
 	          argument is evaluate to a value and inserted using the
@@ -207,6 +351,9 @@ The functions available are:
 	          second argument is evaluate to a value and inserted using the
 	          jp.Set() function.
 
+	  sha256: Returns the SHA-256 digest of the single string argument as a
+	          lowercase hexadecimal string.
+
 	    size: Returns the size or length of a string, array, or object (map).
 	          For all other types zero is returned
 
@@ -216,6 +363,16 @@ The functions available are:
 
 	   split: Split a string on using a specified separator.
 
+	    sqrt: Returns the square root of the single number argument as a
+	          float. An error is raised if the argument is negative.
+
+	startswith: Returns true if the first string argument starts with the
+	          second string argument.
+
+	  stddev: Returns the population standard deviation of all arguments as
+	          a float. All arguments must be numbers or arrays of numbers. If
+	          no numbers are given nil is returned.
+
 	  string: Converts a value into a string.
 
 	 string?: Returns true if the single required argumement is a string
@@ -232,6 +389,13 @@ The functions available are:
 	          a string otherwise the result will be a number. If any of the
 	          arguments are not a number or a string an error is raised.
 
+	template: Returns a new object built from the single template argument.
+	          The template is an object whose leaf values are evaluated. Paths
+	          such as $.src.x and functions such as [sum 1 2] are replaced by
+	          their values. Arrays that are not functions are evaluated
+	          element by element. The template can also be a string that is
+	          parsed as SEN or a path to an object in the data.
+
 	    time: Converts the first argument to a time if possible otherwise
 	          an error is raised. The first argument can be a integer, float,
 	          or string and are converted as follows:
@@ -240,13 +404,48 @@ The functions available are:
 	            decimal (float):  time in seconds 1970-01-01 UTC
 	            string:           assumed to be formated as RFC3339 unless a
 	                              format argument is provided
+	          The format can be a golang time layout, the name of a golang
+	          layout constant such as RFC1123, or a strftime format such as
+	          "%Y-%m-%d %H:%M:%S".
 
 	   time?: Returns true if the single required argumement is a time
 	          otherwise false is returned.
 
+	 timeadd: Adds one or more durations to the first time argument. The
+	          time can be a time, integer nanoseconds since 1970-01-01 UTC,
+	          or an RFC3339 string. A duration can be integer nanoseconds,
+	          float seconds, a golang duration string such as "-1h30m", or
+	          an ISO 8601 duration such as "P1M2DT3H". Years, months, and
+	          days in ISO 8601 durations are added as calendar units.
+
+	timediff: Returns the duration of the first time argument minus the
+	          second time argument as integer nanoseconds. The optional third
+	          argument changes the result to a float in the units of ns, us,
+	          ms, s, m, h, or d (24 hours), or to a string if iso (ISO 8601)
+	          or go (golang duration) is given.
+
+	 timefmt: Formats the first time argument as a string. The optional
+	          second argument is the format which can be a golang time
+	          layout, the name of a golang layout constant such as RFC1123,
+	          or a strftime format such as "%Y-%m-%d". If not provided
+	          RFC3339Nano is used.
+
+	timeround: Rounds the first time argument to the nearest multiple of the
+	          second argument which can be a calendar unit or duration as
+	          described for timetrunc. Halfway values are rounded up.
+
+	timetrunc: Truncates the first time argument to a multiple of the second
+	          argument. The second argument can be one of the calendar units
+	          year, month, week (starting on Monday), day, hour, minute, or
+	          second which truncate in the location of the time, or it can
+	          be a duration as described for timeadd.
+
 	   title: Convert a string to capitalized string. There must be exactly
 	          one string argument.
 
+	toentries: Returns an array of {key: k, value: v} objects for each member
+	          of the single object argument. The entries are sorted by key.
+
 	 tolower: Convert a string to lowercase. There must be exactly one
 	          string argument.
 
@@ -256,6 +455,17 @@ The functions available are:
 	    trim: Trim white space from both ends of a string unless a second
 	          argument provides an alternative cut set.
 
+	urldecode: Reverses the escaping of urlencode on the single string
+	          argument. An error is raised if the string is not a valid
+	          escaped string.
+
+	urlencode: Escapes the single string argument so it can be safely placed
+	          in a URL query.
+
+	 weekday: Returns the day of the week of the single time argument as an
+	          integer where Sunday is 0 and Saturday is 6. Use timefmt with
+	          a %A format to get the name of the day.
+
 	    zone: Changes the timezone on a time to the location specified in the
 	          second argument. Raises an error if the first argument does not
 	          evaluate to a time or the location can not be determined.
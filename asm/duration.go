@@ -0,0 +1,31 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"fmt"
+)
+
+func init() {
+	Define(&Fn{
+		Name: "duration",
+		Eval: duration,
+		Desc: `Converts the first argument to a duration in integer
+nanoseconds. The argument can be integer nanoseconds, float
+seconds, a golang duration string such as "1h30m", or an ISO
+8601 duration such as "PT1H30M" where years, months, and days
+are taken to be 365, 30, and 1 days of 24 hours. The optional
+second argument changes the result as with timediff.`,
+	})
+}
+
+func duration(root map[string]any, at any, args ...any) any {
+	if len(args) < 1 || 2 < len(args) {
+		panic(fmt.Errorf("duration expects one or two arguments. %d given", len(args)))
+	}
+	d := durationArg("duration", root, at, args[0]).Approx()
+	if len(args) < 2 {
+		return int64(d)
+	}
+	return durationAs("duration", d, stringArg("duration", "unit ", root, at, args[1]))
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestDuration(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [duration "1h30m"]]
+           [set $.asm.b [duration "PT1H30M" m]]
+           [set $.asm.c [duration "P1D" h]]
+           [set $.asm.d [duration 90.5 iso]]
+           [set $.asm.e [duration 1500000000 go]]
+         ]`,
+		"{src: []}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: 5400000000000
+  b: 90
+  c: 24
+  d: PT1M30.5S
+  e: "1.5s"
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestDurationArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"duration"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestDurationArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"duration", true},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
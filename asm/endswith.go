@@ -0,0 +1,27 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	Define(&Fn{
+		Name: "endswith",
+		Eval: endswith,
+		Desc: `Returns true if the first string argument ends with the second
+string argument.`,
+	})
+}
+
+func endswith(root map[string]any, at any, args ...any) any {
+	if len(args) != 2 {
+		panic(fmt.Errorf("endswith expects exactly two arguments. %d given", len(args)))
+	}
+	s := stringArg("endswith", "", root, at, args[0])
+	suffix := stringArg("endswith", "second ", root, at, args[1])
+
+	return strings.HasSuffix(s, suffix)
+}
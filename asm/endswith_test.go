@@ -0,0 +1,44 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestEndswith(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [endswith abcdef abc]]
+           [set $.asm.b [endswith abcdef def]]
+         ]`,
+		"{src: []}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: false
+  b: true
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestEndswithArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"endswith", "x"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestEndswithArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"endswith", 1, "x"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
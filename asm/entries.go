@@ -0,0 +1,79 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"fmt"
+	"sort"
+)
+
+func init() {
+	Define(&Fn{
+		Name: "toentries",
+		Eval: toentries,
+		Desc: `Returns an array of {key: k, value: v} objects for each member
+of the single object argument. The entries are sorted by key.`,
+	})
+	Define(&Fn{
+		Name: "fromentries",
+		Eval: fromentries,
+		Desc: `Returns an object built from the single array argument. Each
+element of the array must be either an object with a key and
+value member or an array of a key and a value.`,
+	})
+}
+
+func toentries(root map[string]any, at any, args ...any) any {
+	if len(args) != 1 {
+		panic(fmt.Errorf("toentries expects exactly one argument. %d given", len(args)))
+	}
+	v := evalArg(root, at, args[0])
+	obj, ok := v.(map[string]any)
+	if !ok {
+		panic(fmt.Errorf("toentries expects an object argument, not a %T", v))
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	list := make([]any, 0, len(keys))
+	for _, k := range keys {
+		list = append(list, map[string]any{"key": k, "value": dupValue(obj[k])})
+	}
+	return list
+}
+
+func fromentries(root map[string]any, at any, args ...any) any {
+	if len(args) != 1 {
+		panic(fmt.Errorf("fromentries expects exactly one argument. %d given", len(args)))
+	}
+	v := evalArg(root, at, args[0])
+	list, ok := v.([]any)
+	if !ok {
+		panic(fmt.Errorf("fromentries expects an array argument, not a %T", v))
+	}
+	result := make(map[string]any, len(list))
+	for _, e := range list {
+		var key, val any
+		switch te := e.(type) {
+		case map[string]any:
+			key = te["key"]
+			val = te["value"]
+		case []any:
+			if len(te) != 2 {
+				panic(fmt.Errorf("fromentries expects array entries of a key and a value"))
+			}
+			key = te[0]
+			val = te[1]
+		default:
+			panic(fmt.Errorf("fromentries expects object or array entries, not a %T", e))
+		}
+		k, ok := key.(string)
+		if !ok {
+			panic(fmt.Errorf("fromentries expects string keys, not a %T", key))
+		}
+		result[k] = dupValue(val)
+	}
+	return result
+}
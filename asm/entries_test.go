@@ -0,0 +1,47 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestEntries(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [toentries {b: 2 a: [1]}]]
+           [set $.asm.b [fromentries [list [quote {key: a value: 1}] [list b 2]]]]
+           [set $.asm.c [fromentries [toentries $.src.x]]]
+         ]`,
+		"{src: {x: {a: 1 b: {c: 2}}}}",
+	)
+	tt.Equal(t, `{a:[{key:a value:[1]}{key:b value:2}] b:{a:1 b:2} c:{a:1 b:{c:2}}}`, sen.String(root["asm"], &sopt))
+}
+
+func TestEntriesArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"toentries"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestEntriesArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"toentries", 1},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestEntriesFromArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"fromentries", true},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"fmt"
+)
+
+func init() {
+	Define(&Fn{
+		Name:    "extract",
+		Eval:    extract,
+		Compile: compileRegex,
+		Desc: `Returns the capture groups of the first match of the regular
+expression second argument against the first string argument
+as an array. If the regular expression has no capture groups
+then the whole match is the only element. If the optional third
+argument is an integer then only that group is returned with 0
+being the whole match. If there is no match nil is returned.
+A string pattern is always a literal even if it starts with $
+or @. Use a function such as get for a pattern from the data.`,
+	})
+}
+
+func extract(root map[string]any, at any, args ...any) any {
+	if len(args) < 2 || 3 < len(args) {
+		panic(fmt.Errorf("extract expects two or three arguments. %d given", len(args)))
+	}
+	s := stringArg("extract", "", root, at, args[0])
+	rx := regexArg("extract", root, at, args[1])
+	group := int64(-1)
+	if 2 < len(args) {
+		v := evalArg(root, at, args[2])
+		var ok bool
+		if group, ok = asInt(v); !ok {
+			panic(fmt.Errorf("extract expects an integer group argument, not a %T", v))
+		}
+	}
+	matches := rx.FindStringSubmatch(s)
+	if matches == nil {
+		return nil
+	}
+	if 0 <= group {
+		if int64(len(matches)) <= group {
+			return nil
+		}
+		return matches[group]
+	}
+	if 1 < len(matches) {
+		matches = matches[1:]
+	}
+	list := make([]any, len(matches))
+	for i, m := range matches {
+		list[i] = m
+	}
+	return list
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestExtract(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [extract "id-123-xyz" "(\\d+)-(\\w+)"]]
+           [set $.asm.b [extract "id-123-xyz" "\\d+"]]
+           [set $.asm.c [extract "id-123-xyz" "(\\d+)-(\\w+)" 2]]
+           [set $.asm.d [extract "id-123-xyz" "(\\d+)-(\\w+)" 0]]
+           [set $.asm.e [extract "id-123-xyz" "(\\d+)" 3]]
+           [set $.asm.f [extract abc "\\d+"]]
+         ]`,
+		"{src: []}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: [
+    "123"
+    xyz
+  ]
+  b: [
+    "123"
+  ]
+  c: xyz
+  d: "123-xyz"
+  e: null
+  f: null
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestExtractArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"extract", "x"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestExtractGroupType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"extract", "x", "x", "x"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
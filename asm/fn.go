@@ -73,27 +73,38 @@ func (f *Fn) compile() {
 	if f.Compile != nil {
 		f.Compile(f)
 	} else {
-		for i, a := range f.Args {
-			if list, _ := a.([]any); 0 < len(list) {
-				if name, _ := list[0].(string); 0 < len(name) {
-					if af := NewFn(name); af != nil {
-						af.Args = list[1:]
-						af.compile()
-						f.Args[i] = af
-					}
-				}
-			} else if str, _ := a.(string); 0 < len(str) && (str[0] == '$' || str[0] == '@') {
-				if x, err := jp.Parse([]byte(str)); err == nil {
-					f.Args[i] = x
+		compileArgs(f)
+	}
+	f.compiled = true
+}
+
+// compileArgs is the default compile behavior. Function arguments are
+// converted to functions and path strings are converted to jp.Expr.
+func compileArgs(f *Fn) {
+	for i, a := range f.Args {
+		if list, _ := a.([]any); 0 < len(list) {
+			if name, _ := list[0].(string); 0 < len(name) {
+				if af := NewFn(name); af != nil {
+					af.Args = list[1:]
+					af.compile()
+					f.Args[i] = af
 				}
 			}
+		} else if str, _ := a.(string); 0 < len(str) && (str[0] == '$' || str[0] == '@') {
+			if x, err := jp.Parse([]byte(str)); err == nil {
+				f.Args[i] = x
+			}
 		}
 	}
-	f.compiled = true
 }
 
+// evalArg evaluates an argument. Literal objects and arrays are copied so
+// that modifications to the returned value do not change the plan itself
+// which allows a plan to be executed repeatedly and concurrently.
 func evalArg(root map[string]any, at, arg any) (val any) {
 	switch ta := arg.(type) {
+	case map[string]any, []any:
+		val = dupValue(ta)
 	case *Fn:
 		val = ta.Eval(root, at, ta.Args...)
 	case jp.Expr:
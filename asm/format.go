@@ -0,0 +1,29 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"fmt"
+)
+
+func init() {
+	Define(&Fn{
+		Name: "format",
+		Eval: format,
+		Desc: `Formats the remaining arguments according to the first string
+argument which is a golang fmt package format such as
+[format "%s-%04d" abc 12] which returns "abc-0012".`,
+	})
+}
+
+func format(root map[string]any, at any, args ...any) any {
+	if len(args) < 1 {
+		panic(fmt.Errorf("format expects at least one argument. %d given", len(args)))
+	}
+	f := stringArg("format", "format ", root, at, args[0])
+	vals := make([]any, 0, len(args)-1)
+	for _, a := range args[1:] {
+		vals = append(vals, evalArg(root, at, a))
+	}
+	return fmt.Sprintf(f, vals...)
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestFormat(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [format "%s-%04d" abc 12]]
+           [set $.asm.b [format "%.2f%%" 12.345]]
+           [set $.asm.c [format "%v/%v" $.src.a $.src.b]]
+           [set $.asm.d [format plain]]
+         ]`,
+		"{src: {a: 1 b: true}}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: abc-0012
+  b: "12.35%"
+  c: "1/true"
+  d: plain
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestFormatArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"format"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestFormatArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"format", 1},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"fmt"
+)
+
+func init() {
+	Define(&Fn{
+		Name:    "gsub",
+		Eval:    gsub,
+		Compile: compileRegex,
+		Desc: `Replaces all matches of the regular expression second argument
+in the first string argument with the third string argument.
+The replacement can refer to capture groups with $1 or ${name}.
+A string pattern is always a literal even if it starts with $
+or @. Use a function such as get for a pattern from the data.`,
+	})
+}
+
+func gsub(root map[string]any, at any, args ...any) any {
+	if len(args) != 3 {
+		panic(fmt.Errorf("gsub expects exactly three arguments. %d given", len(args)))
+	}
+	s := stringArg("gsub", "", root, at, args[0])
+	rx := regexArg("gsub", root, at, args[1])
+	rep := stringArg("gsub", "third ", root, at, args[2])
+
+	return rx.ReplaceAllString(s, rep)
+}
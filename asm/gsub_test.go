@@ -0,0 +1,46 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestGsub(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [gsub "a1b22c333" "\\d+" "#"]]
+           [set $.asm.b [gsub "john smith" "(\\w+) (\\w+)" "$2, $1"]]
+           [set $.asm.c [gsub "key=val" "(?P<k>\\w+)=(?P<v>\\w+)" "${v}=${k}"]]
+         ]`,
+		"{src: []}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: "a#b#c#"
+  b: "smith, john"
+  c: "val=key"
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestGsubArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"gsub", "x", "x"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestGsubRepType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"gsub", "x", "x", 1},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
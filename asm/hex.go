@@ -0,0 +1,42 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+func init() {
+	Define(&Fn{
+		Name: "hexencode",
+		Eval: hexencode,
+		Desc: `Returns the lowercase hexadecimal encoding of the single string
+argument.`,
+	})
+	Define(&Fn{
+		Name: "hexdecode",
+		Eval: hexdecode,
+		Desc: `Returns the decoded string of the single hexadecimal encoded
+string argument. An error is raised if the string is not valid
+hexadecimal.`,
+	})
+}
+
+func hexencode(root map[string]any, at any, args ...any) any {
+	if len(args) != 1 {
+		panic(fmt.Errorf("hexencode expects exactly one argument. %d given", len(args)))
+	}
+	return hex.EncodeToString([]byte(bytesArg("hexencode", root, at, args[0])))
+}
+
+func hexdecode(root map[string]any, at any, args ...any) any {
+	if len(args) != 1 {
+		panic(fmt.Errorf("hexdecode expects exactly one argument. %d given", len(args)))
+	}
+	b, err := hex.DecodeString(stringArg("hexdecode", "", root, at, args[0]))
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
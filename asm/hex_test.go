@@ -0,0 +1,44 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestHex(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [hexencode "hi!"]]
+           [set $.asm.b [hexdecode "686921"]]
+         ]`,
+		"{src: []}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: "686921"
+  b: "hi!"
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestHexArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"hexencode", "x", "y"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestHexArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"hexencode", 1},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
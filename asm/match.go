@@ -0,0 +1,30 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"fmt"
+)
+
+func init() {
+	Define(&Fn{
+		Name:    "match",
+		Eval:    match,
+		Compile: compileRegex,
+		Desc: `Returns true if the first string argument matches the regular
+expression that is the second argument. The regular expression
+syntax is that of the golang regexp package.
+A string pattern is always a literal even if it starts with $
+or @. Use a function such as get for a pattern from the data.`,
+	})
+}
+
+func match(root map[string]any, at any, args ...any) any {
+	if len(args) != 2 {
+		panic(fmt.Errorf("match expects exactly two arguments. %d given", len(args)))
+	}
+	s := stringArg("match", "", root, at, args[0])
+	rx := regexArg("match", root, at, args[1])
+
+	return rx.MatchString(s)
+}
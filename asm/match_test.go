@@ -0,0 +1,88 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestMatch(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [match abc123 "^[a-z]+\\d+$"]]
+           [set $.asm.b [match abc "^\\d+$"]]
+           [set $.asm.c [match $.src.id "b+"]]
+           [set $.asm.d [match "" "$^"]]
+           [set $.asm.e [match "a@b" "@b$"]]
+           [set $.asm.f [match $.src.id "$.src.id"]]
+           [set $.asm.g [match $.src.id [get "$.src.pat"]]]
+         ]`,
+		"{src: {id: abbbc pat: \"^ab+c$\"}}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: true
+  b: false
+  c: true
+  d: true
+  e: true
+  f: false
+  g: true
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestMatchArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"match", "x"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestMatchArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"match", 1, "x"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestMatchPatternType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"match", "x", 1},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestMatchBadPattern(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"match", "x", "(x"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestMatchCompiledPattern(t *testing.T) {
+	p := asm.NewPlan([]any{
+		"asm",
+		[]any{"set", "$.asm.a", []any{"match", "$.src.id", "^a+b$"}},
+		[]any{"set", "$.asm.b", []any{"match", "$.src.id", []any{"get", "$.src.pattern"}}},
+	})
+	// The literal pattern is compiled once but still displays as written.
+	tt.Equal(t, `[asm [set $.asm.a [match $.src.id ^a+b$]][set $.asm.b [match $.src.id [get $.src.pattern]]]]`, p.String())
+	for _, id := range []string{"aab", "b"} {
+		root := map[string]any{"src": map[string]any{"id": id, "pattern": "^b$"}}
+		err := p.Execute(root)
+		tt.Nil(t, err)
+		asm := root["asm"].(map[string]any)
+		tt.Equal(t, id == "aab", asm["a"])
+		tt.Equal(t, id == "b", asm["b"])
+	}
+}
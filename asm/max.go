@@ -0,0 +1,18 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+func init() {
+	Define(&Fn{
+		Name: "max",
+		Eval: maxEval,
+		Desc: `Returns the maximum of all arguments. All arguments must be
+numbers or arrays of numbers. If any argument is a float then
+the result will be a float. Big numbers are compared exactly.
+If no numbers are given nil is returned.`,
+	})
+}
+
+func maxEval(root map[string]any, at any, args ...any) any {
+	return extreme("max", root, at, args, 1)
+}
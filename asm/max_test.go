@@ -0,0 +1,40 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestMax(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [max 3 1 2]]
+           [set $.asm.b [max 3 4.5 2]]
+           [set $.asm.c [max [list 4 -2 7] 1]]
+           [set $.asm.e [max]]
+         ]`,
+		"{}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: 3
+  b: 4.5
+  c: 7
+  e: null
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestMaxArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"max", true},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
@@ -0,0 +1,18 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+func init() {
+	Define(&Fn{
+		Name: "median",
+		Eval: median,
+		Desc: `Returns the median of all arguments. All arguments must be
+numbers or arrays of numbers. If there are an even number of
+values the result is the average of the middle two values as
+a float. If no numbers are given nil is returned.`,
+	})
+}
+
+func median(root map[string]any, at any, args ...any) any {
+	return rank(numberArgs("median", root, at, args), 0.5)
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestMedian(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [median 3 1 2]]
+           [set $.asm.b [median [list 4 1 3 2]]]
+           [set $.asm.c [median 1.5 0.5 1]]
+           [set $.asm.d [median]]
+         ]`,
+		"{src: []}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: 2
+  b: 2.5
+  c: 1
+  d: null
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestMedianArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"median", "x"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"fmt"
+)
+
+func init() {
+	Define(&Fn{
+		Name: "merge",
+		Eval: merge,
+		Desc: `Returns a new object that is the deep merge of all the object
+arguments. Values from later arguments replace those from
+earlier arguments unless both values are objects in which case
+they are merged. Null arguments are ignored. The arguments are
+not modified.`,
+	})
+}
+
+func merge(root map[string]any, at any, args ...any) any {
+	result := map[string]any{}
+	for _, arg := range args {
+		switch v := evalArg(root, at, arg).(type) {
+		case nil:
+			// ignore
+		case map[string]any:
+			mergeInto(result, v)
+		default:
+			panic(fmt.Errorf("merge expects object arguments, not a %T", v))
+		}
+	}
+	return result
+}
+
+func mergeInto(dest, src map[string]any) {
+	for k, v := range src {
+		if sm, ok := v.(map[string]any); ok {
+			if dm, ok := dest[k].(map[string]any); ok {
+				mergeInto(dm, sm)
+				continue
+			}
+		}
+		dest[k] = dupValue(v)
+	}
+}
+
+// dupValue makes a deep copy of the maps and slices in a value so that the
+// returned value can be modified without changing the original.
+func dupValue(v any) any {
+	switch tv := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(tv))
+		for k, mv := range tv {
+			m[k] = dupValue(mv)
+		}
+		return m
+	case []any:
+		list := make([]any, len(tv))
+		for i, lv := range tv {
+			list[i] = dupValue(lv)
+		}
+		return list
+	}
+	return v
+}
@@ -0,0 +1,32 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestMerge(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [merge {a: 1 b: {c: 2 d: 3}} {b: {c: 4 e: [5]}} null {f: 6}]]
+           [set $.asm.b [merge $.src.x $.src.y]]
+           [set $.asm.c [merge]]
+           [set $.src.x.b.c 9]
+         ]`,
+		"{src: {x: {a: 1 b: {c: 2}} y: {b: {d: 3}}}}",
+	)
+	tt.Equal(t, `{a:{a:1 b:{c:4 d:3 e:[5]} f:6} b:{a:1 b:{c:2 d:3}} c:{}}`, sen.String(root["asm"], &sopt))
+}
+
+func TestMergeArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"merge", 1},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
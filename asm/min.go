@@ -0,0 +1,36 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+func init() {
+	Define(&Fn{
+		Name: "min",
+		Eval: minEval,
+		Desc: `Returns the minimum of all arguments. All arguments must be
+numbers or arrays of numbers. If any argument is a float then
+the result will be a float. Big numbers are compared exactly.
+If no numbers are given nil is returned.`,
+	})
+}
+
+func minEval(root map[string]any, at any, args ...any) any {
+	return extreme("min", root, at, args, -1)
+}
+
+// extreme returns the number that compares to all others with the dir
+// result.
+func extreme(name string, root map[string]any, at any, args []any, dir int) any {
+	nums := numberArgs(name, root, at, args)
+	if len(nums) == 0 {
+		return nil
+	}
+	kind := widest(nums)
+	result := nums[0].promote(kind)
+	for _, n := range nums[1:] {
+		n = n.promote(kind)
+		if n.cmp(result) == dir {
+			result = n
+		}
+	}
+	return result.value()
+}
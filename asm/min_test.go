@@ -0,0 +1,42 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestMin(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [min 3 1 2]]
+           [set $.asm.b [min 3 1.5 2]]
+           [set $.asm.c [min [list 4 -2 7]]]
+           [set $.asm.d [min $.src.big 5]]
+           [set $.asm.f [min]]
+         ]`,
+		"{src: {big: 123456789012345678901234567890}}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: 1
+  b: 1.5
+  c: -2
+  d: 5
+  f: null
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestMinArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"min", "x"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
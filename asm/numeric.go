@@ -0,0 +1,230 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/khaf/ojg/gen"
+)
+
+// bigPrec is the precision in bits used for big number calculations.
+const bigPrec = 256
+
+// The kinds of number are ordered from narrowest to widest so that the kind
+// of a calculation is the maximum of the kinds of the arguments, just as the
+// sum function promotes integers to floats.
+const (
+	intNum = iota
+	floatNum
+	bigNum
+)
+
+// number is a numeric argument to one of the math functions. Only the field
+// that matches the kind is valid.
+type number struct {
+	kind int
+	i    int64
+	f    float64
+	b    *big.Float
+}
+
+// asNumber converts a value to a number. Integer, float, gen.Big, and
+// json.Number values are accepted. Big values that fit in an int64 are
+// treated as integers and unsigned integers that do not fit are treated as
+// big numbers.
+func asNumber(v any) (n number, ok bool) {
+	ok = true
+	switch tv := v.(type) {
+	case int, int8, int16, int32, int64, uint8, uint16, uint32:
+		n.i, _ = asInt(v)
+	case uint:
+		n = uintNumber(uint64(tv))
+	case uint64:
+		n = uintNumber(tv)
+	case float32, float64:
+		n.kind = floatNum
+		n.f, _ = asFloat(v)
+	case gen.Big:
+		n, ok = parseBigNumber(string(tv))
+	case json.Number:
+		n, ok = parseBigNumber(string(tv))
+	default:
+		ok = false
+	}
+	return
+}
+
+// uintNumber converts an unsigned integer to a number. Values too large for
+// an int64 become big numbers instead of wrapping negative.
+func uintNumber(u uint64) number {
+	if u <= math.MaxInt64 {
+		return number{i: int64(u)}
+	}
+	return number{kind: bigNum, b: new(big.Float).SetPrec(bigPrec).SetUint64(u)}
+}
+
+func parseBigNumber(s string) (n number, ok bool) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return number{i: i}, true
+	}
+	if b, _, err := big.ParseFloat(s, 10, bigPrec, big.ToNearestEven); err == nil {
+		return number{kind: bigNum, b: b}, true
+	}
+	return
+}
+
+// numberArg evaluates an argument and converts it to a number or raises an
+// error naming the function.
+func numberArg(name string, root map[string]any, at, arg any) number {
+	v := evalArg(root, at, arg)
+	n, ok := asNumber(v)
+	if !ok {
+		panic(fmt.Errorf("%s expects number arguments, not a %T", name, v))
+	}
+	return n
+}
+
+// numberArgs evaluates all the arguments and converts them to numbers. Array
+// arguments are expanded so that a function can be called with either a
+// list of numbers or an array of numbers.
+func numberArgs(name string, root map[string]any, at any, args []any) (nums []number) {
+	for _, arg := range args {
+		v := evalArg(root, at, arg)
+		if list, ok := v.([]any); ok {
+			for _, item := range list {
+				n, ok := asNumber(item)
+				if !ok {
+					panic(fmt.Errorf("%s expects number arguments, not a %T", name, item))
+				}
+				nums = append(nums, n)
+			}
+			continue
+		}
+		n, ok := asNumber(v)
+		if !ok {
+			panic(fmt.Errorf("%s expects number arguments, not a %T", name, v))
+		}
+		nums = append(nums, n)
+	}
+	return
+}
+
+// widest returns the widest kind of all the numbers.
+func widest(nums []number) (kind int) {
+	for _, n := range nums {
+		if kind < n.kind {
+			kind = n.kind
+		}
+	}
+	return
+}
+
+// promote a number to the specified kind. Numbers are never demoted.
+func (n number) promote(kind int) number {
+	if kind <= n.kind {
+		return n
+	}
+	switch kind {
+	case floatNum:
+		n.f = float64(n.i)
+	case bigNum:
+		n.b = n.bigFloat()
+	}
+	n.kind = kind
+	return n
+}
+
+// asFloat64 returns the number as a float64 regardless of kind.
+func (n number) asFloat64() (f float64) {
+	switch n.kind {
+	case intNum:
+		f = float64(n.i)
+	case floatNum:
+		f = n.f
+	default:
+		f, _ = n.b.Float64()
+	}
+	return
+}
+
+// bigFloat returns a new big.Float with the value of the number.
+func (n number) bigFloat() *big.Float {
+	b := new(big.Float).SetPrec(bigPrec)
+	switch n.kind {
+	case intNum:
+		b.SetInt64(n.i)
+	case floatNum:
+		b.SetFloat64(n.f)
+	default:
+		b.Set(n.b)
+	}
+	return b
+}
+
+// cmp compares two numbers of the same kind returning -1, 0, or 1.
+func (n number) cmp(n2 number) int {
+	switch n.kind {
+	case intNum:
+		switch {
+		case n.i < n2.i:
+			return -1
+		case n2.i < n.i:
+			return 1
+		}
+		return 0
+	case floatNum:
+		switch {
+		case n.f < n2.f:
+			return -1
+		case n2.f < n.f:
+			return 1
+		}
+		return 0
+	}
+	return n.b.Cmp(n2.b)
+}
+
+// value returns the number as an int64, float64, or json.Number for big
+// numbers. An error is raised if a float is not a valid JSON number.
+func (n number) value() any {
+	switch n.kind {
+	case intNum:
+		return n.i
+	case floatNum:
+		if math.IsNaN(n.f) || math.IsInf(n.f, 0) {
+			panic(fmt.Errorf("%g is not a valid number", n.f))
+		}
+		return n.f
+	}
+	return bigValue(n.b)
+}
+
+// bigValue converts a big.Float to an int64 if it fits otherwise to a
+// json.Number formatted without an exponent.
+func bigValue(b *big.Float) any {
+	if b.IsInt() {
+		if i, acc := b.Int64(); acc == big.Exact {
+			return i
+		}
+		return json.Number(b.Text('f', 0))
+	}
+	return json.Number(b.Text('f', -1))
+}
+
+// bigPow10 returns 10^x as a big.Float.
+func bigPow10(x int) *big.Float {
+	neg := x < 0
+	if neg {
+		x = -x
+	}
+	p := new(big.Float).SetPrec(bigPrec).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(x)), nil))
+	if neg {
+		p.Quo(new(big.Float).SetPrec(bigPrec).SetInt64(1), p)
+	}
+	return p
+}
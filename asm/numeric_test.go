@@ -0,0 +1,76 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/tt"
+)
+
+// TestBigNumbers verifies the big number contract for the math functions.
+// Results that do not fit in an int64 or float64 are json.Number values.
+func TestBigNumbers(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.abs [abs $.src.neg]]
+           [set $.asm.avg [avg $.src.big 0]]
+           [set $.asm.max [max $.src.huge 5]]
+           [set $.asm.min [min $.src.huge 1e30]]
+           [set $.asm.percentile [percentile [list $.src.big 100000000000000000002] 50]]
+           [set $.asm.pow [pow 10 20]]
+           [set $.asm.powBig [pow $.src.big 2]]
+           [set $.asm.round [round $.src.frac 2]]
+           [set $.asm.floor [floor $.src.frac 1]]
+           [set $.asm.ceil [ceil $.src.negFrac 1]]
+           [set $.asm.sqrt [sqrt 10000000000000000000000000000000000000000]]
+         ]`,
+		`{src: {
+            big: 100000000000000000000
+            huge: 123456789012345678901234567890
+            neg: -123456789012345678901234567890
+            frac: 12345678901234567890.125
+            negFrac: -12345678901234567890.125
+          }}`,
+	)
+	for _, x := range []struct {
+		key    string
+		expect any
+	}{
+		{key: "abs", expect: json.Number("123456789012345678901234567890")},
+		{key: "avg", expect: json.Number("50000000000000000000")},
+		{key: "max", expect: json.Number("123456789012345678901234567890")},
+		{key: "min", expect: json.Number("123456789012345678901234567890")},
+		{key: "percentile", expect: json.Number("100000000000000000001")},
+		{key: "pow", expect: json.Number("100000000000000000000")},
+		{key: "powBig", expect: json.Number("10000000000000000000000000000000000000000")},
+		{key: "round", expect: json.Number("12345678901234567890.13")},
+		{key: "floor", expect: json.Number("12345678901234567890.1")},
+		{key: "ceil", expect: json.Number("-12345678901234567890.1")},
+		{key: "sqrt", expect: json.Number("100000000000000000000")},
+	} {
+		tt.Equal(t, x.expect, asmValue(root, x.key), x.key)
+	}
+}
+
+func TestBigNumbersUint64(t *testing.T) {
+	p := asm.NewPlan([]any{
+		"asm",
+		[]any{"set", "$.asm.max", []any{"max", "$.src.u", 1}},
+		[]any{"set", "$.asm.small", []any{"max", "$.src.small", 1}},
+	})
+	root := map[string]any{
+		"src": map[string]any{"u": uint64(math.MaxUint64), "small": uint64(7)},
+	}
+	err := p.Execute(root)
+	tt.Nil(t, err)
+	tt.Equal(t, json.Number("18446744073709551615"), asmValue(root, "max"))
+	tt.Equal(t, int64(7), asmValue(root, "small"))
+}
+
+func asmValue(root map[string]any, key string) any {
+	return root["asm"].(map[string]any)[key]
+}
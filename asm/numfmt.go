@@ -0,0 +1,85 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	Define(&Fn{
+		Name: "numfmt",
+		Eval: numfmt,
+		Desc: `Formats a number as a string. The optional second integer
+argument is the number of decimal places to include. If not
+provided or negative the minimum number of digits needed are
+used. The optional third string argument is inserted between
+groups of three digits in the integer part of the number as
+in [numfmt 1234.5 2 ","] which returns "1,234.50".`,
+	})
+}
+
+func numfmt(root map[string]any, at any, args ...any) any {
+	if len(args) < 1 || 3 < len(args) {
+		panic(fmt.Errorf("numfmt expects one to three arguments. %d given", len(args)))
+	}
+	n := numberArg("numfmt", root, at, args[0])
+	prec := int64(-1)
+	if 1 < len(args) {
+		v := evalArg(root, at, args[1])
+		var ok bool
+		if prec, ok = asInt(v); !ok {
+			panic(fmt.Errorf("numfmt expects an integer precision, not a %T", v))
+		}
+	}
+	var sep string
+	if 2 < len(args) {
+		v := evalArg(root, at, args[2])
+		var ok bool
+		if sep, ok = v.(string); !ok {
+			panic(fmt.Errorf("numfmt expects a string separator, not a %T", v))
+		}
+	}
+	var s string
+	switch n.kind {
+	case intNum:
+		s = strconv.FormatInt(n.i, 10)
+		if 0 < prec {
+			s += "." + strings.Repeat("0", int(prec))
+		}
+	case floatNum:
+		s = strconv.FormatFloat(n.f, 'f', int(prec), 64)
+	default:
+		s = n.b.Text('f', int(prec))
+	}
+	if 0 < len(sep) {
+		s = groupDigits(s, sep)
+	}
+	return s
+}
+
+// groupDigits inserts the separator between every three digits of the
+// integer part of a formatted number.
+func groupDigits(s, sep string) string {
+	var sign string
+	if 0 < len(s) && s[0] == '-' {
+		sign = "-"
+		s = s[1:]
+	}
+	end := strings.IndexByte(s, '.')
+	if end < 0 {
+		end = len(s)
+	}
+	var b strings.Builder
+	b.WriteString(sign)
+	for i := 0; i < end; i++ {
+		if 0 < i && (end-i)%3 == 0 {
+			b.WriteString(sep)
+		}
+		b.WriteByte(s[i])
+	}
+	b.WriteString(s[end:])
+	return b.String()
+}
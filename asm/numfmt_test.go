@@ -0,0 +1,68 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestNumfmt(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [numfmt 1234.5 2 ","]]
+           [set $.asm.b [numfmt -1234567 -1 ","]]
+           [set $.asm.c [numfmt 12 2]]
+           [set $.asm.d [numfmt 0.125]]
+           [set $.asm.e [numfmt 999 0 ","]]
+           [set $.asm.f [numfmt $.src.big 1 " "]]
+         ]`,
+		"{src: {big: 12345678901234567890.25}}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: "1,234.50"
+  b: "-1,234,567"
+  c: "12.00"
+  d: "0.125"
+  e: "999"
+  f: "12 345 678 901 234 567 890.2"
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestNumfmtArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"numfmt", 1, 2, 3, 4},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestNumfmtArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"numfmt", "x"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestNumfmtPrecType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"numfmt", 1, "x"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestNumfmtSepType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"numfmt", 1, 2, 3},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"fmt"
+)
+
+func init() {
+	Define(&Fn{
+		Name: "omit",
+		Eval: omit,
+		Desc: `Returns a copy of the first object argument without the members
+selected by the remaining arguments. The remaining arguments
+can be keys, arrays of keys, or local (@) paths relative to
+the first argument such as @.a[*].b which are removed using the
+jp.Remove() function.`,
+	})
+}
+
+func omit(root map[string]any, at any, args ...any) any {
+	if len(args) < 1 {
+		panic(fmt.Errorf("omit expects at least one argument. %d given", len(args)))
+	}
+	v := evalArg(root, at, args[0])
+	obj, ok := v.(map[string]any)
+	if !ok {
+		panic(fmt.Errorf("omit expects an object first argument, not a %T", v))
+	}
+	result, _ := dupValue(obj).(map[string]any)
+	for _, arg := range args[1:] {
+		if x, ok := localPath(arg); ok {
+			if _, err := x.Remove(result); err != nil {
+				panic(err)
+			}
+			continue
+		}
+		for _, key := range keysArg("omit", root, at, arg) {
+			delete(result, key)
+		}
+	}
+	return result
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestOmit(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [omit $.src.x a e]]
+           [set $.asm.b [omit $.src.x [list b e]]]
+           [set $.asm.c [omit $.src.x "@.b.d" "@.e[*].f"]]
+         ]`,
+		"{src: {x: {a: 1 b: {c: 2 d: 3} e: [{f: 4 g: 5}]}}}",
+	)
+	tt.Equal(t, `{a:{b:{c:2 d:3}} b:{a:1} c:{a:1 b:{c:2} e:[{g:5}]}}`, sen.String(root["asm"], &sopt))
+}
+
+func TestOmitArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"omit"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestOmitArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"omit", 1},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
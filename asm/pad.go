@@ -0,0 +1,61 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+func init() {
+	Define(&Fn{
+		Name: "lpad",
+		Eval: lpad,
+		Desc: `Pads the left side of the first string argument until it is as
+long as the second integer argument. The optional third string
+argument is used for padding instead of a space.`,
+	})
+	Define(&Fn{
+		Name: "rpad",
+		Eval: rpad,
+		Desc: `Pads the right side of the first string argument until it is as
+long as the second integer argument. The optional third string
+argument is used for padding instead of a space.`,
+	})
+}
+
+func lpad(root map[string]any, at any, args ...any) any {
+	s, fill := padding("lpad", root, at, args)
+	return fill + s
+}
+
+func rpad(root map[string]any, at any, args ...any) any {
+	s, fill := padding("rpad", root, at, args)
+	return s + fill
+}
+
+// padding returns the string argument and the padding to add to it.
+func padding(name string, root map[string]any, at any, args []any) (s, fill string) {
+	if len(args) < 2 || 3 < len(args) {
+		panic(fmt.Errorf("%s expects two or three arguments. %d given", name, len(args)))
+	}
+	s = stringArg(name, "", root, at, args[0])
+	v := evalArg(root, at, args[1])
+	width, ok := asInt(v)
+	if !ok {
+		panic(fmt.Errorf("%s expects an integer width argument, not a %T", name, v))
+	}
+	pad := " "
+	if 2 < len(args) {
+		if pad = stringArg(name, "padding ", root, at, args[2]); len(pad) == 0 {
+			panic(fmt.Errorf("%s padding can not be empty", name))
+		}
+	}
+	n := int(width) - utf8.RuneCountInString(s)
+	if n <= 0 {
+		return
+	}
+	fill = string([]rune(strings.Repeat(pad, n))[:n])
+	return
+}
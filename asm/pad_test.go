@@ -0,0 +1,58 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestPad(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [lpad "7" 3 "0"]]
+           [set $.asm.b [lpad abc 5]]
+           [set $.asm.c [rpad abc 6 "-="]]
+           [set $.asm.d [rpad abcdef 3]]
+           [set $.asm.e [lpad "é" 3 "*"]]
+         ]`,
+		"{src: []}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: "007"
+  b: "  abc"
+  c: "abc-=-"
+  d: abcdef
+  e: **é
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestPadArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"lpad", "x"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestPadWidthType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"lpad", "x", "x"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestPadPadEmpty(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"lpad", "x", 3, ""},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+)
+
+func init() {
+	Define(&Fn{
+		Name: "percentile",
+		Eval: percentile,
+		Desc: `Returns the value at the percentile given by the second
+argument of the array of numbers that is the first argument.
+The percentile must be a number from 0 to 100. When the
+percentile falls between two values the result is linearly
+interpolated as a float. If the array is empty nil is returned.`,
+	})
+}
+
+func percentile(root map[string]any, at any, args ...any) any {
+	if len(args) != 2 {
+		panic(fmt.Errorf("percentile expects exactly two arguments. %d given", len(args)))
+	}
+	v := evalArg(root, at, args[0])
+	if _, ok := v.([]any); !ok {
+		panic(fmt.Errorf("percentile expects an array first argument, not a %T", v))
+	}
+	nums := numberArgs("percentile", root, at, []any{v})
+	p := numberArg("percentile", root, at, args[1]).asFloat64()
+	if p < 0.0 || 100.0 < p {
+		panic(fmt.Errorf("percentile must be between 0 and 100, not %g", p))
+	}
+	return rank(nums, p/100.0)
+}
+
+// rank sorts the numbers and returns the value at the fractional position
+// pos which must be between 0.0 and 1.0.
+func rank(nums []number, pos float64) any {
+	if len(nums) == 0 {
+		return nil
+	}
+	kind := widest(nums)
+	for i, n := range nums {
+		nums[i] = n.promote(kind)
+	}
+	sort.Slice(nums, func(i, j int) bool { return nums[i].cmp(nums[j]) < 0 })
+	r := pos * float64(len(nums)-1)
+	lo := int(math.Floor(r))
+	frac := r - float64(lo)
+	if frac == 0.0 {
+		return nums[lo].value()
+	}
+	low := nums[lo]
+	high := nums[lo+1]
+	if kind == bigNum {
+		d := new(big.Float).SetPrec(bigPrec).Sub(high.b, low.b)
+		d.Mul(d, big.NewFloat(frac))
+		return bigValue(d.Add(d, low.b))
+	}
+	lf := low.asFloat64()
+	return lf + (high.asFloat64()-lf)*frac
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestPercentile(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [percentile [list 1 2 3 4 5] 50]]
+           [set $.asm.b [percentile [list 1 2 3 4] 50]]
+           [set $.asm.c [percentile [list 15 20 35 40 50] 0]]
+           [set $.asm.d [percentile [list 15 20 35 40 50] 100]]
+           [set $.asm.e [percentile [list 1.0 2.0] 25]]
+           [set $.asm.f [percentile [] 50]]
+         ]`,
+		"{}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: 3
+  b: 2.5
+  c: 15
+  d: 50
+  e: 1.25
+  f: null
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestPercentileArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"percentile", 1},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestPercentileArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"percentile", 1, 50},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestPercentileRange(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"percentile", []any{1, 2}, 101},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"fmt"
+
+	"github.com/khaf/ojg/jp"
+)
+
+func init() {
+	Define(&Fn{
+		Name: "pick",
+		Eval: pick,
+		Desc: `Returns a new object with only the selected members of the first
+object argument. The remaining arguments select members and can
+be keys, arrays of keys, or local (@) paths that are relative
+to the first argument such as @.a.b. Values selected by a path
+are placed at the same path in the new object.`,
+	})
+}
+
+func pick(root map[string]any, at any, args ...any) any {
+	if len(args) < 1 {
+		panic(fmt.Errorf("pick expects at least one argument. %d given", len(args)))
+	}
+	v := evalArg(root, at, args[0])
+	obj, ok := v.(map[string]any)
+	if !ok {
+		panic(fmt.Errorf("pick expects an object first argument, not a %T", v))
+	}
+	result := map[string]any{}
+	for _, arg := range args[1:] {
+		if x, ok := localPath(arg); ok {
+			if x.Has(obj) {
+				if err := x.SetOne(result, dupValue(x.First(obj))); err != nil {
+					panic(err)
+				}
+			}
+			continue
+		}
+		for _, key := range keysArg("pick", root, at, arg) {
+			if mv, has := obj[key]; has {
+				result[key] = dupValue(mv)
+			}
+		}
+	}
+	return result
+}
+
+// localPath returns the argument as a jp.Expr if it is a local (@) path.
+func localPath(arg any) (x jp.Expr, ok bool) {
+	if x, ok = arg.(jp.Expr); ok && 0 < len(x) {
+		_, ok = x[0].(jp.At)
+	}
+	return
+}
+
+// keysArg evaluates an argument that must be either a string key or an
+// array of string keys.
+func keysArg(name string, root map[string]any, at, arg any) []string {
+	switch v := evalArg(root, at, arg).(type) {
+	case string:
+		return []string{v}
+	case []any:
+		keys := make([]string, 0, len(v))
+		for _, k := range v {
+			key, ok := k.(string)
+			if !ok {
+				panic(fmt.Errorf("%s expects string keys, not a %T", name, k))
+			}
+			keys = append(keys, key)
+		}
+		return keys
+	default:
+		panic(fmt.Errorf("%s expects string keys, not a %T", name, v))
+	}
+}
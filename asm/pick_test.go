@@ -0,0 +1,56 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestPick(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [pick $.src.x a c]]
+           [set $.asm.b [pick $.src.x [list a b]]]
+           [set $.asm.c [pick $.src.x "@.b.d" "@.e[1]" "@.z"]]
+           [set $.asm.d [pick $.src.x $.src.keys]]
+         ]`,
+		"{src: {x: {a: 1 b: {c: 2 d: 3} e: [4 5 6]} keys: [e]}}",
+	)
+	tt.Equal(t, `{a:{a:1} b:{a:1 b:{c:2 d:3}} c:{b:{d:3} e:[null 5]} d:{e:[4 5 6]}}`, sen.String(root["asm"], &sopt))
+}
+
+func TestPickArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"pick"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestPickArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"pick", 1},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestPickKeyType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"pick", map[string]any{}, 1},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestPickKeyListType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"pick", map[string]any{}, []any{1}},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+func init() {
+	Define(&Fn{
+		Name: "pow",
+		Eval: pow,
+		Desc: `Returns the first argument raised to the power of the second
+argument. Both arguments must be numbers. An integer raised to
+a non-negative integer power is an integer or a big number if
+it does not fit in an integer. Otherwise the result is a float.
+An error is raised if an integer result would have more than
+65536 bits.`,
+	})
+}
+
+// maxPowBits is the maximum number of bits in an integer pow result.
+const maxPowBits = 65536
+
+func pow(root map[string]any, at any, args ...any) any {
+	if len(args) != 2 {
+		panic(fmt.Errorf("pow expects exactly two arguments. %d given", len(args)))
+	}
+	base := numberArg("pow", root, at, args[0])
+	exp := numberArg("pow", root, at, args[1])
+	switch {
+	case base.kind == intNum && exp.kind == intNum && 0 <= exp.i:
+		b := big.NewInt(base.i)
+		// The result has at least (bits - 1) * exp bits.
+		if bits := int64(b.BitLen()); 1 < bits && (maxPowBits/(bits-1)) < exp.i {
+			panic(fmt.Errorf("pow result of %d raised to %d is too large", base.i, exp.i))
+		}
+		r := b.Exp(b, big.NewInt(exp.i), nil)
+		if r.IsInt64() {
+			return r.Int64()
+		}
+		return json.Number(r.String())
+	case base.kind == bigNum && exp.kind == intNum:
+		return bigValue(bigPow(base.b, exp.i))
+	}
+	return number{kind: floatNum, f: math.Pow(base.asFloat64(), exp.asFloat64())}.value()
+}
+
+// bigPow raises b to an integer power by repeated squaring.
+func bigPow(b *big.Float, exp int64) *big.Float {
+	neg := exp < 0
+	if neg {
+		exp = -exp
+	}
+	result := new(big.Float).SetPrec(bigPrec).SetInt64(1)
+	x := new(big.Float).SetPrec(bigPrec).Set(b)
+	for ; 0 < exp; exp >>= 1 {
+		if exp&1 == 1 {
+			result.Mul(result, x)
+		}
+		x.Mul(x, x)
+	}
+	if neg {
+		result.Quo(new(big.Float).SetPrec(bigPrec).SetInt64(1), result)
+	}
+	return result
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestPow(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [pow 2 10]]
+           [set $.asm.b [pow 2 0.5]]
+           [set $.asm.c [pow 2 -1]]
+           [set $.asm.e [pow 1.5 2]]
+           [set $.asm.f [pow 3 50]]
+           [set $.asm.g [pow -1 9223372036854775807]]
+         ]`,
+		"{}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: 1024
+  b: 1.4142135623730951
+  c: 0.5
+  e: 2.25
+  f: 717897987691852588770249
+  g: -1
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestPowArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"pow", 2},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestPowArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"pow", 2, "x"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestPowTooLarge(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"pow", 2, 100000},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestPowNaN(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"pow", -1, 0.5},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
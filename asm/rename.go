@@ -0,0 +1,44 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"fmt"
+)
+
+func init() {
+	Define(&Fn{
+		Name: "rename",
+		Eval: rename,
+		Desc: `Returns a copy of the first object argument with keys renamed
+according to the second argument which must be an object that
+maps old key names to new key names such as {old: new}. Keys
+not in the mapping are unchanged.`,
+	})
+}
+
+func rename(root map[string]any, at any, args ...any) any {
+	if len(args) != 2 {
+		panic(fmt.Errorf("rename expects exactly two arguments. %d given", len(args)))
+	}
+	v := evalArg(root, at, args[0])
+	obj, ok := v.(map[string]any)
+	if !ok {
+		panic(fmt.Errorf("rename expects an object first argument, not a %T", v))
+	}
+	v = evalArg(root, at, args[1])
+	var names map[string]any
+	if names, ok = v.(map[string]any); !ok {
+		panic(fmt.Errorf("rename expects an object second argument, not a %T", v))
+	}
+	result := make(map[string]any, len(obj))
+	for k, mv := range obj {
+		if nv, has := names[k]; has {
+			if k, ok = nv.(string); !ok {
+				panic(fmt.Errorf("rename expects string new key names, not a %T", nv))
+			}
+		}
+		result[k] = dupValue(mv)
+	}
+	return result
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestRename(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [rename $.src.x {a: aa c: cc}]]
+         ]`,
+		"{src: {x: {a: 1 b: 2}}}",
+	)
+	tt.Equal(t, `{a:{aa:1 b:2}}`, sen.String(root["asm"], &sopt))
+}
+
+func TestRenameArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"rename", map[string]any{}},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestRenameArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"rename", 1, map[string]any{}},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestRenameMapType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"rename", map[string]any{}, 1},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestRenameNameType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"rename", map[string]any{"a": 1}, map[string]any{"a": 2}},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+func init() {
+	Define(&Fn{
+		Name: "round",
+		Eval: round,
+		Desc: `Rounds the first number argument to the nearest value with the
+number of decimal places given by the optional second integer
+argument. Halfway values are rounded away from zero. A negative
+precision rounds to tens, hundreds, and so on. The result has
+the same type as the first argument.`,
+	})
+	Define(&Fn{
+		Name: "floor",
+		Eval: floor,
+		Desc: `Returns the greatest value less than or equal to the first
+number argument with the number of decimal places given by the
+optional second integer argument. The result has the same type
+as the first argument.`,
+	})
+	Define(&Fn{
+		Name: "ceil",
+		Eval: ceil,
+		Desc: `Returns the least value greater than or equal to the first
+number argument with the number of decimal places given by the
+optional second integer argument. The result has the same type
+as the first argument.`,
+	})
+}
+
+const (
+	roundNearest = iota
+	roundDown
+	roundUp
+)
+
+func round(root map[string]any, at any, args ...any) any {
+	return roundNumber("round", root, at, args, roundNearest)
+}
+
+func floor(root map[string]any, at any, args ...any) any {
+	return roundNumber("floor", root, at, args, roundDown)
+}
+
+func ceil(root map[string]any, at any, args ...any) any {
+	return roundNumber("ceil", root, at, args, roundUp)
+}
+
+func roundNumber(name string, root map[string]any, at any, args []any, mode int) any {
+	if len(args) < 1 || 2 < len(args) {
+		panic(fmt.Errorf("%s expects one or two arguments. %d given", name, len(args)))
+	}
+	n := numberArg(name, root, at, args[0])
+	var prec int64
+	if 1 < len(args) {
+		v := evalArg(root, at, args[1])
+		var ok bool
+		if prec, ok = asInt(v); !ok {
+			panic(fmt.Errorf("%s expects an integer precision, not a %T", name, v))
+		}
+	}
+	switch n.kind {
+	case intNum:
+		if prec < 0 {
+			if i, ok := roundInt(n.i, int(-prec), mode); ok {
+				n.i = i
+			} else {
+				n = n.promote(bigNum)
+				n.b = roundBig(n.b, int(prec), mode)
+			}
+		}
+	case floatNum:
+		scale := math.Pow10(int(prec))
+		f := n.f * scale
+		switch mode {
+		case roundNearest:
+			f = math.Round(f)
+		case roundDown:
+			f = math.Floor(f)
+		case roundUp:
+			f = math.Ceil(f)
+		}
+		n.f = f / scale
+	default:
+		n.b = roundBig(n.b, int(prec), mode)
+	}
+	return n.value()
+}
+
+// roundInt rounds to the number of places returning false if the result
+// does not fit in an int64.
+func roundInt(i int64, places, mode int) (int64, bool) {
+	if 18 < places {
+		return 0, false
+	}
+	scale := int64(1)
+	for ; 0 < places; places-- {
+		scale *= 10
+	}
+	q := i / scale
+	r := i % scale
+	switch mode {
+	case roundNearest:
+		switch {
+		case scale <= r*2:
+			q++
+		case r*2 <= -scale:
+			q--
+		}
+	case roundDown:
+		if r < 0 {
+			q--
+		}
+	case roundUp:
+		if 0 < r {
+			q++
+		}
+	}
+	if math.MaxInt64/scale < q || q < math.MinInt64/scale {
+		return 0, false
+	}
+	return q * scale, true
+}
+
+func roundBig(b *big.Float, prec, mode int) *big.Float {
+	scale := bigPow10(prec)
+	x := new(big.Float).SetPrec(bigPrec).Mul(b, scale)
+	if mode == roundNearest {
+		half := big.NewFloat(0.5)
+		if x.Sign() < 0 {
+			half.Neg(half)
+		}
+		x.Add(x, half)
+	}
+	i, acc := x.Int(nil)
+	switch {
+	case mode == roundDown && acc == big.Above:
+		i.Sub(i, big.NewInt(1))
+	case mode == roundUp && acc == big.Below:
+		i.Add(i, big.NewInt(1))
+	}
+	x.SetInt(i)
+	return x.Quo(x, scale)
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestRound(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [round 2.5]]
+           [set $.asm.b [round -2.5]]
+           [set $.asm.c [round 1.2345 2]]
+           [set $.asm.d [round 1250 -2]]
+           [set $.asm.e [round -1250 -2]]
+           [set $.asm.f [round 7]]
+           [set $.asm.h [floor 1.27 1]]
+           [set $.asm.i [floor -1.2]]
+           [set $.asm.j [floor -1234 -2]]
+           [set $.asm.k [ceil 1.21 1]]
+           [set $.asm.l [ceil -1.8]]
+           [set $.asm.m [ceil 1201 -2]]
+           [set $.asm.n [round 9223372036854775807 -1]]
+           [set $.asm.o [floor -9223372036854775808 -1]]
+           [set $.asm.p [round 1234 -20]]
+           [set $.asm.q [ceil 5 -19]]
+         ]`,
+		"{}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: 3
+  b: -3
+  c: 1.23
+  d: 1300
+  e: -1300
+  f: 7
+  h: 1.2
+  i: -2
+  j: -1300
+  k: 1.3
+  l: -1
+  m: 1300
+  n: 9223372036854775810
+  o: -9223372036854775810
+  p: 0
+  q: 10000000000000000000
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestRoundArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"round", 1, 2, 3},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestRoundArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"round", "x"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestRoundPrecType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"round", 1.5, "x"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
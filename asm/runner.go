@@ -0,0 +1,145 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"io"
+	"runtime"
+	"sync"
+
+	"github.com/khaf/ojg/oj"
+)
+
+// Runner executes a Plan against each document in a stream of documents. A
+// root is built for each document with the document in $.src and the plan
+// is executed on that root. Plans are executed concurrently by a bounded
+// number of workers while the results are delivered in the same order as
+// the documents were read.
+type Runner struct {
+	// Plan to execute for each document.
+	Plan *Plan
+
+	// Parser used by Run to read documents. If nil an oj.Parser is used. A
+	// sen.Parser can be used for SEN documents.
+	Parser oj.SimpleParser
+
+	// Workers is the maximum number of plans executed concurrently. If less
+	// than 1 then runtime.NumCPU() is used.
+	Workers int
+
+	// Root, if not nil, provides the initial members of each root. The
+	// members are copied for each document so changes made by one
+	// execution are not seen by others.
+	Root map[string]any
+}
+
+// Record is the result of executing a plan for one document.
+type Record struct {
+	// Index of the document in the stream starting at zero.
+	Index int
+
+	// Root the plan was executed on.
+	Root map[string]any
+
+	// Err is the error returned from the plan execution if there was one.
+	Err error
+}
+
+// Src returns the source document the plan was executed on.
+func (rec *Record) Src() any {
+	return rec.Root["src"]
+}
+
+// Asm returns the assembled output of the plan.
+func (rec *Record) Asm() any {
+	return rec.Root["asm"]
+}
+
+type runJob struct {
+	rec  Record
+	done chan struct{}
+}
+
+// Run parses the documents read from rd and executes the plan for each one.
+// The callback is called with each record in the order the documents were
+// read. Errors from plan executions are reported in the records while a
+// parse error is returned after all the documents before the error have
+// been processed.
+func (r *Runner) Run(rd io.Reader, cb func(rec *Record)) error {
+	p := r.Parser
+	if p == nil {
+		p = &oj.Parser{}
+	}
+	docs := make(chan any, r.workers())
+	errs := make(chan error, 1)
+	go func() {
+		// The channel option also turns off map reuse in the oj.Parser
+		// which would not be safe when executing concurrently.
+		_, err := p.ParseReader(rd, docs)
+		close(docs)
+		errs <- err
+	}()
+	r.RunChan(docs, cb)
+
+	return <-errs
+}
+
+// RunChan executes the plan for each document received on the docs channel
+// until the channel is closed. The callback is called with each record in
+// the order the documents were received. The documents must not be modified
+// by the caller once sent.
+func (r *Runner) RunChan(docs <-chan any, cb func(rec *Record)) {
+	workers := r.workers()
+	jobs := make(chan *runJob, workers)
+	ordered := make(chan *runJob, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				job.rec.Err = r.Plan.Execute(job.rec.Root)
+				close(job.done)
+			}
+		}()
+	}
+	go func() {
+		index := 0
+		for doc := range docs {
+			job := runJob{
+				rec:  Record{Index: index, Root: r.newRoot(doc)},
+				done: make(chan struct{}),
+			}
+			index++
+			// Queue in order first so the consumer always waits on a job
+			// that has already been handed to the workers.
+			ordered <- &job
+			jobs <- &job
+		}
+		close(jobs)
+		close(ordered)
+	}()
+	for job := range ordered {
+		<-job.done
+		cb(&job.rec)
+	}
+	wg.Wait()
+}
+
+func (r *Runner) workers() int {
+	if r.Workers < 1 {
+		return runtime.NumCPU()
+	}
+	return r.Workers
+}
+
+func (r *Runner) newRoot(src any) map[string]any {
+	root := make(map[string]any, len(r.Root)+2)
+	for k, v := range r.Root {
+		root[k] = dupValue(v)
+	}
+	root["src"] = src
+	delete(root, "asm")
+
+	return root
+}
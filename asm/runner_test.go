@@ -0,0 +1,67 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestRunner(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"set", "$.asm", map[string]any{"n": "$.src.n"}},
+		[]any{"set", "$.asm.sq", []any{"*", "$.src.n", "$.src.n"}},
+		[]any{"set", "$.asm.tag", "$.tag"},
+	})
+	var b strings.Builder
+	for i := 0; i < 100; i++ {
+		fmt.Fprintf(&b, `{"n":%d}`+"\n", i)
+	}
+	r := asm.Runner{Plan: p, Workers: 4, Root: map[string]any{"tag": "x"}}
+	var out []string
+	err := r.Run(strings.NewReader(b.String()), func(rec *asm.Record) {
+		tt.Nil(t, rec.Err)
+		tt.Equal(t, len(out), rec.Index)
+		out = append(out, sen.String(rec.Asm(), &sopt))
+	})
+	tt.Nil(t, err)
+	tt.Equal(t, 100, len(out))
+	for i, s := range out {
+		tt.Equal(t, fmt.Sprintf("{n:$.src.n sq:%d tag:x}", i*i), s)
+	}
+}
+
+func TestRunnerRecordError(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"set", "$.asm", []any{"+", 1, "$.src"}},
+	})
+	r := asm.Runner{Plan: p, Parser: &sen.Parser{}}
+	var recs []*asm.Record
+	err := r.Run(strings.NewReader("1 true 3"), func(rec *asm.Record) {
+		recs = append(recs, rec)
+	})
+	tt.Nil(t, err)
+	tt.Equal(t, 3, len(recs))
+	tt.Equal(t, 2, recs[0].Asm())
+	tt.NotNil(t, recs[1].Err)
+	tt.Equal(t, true, recs[1].Src())
+	tt.Equal(t, 4, recs[2].Asm())
+}
+
+func TestRunnerParseError(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"set", "$.asm", "$.src"},
+	})
+	r := asm.Runner{Plan: p}
+	var cnt int
+	err := r.Run(strings.NewReader(`{"a":1} {"b":2} {"c":`), func(rec *asm.Record) {
+		cnt++
+	})
+	tt.NotNil(t, err)
+	tt.Equal(t, 2, cnt)
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+func init() {
+	Define(&Fn{
+		Name: "sqrt",
+		Eval: sqrt,
+		Desc: `Returns the square root of the single number argument as a
+float. An error is raised if the argument is negative.`,
+	})
+}
+
+func sqrt(root map[string]any, at any, args ...any) any {
+	if len(args) != 1 {
+		panic(fmt.Errorf("sqrt expects exactly one argument. %d given", len(args)))
+	}
+	n := numberArg("sqrt", root, at, args[0])
+	if n.kind == bigNum {
+		if n.b.Sign() < 0 {
+			panic(fmt.Errorf("sqrt of a negative number"))
+		}
+		return bigValue(new(big.Float).SetPrec(bigPrec).Sqrt(n.b))
+	}
+	f := n.asFloat64()
+	if f < 0.0 {
+		panic(fmt.Errorf("sqrt of a negative number"))
+	}
+	return math.Sqrt(f)
+}
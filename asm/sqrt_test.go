@@ -0,0 +1,52 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestSqrt(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [sqrt 16]]
+           [set $.asm.b [sqrt 2.25]]
+         ]`,
+		"{}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: 4
+  b: 1.5
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestSqrtArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"sqrt", 1, 2},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestSqrtNegative(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"sqrt", -1},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestSqrtArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"sqrt", "x"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
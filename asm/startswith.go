@@ -0,0 +1,27 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	Define(&Fn{
+		Name: "startswith",
+		Eval: startswith,
+		Desc: `Returns true if the first string argument starts with the
+second string argument.`,
+	})
+}
+
+func startswith(root map[string]any, at any, args ...any) any {
+	if len(args) != 2 {
+		panic(fmt.Errorf("startswith expects exactly two arguments. %d given", len(args)))
+	}
+	s := stringArg("startswith", "", root, at, args[0])
+	prefix := stringArg("startswith", "second ", root, at, args[1])
+
+	return strings.HasPrefix(s, prefix)
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestStartswith(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [startswith abcdef abc]]
+           [set $.asm.b [startswith abcdef def]]
+         ]`,
+		"{src: []}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: true
+  b: false
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestStartswithArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"startswith", "x"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestStartswithArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"startswith", "x", 1},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
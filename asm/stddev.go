@@ -0,0 +1,42 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"math"
+	"math/big"
+)
+
+func init() {
+	Define(&Fn{
+		Name: "stddev",
+		Eval: stddev,
+		Desc: `Returns the population standard deviation of all arguments as
+a float. All arguments must be numbers or arrays of numbers. If
+no numbers are given nil is returned.`,
+	})
+}
+
+func stddev(root map[string]any, at any, args ...any) any {
+	nums := numberArgs("stddev", root, at, args)
+	if len(nums) == 0 {
+		return nil
+	}
+	m := mean(nums)
+	if m.kind == bigNum {
+		sum := new(big.Float).SetPrec(bigPrec)
+		for _, n := range nums {
+			d := n.bigFloat()
+			d.Sub(d, m.b)
+			sum.Add(sum, d.Mul(d, d))
+		}
+		sum.Quo(sum, new(big.Float).SetInt64(int64(len(nums))))
+		return bigValue(sum.Sqrt(sum))
+	}
+	var sum float64
+	for _, n := range nums {
+		d := n.asFloat64() - m.f
+		sum += d * d
+	}
+	return math.Sqrt(sum / float64(len(nums)))
+}
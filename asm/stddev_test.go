@@ -0,0 +1,40 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestStddev(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [stddev 2 4 4 4 5 5 7 9]]
+           [set $.asm.b [stddev [list 1.5]]]
+           [set $.asm.c [stddev $.src.big $.src.big]]
+           [set $.asm.d [stddev]]
+         ]`,
+		"{src: {big: 100000000000000000000}}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: 2
+  b: 0
+  c: 0
+  d: null
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestStddevArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"stddev", "x"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
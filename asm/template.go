@@ -0,0 +1,108 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"fmt"
+
+	"github.com/khaf/ojg/jp"
+	"github.com/khaf/ojg/sen"
+)
+
+func init() {
+	Define(&Fn{
+		Name:    "template",
+		Eval:    template,
+		Compile: compileTemplate,
+		Desc: `Returns a new object built from the single template argument.
+The template is an object whose leaf values are evaluated. Paths
+such as $.src.x and functions such as [sum 1 2] are replaced by
+their values. Arrays that are not functions are evaluated
+element by element. The template can also be a string that is
+parsed as SEN or a path to an object in the data.`,
+	})
+}
+
+func compileTemplate(f *Fn) {
+	for i, a := range f.Args {
+		// A SEN string template is parsed once when compiled. If the parse
+		// fails the error is raised when evaluated.
+		if s, ok := a.(string); ok && 0 < len(s) && s[0] != '$' && s[0] != '@' {
+			if v, err := sen.Parse([]byte(s)); err == nil {
+				a = v
+			}
+		}
+		f.Args[i] = compileTemplateValue(a)
+	}
+}
+
+func compileTemplateValue(v any) any {
+	switch tv := v.(type) {
+	case map[string]any:
+		for k, mv := range tv {
+			tv[k] = compileTemplateValue(mv)
+		}
+	case []any:
+		if 0 < len(tv) {
+			if name, _ := tv[0].(string); 0 < len(name) {
+				if af := NewFn(name); af != nil {
+					af.Args = tv[1:]
+					af.compile()
+					return af
+				}
+			}
+		}
+		for i, lv := range tv {
+			tv[i] = compileTemplateValue(lv)
+		}
+	case string:
+		if 0 < len(tv) && (tv[0] == '$' || tv[0] == '@') {
+			if x, err := jp.Parse([]byte(tv)); err == nil {
+				return x
+			}
+		}
+	}
+	return v
+}
+
+func template(root map[string]any, at any, args ...any) any {
+	if len(args) != 1 {
+		panic(fmt.Errorf("template expects exactly one argument. %d given", len(args)))
+	}
+	tmpl := args[0]
+	switch ta := tmpl.(type) {
+	case map[string]any:
+		// compiled already
+	case string:
+		v, err := sen.Parse([]byte(ta))
+		if err != nil {
+			panic(err)
+		}
+		tmpl = compileTemplateValue(v)
+	default:
+		v := evalArg(root, at, ta)
+		if _, ok := v.(map[string]any); !ok {
+			panic(fmt.Errorf("template expects an object template, not a %T", v))
+		}
+		tmpl = compileTemplateValue(dupValue(v))
+	}
+	return evalTemplate(root, at, tmpl)
+}
+
+func evalTemplate(root map[string]any, at, v any) any {
+	switch tv := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(tv))
+		for k, mv := range tv {
+			m[k] = evalTemplate(root, at, mv)
+		}
+		return m
+	case []any:
+		list := make([]any, len(tv))
+		for i, lv := range tv {
+			list[i] = evalTemplate(root, at, lv)
+		}
+		return list
+	}
+	return evalArg(root, at, v)
+}
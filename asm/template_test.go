@@ -0,0 +1,47 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestTemplate(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [template {name: $.src.name total: [sum $.src.a $.src.b] nested: {list: [$.src.a 7 [list x]] fixed: yes}}]]
+           [set $.asm.b [template "{n: $.src.name s: [toupper $.src.name]}"]]
+           [set $.asm.c [template $.src.tmpl]]
+         ]`,
+		"{src: {name: sam a: 1 b: 2 tmpl: {x: $.src.a}}}",
+	)
+	tt.Equal(t, `{a:{name:sam nested:{fixed:yes list:[1 7 [x]]} total:3} b:{n:sam s:SAM} c:{x:1}}`, sen.String(root["asm"], &sopt))
+}
+
+func TestTemplateArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"template"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestTemplateArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"template", "$.src"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestTemplateBadSEN(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"template", "{a: ["},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
@@ -24,7 +24,10 @@ or string and are converted as follows:
   integer >= 10^10: time in nanoseconds 1970-01-01 UTC
   decimal (float):  time in seconds 1970-01-01 UTC
   string:           assumed to be formated as RFC3339 unless a
-                    format argument is provided`,
+                    format argument is provided
+The format can be a golang time layout, the name of a golang
+layout constant such as RFC1123, or a strftime format such as
+"%Y-%m-%d %H:%M:%S".`,
 	})
 }
 
@@ -65,7 +68,7 @@ func timeConv(root map[string]any, at any, args ...any) (t any) {
 			}
 		}
 		var err error
-		if t, err = time.Parse(layout, v); err != nil {
+		if t, err = parseTime(layout, v); err != nil {
 			panic(err)
 		}
 	}
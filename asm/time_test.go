@@ -38,6 +38,9 @@ func TestTimeConv(t *testing.T) {
            [set $.asm.d [time 1612832523123456789]]
            [set $.asm.e [time 1612832523.123456789]]
            [set $.asm.f [time "05 Jan 2021 -0400" "02 Jan 2006 -0700"]]
+           [set $.asm.g [time "2021/01/05 10:11" "%Y/%m/%d %H:%M"]]
+           [set $.asm.h [time "Tue, 05 Jan 2021 10:11:12 UTC" RFC1123]]
+           [set $.asm.i [time "Day 05 of Jan 2021 at 10:11" "Day %d of Jan %Y at %H:%M"]]
          ]`,
 		"{src: []}",
 	)
@@ -53,6 +56,9 @@ func TestTimeConv(t *testing.T) {
   d: "2021-02-09T01:02:03.123456789Z"
   e: "2021-02-09T01:02:03.123456716Z"
   f: "2021-01-05T00:00:00-04:00"
+  g: "2021-01-05T10:11:00Z"
+  h: "2021-01-05T10:11:12Z"
+  i: "2021-01-05T10:11:00Z"
 }`, sen.String(root["asm"], &opt))
 }
 
@@ -0,0 +1,31 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"fmt"
+)
+
+func init() {
+	Define(&Fn{
+		Name: "timeadd",
+		Eval: timeadd,
+		Desc: `Adds one or more durations to the first time argument. The
+time can be a time, integer nanoseconds since 1970-01-01 UTC,
+or an RFC3339 string. A duration can be integer nanoseconds,
+float seconds, a golang duration string such as "-1h30m", or
+an ISO 8601 duration such as "P1M2DT3H". Years, months, and
+days in ISO 8601 durations are added as calendar units.`,
+	})
+}
+
+func timeadd(root map[string]any, at any, args ...any) any {
+	if len(args) < 2 {
+		panic(fmt.Errorf("timeadd expects at least two arguments. %d given", len(args)))
+	}
+	t := timeArg("timeadd", root, at, args[0])
+	for _, arg := range args[1:] {
+		t = durationArg("timeadd", root, at, arg).AddTo(t)
+	}
+	return t
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestTimeadd(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [timeadd "2023-01-31T12:00:00Z" "P1M"]]
+           [set $.asm.b [timeadd "2023-01-31T12:00:00Z" "1h30m" "-PT30M"]]
+           [set $.asm.c [timeadd [time "2023-01-31T12:00:00Z"] 1000000000]]
+           [set $.asm.d [timeadd 1675166400000000000 1.5]]
+           [set $.asm.e [timeadd "2023-01-31T12:00:00Z" "P1Y2DT1S"]]
+         ]`,
+		"{src: []}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: "2023-03-03T12:00:00Z"
+  b: "2023-01-31T13:00:00Z"
+  c: "2023-01-31T12:00:01Z"
+  d: "2023-01-31T12:00:01.5Z"
+  e: "2024-02-02T12:00:01Z"
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestTimeaddArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"timeadd", "2023-01-31T12:00:00Z"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestTimeaddArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"timeadd", true, 1},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestTimeaddDuration(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"timeadd", "2023-01-31T12:00:00Z", "x"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
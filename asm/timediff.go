@@ -0,0 +1,60 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/khaf/ojg/alt"
+)
+
+func init() {
+	Define(&Fn{
+		Name: "timediff",
+		Eval: timediff,
+		Desc: `Returns the duration of the first time argument minus the
+second time argument as integer nanoseconds. The optional third
+argument changes the result to a float in the units of ns, us,
+ms, s, m, h, or d (24 hours), or to a string if iso (ISO 8601)
+or go (golang duration) is given.`,
+	})
+}
+
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"d":  24 * time.Hour,
+}
+
+func timediff(root map[string]any, at any, args ...any) any {
+	if len(args) < 2 || 3 < len(args) {
+		panic(fmt.Errorf("timediff expects two or three arguments. %d given", len(args)))
+	}
+	t0 := timeArg("timediff", root, at, args[0])
+	t1 := timeArg("timediff", root, at, args[1])
+	d := t0.Sub(t1)
+	if len(args) < 3 {
+		return int64(d)
+	}
+	return durationAs("timediff", d, stringArg("timediff", "unit ", root, at, args[2]))
+}
+
+// durationAs returns the duration in the unit or format specified.
+func durationAs(name string, d time.Duration, unit string) any {
+	switch unit {
+	case "iso":
+		return alt.ISODuration{Dur: d}.String()
+	case "go":
+		return d.String()
+	}
+	u, has := durationUnits[unit]
+	if !has {
+		panic(fmt.Errorf("%s unit must be one of ns, us, ms, s, m, h, d, iso, or go, not %s", name, unit))
+	}
+	return float64(d) / float64(u)
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestTimediff(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [timediff "2023-01-31T13:30:00Z" "2023-01-31T12:00:00Z"]]
+           [set $.asm.b [timediff "2023-01-31T13:30:00Z" "2023-01-31T12:00:00Z" h]]
+           [set $.asm.c [timediff "2023-01-31T13:30:00Z" "2023-01-31T12:00:00Z" iso]]
+           [set $.asm.d [timediff "2023-01-31T12:00:00Z" "2023-01-31T13:30:00Z" go]]
+           [set $.asm.e [timediff "2023-02-02T00:00:00Z" "2023-01-31T12:00:00Z" d]]
+         ]`,
+		"{src: []}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: 5400000000000
+  b: 1.5
+  c: PT1H30M
+  d: "-1h30m0s"
+  e: 1.5
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestTimediffArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"timediff", "2023-01-31T12:00:00Z"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestTimediffUnit(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"timediff", "2023-01-31T12:00:00Z", "2023-01-31T12:00:00Z", "x"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"fmt"
+	"time"
+)
+
+func init() {
+	Define(&Fn{
+		Name: "timefmt",
+		Eval: timefmt,
+		Desc: `Formats the first time argument as a string. The optional
+second argument is the format which can be a golang time
+layout, the name of a golang layout constant such as RFC1123,
+or a strftime format such as "%Y-%m-%d". If not provided
+RFC3339Nano is used.`,
+	})
+}
+
+func timefmt(root map[string]any, at any, args ...any) any {
+	if len(args) < 1 || 2 < len(args) {
+		panic(fmt.Errorf("timefmt expects one or two arguments. %d given", len(args)))
+	}
+	t := timeArg("timefmt", root, at, args[0])
+	if 1 < len(args) {
+		return formatTime(t, stringArg("timefmt", "format ", root, at, args[1]))
+	}
+	return t.Format(time.RFC3339Nano)
+}
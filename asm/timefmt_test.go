@@ -0,0 +1,52 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestTimefmt(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [timefmt "2023-02-05T14:07:09.5Z"]]
+           [set $.asm.b [timefmt "2023-02-05T14:07:09.5Z" "%Y/%m/%d %H:%M"]]
+           [set $.asm.c [timefmt "2023-02-05T14:07:09.5Z" RFC1123]]
+           [set $.asm.d [timefmt "2023-02-05T14:07:09.5Z" "Jan 2, 2006"]]
+           [set $.asm.e [timefmt 0 "%A"]]
+           [set $.asm.f [timefmt "2023-02-05T14:07:09.5Z" "Day %d of Jan at 3 PM %H:%M"]]
+         ]`,
+		"{src: []}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: "2023-02-05T14:07:09.5Z"
+  b: "2023/02/05 14:07"
+  c: "Sun, 05 Feb 2023 14:07:09 UTC"
+  d: "Feb 5, 2023"
+  e: Thursday
+  f: "Day 05 of Jan at 3 PM 14:07"
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestTimefmtArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"timefmt"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestTimefmtFormatType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"timefmt", 0, 1},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
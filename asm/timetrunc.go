@@ -0,0 +1,86 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"fmt"
+	"time"
+)
+
+func init() {
+	Define(&Fn{
+		Name: "timetrunc",
+		Eval: timetrunc,
+		Desc: `Truncates the first time argument to a multiple of the second
+argument. The second argument can be one of the calendar units
+year, month, week (starting on Monday), day, hour, minute, or
+second which truncate in the location of the time, or it can
+be a duration as described for timeadd.`,
+	})
+	Define(&Fn{
+		Name: "timeround",
+		Eval: timeround,
+		Desc: `Rounds the first time argument to the nearest multiple of the
+second argument which can be a calendar unit or duration as
+described for timetrunc. Halfway values are rounded up.`,
+	})
+}
+
+func timetrunc(root map[string]any, at any, args ...any) any {
+	_, t0, _ := timeUnit("timetrunc", root, at, args)
+	return t0
+}
+
+func timeround(root map[string]any, at any, args ...any) any {
+	t, t0, next := timeUnit("timeround", root, at, args)
+	if t1 := next(t0); t1.Sub(t) <= t.Sub(t0) {
+		return t1
+	}
+	return t0
+}
+
+// timeUnit returns the time argument, the time truncated to the unit
+// specified, and a function that returns the next unit boundary after a
+// truncated time.
+func timeUnit(name string, root map[string]any, at any, args []any) (time.Time, time.Time, func(time.Time) time.Time) {
+	if len(args) != 2 {
+		panic(fmt.Errorf("%s expects exactly two arguments. %d given", name, len(args)))
+	}
+	t := timeArg(name, root, at, args[0])
+	if unit, ok := evalArg(root, at, args[1]).(string); ok {
+		y, mo, d := t.Date()
+		loc := t.Location()
+		switch unit {
+		case "year":
+			return t, time.Date(y, time.January, 1, 0, 0, 0, 0, loc),
+				func(t time.Time) time.Time { return t.AddDate(1, 0, 0) }
+		case "month":
+			return t, time.Date(y, mo, 1, 0, 0, 0, 0, loc),
+				func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }
+		case "week":
+			offset := (int(t.Weekday()) + 6) % 7
+			return t, time.Date(y, mo, d-offset, 0, 0, 0, 0, loc),
+				func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }
+		case "day":
+			return t, time.Date(y, mo, d, 0, 0, 0, 0, loc),
+				func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }
+		case "hour":
+			return t, time.Date(y, mo, d, t.Hour(), 0, 0, 0, loc),
+				func(t time.Time) time.Time { return t.Add(time.Hour) }
+		case "minute":
+			return t, time.Date(y, mo, d, t.Hour(), t.Minute(), 0, 0, loc),
+				func(t time.Time) time.Time { return t.Add(time.Minute) }
+		case "second":
+			return t, time.Date(y, mo, d, t.Hour(), t.Minute(), t.Second(), 0, loc),
+				func(t time.Time) time.Time { return t.Add(time.Second) }
+		}
+	}
+	iso := durationArg(name, root, at, args[1])
+	if iso.Years != 0 || iso.Months != 0 || iso.Days != 0 {
+		panic(fmt.Errorf("%s can not use a duration with years, months, or days, use a calendar unit", name))
+	}
+	if iso.Dur <= 0 {
+		panic(fmt.Errorf("%s expects a positive duration", name))
+	}
+	return t, t.Truncate(iso.Dur), func(t time.Time) time.Time { return t.Add(iso.Dur) }
+}
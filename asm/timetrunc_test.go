@@ -0,0 +1,72 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestTimetrunc(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [timetrunc "2023-02-15T14:37:09.5Z" year]]
+           [set $.asm.b [timetrunc "2023-02-15T14:37:09.5Z" month]]
+           [set $.asm.c [timetrunc "2023-02-15T14:37:09.5Z" week]]
+           [set $.asm.d [timetrunc "2023-02-15T14:37:09.5Z" day]]
+           [set $.asm.e [timetrunc "2023-02-15T14:37:09.5Z" hour]]
+           [set $.asm.f [timetrunc "2023-02-15T14:37:09.5Z" minute]]
+           [set $.asm.g [timetrunc "2023-02-15T14:37:09.5Z" second]]
+           [set $.asm.h [timetrunc "2023-02-15T14:37:09.5Z" "15m"]]
+           [set $.asm.i [timeround "2023-02-15T14:37:09.5Z" "PT15M"]]
+           [set $.asm.j [timeround "2023-02-15T14:37:09.5Z" day]]
+           [set $.asm.k [timeround "2023-02-15T11:37:09.5Z" day]]
+           [set $.asm.l [timeround "2023-02-15T14:37:09.5Z" second]]
+         ]`,
+		"{src: []}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: "2023-01-01T00:00:00Z"
+  b: "2023-02-01T00:00:00Z"
+  c: "2023-02-13T00:00:00Z"
+  d: "2023-02-15T00:00:00Z"
+  e: "2023-02-15T14:00:00Z"
+  f: "2023-02-15T14:37:00Z"
+  g: "2023-02-15T14:37:09Z"
+  h: "2023-02-15T14:30:00Z"
+  i: "2023-02-15T14:30:00Z"
+  j: "2023-02-16T00:00:00Z"
+  k: "2023-02-15T00:00:00Z"
+  l: "2023-02-15T14:37:10Z"
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestTimetruncArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"timetrunc", "2023-01-31T12:00:00Z"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestTimetruncCalendar(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"timetrunc", "2023-01-31T12:00:00Z", "P1D"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestTimetruncNegative(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"timetrunc", "2023-01-31T12:00:00Z", "-1h"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"fmt"
+	"net/url"
+)
+
+func init() {
+	Define(&Fn{
+		Name: "urlencode",
+		Eval: urlencode,
+		Desc: `Escapes the single string argument so it can be safely placed
+in a URL query.`,
+	})
+	Define(&Fn{
+		Name: "urldecode",
+		Eval: urldecode,
+		Desc: `Reverses the escaping of urlencode on the single string
+argument. An error is raised if the string is not a valid
+escaped string.`,
+	})
+}
+
+func urlencode(root map[string]any, at any, args ...any) any {
+	if len(args) != 1 {
+		panic(fmt.Errorf("urlencode expects exactly one argument. %d given", len(args)))
+	}
+	return url.QueryEscape(stringArg("urlencode", "", root, at, args[0]))
+}
+
+func urldecode(root map[string]any, at any, args ...any) any {
+	if len(args) != 1 {
+		panic(fmt.Errorf("urldecode expects exactly one argument. %d given", len(args)))
+	}
+	s, err := url.QueryUnescape(stringArg("urldecode", "", root, at, args[0]))
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
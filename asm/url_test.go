@@ -0,0 +1,44 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestURL(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [urlencode "a b+c=d/é"]]
+           [set $.asm.b [urldecode "a+b%2Bc%3Dd%2F%C3%A9"]]
+         ]`,
+		"{src: []}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: "a+b%2Bc%3Dd%2F%C3%A9"
+  b: "a b+c=d/é"
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestURLArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"urlencode", "x", "y"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestURLArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"urlencode", 1},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
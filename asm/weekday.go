@@ -0,0 +1,24 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm
+
+import (
+	"fmt"
+)
+
+func init() {
+	Define(&Fn{
+		Name: "weekday",
+		Eval: weekday,
+		Desc: `Returns the day of the week of the single time argument as an
+integer where Sunday is 0 and Saturday is 6. Use timefmt with
+a %A format to get the name of the day.`,
+	})
+}
+
+func weekday(root map[string]any, at any, args ...any) any {
+	if len(args) != 1 {
+		panic(fmt.Errorf("weekday expects exactly one argument. %d given", len(args)))
+	}
+	return int64(timeArg("weekday", root, at, args[0]).Weekday())
+}
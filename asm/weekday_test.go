@@ -0,0 +1,44 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package asm_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/asm"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestWeekday(t *testing.T) {
+	root := testPlan(t,
+		`[
+           [set $.asm.a [weekday "2023-02-05T14:07:09Z"]]
+           [set $.asm.b [weekday [time "2023-02-11T00:00:00Z"]]]
+         ]`,
+		"{src: []}",
+	)
+	opt := sopt
+	opt.Indent = 2
+	tt.Equal(t,
+		`{
+  a: 0
+  b: 6
+}`, sen.String(root["asm"], &opt))
+}
+
+func TestWeekdayArgCount(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"weekday"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
+
+func TestWeekdayArgType(t *testing.T) {
+	p := asm.NewPlan([]any{
+		[]any{"weekday", "x"},
+	})
+	err := p.Execute(map[string]any{})
+	tt.NotNil(t, err)
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package cbor
+
+import (
+	"io"
+	"sync"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/alt"
+	"github.com/khaf/ojg/gen"
+)
+
+var (
+	// DefaultOptions are the default options for the this package. Unlike
+	// the JSON defaults, times are encoded as tag 1 epoch times.
+	DefaultOptions = ojg.DefaultOptions
+
+	writerPool = sync.Pool{
+		New: func() any {
+			return &Writer{Options: DefaultOptions, buf: make([]byte, 0, 1024)}
+		},
+	}
+	parserPool = sync.Pool{
+		New: func() any {
+			return &Parser{}
+		},
+	}
+)
+
+func init() {
+	DefaultOptions.TimeFormat = "time"
+}
+
+// Parse CBOR into a simple type. Arguments are optional and can be a
+// func(any) bool or func(any) for callbacks, or a chan any for chan based
+// result delivery of each item in a CBOR sequence.
+func Parse(b []byte, args ...any) (n any, err error) {
+	p := parserPool.Get().(*Parser)
+	defer parserPool.Put(p)
+	return p.Parse(b, args...)
+}
+
+// MustParse CBOR into a simple type. Panics on error.
+func MustParse(b []byte, args ...any) (n any) {
+	p := parserPool.Get().(*Parser)
+	defer parserPool.Put(p)
+	var err error
+	if n, err = p.Parse(b, args...); err != nil {
+		panic(err)
+	}
+	return
+}
+
+// ParseNode parses CBOR into a gen.Node.
+func ParseNode(b []byte) (gen.Node, error) {
+	p := parserPool.Get().(*Parser)
+	defer parserPool.Put(p)
+	return p.ParseNode(b)
+}
+
+// Load CBOR from a io.Reader into a simple type. An error is returned if
+// not valid CBOR.
+func Load(r io.Reader, args ...any) (any, error) {
+	p := parserPool.Get().(*Parser)
+	defer parserPool.Put(p)
+	return p.ParseReader(r, args...)
+}
+
+// MustLoad CBOR from a io.Reader into a simple type. Panics on error.
+func MustLoad(r io.Reader, args ...any) (n any) {
+	p := parserPool.Get().(*Parser)
+	defer parserPool.Put(p)
+	var err error
+	if n, err = p.ParseReader(r, args...); err != nil {
+		panic(err)
+	}
+	return
+}
+
+// Unmarshal parses the provided CBOR and stores the result in the value
+// pointed to by vp.
+func Unmarshal(data []byte, vp any, recomposer ...*alt.Recomposer) error {
+	p := Parser{}
+	return p.Unmarshal(data, vp, recomposer...)
+}
+
+// Marshal returns the CBOR encoding of the data provided. The data can be a
+// simple type, a gen.Node, or any other value which is decomposed with the
+// alt package. The args, if supplied can be a *ojg.Options or a *Writer.
+func Marshal(data any, args ...any) (out []byte, err error) {
+	var wr *Writer
+	if 0 < len(args) {
+		wr = pickWriter(args[0])
+	}
+	if wr == nil {
+		wr, _ = writerPool.Get().(*Writer)
+		defer writerPool.Put(wr)
+	}
+	return wr.Marshal(data)
+}
+
+// MustMarshal is the same as Marshal except it panics on error.
+func MustMarshal(data any, args ...any) []byte {
+	out, err := Marshal(data, args...)
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
+
+// Write the CBOR encoding of the data provided to w. The args, if supplied
+// can be a *ojg.Options or a *Writer.
+func Write(w io.Writer, data any, args ...any) (err error) {
+	var wr *Writer
+	if 0 < len(args) {
+		wr = pickWriter(args[0])
+	}
+	if wr == nil {
+		wr, _ = writerPool.Get().(*Writer)
+		defer writerPool.Put(wr)
+	}
+	return wr.Write(w, data)
+}
+
+func pickWriter(arg any) (wr *Writer) {
+	switch ta := arg.(type) {
+	case *ojg.Options:
+		wr = &Writer{
+			Options: *ta,
+			buf:     make([]byte, 0, 1024),
+		}
+	case *Writer:
+		wr = ta
+	}
+	return
+}
@@ -0,0 +1,26 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+/*
+Package cbor reads and writes CBOR (RFC 8949) with the same options and
+much the same API as the oj package. Data is parsed into simple types or
+into gen.Node and any value that can be written as JSON can be written as
+CBOR.
+
+	b, err := cbor.Marshal(map[string]any{"a": []any{1, 2.5, "x"}})
+	v, err := cbor.Parse(b)
+
+Values other than simple types and gen.Node are decomposed using the same
+cached struct information as alt.Decompose so the ojg.Options such as
+OmitNil, CreateKey, UseTags, and KeyExact apply. Unmarshal recomposes
+parsed data into a Go value.
+
+Times are encoded according to the TimeFormat option. A TimeFormat of
+"time", the default for this package, encodes whole second times as tag 1
+integer epoch times and times with a fraction of a second as tag 0 RFC 3339
+strings so that nanoseconds are not lost. Tag 0 and tag 1 times are parsed
+into time.Time values.
+
+A Tokenizer calls the functions of an oj.TokenHandler for each item so
+handlers written for JSON can be used with CBOR.
+*/
+package cbor
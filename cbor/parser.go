@@ -0,0 +1,488 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package cbor
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/alt"
+	"github.com/khaf/ojg/gen"
+)
+
+// maxDepth limits the nesting of arrays, maps, and tags so that a small
+// malicious input can not exhaust the stack.
+const maxDepth = 10000
+
+// Parser is a reusable CBOR parser. A Parser is not safe for concurrent
+// use.
+type Parser struct {
+	buf        []byte
+	pos        int
+	cb         func(any)
+	resultChan chan any
+}
+
+// Parse CBOR into simple types. Arguments are optional and can be a
+// func(any) bool or func(any) for callbacks, or a chan any for chan based
+// result delivery. If a callback or chan is provided all the items in a
+// CBOR sequence are parsed, otherwise the data must be a single item.
+//
+// Integers that do not fit in an int64 and bignums are returned as a
+// json.Number, byte strings as a []byte, and tag 0 and tag 1 times as a
+// time.Time.
+func (p *Parser) Parse(buf []byte, args ...any) (result any, err error) {
+	p.cb = nil
+	p.resultChan = nil
+	for _, a := range args {
+		switch ta := a.(type) {
+		case func(any) bool:
+			p.cb = func(x any) { _ = ta(x) }
+		case func(any):
+			p.cb = ta
+		case chan any:
+			p.resultChan = ta
+		default:
+			return nil, fmt.Errorf("a %T is not a valid option type", a)
+		}
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			result = nil
+			err = ojg.NewError(r)
+		}
+	}()
+	p.buf = buf
+	p.pos = 0
+	if p.cb == nil && p.resultChan == nil {
+		result = p.value(0)
+		p.checkEnd()
+		return
+	}
+	for p.pos < len(p.buf) {
+		v := p.value(0)
+		if p.cb != nil {
+			p.cb(v)
+		}
+		if p.resultChan != nil {
+			p.resultChan <- v
+		}
+	}
+	return
+}
+
+// ParseReader reads CBOR from an io.Reader. All the data is read before
+// parsing. The arguments are the same as for Parse.
+func (p *Parser) ParseReader(r io.Reader, args ...any) (any, error) {
+	buf, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return p.Parse(buf, args...)
+}
+
+// ParseNode parses a single CBOR item into a gen.Node. Integers that do not
+// fit in an int64 are returned as a gen.Uint or gen.Big, byte strings as a
+// gen.Bytes, and times as a gen.Time.
+func (p *Parser) ParseNode(buf []byte) (result gen.Node, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = nil
+			err = ojg.NewError(r)
+		}
+	}()
+	p.buf = buf
+	p.pos = 0
+	result = p.node(0)
+	p.checkEnd()
+
+	return
+}
+
+// Unmarshal parses the provided CBOR and stores the result in the value
+// pointed to by vp.
+func (p *Parser) Unmarshal(data []byte, vp any, recomposer ...*alt.Recomposer) (err error) {
+	var v any
+	if v, err = p.Parse(data); err == nil {
+		if 0 < len(recomposer) {
+			_, err = recomposer[0].Recompose(v, vp)
+		} else {
+			_, err = alt.Recompose(v, vp)
+		}
+	}
+	return
+}
+
+func (p *Parser) value(depth int) (v any) {
+	start := p.pos
+	major, info, arg := p.head()
+	switch major {
+	case majorUint:
+		if arg <= math.MaxInt64 {
+			v = int64(arg)
+		} else {
+			v = json.Number(strconv.FormatUint(arg, 10))
+		}
+	case majorNeg:
+		if arg <= math.MaxInt64 {
+			v = -1 - int64(arg)
+		} else {
+			v = json.Number(negBig(arg).String())
+		}
+	case majorBytes:
+		v = p.bytes(info, arg)
+	case majorText:
+		v = p.text(info, arg)
+	case majorArray:
+		p.checkDepth(depth)
+		a := []any{}
+		if info == 31 {
+			for !p.atBreak() {
+				a = append(a, p.value(depth+1))
+			}
+		} else {
+			p.checkCount(arg)
+			a = make([]any, 0, arg)
+			for i := uint64(0); i < arg; i++ {
+				a = append(a, p.value(depth+1))
+			}
+		}
+		v = a
+	case majorMap:
+		p.checkDepth(depth)
+		obj := map[string]any{}
+		if info == 31 {
+			for !p.atBreak() {
+				k := p.key(depth)
+				obj[k] = p.value(depth + 1)
+			}
+		} else {
+			p.checkCount(arg)
+			for i := uint64(0); i < arg; i++ {
+				k := p.key(depth)
+				obj[k] = p.value(depth + 1)
+			}
+		}
+		v = obj
+	case majorTag:
+		p.checkDepth(depth)
+		switch arg {
+		case tagTimeString, tagTimeEpoch:
+			v = p.timeValue(arg, depth)
+		case tagPosBig, tagNegBig:
+			v = json.Number(p.bignum(arg).String())
+		default:
+			// Unknown tags are ignored and the enclosed item is returned.
+			v = p.value(depth + 1)
+		}
+	default:
+		v = p.simple(start, info, arg)
+	}
+	return
+}
+
+func (p *Parser) node(depth int) (v gen.Node) {
+	start := p.pos
+	major, info, arg := p.head()
+	switch major {
+	case majorUint:
+		if arg <= math.MaxInt64 {
+			v = gen.Int(arg)
+		} else {
+			v = gen.Uint(arg)
+		}
+	case majorNeg:
+		if arg <= math.MaxInt64 {
+			v = gen.Int(-1 - int64(arg))
+		} else {
+			v = gen.Big(negBig(arg).String())
+		}
+	case majorBytes:
+		v = gen.Bytes(p.bytes(info, arg))
+	case majorText:
+		v = gen.String(p.text(info, arg))
+	case majorArray:
+		p.checkDepth(depth)
+		a := gen.Array{}
+		if info == 31 {
+			for !p.atBreak() {
+				a = append(a, p.node(depth+1))
+			}
+		} else {
+			p.checkCount(arg)
+			a = make(gen.Array, 0, arg)
+			for i := uint64(0); i < arg; i++ {
+				a = append(a, p.node(depth+1))
+			}
+		}
+		v = a
+	case majorMap:
+		p.checkDepth(depth)
+		obj := gen.Object{}
+		if info == 31 {
+			for !p.atBreak() {
+				k := p.key(depth)
+				obj[k] = p.node(depth + 1)
+			}
+		} else {
+			p.checkCount(arg)
+			for i := uint64(0); i < arg; i++ {
+				k := p.key(depth)
+				obj[k] = p.node(depth + 1)
+			}
+		}
+		v = obj
+	case majorTag:
+		p.checkDepth(depth)
+		switch arg {
+		case tagTimeString, tagTimeEpoch:
+			v = gen.Time(p.timeValue(arg, depth))
+		case tagPosBig, tagNegBig:
+			bi := p.bignum(arg)
+			if bi.IsUint64() && math.MaxInt64 < bi.Uint64() {
+				v = gen.Uint(bi.Uint64())
+			} else if bi.IsInt64() {
+				v = gen.Int(bi.Int64())
+			} else {
+				v = gen.Big(bi.String())
+			}
+		default:
+			v = p.node(depth + 1)
+		}
+	default:
+		switch tv := p.simple(start, info, arg).(type) {
+		case bool:
+			v = gen.Bool(tv)
+		case float64:
+			v = gen.Float(tv)
+		}
+	}
+	return
+}
+
+// key reads a map key. Text keys are used as is while other keys are
+// converted to a string.
+func (p *Parser) key(depth int) string {
+	k := p.value(depth + 1)
+	if s, ok := k.(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%v", k)
+}
+
+// timeValue reads the item following a time tag.
+func (p *Parser) timeValue(tag uint64, depth int) (t time.Time) {
+	start := p.pos
+	switch tv := p.value(depth + 1).(type) {
+	case string:
+		if tag == tagTimeString {
+			var err error
+			if t, err = time.Parse(time.RFC3339Nano, tv); err != nil {
+				panic(fmt.Errorf("invalid time at %d: %w", start, err))
+			}
+			return
+		}
+	case int64:
+		if tag == tagTimeEpoch {
+			return time.Unix(tv, 0).UTC()
+		}
+	case float64:
+		if tag == tagTimeEpoch && !math.IsNaN(tv) && !math.IsInf(tv, 0) {
+			secs := math.Floor(tv)
+			return time.Unix(int64(secs), int64((tv-secs)*float64(time.Second))).UTC()
+		}
+	}
+	panic(fmt.Errorf("invalid time at %d", start))
+}
+
+// bignum reads the byte string following a bignum tag.
+func (p *Parser) bignum(tag uint64) *big.Int {
+	start := p.pos
+	major, info, arg := p.head()
+	if major != majorBytes {
+		panic(fmt.Errorf("invalid bignum at %d", start))
+	}
+	bi := new(big.Int).SetBytes(p.bytes(info, arg))
+	if tag == tagNegBig {
+		bi.Neg(bi).Sub(bi, big.NewInt(1))
+	}
+	return bi
+}
+
+func (p *Parser) checkDepth(depth int) {
+	if maxDepth <= depth {
+		panic(fmt.Errorf("too deeply nested at %d", p.pos))
+	}
+}
+
+// head reads the initial byte and argument of an item. The info is the low
+// 5 bits of the initial byte and is 31 for indefinite length items.
+func (p *Parser) head() (major, info byte, arg uint64) {
+	if len(p.buf) <= p.pos {
+		panic(fmt.Errorf("incomplete CBOR at %d", p.pos))
+	}
+	b := p.buf[p.pos]
+	p.pos++
+	major = b & 0xe0
+	info = b & 0x1f
+	switch {
+	case info < 24:
+		arg = uint64(info)
+	case info == 24:
+		arg = uint64(p.read(1)[0])
+	case info == 25:
+		arg = uint64(binary.BigEndian.Uint16(p.read(2)))
+	case info == 26:
+		arg = uint64(binary.BigEndian.Uint32(p.read(4)))
+	case info == 27:
+		arg = binary.BigEndian.Uint64(p.read(8))
+	case info == 31:
+		switch major {
+		case majorBytes, majorText, majorArray, majorMap, majorSimple:
+		default:
+			panic(fmt.Errorf("invalid indefinite length item at %d", p.pos-1))
+		}
+	default:
+		panic(fmt.Errorf("invalid additional information %d at %d", info, p.pos-1))
+	}
+	return
+}
+
+func (p *Parser) read(n uint64) []byte {
+	if uint64(len(p.buf)-p.pos) < n {
+		panic(fmt.Errorf("incomplete CBOR at %d", p.pos))
+	}
+	b := p.buf[p.pos : p.pos+int(n)]
+	p.pos += int(n)
+
+	return b
+}
+
+// atBreak returns true and skips the break if the next byte is a break
+// stop code.
+func (p *Parser) atBreak() bool {
+	if len(p.buf) <= p.pos {
+		panic(fmt.Errorf("incomplete CBOR at %d", p.pos))
+	}
+	if p.buf[p.pos] == cborBreak {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// checkCount verifies there are at least as many bytes remaining as items
+// expected so that a bogus count does not cause a huge allocation.
+func (p *Parser) checkCount(n uint64) {
+	if uint64(len(p.buf)-p.pos) < n {
+		panic(fmt.Errorf("incomplete CBOR at %d", p.pos))
+	}
+}
+
+func (p *Parser) checkEnd() {
+	if p.pos < len(p.buf) {
+		panic(fmt.Errorf("extra data after CBOR item at %d", p.pos))
+	}
+}
+
+// bytes reads the content of a byte string including the chunks of an
+// indefinite length byte string. A copy is always returned.
+func (p *Parser) bytes(info byte, arg uint64) []byte {
+	if info != 31 {
+		return append([]byte{}, p.read(arg)...)
+	}
+	b := []byte{}
+	for !p.atBreak() {
+		start := p.pos
+		major, ci, n := p.head()
+		if major != majorBytes || ci == 31 {
+			panic(fmt.Errorf("invalid byte string chunk at %d", start))
+		}
+		b = append(b, p.read(n)...)
+	}
+	return b
+}
+
+// text reads the content of a text string including the chunks of an
+// indefinite length text string.
+func (p *Parser) text(info byte, arg uint64) string {
+	start := p.pos
+	var b []byte
+	if info == 31 {
+		for !p.atBreak() {
+			cs := p.pos
+			major, ci, n := p.head()
+			if major != majorText || ci == 31 {
+				panic(fmt.Errorf("invalid text string chunk at %d", cs))
+			}
+			b = append(b, p.read(n)...)
+		}
+	} else {
+		b = p.read(arg)
+	}
+	if !utf8.Valid(b) {
+		panic(fmt.Errorf("invalid UTF-8 text string at %d", start))
+	}
+	return string(b)
+}
+
+// simple decodes a major type 7 item which is either a simple value or a
+// float. Booleans and floats are returned as bool and float64 and null and
+// undefined as nil.
+func (p *Parser) simple(start int, info byte, arg uint64) (v any) {
+	switch info {
+	case cborFalse & 0x1f:
+		v = false
+	case cborTrue & 0x1f:
+		v = true
+	case cborNull & 0x1f, cborUndefined & 0x1f:
+	case cborHalf & 0x1f:
+		v = halfToFloat(uint16(arg))
+	case cborFloat32 & 0x1f:
+		v = float64(math.Float32frombits(uint32(arg)))
+	case cborFloat64 & 0x1f:
+		v = math.Float64frombits(arg)
+	case cborBreak & 0x1f:
+		panic(fmt.Errorf("unexpected break at %d", start))
+	default:
+		panic(fmt.Errorf("simple value %d at %d is not supported", arg, start))
+	}
+	return
+}
+
+// halfToFloat converts an IEEE 754 half precision float to a float64.
+func halfToFloat(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	frac := float64(h & 0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(frac, -24)
+	case 0x1f:
+		if frac == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(frac+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		f = -f
+	}
+	return f
+}
+
+// negBig returns -1 - n as a big.Int.
+func negBig(n uint64) *big.Int {
+	bi := new(big.Int).SetUint64(n)
+	return bi.Neg(bi).Sub(bi, big.NewInt(1))
+}
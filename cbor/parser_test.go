@@ -0,0 +1,205 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package cbor_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khaf/ojg/alt"
+	"github.com/khaf/ojg/cbor"
+	"github.com/khaf/ojg/gen"
+	"github.com/khaf/ojg/tt"
+)
+
+func unhex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestParseRFC(t *testing.T) {
+	// Examples from RFC 8949 Appendix A.
+	when := time.Date(2013, 3, 21, 20, 4, 0, 0, time.UTC)
+	for _, d := range []struct {
+		src    string
+		expect any
+	}{
+		{src: "00", expect: int64(0)},
+		{src: "17", expect: int64(23)},
+		{src: "1818", expect: int64(24)},
+		{src: "1903e8", expect: int64(1000)},
+		{src: "1a000f4240", expect: int64(1000000)},
+		{src: "1b000000e8d4a51000", expect: int64(1000000000000)},
+		{src: "1bffffffffffffffff", expect: json.Number("18446744073709551615")},
+		{src: "c249010000000000000000", expect: json.Number("18446744073709551616")},
+		{src: "3bffffffffffffffff", expect: json.Number("-18446744073709551616")},
+		{src: "c349010000000000000000", expect: json.Number("-18446744073709551617")},
+		{src: "20", expect: int64(-1)},
+		{src: "3903e7", expect: int64(-1000)},
+		{src: "f90000", expect: 0.0},
+		{src: "f93c00", expect: 1.0},
+		{src: "f93e00", expect: 1.5},
+		{src: "f97bff", expect: 65504.0},
+		{src: "f90001", expect: 5.960464477539063e-08},
+		{src: "f90400", expect: 6.103515625e-05},
+		{src: "f9c400", expect: -4.0},
+		{src: "fa47c35000", expect: 100000.0},
+		{src: "fb3ff199999999999a", expect: 1.1},
+		{src: "f4", expect: false},
+		{src: "f5", expect: true},
+		{src: "f6", expect: nil},
+		{src: "f7", expect: nil},
+		{src: "c074323031332d30332d32315432303a30343a30305a", expect: when},
+		{src: "c11a514b67b0", expect: when},
+		{src: "c1fb41d452d9ec200000", expect: when.Add(time.Second / 2)},
+		{src: "d74401020304", expect: []byte{1, 2, 3, 4}},
+		{src: "d82076687474703a2f2f7777772e6578616d706c652e636f6d", expect: "http://www.example.com"},
+		{src: "6449455446", expect: "IETF"},
+		{src: "63e6b0b4", expect: "水"},
+		{src: "83010203", expect: []any{int64(1), int64(2), int64(3)}},
+		{src: "a201020304", expect: map[string]any{"1": int64(2), "3": int64(4)}},
+		{src: "a26161016162820203", expect: map[string]any{"a": int64(1), "b": []any{int64(2), int64(3)}}},
+		{src: "5f42010243030405ff", expect: []byte{1, 2, 3, 4, 5}},
+		{src: "7f657374726561646d696e67ff", expect: "streaming"},
+		{src: "9fff", expect: []any{}},
+		{src: "9f018202039f0405ffff", expect: []any{int64(1), []any{int64(2), int64(3)}, []any{int64(4), int64(5)}}},
+		{src: "bf61610161629f0203ffff", expect: map[string]any{"a": int64(1), "b": []any{int64(2), int64(3)}}},
+	} {
+		v, err := cbor.Parse(unhex(d.src))
+		tt.Nil(t, err, d.src)
+		tt.Equal(t, d.expect, v, d.src)
+	}
+	for _, src := range []string{"f97c00", "f9fc00", "f97e00"} {
+		v, err := cbor.Parse(unhex(src))
+		tt.Nil(t, err, src)
+		f, _ := v.(float64)
+		tt.Equal(t, true, math.IsInf(f, 0) || math.IsNaN(f), src)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, d := range []struct {
+		src    string
+		expect string
+	}{
+		{src: "", expect: "incomplete"},
+		{src: "1903", expect: "incomplete"},
+		{src: "0000", expect: "extra data"},
+		{src: "62c328", expect: "UTF-8"},
+		{src: "ff", expect: "unexpected break"},
+		{src: "1c", expect: "additional information"},
+		{src: "1f", expect: "indefinite"},
+		{src: "5f01ff", expect: "chunk"},
+		{src: "7f4161ff", expect: "chunk"},
+		{src: "9b00000000ffffffff00", expect: "incomplete"},
+		{src: "9f01", expect: "incomplete"},
+		{src: "f0", expect: "simple value"},
+		{src: "c001", expect: "invalid time"},
+		{src: "c06161", expect: "invalid time"},
+		{src: "c1f97e00", expect: "invalid time"},
+		{src: "c201", expect: "invalid bignum"},
+		{src: strings.Repeat("81", 10001) + "00", expect: "nested"},
+	} {
+		_, err := cbor.Parse(unhex(d.src))
+		tt.NotNil(t, err, d.src)
+		tt.Equal(t, true, strings.Contains(err.Error(), d.expect), d.src, ": ", err)
+		_, err = cbor.ParseNode(unhex(d.src))
+		tt.NotNil(t, err, d.src)
+	}
+	_, err := cbor.Parse([]byte{0}, 7)
+	tt.NotNil(t, err)
+	tt.Panic(t, func() { cbor.MustParse([]byte{0xff}) })
+	tt.Panic(t, func() { cbor.MustLoad(bytes.NewReader([]byte{0xff})) })
+}
+
+func TestParseSequence(t *testing.T) {
+	src := unhex("0102f5")
+	var items []any
+	_, err := cbor.Parse(src, func(v any) bool { items = append(items, v); return false })
+	tt.Nil(t, err)
+	tt.Equal(t, []any{int64(1), int64(2), true}, items)
+
+	items = items[:0]
+	_, err = cbor.Load(bytes.NewReader(src), func(v any) { items = append(items, v) })
+	tt.Nil(t, err)
+	tt.Equal(t, []any{int64(1), int64(2), true}, items)
+
+	ch := make(chan any, 3)
+	_, err = cbor.Parse(src, ch)
+	tt.Nil(t, err)
+	tt.Equal(t, int64(1), <-ch)
+
+	v, err := cbor.Load(bytes.NewReader(unhex("8101")))
+	tt.Nil(t, err)
+	tt.Equal(t, []any{int64(1)}, v)
+	tt.Equal(t, []any{int64(1)}, cbor.MustLoad(bytes.NewReader(unhex("8101"))))
+}
+
+func TestParseNode(t *testing.T) {
+	src := unhex("a861618320c349010000000000000000f6616242010261631bffffffffffffffff6164c1006165f93e0061666161" +
+		"6167bf6168f4ff6169c24101")
+	n, err := cbor.ParseNode(src)
+	tt.Nil(t, err)
+	tt.Equal(t, gen.Object{
+		"a": gen.Array{gen.Int(-1), gen.Big("-18446744073709551617"), nil},
+		"b": gen.Bytes{1, 2},
+		"c": gen.Uint(18446744073709551615),
+		"d": gen.Time(time.Unix(0, 0).UTC()),
+		"e": gen.Float(1.5),
+		"f": gen.String("a"),
+		"g": gen.Object{"h": gen.Bool(false)},
+		"i": gen.Int(1),
+	}, n)
+
+	for _, d := range []struct {
+		src    string
+		expect gen.Node
+	}{
+		{src: "3bffffffffffffffff", expect: gen.Big("-18446744073709551616")},
+		{src: "c24900ffffffffffffffff", expect: gen.Uint(18446744073709551615)},
+		{src: "9f01ff", expect: gen.Array{gen.Int(1)}},
+		{src: "d74101", expect: gen.Bytes{1}},
+	} {
+		n, err = cbor.ParseNode(unhex(d.src))
+		tt.Nil(t, err, d.src)
+		tt.Equal(t, d.expect, n, d.src)
+	}
+	// Round trip through the writer.
+	out, err := cbor.Marshal(n)
+	tt.Nil(t, err)
+	tt.Equal(t, "4101", hex.EncodeToString(out))
+}
+
+func TestUnmarshal(t *testing.T) {
+	when := time.Date(2023, 1, 2, 3, 4, 5, 6, time.UTC)
+	src := &sample{Name: "x", Count: 2, When: when, Data: []byte{1, 2}, Ptr: &sample{Name: "y"}, Other: []any{1.5}}
+	out, err := cbor.Marshal(src)
+	tt.Nil(t, err)
+
+	var s sample
+	err = cbor.Unmarshal(out, &s)
+	tt.Nil(t, err)
+	tt.Equal(t, "x", s.Name)
+	tt.Equal(t, 2, s.Count)
+	tt.Equal(t, when.UnixNano(), s.When.UnixNano())
+	tt.Equal(t, []byte{1, 2}, s.Data)
+	tt.Equal(t, "y", s.Ptr.Name)
+	tt.Equal(t, []any{1.5}, s.Other)
+
+	var p cbor.Parser
+	var s2 sample
+	err = p.Unmarshal(out, &s2, &alt.DefaultRecomposer)
+	tt.Nil(t, err)
+	tt.Equal(t, "x", s2.Name)
+
+	err = cbor.Unmarshal([]byte{0xff}, &s)
+	tt.NotNil(t, err)
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package cbor
+
+import (
+	"encoding/base64"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/oj"
+)
+
+// Tokenizer is used to tokenize CBOR. The same oj.TokenHandler used with
+// the JSON tokenizer can be used with the CBOR tokenizer. Since the handler
+// only has JSON types, byte strings are passed to String() as base64,
+// times as RFC 3339 strings, and integers that do not fit in an int64 to
+// Number().
+type Tokenizer struct {
+	p       Parser
+	handler oj.TokenHandler
+}
+
+// Tokenize the provided CBOR and call the TokenHandler functions for each
+// token. All the items in a CBOR sequence are tokenized.
+func Tokenize(data []byte, handler oj.TokenHandler) error {
+	t := Tokenizer{}
+	return t.Parse(data, handler)
+}
+
+// TokenizeLoad CBOR from an io.Reader and call the TokenHandler functions
+// for each token.
+func TokenizeLoad(r io.Reader, handler oj.TokenHandler) error {
+	t := Tokenizer{}
+	return t.Load(r, handler)
+}
+
+// Parse the CBOR and call the handler functions for each token.
+func (t *Tokenizer) Parse(buf []byte, handler oj.TokenHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = ojg.NewError(r)
+		}
+	}()
+	t.handler = handler
+	t.p.buf = buf
+	t.p.pos = 0
+	for t.p.pos < len(t.p.buf) {
+		t.token(0)
+	}
+	return
+}
+
+// Load and parse the CBOR and call the handler functions for each token.
+// All the data is read before tokenizing.
+func (t *Tokenizer) Load(r io.Reader, handler oj.TokenHandler) error {
+	buf, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return t.Parse(buf, handler)
+}
+
+func (t *Tokenizer) token(depth int) {
+	p := &t.p
+	start := p.pos
+	major, info, arg := p.head()
+	switch major {
+	case majorUint:
+		if arg <= math.MaxInt64 {
+			t.handler.Int(int64(arg))
+		} else {
+			t.handler.Number(strconv.FormatUint(arg, 10))
+		}
+	case majorNeg:
+		if arg <= math.MaxInt64 {
+			t.handler.Int(-1 - int64(arg))
+		} else {
+			t.handler.Number(negBig(arg).String())
+		}
+	case majorBytes:
+		t.handler.String(base64.StdEncoding.EncodeToString(p.bytes(info, arg)))
+	case majorText:
+		t.handler.String(p.text(info, arg))
+	case majorArray:
+		p.checkDepth(depth)
+		t.handler.ArrayStart()
+		if info == 31 {
+			for !p.atBreak() {
+				t.token(depth + 1)
+			}
+		} else {
+			p.checkCount(arg)
+			for i := uint64(0); i < arg; i++ {
+				t.token(depth + 1)
+			}
+		}
+		t.handler.ArrayEnd()
+	case majorMap:
+		p.checkDepth(depth)
+		t.handler.ObjectStart()
+		if info == 31 {
+			for !p.atBreak() {
+				t.handler.Key(p.key(depth))
+				t.token(depth + 1)
+			}
+		} else {
+			p.checkCount(arg)
+			for i := uint64(0); i < arg; i++ {
+				t.handler.Key(p.key(depth))
+				t.token(depth + 1)
+			}
+		}
+		t.handler.ObjectEnd()
+	case majorTag:
+		p.checkDepth(depth)
+		switch arg {
+		case tagTimeString, tagTimeEpoch:
+			t.handler.String(p.timeValue(arg, depth).Format(time.RFC3339Nano))
+		case tagPosBig, tagNegBig:
+			bi := p.bignum(arg)
+			if bi.IsInt64() {
+				t.handler.Int(bi.Int64())
+			} else {
+				t.handler.Number(bi.String())
+			}
+		default:
+			t.token(depth + 1)
+		}
+	default:
+		switch tv := p.simple(start, info, arg).(type) {
+		case nil:
+			t.handler.Null()
+		case bool:
+			t.handler.Bool(tv)
+		case float64:
+			t.handler.Float(tv)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package cbor_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/khaf/ojg/cbor"
+	"github.com/khaf/ojg/oj"
+	"github.com/khaf/ojg/tt"
+)
+
+type testHandler struct {
+	buf []byte
+}
+
+func (h *testHandler) Null() {
+	h.buf = append(h.buf, "null "...)
+}
+
+func (h *testHandler) Bool(v bool) {
+	h.buf = append(h.buf, fmt.Sprintf("%t ", v)...)
+}
+
+func (h *testHandler) Int(v int64) {
+	h.buf = append(h.buf, fmt.Sprintf("%d ", v)...)
+}
+
+func (h *testHandler) Float(v float64) {
+	h.buf = append(h.buf, fmt.Sprintf("%g ", v)...)
+}
+
+func (h *testHandler) Number(v string) {
+	h.buf = append(h.buf, fmt.Sprintf("%s ", v)...)
+}
+
+func (h *testHandler) String(v string) {
+	h.buf = append(h.buf, fmt.Sprintf("%s ", v)...)
+}
+
+func (h *testHandler) ObjectStart() {
+	h.buf = append(h.buf, "{ "...)
+}
+
+func (h *testHandler) ObjectEnd() {
+	h.buf = append(h.buf, "} "...)
+}
+
+func (h *testHandler) Key(v string) {
+	h.buf = append(h.buf, fmt.Sprintf("%s: ", v)...)
+}
+
+func (h *testHandler) ArrayStart() {
+	h.buf = append(h.buf, "[ "...)
+}
+
+func (h *testHandler) ArrayEnd() {
+	h.buf = append(h.buf, "] "...)
+}
+
+type errReader struct{}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, errors.New("failed")
+}
+
+func TestTokenize(t *testing.T) {
+	var h testHandler
+	src := unhex("84f5f6187bf93e00" + "a161781bffffffffffffffff" + "9f3bffffffffffffffffc24101c349010000000000000000ff" +
+		"bf42010261616162d8204100ff" + "c11a514b67b0")
+	err := cbor.Tokenize(src, &h)
+	tt.Nil(t, err)
+	tt.Equal(t, "[ true null 123 1.5 ] { x: 18446744073709551615 } "+
+		"[ -18446744073709551616 1 -18446744073709551617 ] { [1 2]: a b: AA== } 2013-03-21T20:04:00Z ",
+		string(h.buf))
+
+	h.buf = h.buf[:0]
+	err = cbor.TokenizeLoad(bytes.NewReader(unhex("8201f4")), &h)
+	tt.Nil(t, err)
+	tt.Equal(t, "[ 1 false ] ", string(h.buf))
+
+	err = cbor.TokenizeLoad(errReader{}, &h)
+	tt.NotNil(t, err)
+	err = cbor.Tokenize(unhex("82"), &h)
+	tt.NotNil(t, err)
+	err = cbor.Tokenize(unhex("a16161"+strings.Repeat("81", 10001)), &h)
+	tt.NotNil(t, err)
+	err = cbor.Tokenize(unhex(strings.Repeat("a16161", 10001)+"00"), &h)
+	tt.NotNil(t, err)
+
+	// Any oj.TokenHandler can be used.
+	err = cbor.Tokenize(src, &oj.ZeroHandler{})
+	tt.Nil(t, err)
+}
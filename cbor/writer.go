@@ -0,0 +1,372 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package cbor
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/alt"
+	"github.com/khaf/ojg/gen"
+)
+
+// Major types are in the high 3 bits of the initial byte.
+const (
+	majorUint   = 0x00
+	majorNeg    = 0x20
+	majorBytes  = 0x40
+	majorText   = 0x60
+	majorArray  = 0x80
+	majorMap    = 0xa0
+	majorTag    = 0xc0
+	majorSimple = 0xe0
+
+	cborFalse     = 0xf4
+	cborTrue      = 0xf5
+	cborNull      = 0xf6
+	cborUndefined = 0xf7
+	cborHalf      = 0xf9
+	cborFloat32   = 0xfa
+	cborFloat64   = 0xfb
+	cborBreak     = 0xff
+
+	tagTimeString = 0
+	tagTimeEpoch  = 1
+	tagPosBig     = 2
+	tagNegBig     = 3
+)
+
+// Writer is a CBOR writer that includes a reused buffer for reduced
+// allocations for repeated encoding calls.
+type Writer struct {
+	ojg.Options
+	buf  []byte
+	w    io.Writer
+	dopt ojg.Options
+}
+
+// Marshal data as CBOR. The returned slice is a copy and is not reused.
+func (wr *Writer) Marshal(data any) (out []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			wr.buf = wr.buf[:0]
+			err = ojg.NewError(r)
+		}
+	}()
+	wr.MustMarshal(data)
+	out = make([]byte, len(wr.buf))
+	copy(out, wr.buf)
+
+	return
+}
+
+// MustMarshal data as CBOR. On error a panic is called with the error. The
+// returned buffer is the Writer buffer and is reused on the next call to
+// write. If returned value is to be preserved past a second invocation then
+// the buffer should be copied.
+func (wr *Writer) MustMarshal(data any) []byte {
+	wr.w = nil
+	wr.prepare()
+	wr.appendValue(alt.ConvertForWrite(data, &wr.Options))
+
+	return wr.buf
+}
+
+// Write CBOR for the data provided.
+func (wr *Writer) Write(w io.Writer, data any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			wr.buf = wr.buf[:0]
+			err = ojg.NewError(r)
+		}
+	}()
+	wr.MustWrite(w, data)
+	return
+}
+
+// MustWrite CBOR for the data provided. If an error occurs panic is called
+// with the error.
+func (wr *Writer) MustWrite(w io.Writer, data any) {
+	wr.w = w
+	if wr.WriteLimit <= 0 {
+		wr.WriteLimit = 1024
+	}
+	wr.prepare()
+	wr.appendValue(alt.ConvertForWrite(data, &wr.Options))
+	if 0 < len(wr.buf) {
+		if _, err := wr.w.Write(wr.buf); err != nil {
+			panic(err)
+		}
+		wr.buf = wr.buf[:0]
+	}
+}
+
+func (wr *Writer) prepare() {
+	if wr.InitSize <= 0 {
+		wr.InitSize = 256
+	}
+	if cap(wr.buf) < wr.InitSize {
+		wr.buf = make([]byte, 0, wr.InitSize)
+	} else {
+		wr.buf = wr.buf[:0]
+	}
+	// Values that are not handled directly are decomposed with times left
+	// as time.Time and []byte left as is so they are encoded the same as
+	// top level values.
+	wr.dopt = wr.Options
+	wr.dopt.Converter = nil
+	wr.dopt.BytesAs = ojg.BytesAsBytes
+	wr.dopt.TimeFormat = "time"
+	wr.dopt.TimeMap = false
+	wr.dopt.TimeWrap = ""
+}
+
+func (wr *Writer) appendValue(data any) {
+	switch td := data.(type) {
+	case nil:
+		wr.buf = append(wr.buf, cborNull)
+	case bool:
+		wr.appendBool(td)
+	case int:
+		wr.appendInt(int64(td))
+	case int8:
+		wr.appendInt(int64(td))
+	case int16:
+		wr.appendInt(int64(td))
+	case int32:
+		wr.appendInt(int64(td))
+	case int64:
+		wr.appendInt(td)
+	case uint:
+		wr.buf = appendHead(wr.buf, majorUint, uint64(td))
+	case uint8:
+		wr.buf = appendHead(wr.buf, majorUint, uint64(td))
+	case uint16:
+		wr.buf = appendHead(wr.buf, majorUint, uint64(td))
+	case uint32:
+		wr.buf = appendHead(wr.buf, majorUint, uint64(td))
+	case uint64:
+		wr.buf = appendHead(wr.buf, majorUint, td)
+	case float32:
+		wr.buf = append(wr.buf, cborFloat32)
+		wr.buf = binary.BigEndian.AppendUint32(wr.buf, math.Float32bits(td))
+	case float64:
+		wr.appendFloat(td)
+	case string:
+		wr.appendString(td)
+	case []byte:
+		wr.buf = appendHead(wr.buf, majorBytes, uint64(len(td)))
+		wr.buf = append(wr.buf, td...)
+	case time.Time:
+		wr.appendTime(td)
+	case json.Number:
+		wr.appendNumber(string(td))
+	case []any:
+		wr.buf = appendHead(wr.buf, majorArray, uint64(len(td)))
+		for _, v := range td {
+			wr.appendValue(v)
+		}
+	case map[string]any:
+		wr.appendObject(td)
+
+	case gen.Null:
+		wr.buf = append(wr.buf, cborNull)
+	case gen.Bool:
+		wr.appendBool(bool(td))
+	case gen.Int:
+		wr.appendInt(int64(td))
+	case gen.Uint:
+		wr.buf = appendHead(wr.buf, majorUint, uint64(td))
+	case gen.Float:
+		wr.appendFloat(float64(td))
+	case gen.String:
+		wr.appendString(string(td))
+	case gen.Bytes:
+		wr.buf = appendHead(wr.buf, majorBytes, uint64(len(td)))
+		wr.buf = append(wr.buf, td...)
+	case gen.Time:
+		wr.appendTime(time.Time(td))
+	case gen.Big:
+		wr.appendNumber(string(td))
+	case gen.Decimal:
+		wr.appendNumber(string(td))
+	case gen.Array:
+		wr.buf = appendHead(wr.buf, majorArray, uint64(len(td)))
+		for _, v := range td {
+			wr.appendValue(v)
+		}
+	case gen.Object:
+		wr.appendGenObject(td)
+	default:
+		wr.appendDefault(data)
+	}
+	if wr.w != nil && wr.WriteLimit < len(wr.buf) {
+		if _, err := wr.w.Write(wr.buf); err != nil {
+			panic(err)
+		}
+		wr.buf = wr.buf[:0]
+	}
+}
+
+func (wr *Writer) appendDefault(data any) {
+	if simp, ok := data.(alt.Simplifier); ok {
+		wr.appendValue(simp.Simplify())
+		return
+	}
+	rv := reflect.ValueOf(data)
+	switch rv.Kind() {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		panic(fmt.Errorf("%T can not be encoded as a CBOR element", data))
+	}
+	// Structs, maps, and slices are decomposed using the cached struct
+	// information of the alt package.
+	wr.appendValue(alt.Decompose(data, &wr.dopt))
+}
+
+func (wr *Writer) appendBool(b bool) {
+	if b {
+		wr.buf = append(wr.buf, cborTrue)
+	} else {
+		wr.buf = append(wr.buf, cborFalse)
+	}
+}
+
+func (wr *Writer) appendInt(i int64) {
+	if i < 0 {
+		wr.buf = appendHead(wr.buf, majorNeg, uint64(-1-i))
+	} else {
+		wr.buf = appendHead(wr.buf, majorUint, uint64(i))
+	}
+}
+
+func (wr *Writer) appendFloat(f float64) {
+	wr.buf = append(wr.buf, cborFloat64)
+	wr.buf = binary.BigEndian.AppendUint64(wr.buf, math.Float64bits(f))
+}
+
+func (wr *Writer) appendString(s string) {
+	wr.buf = appendHead(wr.buf, majorText, uint64(len(s)))
+	wr.buf = append(wr.buf, s...)
+}
+
+// appendTime encodes a time as a tag 1 integer epoch time if the
+// TimeFormat is "time" and there is no fraction of a second. A time with a
+// fraction is encoded as a tag 0 RFC 3339 string with nanoseconds since a
+// float epoch time does not have the precision to hold the nanoseconds.
+// Otherwise the time is encoded the same as it would be for JSON including
+// the TimeMap and TimeWrap options.
+func (wr *Writer) appendTime(t time.Time) {
+	if wr.TimeFormat != "time" {
+		wr.appendValue(wr.DecomposeTime(t))
+		return
+	}
+	if t.Nanosecond() == 0 {
+		wr.buf = appendHead(wr.buf, majorTag, tagTimeEpoch)
+		wr.appendInt(t.Unix())
+	} else {
+		wr.buf = appendHead(wr.buf, majorTag, tagTimeString)
+		wr.appendString(t.UTC().Format(time.RFC3339Nano))
+	}
+}
+
+// appendNumber encodes a number string as an integer if it fits in 64
+// bits, as a bignum if it is an integer that does not, and as a float
+// otherwise.
+func (wr *Writer) appendNumber(s string) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		wr.appendInt(i)
+		return
+	}
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+		wr.buf = appendHead(wr.buf, majorUint, u)
+		return
+	}
+	if bi, ok := new(big.Int).SetString(s, 10); ok {
+		if bi.Sign() < 0 {
+			// Negative integers are encoded as -1 - n.
+			bi.Neg(bi).Sub(bi, big.NewInt(1))
+			if bi.IsUint64() {
+				wr.buf = appendHead(wr.buf, majorNeg, bi.Uint64())
+				return
+			}
+			wr.buf = appendHead(wr.buf, majorTag, tagNegBig)
+		} else {
+			wr.buf = appendHead(wr.buf, majorTag, tagPosBig)
+		}
+		b := bi.Bytes()
+		wr.buf = appendHead(wr.buf, majorBytes, uint64(len(b)))
+		wr.buf = append(wr.buf, b...)
+		return
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		panic(fmt.Errorf("%q is not a valid number", s))
+	}
+	wr.appendFloat(f)
+}
+
+func (wr *Writer) appendObject(obj map[string]any) {
+	keys := make([]string, 0, len(obj))
+	for k, v := range obj {
+		if v == nil && wr.OmitNil {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	if wr.Sort {
+		sort.Strings(keys)
+	}
+	wr.buf = appendHead(wr.buf, majorMap, uint64(len(keys)))
+	for _, k := range keys {
+		wr.appendString(k)
+		wr.appendValue(obj[k])
+	}
+}
+
+func (wr *Writer) appendGenObject(obj gen.Object) {
+	keys := make([]string, 0, len(obj))
+	for k, v := range obj {
+		if v == nil && wr.OmitNil {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	if wr.Sort {
+		sort.Strings(keys)
+	}
+	wr.buf = appendHead(wr.buf, majorMap, uint64(len(keys)))
+	for _, k := range keys {
+		wr.appendString(k)
+		wr.appendValue(obj[k])
+	}
+}
+
+// appendHead appends the initial byte and argument of an item using the
+// shortest encoding for the argument.
+func appendHead(buf []byte, major byte, arg uint64) []byte {
+	switch {
+	case arg < 24:
+		buf = append(buf, major|byte(arg))
+	case arg <= math.MaxUint8:
+		buf = append(buf, major|24, byte(arg))
+	case arg <= math.MaxUint16:
+		buf = append(buf, major|25)
+		buf = binary.BigEndian.AppendUint16(buf, uint16(arg))
+	case arg <= math.MaxUint32:
+		buf = append(buf, major|26)
+		buf = binary.BigEndian.AppendUint32(buf, uint32(arg))
+	default:
+		buf = append(buf, major|27)
+		buf = binary.BigEndian.AppendUint64(buf, arg)
+	}
+	return buf
+}
@@ -0,0 +1,202 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package cbor_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/cbor"
+	"github.com/khaf/ojg/gen"
+	"github.com/khaf/ojg/tt"
+)
+
+type sample struct {
+	Name  string
+	Count int
+	When  time.Time
+	Data  []byte
+	Ptr   *sample
+	Skip  int `json:"-"`
+	Other any
+}
+
+type failWriter struct{}
+
+func (w failWriter) Write([]byte) (int, error) {
+	return 0, errors.New("failed")
+}
+
+func TestMarshalRFC(t *testing.T) {
+	// Examples from RFC 8949 Appendix A.
+	opt := ojg.Options{Sort: true, TimeFormat: "time"}
+	for _, d := range []struct {
+		value  any
+		expect string
+	}{
+		{value: 0, expect: "00"},
+		{value: 1, expect: "01"},
+		{value: int8(10), expect: "0a"},
+		{value: int16(23), expect: "17"},
+		{value: int32(24), expect: "1818"},
+		{value: int64(25), expect: "1819"},
+		{value: uint(100), expect: "1864"},
+		{value: uint16(1000), expect: "1903e8"},
+		{value: uint32(1000000), expect: "1a000f4240"},
+		{value: 1000000000000, expect: "1b000000e8d4a51000"},
+		{value: uint64(18446744073709551615), expect: "1bffffffffffffffff"},
+		{value: json.Number("18446744073709551616"), expect: "c249010000000000000000"},
+		{value: json.Number("-18446744073709551616"), expect: "3bffffffffffffffff"},
+		{value: json.Number("-18446744073709551617"), expect: "c349010000000000000000"},
+		{value: -1, expect: "20"},
+		{value: -10, expect: "29"},
+		{value: -100, expect: "3863"},
+		{value: -1000, expect: "3903e7"},
+		{value: 1.1, expect: "fb3ff199999999999a"},
+		{value: float32(100000.0), expect: "fa47c35000"},
+		{value: float32(3.4028234663852886e+38), expect: "fa7f7fffff"},
+		{value: 1.0e+300, expect: "fb7e37e43c8800759c"},
+		{value: -4.1, expect: "fbc010666666666666"},
+		{value: json.Number("1.5"), expect: "fb3ff8000000000000"},
+		{value: false, expect: "f4"},
+		{value: true, expect: "f5"},
+		{value: nil, expect: "f6"},
+		{value: time.Unix(1363896240, 0), expect: "c11a514b67b0"},
+		// A fraction of a second is written as a tag 0 string to avoid
+		// losing precision in a float.
+		{value: time.Unix(1363896240, 500000000), expect: "c076323031332d30332d32315432303a30343a30302e355a"},
+		{value: []byte{}, expect: "40"},
+		{value: []byte{1, 2, 3, 4}, expect: "4401020304"},
+		{value: "", expect: "60"},
+		{value: "a", expect: "6161"},
+		{value: "IETF", expect: "6449455446"},
+		{value: "\"\\", expect: "62225c"},
+		{value: "ü", expect: "62c3bc"},
+		{value: "水", expect: "63e6b0b4"},
+		{value: []any{}, expect: "80"},
+		{value: []any{1, 2, 3}, expect: "83010203"},
+		{value: []any{1, []any{2, 3}, []int{4, 5}}, expect: "8301820203820405"},
+		{value: map[string]any{}, expect: "a0"},
+		{value: map[string]any{"a": 1, "b": []any{2, 3}}, expect: "a26161016162820203"},
+		{value: []any{"a", map[string]any{"b": "c"}}, expect: "826161a161626163"},
+	} {
+		out, err := cbor.Marshal(d.value, &opt)
+		tt.Nil(t, err, d.expect)
+		tt.Equal(t, d.expect, hex.EncodeToString(out), d.value)
+	}
+}
+
+func TestMarshalNode(t *testing.T) {
+	opt := ojg.Options{Sort: true, OmitNil: true}
+	node := gen.Object{
+		"a": gen.Array{gen.Int(-1), gen.Uint(1 << 63), gen.Float(1.5), gen.Bool(true), gen.Null{}},
+		"b": gen.String("x"),
+		"c": gen.Bytes{1},
+		"d": gen.Big("18446744073709551616"),
+		"e": gen.Decimal("0.5"),
+		"f": nil,
+		"g": gen.Time(time.Unix(0, 5).UTC()),
+	}
+	out, err := cbor.Marshal(node, &opt)
+	tt.Nil(t, err)
+	tt.Equal(t,
+		"a6616185201b8000000000000000fb3ff8000000000000f5f661626178616341016164c249010000000000000000"+
+			"6165fb3fe0000000000000616705",
+		hex.EncodeToString(out))
+
+	pnode := gen.Persist(gen.Object{"a": gen.Array{gen.Int(1)}})
+	out, err = cbor.Marshal(pnode)
+	tt.Nil(t, err)
+	tt.Equal(t, "a161618101", hex.EncodeToString(out))
+
+	_, err = cbor.Marshal(gen.Big("x"))
+	tt.NotNil(t, err)
+}
+
+func TestMarshalStruct(t *testing.T) {
+	when := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	s := &sample{Name: "x", Count: 2, When: when, Data: []byte("hi"), Ptr: &sample{Name: "y", When: when}, Skip: 3}
+
+	out, err := cbor.Marshal(s, &ojg.Options{OmitNil: true, CreateKey: "^", UseTags: true, TimeFormat: "time"})
+	tt.Nil(t, err)
+	v, err := cbor.Parse(out)
+	tt.Nil(t, err)
+	tt.Equal(t, map[string]any{
+		"^":     "sample",
+		"Name":  "x",
+		"Count": 2,
+		"When":  when,
+		"Data":  []byte("hi"),
+		"Ptr":   map[string]any{"^": "sample", "Name": "y", "Count": 0, "When": when, "Data": []byte{}},
+	}, v)
+
+	// Times are encoded as for JSON unless the TimeFormat is "time".
+	out, err = cbor.Marshal(map[string]any{"t": when}, &ojg.Options{TimeFormat: time.RFC3339})
+	tt.Nil(t, err)
+	tt.Equal(t, map[string]any{"t": "2023-01-02T03:04:05Z"}, cbor.MustParse(out))
+	out, err = cbor.Marshal(when, &ojg.Options{TimeFormat: "second", TimeWrap: "@"})
+	tt.Nil(t, err)
+	tt.Equal(t, map[string]any{"@": float64(when.Unix())}, cbor.MustParse(out))
+
+	// The package defaults use tag 1 times.
+	tt.Equal(t, "c11a63b249a5", hex.EncodeToString(cbor.MustMarshal(when)))
+
+	_, err = cbor.Marshal(func() {})
+	tt.NotNil(t, err)
+	_, err = cbor.Marshal([]any{make(chan int)})
+	tt.NotNil(t, err)
+	tt.Panic(t, func() { cbor.MustMarshal(func() {}) })
+}
+
+func TestMarshalTimeNano(t *testing.T) {
+	for _, when := range []time.Time{
+		time.Date(2023, 1, 2, 3, 4, 5, 123456789, time.UTC),
+		time.Date(2262, 4, 11, 23, 47, 16, 854775807, time.UTC),
+		time.Date(1969, 12, 31, 23, 59, 59, 1, time.UTC),
+		time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+	} {
+		out, err := cbor.Marshal(when)
+		tt.Nil(t, err)
+		v, err := cbor.Parse(out)
+		tt.Nil(t, err)
+		tt.Equal(t, when, v, when)
+
+		n, err := (&cbor.Parser{}).ParseNode(out)
+		tt.Nil(t, err)
+		tt.Equal(t, when, time.Time(n.(gen.Time)), when)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var b strings.Builder
+	data := []any{strings.Repeat("x", 20), strings.Repeat("y", 20), map[string]any{"z": nil}}
+	wr := cbor.Writer{Options: ojg.Options{WriteLimit: 8}}
+	err := wr.Write(&b, data)
+	tt.Nil(t, err)
+	v, err := cbor.Parse([]byte(b.String()))
+	tt.Nil(t, err)
+	tt.Equal(t, data, v)
+
+	b.Reset()
+	err = cbor.Write(&b, data, &wr)
+	tt.Nil(t, err)
+	tt.Equal(t, data, cbor.MustParse([]byte(b.String())))
+
+	b.Reset()
+	err = cbor.Write(&b, 1)
+	tt.Nil(t, err)
+	tt.Equal(t, "\x01", b.String())
+
+	err = cbor.Write(failWriter{}, data, &ojg.Options{WriteLimit: 8})
+	tt.NotNil(t, err)
+	err = cbor.Write(failWriter{}, 1, &ojg.Options{})
+	tt.NotNil(t, err)
+
+	out := wr.MustMarshal(true)
+	tt.Equal(t, "f5", hex.EncodeToString(out))
+}
@@ -0,0 +1,242 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/khaf/ojg/schema"
+)
+
+// Common initialisms that are written in upper case in Go identifiers.
+var initialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true,
+	"EOF": true, "GUID": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true,
+	"IP": true, "JSON": true, "LHS": true, "QPS": true, "RAM": true, "RHS": true,
+	"RPC": true, "SLA": true, "SMTP": true, "SQL": true, "SSH": true, "TCP": true,
+	"TLS": true, "TTL": true, "UDP": true, "UI": true, "UID": true, "UUID": true,
+	"URI": true, "URL": true, "UTF8": true, "VM": true, "XML": true, "XMPP": true,
+	"XSRF": true, "XSS": true,
+}
+
+type goType struct {
+	name string
+	body string
+}
+
+// goGen generates Go type definitions from an inferred shape. Struct types
+// are named after the member they describe. Structs with identical fields
+// share a single type.
+type goGen struct {
+	types   []*goType
+	bySig   map[string]*goType
+	used    map[string]bool
+	useTime bool
+	useJSON bool
+}
+
+func goSource(s *schema.Shape, name string) string {
+	g := goGen{bySig: map[string]*goType{}, used: map[string]bool{}}
+	var b strings.Builder
+	g.used[name] = true
+	if s != nil && len(nonNullTypes(s)) == 1 && s.Objects == s.Count {
+		body := g.structBody(s, name)
+		g.types = append([]*goType{{name: name, body: body}}, g.types...)
+	} else {
+		g.types = append([]*goType{{name: name, body: g.typeOf(s, name, "")}}, g.types...)
+	}
+	switch {
+	case g.useJSON && g.useTime:
+		b.WriteString("import (\n\t\"encoding/json\"\n\t\"time\"\n)\n\n")
+	case g.useJSON:
+		b.WriteString("import \"encoding/json\"\n\n")
+	case g.useTime:
+		b.WriteString("import \"time\"\n\n")
+	}
+	for i, t := range g.types {
+		if 0 < i {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "type %s %s\n", t.name, t.body)
+	}
+	src, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return b.String()
+	}
+	return string(src)
+}
+
+// typeOf returns the Go type for a shape. The name is used for struct
+// types and the parent name is used to qualify the name if it conflicts
+// with a different struct.
+func (g *goGen) typeOf(s *schema.Shape, name, parent string) string {
+	if s == nil {
+		return "any"
+	}
+	types := nonNullTypes(s)
+	if len(types) != 1 {
+		return "any"
+	}
+	var ptr string
+	if 0 < s.Nulls {
+		ptr = "*"
+	}
+	switch types[0] {
+	case "boolean":
+		return ptr + "bool"
+	case "integer":
+		return ptr + g.intType(s)
+	case "number":
+		return ptr + "float64"
+	case "string":
+		if s.Times == s.Strings {
+			g.useTime = true
+			return ptr + "time.Time"
+		}
+		return ptr + "string"
+	case "array":
+		item := singular(name)
+		if item == name && len(parent) == 0 {
+			item += "Item"
+		}
+		return "[]" + g.typeOf(s.Items, item, parent)
+	}
+	// object
+	body := g.structBody(s, name)
+	if t := g.bySig[body]; t != nil {
+		return ptr + t.name
+	}
+	tname := name
+	if g.used[tname] {
+		tname = parent + name
+	}
+	base := tname
+	for i := 2; g.used[tname]; i++ {
+		tname = fmt.Sprintf("%s%d", base, i)
+	}
+	g.used[tname] = true
+	t := &goType{name: tname, body: body}
+	g.bySig[body] = t
+	g.types = append(g.types, t)
+
+	return ptr + tname
+}
+
+func (g *goGen) structBody(s *schema.Shape, name string) string {
+	keys := make([]string, 0, len(s.Props))
+	for k := range s.Props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteString("struct {\n")
+	fields := map[string]bool{}
+	for _, k := range keys {
+		p := s.Props[k]
+		field := goName(k)
+		for i := 2; fields[field]; i++ {
+			field = fmt.Sprintf("%s%d", goName(k), i)
+		}
+		fields[field] = true
+		typ := g.typeOf(p, field, name)
+		tag := k
+		if p.Count < s.Objects {
+			tag += ",omitempty"
+			// The omitempty option has no effect on structs so a pointer
+			// is used instead.
+			if p.Nulls == 0 && (p.Objects == p.Count || (0 < p.Times && p.Times == p.Count)) {
+				typ = "*" + typ
+			}
+		}
+		fmt.Fprintf(&b, "\t%s %s `json:%q`\n", field, typ, tag)
+	}
+	b.WriteString("}")
+
+	return b.String()
+}
+
+// intType returns int64 if all the integers observed fit in an int64,
+// uint64 if they are all non-negative and fit in a uint64, and json.Number
+// otherwise.
+func (g *goGen) intType(s *schema.Shape) string {
+	switch {
+	case s.IntMin == nil || s.IntMin.IsInt64() && s.IntMax.IsInt64():
+		return "int64"
+	case 0 <= s.IntMin.Sign() && s.IntMax.IsUint64():
+		return "uint64"
+	}
+	g.useJSON = true
+	return "json.Number"
+}
+
+func nonNullTypes(s *schema.Shape) (types []string) {
+	for _, t := range s.Types() {
+		if t != "null" {
+			types = append(types, t)
+		}
+	}
+	return
+}
+
+// goName converts a JSON key into an exported Go identifier. Words are
+// separated by non-alphanumeric characters or a change to upper case.
+func goName(key string) string {
+	var words []string
+	var w []rune
+	var prev rune
+	for _, r := range key {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if 0 < len(w) {
+				words = append(words, string(w))
+				w = w[:0]
+			}
+			prev = r
+			continue
+		}
+		if 0 < len(w) && unicode.IsUpper(r) && unicode.IsLower(prev) {
+			words = append(words, string(w))
+			w = w[:0]
+		}
+		w = append(w, r)
+		prev = r
+	}
+	if 0 < len(w) {
+		words = append(words, string(w))
+	}
+	var b strings.Builder
+	for _, word := range words {
+		if upper := strings.ToUpper(word); initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		rs := []rune(word)
+		rs[0] = unicode.ToUpper(rs[0])
+		b.WriteString(string(rs))
+	}
+	name := b.String()
+	if len(name) == 0 {
+		return "X"
+	}
+	if unicode.IsDigit([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
+
+// singular makes a best effort at converting a plural name to a singular
+// name for the element type of a slice.
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies") && 3 < len(name):
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(name, "ss"), strings.HasSuffix(name, "us"):
+		return name
+	case strings.HasSuffix(name, "s") && 1 < len(name):
+		return name[:len(name)-1]
+	}
+	return name
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/schema"
+)
+
+var (
+	inferMode = ""
+	inferName = "Root"
+	inferrer  schema.Inferrer
+)
+
+// inferAdd adds a document or the extracted values from a document to the
+// inferrer.
+func inferAdd(v any) bool {
+	var ok bool
+	if v, ok = prepare(v); !ok {
+		return false
+	}
+	if 0 < len(extracts) {
+		for _, x := range extracts {
+			for _, v2 := range x.Get(v) {
+				addSample(v2)
+			}
+		}
+		return false
+	}
+	addSample(v)
+
+	return false
+}
+
+func addSample(v any) {
+	if inferMode == "go" {
+		// Strings converted to time.Time become time.Time fields.
+		v = ojg.TimeRFC3339Converter.Convert(v)
+	}
+	inferrer.Add(v)
+}
+
+// writeInferred writes the structure inferred from all the documents read.
+func writeInferred() {
+	if inferMode == "go" {
+		fmt.Println(goSource(inferrer.Shape(), inferName))
+	} else {
+		writeOut(inferrer.Schema())
+	}
+}
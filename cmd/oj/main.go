@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
@@ -20,6 +21,7 @@ import (
 	"github.com/khaf/ojg/oj"
 	"github.com/khaf/ojg/pretty"
 	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/yaml"
 )
 
 const version = "1.9.4"
@@ -31,12 +33,15 @@ var (
 	sortKeys       = false
 	lazy           = false
 	senOut         = false
+	yamlIn         = false
+	yamlOut        = false
 	tab            = false
 	showFnDocs     = false
 	showFilterDocs = false
 	showConf       = false
 	safe           = false
 	mongo          = false
+	canonical      = false
 
 	// If true wrap extracts with an array.
 	wrapExtract = false
@@ -56,9 +61,15 @@ var (
 	html        = false
 	convName    = ""
 	confFile    = ""
+	convConf    any
+	convDefs    = map[string]any{}
 
 	conv    *alt.Converter
 	options *ojg.Options
+	// Number of YAML documents written so far.
+	yamlCount int
+	// Number of documents the plan has been executed on sequentially.
+	planCount int
 )
 
 func init() {
@@ -69,6 +80,8 @@ func init() {
 	flag.BoolVar(&wrapExtract, "w", wrapExtract, "wrap extracts in an array")
 	flag.BoolVar(&lazy, "z", lazy, "lazy mode accepts Simple Encoding Notation (quotes and commas mostly optional)")
 	flag.BoolVar(&senOut, "sen", senOut, "output in Simple Encoding Notation")
+	flag.BoolVar(&yamlIn, "yaml-in", yamlIn, "parse YAML input, multiple documents are processed in order")
+	flag.BoolVar(&yamlOut, "yaml", yamlOut, "output in YAML with block style unless the indent is 0")
 	flag.BoolVar(&tab, "t", tab, "indent with tabs")
 	flag.Var(&exValue{}, "x", "extract path")
 	flag.Var(&matchValue{}, "m", "match equation/script")
@@ -79,16 +92,23 @@ func init() {
 	flag.StringVar(&prettyOpt, "p", prettyOpt, `pretty print with the width, depth, and align as <width>.<max-depth>.<align>`)
 	flag.BoolVar(&html, "html", html, "output colored output as HTML")
 	flag.BoolVar(&safe, "safe", safe, "escape &, <, and > for HTML inclusion")
+	flag.BoolVar(&canonical, "jcs", canonical, "write canonical JSON as defined by RFC 8785, other format options are ignored")
 	flag.StringVar(&confFile, "f", confFile, "configuration file (see -help-config), - indicates no file")
 	flag.BoolVar(&showFnDocs, "fn", showFnDocs, "describe assembly plan functions")
 	flag.BoolVar(&showFnDocs, "help-fn", showFnDocs, "describe assembly plan functions")
 	flag.BoolVar(&showFilterDocs, "help-filter", showFilterDocs, "describe filter operators like [?(@.x == 3)]")
 	flag.BoolVar(&showConf, "help-config", showConf, "describe .oj-config.sen format")
 	flag.BoolVar(&mongo, "mongo", mongo, "parse mongo Javascript output")
+	flag.StringVar(&inferMode, "infer", inferMode, `infer the structure of all the input documents and write it as:
+  schema - a JSON Schema
+  go - Go type definitions`)
+	flag.StringVar(&inferName, "infer-name", inferName, "name of the top level type when inferring Go types")
 	flag.StringVar(&convName, "conv", convName, `apply converter before writing. Supported values are:
   nano - converts integers over 946684800000000000 (2000-01-01) to time
   rcf3339 - converts string in RFC3339 or RFC3339Nano to time
   mongo - converts mongo wrapped values e.g.,  {$numberLong: "123"} => 123
+  <defined> - a converter defined in the converters member of the config
+  <name>,<name> - a comma separated list of built in or defined converters only
   <with-numbers> - if digits are included then time layout is assumed
   <other> - any other is taken to be a key in a map with a string or nano time
 `)
@@ -134,6 +154,13 @@ Oj can also be used to assemble new JSON output from input data. An assembly
 plan that describes how to assemble the new JSON if specified by the -a
 option. The -fn option will display the documentation for assembly.
 
+YAML input is parsed with the -yaml-in option and YAML is written with the
+-yaml option. Each YAML document in a stream is treated as a separate JSON
+document and each document written is separated by a --- marker.
+
+  oj -yaml-in -x spec.containers config.yaml
+  oj -yaml -s myfile.json
+
 Pretty mode output can be used with JSON or the -sen option. It indents
 according to a defined width and maximum depth in a best effort approach. The
 -p takes a pattern of <width>.<max-depth>.<align> where width and max-depth
@@ -206,50 +233,8 @@ func run() (err error) {
 			files = append(files, arg)
 		}
 	}
-	if 0 < len(convName) {
-		switch strings.ToLower(convName) {
-		case "nano":
-			conv = &alt.TimeNanoConverter
-		case "rfc3339":
-			conv = &alt.TimeRFC3339Converter
-		case "mongo":
-			conv = &alt.MongoConverter
-		default:
-			if strings.ContainsAny(convName, "0123456789") {
-				conv = &alt.Converter{
-					String: []func(val string) (any, bool){
-						func(val string) (any, bool) {
-							if len(val) == len(convName) {
-								if t, err := time.ParseInLocation(convName, val, time.UTC); err == nil {
-									return t, true
-								}
-							}
-							return val, false
-						},
-					},
-				}
-			} else {
-				conv = &alt.Converter{
-					Map: []func(val map[string]any) (any, bool){
-						func(val map[string]any) (any, bool) {
-							if len(val) == 1 {
-								switch tv := val[convName].(type) {
-								case string:
-									for _, layout := range []string{time.RFC3339Nano, time.RFC3339, "2006-01-02"} {
-										if t, err := time.ParseInLocation(layout, tv, time.UTC); err == nil {
-											return t, true
-										}
-									}
-								case int64:
-									return time.Unix(0, tv), true
-								}
-							}
-							return val, false
-						},
-					},
-				}
-			}
-		}
+	if conv, err = buildConverter(); err != nil {
+		return err
 	}
 	var p oj.SimpleParser
 	switch {
@@ -260,6 +245,8 @@ func run() (err error) {
 		if conv == nil {
 			conv = &alt.MongoConverter
 		}
+	case yamlIn:
+		p = &yaml.Parser{}
 	case lazy:
 		p = &sen.Parser{}
 	default:
@@ -284,11 +271,42 @@ func run() (err error) {
 		}
 		plan = asm.NewPlan(plist)
 	}
+	cb := write
+	switch {
+	case 0 < len(inferMode):
+		if inferMode != "schema" && inferMode != "go" {
+			return fmt.Errorf("%q is not a valid infer mode, must be schema or go", inferMode)
+		}
+		cb = inferAdd
+	case plan != nil && len(extracts) == 0 && !showRoot && !sharesRoot(plan.Simplify()):
+		// Documents are handed off to the runner so the parser must not
+		// reuse maps. Each document gets its own root so plans that keep
+		// state in the root or print the root are executed sequentially
+		// by write() instead.
+		if op, ok := p.(*oj.Parser); ok {
+			op.Reuse = false
+		}
+		docs := make(chan any, runtime.NumCPU())
+		done := make(chan error, 1)
+		go runPlan(docs, done)
+		cb = func(v any) bool {
+			if v, ok := prepare(v); ok {
+				docs <- v
+			}
+			return false
+		}
+		defer func() {
+			close(docs)
+			if perr := <-done; perr != nil && err == nil {
+				err = perr
+			}
+		}()
+	}
 	if 0 < len(files) {
 		var f *os.File
 		for _, file := range files {
 			if f, err = os.Open(file); err == nil {
-				_, err = p.ParseReader(f, write)
+				_, err = p.ParseReader(f, cb)
 				_ = f.Close()
 			}
 			if err != nil {
@@ -297,25 +315,77 @@ func run() (err error) {
 		}
 	}
 	if 0 < len(input) {
-		if _, err = p.Parse(input, write); err != nil {
+		if _, err = p.Parse(input, cb); err != nil {
 			panic(err)
 		}
 	}
 	if len(files) == 0 && len(input) == 0 {
-		if _, err = p.ParseReader(os.Stdin, write); err != nil {
+		if _, err = p.ParseReader(os.Stdin, cb); err != nil {
 			panic(err)
 		}
 	}
+	if 0 < len(inferMode) {
+		writeInferred()
+	}
 	if showRoot && plan != nil {
-		plan = nil
 		delete(root, "src")
 		delete(root, "asm")
-		write(root)
+		writeOut(root)
 	}
 	return
 }
 
-func write(v any) bool {
+// runPlan executes the assembly plan on each document received with an
+// asm.Runner and writes the results in order. Output stops at the first
+// plan error which is sent on the done channel.
+func runPlan(docs chan any, done chan error) {
+	var err error
+	(&asm.Runner{Plan: plan, Root: root}).RunChan(docs, func(rec *asm.Record) {
+		switch {
+		case err != nil:
+			// Drain the remaining records.
+		case rec.Err != nil:
+			err = fmt.Errorf("record %d: %w", rec.Index, rec.Err)
+		default:
+			writeOut(rec.Asm())
+		}
+	})
+	done <- err
+}
+
+// sharesRoot returns true if a simplified plan modifies the root outside of
+// $.asm with set, setall, del, or delall. Paths that are not literal are
+// assumed to modify the root.
+func sharesRoot(v any) bool {
+	switch tv := v.(type) {
+	case []any:
+		if 1 < len(tv) {
+			switch tv[0] {
+			case "set", "setall", "del", "delall":
+				path, _ := tv[1].(string)
+				if path != "$.asm" && !strings.HasPrefix(path, "$.asm.") && !strings.HasPrefix(path, "$.asm[") {
+					return true
+				}
+			}
+		}
+		for _, item := range tv {
+			if sharesRoot(item) {
+				return true
+			}
+		}
+	case map[string]any:
+		for _, mv := range tv {
+			if sharesRoot(mv) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// prepare applies the conversion, match, and delete options to a
+// document. If the document does not match then false is returned.
+func prepare(v any) (any, bool) {
 	if conv != nil {
 		v = conv.Convert(v)
 	}
@@ -328,50 +398,61 @@ func write(v any) bool {
 			}
 		}
 		if !match {
-			return false
+			return nil, false
 		}
 	}
 	for _, x := range dels {
 		_ = x.Del(v)
 	}
-	switch {
-	case 0 < len(extracts):
+	return v, true
+}
+
+func write(v any) bool {
+	var ok bool
+	if v, ok = prepare(v); !ok {
+		return false
+	}
+	if 0 < len(extracts) {
 		if wrapExtract {
 			var w []any
 			for _, x := range extracts {
 				w = append(w, x.Get(v)...)
 			}
-			if senOut {
-				writeSEN(w)
-			} else {
-				writeJSON(w)
-			}
+			writeOut(w)
 		} else {
 			for _, x := range extracts {
 				for _, v2 := range x.Get(v) {
-					if senOut {
-						writeSEN(v2)
-					} else {
-						writeJSON(v2)
-					}
+					writeOut(v2)
 				}
 			}
 		}
+		return false
+	}
+	if plan != nil {
+		// As with the runner, $.asm is reset for each document but the
+		// rest of the root is shared.
+		delete(root, "asm")
+		root["src"] = v
+		if err := plan.Execute(root); err != nil {
+			panic(fmt.Errorf("record %d: %w", planCount, err))
+		}
+		planCount++
+		v = root["asm"]
+	}
+	writeOut(v)
+
+	return false
+}
+
+func writeOut(v any) {
+	switch {
+	case yamlOut:
+		writeYAML(v)
 	case senOut:
 		writeSEN(v)
 	default:
-		if plan != nil {
-			root["src"] = v
-			if err := plan.Execute(root); err != nil {
-				fmt.Fprintf(os.Stderr, "*-*-* %s\n", err)
-				os.Exit(1)
-			} else {
-				v = root["asm"]
-			}
-		}
 		writeJSON(v)
 	}
-	return false
 }
 
 func writeJSON(v any) {
@@ -390,6 +471,7 @@ func writeJSON(v any) {
 		o.HTMLUnsafe = !safe
 		o.TimeFormat = time.RFC3339Nano
 		o.Sort = sortKeys
+		o.Canonical = canonical
 		if html {
 			o.HTMLUnsafe = false
 			if color {
@@ -408,7 +490,7 @@ func writeJSON(v any) {
 	if 0 < len(prettyOpt) {
 		parsePrettyOpt()
 	}
-	if prettyOn {
+	if prettyOn && !options.Canonical {
 		_ = pretty.WriteJSON(os.Stdout, v, options, float64(width)+float64(maxDepth)/10.0, align)
 	} else {
 		_ = oj.Write(os.Stdout, v, options)
@@ -449,6 +531,20 @@ func writeSEN(v any) {
 	_, _ = os.Stdout.Write([]byte{'\n'})
 }
 
+func writeYAML(v any) {
+	if options == nil {
+		o := yaml.DefaultOptions
+		o.Indent = indent
+		o.Sort = sortKeys
+		options = &o
+	}
+	if 0 < yamlCount {
+		_, _ = os.Stdout.Write([]byte("---\n"))
+	}
+	yamlCount++
+	_ = yaml.Write(os.Stdout, v, options)
+}
+
 func parsePrettyOpt() {
 	if 0 < len(prettyOpt) {
 		parts := strings.Split(prettyOpt, ".")
@@ -523,6 +619,85 @@ func (dv delValue) Set(s string) error {
 	return err
 }
 
+// buildConverter returns the converter described by the -conv option or the
+// conv member of the configuration. The -conv option can be a comma
+// separated list of built in converters and converters defined in the
+// converters member of the configuration. Every name in a list must be
+// built in or defined while a single name can also be a time layout or a
+// map key as described by namedConverter.
+func buildConverter() (*alt.Converter, error) {
+	if len(convName) == 0 {
+		if convConf != nil {
+			return ojg.LoadConverter(convConf)
+		}
+		return nil, nil
+	}
+	list := strings.Contains(convName, ",")
+	var convs []*alt.Converter
+	for _, name := range strings.Split(convName, ",") {
+		name = strings.TrimSpace(name)
+		if c := ojg.BuiltinConverter(name); c != nil {
+			convs = append(convs, c)
+			continue
+		}
+		def, has := convDefs[name]
+		if !has {
+			if list {
+				return nil, fmt.Errorf("-conv %s: %q is not a built in or defined converter", convName, name)
+			}
+			return namedConverter(convName), nil
+		}
+		c, err := ojg.LoadConverter(def)
+		if err != nil {
+			return nil, fmt.Errorf("converter %s: %w", name, err)
+		}
+		convs = append(convs, c)
+	}
+	if len(convs) == 1 {
+		return convs[0], nil
+	}
+	return ojg.CombineConverters(convs...), nil
+}
+
+// namedConverter returns a converter for a time layout if the name includes
+// digits otherwise the name is taken to be the key of a single member map
+// with a string or nano time value.
+func namedConverter(convName string) *alt.Converter {
+	if strings.ContainsAny(convName, "0123456789") {
+		return &alt.Converter{
+			String: []func(val string) (any, bool){
+				func(val string) (any, bool) {
+					if len(val) == len(convName) {
+						if t, err := time.ParseInLocation(convName, val, time.UTC); err == nil {
+							return t, true
+						}
+					}
+					return val, false
+				},
+			},
+		}
+	}
+	return &alt.Converter{
+		Map: []func(val map[string]any) (any, bool){
+			func(val map[string]any) (any, bool) {
+				if len(val) == 1 {
+					switch tv := val[convName].(type) {
+					case string:
+						for _, layout := range []string{time.RFC3339Nano, time.RFC3339, "2006-01-02"} {
+							if t, err := time.ParseInLocation(layout, tv, time.UTC); err == nil {
+								return t, true
+							}
+						}
+					case int64:
+						return time.Unix(0, tv), true
+					}
+				}
+				return val, false
+			},
+		},
+	}
+}
+
 func loadConfig() {
 	var conf any
 	if 0 < len(confFile) {
@@ -583,7 +758,15 @@ func applyConf(conf any) {
 	safe, _ = jp.C("html-safe").First(conf).(bool)
 	lazy, _ = jp.C("lazy").First(conf).(bool)
 	senOut, _ = jp.C("sen").First(conf).(bool)
+	yamlIn, _ = jp.C("yaml-in").First(conf).(bool)
+	yamlOut, _ = jp.C("yaml").First(conf).(bool)
 	convName, _ = jp.C("conv").First(conf).(string)
+	if len(convName) == 0 {
+		convConf = jp.C("conv").First(conf)
+	}
+	if defs, ok := jp.C("converters").First(conf).(map[string]any); ok {
+		convDefs = defs
+	}
 	mongo, _ = jp.C("mongo").First(conf).(bool)
 
 	setOptionsColor(conf, "bool", setBoolColor)
@@ -842,7 +1025,24 @@ The file format (SEN with comments) is:
   html-safe: false
   lazy: true // -z option, lazy read for SEN format
   sen: true
+  yaml-in: false // -yaml-in option, read YAML input
+  yaml: false // -yaml option, write YAML output
+  // The conv value can be a built in converter name (nano, rfc3339, or
+  // mongo), a converter defined in converters, a comma separated list of
+  // those, or a converter spec or list of specs.
   conv: rfc3339
+  // Converter specs have a type (int, float, number, string, or object),
+  // match rules (regexp, min, max, key, and single), and a target (to) of
+  // time, int, float, string, bool, or value. Times use the layouts or the
+  // unit (s, ms, us, or ns) options. Use with -conv epoch,rfc3339.
+  converters: {
+    epoch: {type: int min: 946684800 max: 4102444800 to: time unit: s}
+    hex: {type: string regexp: "^0x[0-9a-fA-F]+$" to: int}
+    wrapped: [
+      {type: object key: "$date" single: true to: time layout: "2006-01-02"}
+      {type: object key: "$value" single: true to: value}
+    ]
+  }
   mongo: false
 }
 `)
@@ -0,0 +1,110 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package ojg
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// Codec encodes values of a specific type as simple data and decodes simple
+// data back into values of that type. Codecs make it possible to control
+// how types that can not be modified, such as those from third party
+// packages, are written and recomposed.
+type Codec struct {
+	// Encode converts a value of the registered type to simple data. If nil
+	// then values are encoded as if there were no codec.
+	Encode func(v any) any
+
+	// Decode converts simple data to a value of the registered type. If
+	// nil then values are decoded as if there were no codec.
+	Decode func(v any) (any, error)
+}
+
+var (
+	codecMu sync.Mutex
+	// codecs holds a map[reflect.Type]*Codec that is replaced and never
+	// modified once stored.
+	codecs atomic.Value
+)
+
+// RegisterCodec registers a codec for a type. The codec is used by the oj
+// and sen writers, the pretty package, alt.Decompose(), alt.Generify(), and
+// alt.Recomposer. The codec is also used for pointers to the type with a
+// nil pointer written as null. Struct field information is cached so codecs
+// should be registered before values that include the type are written or
+// recomposed, typically in an init function. A nil codec removes the
+// registration.
+func RegisterCodec(rt reflect.Type, codec *Codec) error {
+	if rt == nil {
+		return fmt.Errorf("a codec type can not be nil")
+	}
+	codecMu.Lock()
+	defer codecMu.Unlock()
+
+	cur, _ := codecs.Load().(map[reflect.Type]*Codec)
+	reg := make(map[reflect.Type]*Codec, len(cur)+1)
+	for k, c := range cur {
+		reg[k] = c
+	}
+	if codec == nil {
+		delete(reg, rt)
+	} else {
+		reg[rt] = codec
+	}
+	codecs.Store(reg)
+
+	return nil
+}
+
+// LookupCodec returns the codec registered for a type or nil if there is no
+// codec for the type.
+func LookupCodec(rt reflect.Type) *Codec {
+	reg, _ := codecs.Load().(map[reflect.Type]*Codec)
+	if len(reg) == 0 {
+		return nil
+	}
+	return reg[rt]
+}
+
+// FindEncoder returns the encode function of the codec registered for the
+// type of the value or nil if there is none. A pointer to a type with a codec
+// is encoded as the value pointed to or as nil if the pointer is nil.
+func FindEncoder(v any) func(v any) any {
+	if v == nil {
+		return nil
+	}
+	return LookupEncoder(reflect.TypeOf(v))
+}
+
+// LookupEncoder returns the encode function of the codec registered for a
+// type or nil if there is none. If the type is a pointer to a type with a
+// codec then the returned function encodes the value pointed to and encodes
+// a nil pointer as nil.
+func LookupEncoder(rt reflect.Type) func(v any) any {
+	reg, _ := codecs.Load().(map[reflect.Type]*Codec)
+	if len(reg) == 0 {
+		return nil
+	}
+	if c := reg[rt]; c != nil {
+		return c.Encode
+	}
+	if rt.Kind() == reflect.Ptr {
+		if c := reg[rt.Elem()]; c != nil && c.Encode != nil {
+			return ptrEncoder(c.Encode)
+		}
+	}
+	return nil
+}
+
+func ptrEncoder(encode func(v any) any) func(v any) any {
+	return func(v any) any {
+		rv := reflect.ValueOf(v)
+		if rv.IsNil() {
+			return nil
+		}
+		return encode(rv.Elem().Interface())
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package ojg_test
+
+import (
+	"fmt"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/alt"
+	"github.com/khaf/ojg/gen"
+	"github.com/khaf/ojg/oj"
+	"github.com/khaf/ojg/pretty"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+// codecLevel is a named type with a basic kind that is written as a name.
+type codecLevel int
+
+// codecHost includes a third party type and a named basic type that have
+// codecs registered.
+type codecHost struct {
+	Addr  netip.Addr
+	Level codecLevel
+	Addrs []netip.Addr
+	Opt   *netip.Addr `json:",omitempty"`
+}
+
+var codecLevels = []string{"low", "mid", "high"}
+
+func registerTestCodecs(t *testing.T) {
+	err := ojg.RegisterCodec(reflect.TypeOf(netip.Addr{}), &ojg.Codec{
+		Encode: func(v any) any {
+			return map[string]any{"ip": v.(netip.Addr).String()}
+		},
+		Decode: func(v any) (any, error) {
+			m, _ := v.(map[string]any)
+			s, _ := m["ip"].(string)
+			return netip.ParseAddr(s)
+		},
+	})
+	tt.Nil(t, err)
+	err = ojg.RegisterCodec(reflect.TypeOf(codecLevel(0)), &ojg.Codec{
+		Encode: func(v any) any {
+			return codecLevels[v.(codecLevel)]
+		},
+		Decode: func(v any) (any, error) {
+			for i, s := range codecLevels {
+				if s == v {
+					return codecLevel(i), nil
+				}
+			}
+			return nil, fmt.Errorf("%v is not a level", v)
+		},
+	})
+	tt.Nil(t, err)
+	t.Cleanup(func() {
+		_ = ojg.RegisterCodec(reflect.TypeOf(netip.Addr{}), nil)
+		_ = ojg.RegisterCodec(reflect.TypeOf(codecLevel(0)), nil)
+	})
+}
+
+func codecSample() *codecHost {
+	return &codecHost{
+		Addr:  netip.MustParseAddr("10.0.0.1"),
+		Level: 2,
+		Addrs: []netip.Addr{netip.MustParseAddr("::1")},
+	}
+}
+
+func TestCodecWrite(t *testing.T) {
+	registerTestCodecs(t)
+	expect := `{"Addr":{"ip":"10.0.0.1"},"Addrs":[{"ip":"::1"}],"Level":"high"}`
+	opt := ojg.Options{Sort: true, UseTags: true}
+
+	tt.Equal(t, expect, oj.JSON(codecSample(), &opt))
+	tt.Equal(t, `{"ip":"10.0.0.1"}`, oj.JSON(netip.MustParseAddr("10.0.0.1")))
+	tt.Equal(t, `[{ip:"::1"}low]`, sen.String([]any{netip.MustParseAddr("::1"), codecLevel(0)}))
+
+	opt.Indent = 2
+	out := oj.JSON(codecSample(), &opt)
+	tt.Equal(t, expect, strings.Join(strings.Fields(strings.ReplaceAll(out, `": `, `":`)), ""))
+
+	out = sen.String(codecSample(), &ojg.Options{Sort: true, UseTags: true})
+	tt.Equal(t, `{Addr:{ip:"10.0.0.1"} Addrs:[{ip:"::1"}] Level:high}`, out)
+	out = sen.String(codecSample(), &ojg.Options{Sort: true, UseTags: true, Indent: 2})
+	tt.Equal(t, expect, oj.JSON(sen.MustParse([]byte(out)), &ojg.Options{Sort: true}))
+
+	out = pretty.JSON(codecSample(), &ojg.Options{Sort: true, UseTags: true})
+	tt.Equal(t, expect, oj.JSON(oj.MustParseString(out), &ojg.Options{Sort: true}))
+
+	out = oj.JSON(codecSample(), &ojg.Options{Sort: true, Color: true})
+	tt.Equal(t, true, strings.Contains(out, "high"))
+}
+
+func TestCodecDecompose(t *testing.T) {
+	registerTestCodecs(t)
+
+	v := alt.Decompose(codecSample(), &ojg.Options{UseTags: true})
+	tt.Equal(t,
+		map[string]any{
+			"Addr":  map[string]any{"ip": "10.0.0.1"},
+			"Addrs": []any{map[string]any{"ip": "::1"}},
+			"Level": "high",
+		}, v)
+
+	g := alt.Generify(codecSample(), &ojg.Options{UseTags: true})
+	tt.Equal(t, gen.String("high"), g.(gen.Object)["Level"])
+	tt.Equal(t, gen.Object{"ip": gen.String("10.0.0.1")}, g.(gen.Object)["Addr"])
+	tt.Equal(t, gen.String("low"), alt.Generify(codecLevel(0)))
+}
+
+func TestCodecRecompose(t *testing.T) {
+	registerTestCodecs(t)
+
+	src := map[string]any{
+		"Addr":  map[string]any{"ip": "10.0.0.1"},
+		"Level": "mid",
+		"Addrs": []any{map[string]any{"ip": "::1"}},
+		"Opt":   map[string]any{"ip": "192.168.1.1"},
+	}
+	var host codecHost
+	_, err := alt.Recompose(src, &host)
+	tt.Nil(t, err)
+	tt.Equal(t, "10.0.0.1", host.Addr.String())
+	tt.Equal(t, codecLevel(1), host.Level)
+	tt.Equal(t, "::1", host.Addrs[0].String())
+	tt.Equal(t, "192.168.1.1", host.Opt.String())
+
+	src["Level"] = "max"
+	_, err = alt.Recompose(src, &host)
+	tt.NotNil(t, err)
+}
+
+type codecPtrs struct {
+	Addr  *netip.Addr
+	Level *codecLevel
+	None  *netip.Addr
+	Skip  *netip.Addr `json:",omitempty"`
+}
+
+func TestCodecPointer(t *testing.T) {
+	registerTestCodecs(t)
+	addr := netip.MustParseAddr("10.0.0.1")
+	level := codecLevel(1)
+	sample := &codecPtrs{Addr: &addr, Level: &level}
+	expect := `{"Addr":{"ip":"10.0.0.1"},"Level":"mid","None":null}`
+	opt := ojg.Options{Sort: true, UseTags: true}
+
+	tt.Equal(t, expect, oj.JSON(sample, &opt))
+	tt.Equal(t, `{"ip":"10.0.0.1"}`, oj.JSON(&addr))
+	tt.Equal(t, `[mid null]`, sen.String([]any{&level, (*codecLevel)(nil)}))
+
+	opt.Indent = 2
+	out := oj.JSON(sample, &opt)
+	tt.Equal(t, expect, oj.JSON(oj.MustParseString(out), &ojg.Options{Sort: true}))
+
+	out = sen.String(sample, &ojg.Options{Sort: true, UseTags: true})
+	tt.Equal(t, `{Addr:{ip:"10.0.0.1"} Level:mid None:null}`, out)
+	out = sen.String(sample, &ojg.Options{Sort: true, UseTags: true, Indent: 2})
+	tt.Equal(t, expect, oj.JSON(sen.MustParse([]byte(out)), &ojg.Options{Sort: true}))
+
+	out = pretty.JSON(sample, &ojg.Options{Sort: true, UseTags: true})
+	tt.Equal(t, expect, oj.JSON(oj.MustParseString(out), &ojg.Options{Sort: true}))
+
+	v := alt.Decompose(sample, &ojg.Options{UseTags: true})
+	tt.Equal(t,
+		map[string]any{
+			"Addr":  map[string]any{"ip": "10.0.0.1"},
+			"Level": "mid",
+			"None":  nil,
+		}, v)
+	g := alt.Generify(sample, &ojg.Options{UseTags: true})
+	tt.Equal(t, gen.String("mid"), g.(gen.Object)["Level"])
+
+	var back codecPtrs
+	_, err := alt.Recompose(v, &back)
+	tt.Nil(t, err)
+	tt.Equal(t, "10.0.0.1", back.Addr.String())
+	tt.Equal(t, level, *back.Level)
+	tt.Nil(t, back.None)
+}
+
+func TestCodecRegister(t *testing.T) {
+	tt.NotNil(t, ojg.RegisterCodec(nil, &ojg.Codec{}))
+	tt.Nil(t, ojg.LookupCodec(reflect.TypeOf(codecLevel(0))))
+	tt.Nil(t, ojg.FindEncoder(codecLevel(0)))
+	tt.Nil(t, ojg.LookupEncoder(reflect.TypeOf((*codecLevel)(nil))))
+}
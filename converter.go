@@ -14,7 +14,9 @@ const fracMax = 10000000.0
 
 // Converter types are used to convert data element to alternate
 // values. Common uses are to match a pattern such as strings representing
-// dates to time.Time.
+// dates to time.Time. When set in the Options a Converter is also applied
+// by the oj, sen, and pretty writers to mask strings, reformat times, or
+// round floats as data is written.
 type Converter struct {
 	// Int are a slice of functions to match and convert Ints.
 	Int []func(val int64) (any, bool)
@@ -30,6 +32,15 @@ type Converter struct {
 
 	// Array are a slice of functions to match and convert Arrays.
 	Array []func(val []any) (any, bool)
+
+	// Bool are a slice of functions to match and convert Bools.
+	Bool []func(val bool) (any, bool)
+
+	// Time are a slice of functions to match and convert Times.
+	Time []func(val time.Time) (any, bool)
+
+	// Key are a slice of functions to match and convert the keys of Maps.
+	Key []func(key string) (string, bool)
 }
 
 var (
@@ -110,11 +121,19 @@ var (
 // will remain the same but will be modified if any of it's members are
 // converted.
 func (c *Converter) Convert(v any) any {
-	v, _ = c.convert(v)
+	v, _ = c.convert(v, true)
 	return v
 }
 
-func (c *Converter) convert(v any) (any, bool) {
+// ConvertValue converts a single value according to the conversion
+// functions of the converter. Unlike Convert the members of a map or slice
+// are neither converted nor modified. The converted value and true are
+// returned if one of the functions matched.
+func (c *Converter) ConvertValue(v any) (any, bool) {
+	return c.convert(v, false)
+}
+
+func (c *Converter) convert(v any, deep bool) (any, bool) {
 	switch tv := v.(type) {
 	case int64:
 		for _, fun := range c.Int {
@@ -134,14 +153,29 @@ func (c *Converter) convert(v any) (any, bool) {
 				return cv, true
 			}
 		}
+	case bool:
+		for _, fun := range c.Bool {
+			if cv, ok := fun(tv); ok {
+				return cv, true
+			}
+		}
+	case time.Time:
+		for _, fun := range c.Time {
+			if cv, ok := fun(tv); ok {
+				return cv, true
+			}
+		}
 	case []any:
 		for _, fun := range c.Array {
 			if cv, ok := fun(tv); ok {
 				return cv, true
 			}
 		}
+		if !deep {
+			break
+		}
 		for i, m := range tv {
-			if cv, ok := c.convert(m); ok {
+			if cv, ok := c.convert(m, true); ok {
 				tv[i] = cv
 			}
 		}
@@ -151,40 +185,73 @@ func (c *Converter) convert(v any) (any, bool) {
 				return cv, true
 			}
 		}
+		if !deep {
+			break
+		}
+		// Collect the changes first so that a converted member is not
+		// visited again.
+		changes := map[string]any{}
 		for k, m := range tv {
-			if cv, ok := c.convert(m); ok {
-				tv[k] = cv
+			if cv, ok := c.convert(m, true); ok {
+				changes[k] = cv
+			}
+		}
+		for k, cv := range changes {
+			tv[k] = cv
+		}
+		if 0 < len(c.Key) {
+			renames := map[string]string{}
+			for k := range tv {
+				if nk := c.ConvertKey(k); nk != k {
+					renames[k] = nk
+				}
+			}
+			for k, nk := range renames {
+				v := tv[k]
+				delete(tv, k)
+				tv[nk] = v
 			}
 		}
 
 	case int:
-		return c.convert(int64(tv))
+		return c.convert(int64(tv), deep)
 	case int8:
-		return c.convert(int64(tv))
+		return c.convert(int64(tv), deep)
 	case int16:
-		return c.convert(int64(tv))
+		return c.convert(int64(tv), deep)
 	case int32:
-		return c.convert(int64(tv))
+		return c.convert(int64(tv), deep)
 	case uint:
-		return c.convert(int64(tv))
+		return c.convert(int64(tv), deep)
 	case uint8:
-		return c.convert(int64(tv))
+		return c.convert(int64(tv), deep)
 	case uint16:
-		return c.convert(int64(tv))
+		return c.convert(int64(tv), deep)
 	case uint32:
-		return c.convert(int64(tv))
+		return c.convert(int64(tv), deep)
 	case uint64:
-		return c.convert(int64(tv))
+		return c.convert(int64(tv), deep)
 	case float32:
 		// This small rounding makes the conversion from 32 bit to 64 bit
 		// display nicer.
 		f, i := math.Frexp(float64(tv))
 		f = float64(int64(f*fracMax)) / fracMax
-		return c.convert(math.Ldexp(f, i))
+		return c.convert(math.Ldexp(f, i), deep)
 	}
 	return v, false
 }
 
+// ConvertKey returns the key converted by the first of the Key functions
+// that matches or the key unchanged if none match.
+func (c *Converter) ConvertKey(key string) string {
+	for _, fun := range c.Key {
+		if ck, ok := fun(key); ok {
+			return ck
+		}
+	}
+	return key
+}
+
 // Convert a value according to the conversion functions provided. If the
 // value is a map or slice and not converted itself the provided value will
 // remain the same but will be modified if any of it's members are converted.
@@ -202,9 +269,15 @@ func Convert(v any, funcs ...any) any {
 			c.Map = append(c.Map, tf)
 		case func(val []any) (any, bool):
 			c.Array = append(c.Array, tf)
+		case func(val bool) (any, bool):
+			c.Bool = append(c.Bool, tf)
+		case func(val time.Time) (any, bool):
+			c.Time = append(c.Time, tf)
+		case func(key string) (string, bool):
+			c.Key = append(c.Key, tf)
 		}
 	}
-	v, _ = c.convert(v)
+	v, _ = c.convert(v, true)
 
 	return v
 }
@@ -4,6 +4,7 @@ package ojg_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -149,3 +150,28 @@ func TestConverterMongo(t *testing.T) {
 	tt.Equal(t, map[string]any{"$numberDecimal": "123.456", "x": 3}, v2[4])
 	tt.Equal(t, map[string]any{"$numberDecimal": 3}, v2[5])
 }
+
+func TestConverterBoolTimeKey(t *testing.T) {
+	tm := time.Date(2021, time.March, 5, 10, 11, 12, 0, time.UTC)
+	val := map[string]any{
+		"flag":  true,
+		"when":  tm,
+		"Inner": map[string]any{"Deep": false},
+	}
+	v2 := ojg.Convert(val,
+		func(val bool) (any, bool) { return map[bool]string{true: "yes", false: "no"}[val], true },
+		func(val time.Time) (any, bool) { return val.Format("2006-01-02"), true },
+		func(key string) (string, bool) { return strings.ToLower(key), key != strings.ToLower(key) },
+	)
+	tt.Equal(t, map[string]any{
+		"flag":  "yes",
+		"when":  "2021-03-05",
+		"inner": map[string]any{"deep": "no"},
+	}, v2)
+
+	c := ojg.Converter{Key: []func(key string) (string, bool){
+		func(key string) (string, bool) { return "x", key == "a" },
+	}}
+	tt.Equal(t, "x", c.ConvertKey("a"))
+	tt.Equal(t, "b", c.ConvertKey("b"))
+}
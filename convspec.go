@@ -0,0 +1,425 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package ojg
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConvSpec is a declarative description of a conversion. It identifies the
+// type of value to convert, the rules a value must match, and what the
+// value is converted to. Specs are compiled into a Converter with
+// NewConverter() or loaded from decoded JSON or SEN such as the
+// .oj-config.sen file with LoadConverter().
+type ConvSpec struct {
+	// Type of the value to convert. Supported values are int, float,
+	// number (int or float), string, bool, time, object, and key. A key
+	// spec converts the keys of objects and must have a string target.
+	Type string
+
+	// Key for object specs is the key of the member that must be present
+	// in the object. The member value is the value matched and converted.
+	Key string
+
+	// Single for object specs requires that the Key member be the only
+	// member of the object.
+	Single bool
+
+	// Regexp if not empty must match string values.
+	Regexp string
+
+	// Replace if not empty and the target is string replaces the matches
+	// of Regexp with the Replace value which can include $1 style
+	// references to the submatches. This is useful for masking strings.
+	Replace string
+
+	// Min if not nil is the minimum numeric value that will match.
+	Min *float64
+
+	// Max if not nil is the maximum numeric value that will match.
+	Max *float64
+
+	// To is the target of the conversion. Supported values are time, int,
+	// float, string, bool, and value. The value target uses the matched
+	// value as is which is useful for unwrapping object members.
+	To string
+
+	// Layouts are the time layouts tried when converting a string to a
+	// time. If empty then RFC3339Nano, RFC3339, and 2006-01-02 are used.
+	// When converting a time to a string the first layout is used.
+	Layouts []string
+
+	// Unit of numbers converted to or from a time. Supported values are s,
+	// ms, us, and ns. The default is ns.
+	Unit string
+}
+
+var builtinConverters = map[string]*Converter{
+	"nano":    &TimeNanoConverter,
+	"rfc3339": &TimeRFC3339Converter,
+	"mongo":   &MongoConverter,
+}
+
+// BuiltinConverter returns the named built in converter or nil if there is
+// no converter with that name. The names are nano, rfc3339, and mongo.
+func BuiltinConverter(name string) *Converter {
+	return builtinConverters[strings.ToLower(name)]
+}
+
+// NewConverter compiles the specs into a Converter. The conversion
+// functions are applied in the order of the specs.
+func NewConverter(specs ...*ConvSpec) (*Converter, error) {
+	var c Converter
+	for _, spec := range specs {
+		if err := spec.addTo(&c); err != nil {
+			return nil, err
+		}
+	}
+	return &c, nil
+}
+
+// CombineConverters returns a new Converter that applies the conversion
+// functions of each of the converters in order.
+func CombineConverters(convs ...*Converter) *Converter {
+	var c Converter
+	for _, cv := range convs {
+		if cv == nil {
+			continue
+		}
+		c.Int = append(c.Int, cv.Int...)
+		c.Float = append(c.Float, cv.Float...)
+		c.String = append(c.String, cv.String...)
+		c.Map = append(c.Map, cv.Map...)
+		c.Array = append(c.Array, cv.Array...)
+		c.Bool = append(c.Bool, cv.Bool...)
+		c.Time = append(c.Time, cv.Time...)
+		c.Key = append(c.Key, cv.Key...)
+	}
+	return &c
+}
+
+// LoadConverter builds a Converter from a description that is usually
+// decoded from JSON or SEN. The description can be the name of a built in
+// converter, a map[string]any with keys that match the ConvSpec fields in
+// lowercase, a *ConvSpec, or a []any of any of those which are combined in
+// order. As an example in SEN:
+//
+//	[
+//	  rfc3339
+//	  {type: string regexp: "^0x[0-9a-f]+$" to: int}
+//	  {type: object key: "$epoch" single: true to: time unit: s}
+//	]
+func LoadConverter(v any) (*Converter, error) {
+	switch tv := v.(type) {
+	case string:
+		if c := BuiltinConverter(tv); c != nil {
+			return c, nil
+		}
+		return nil, fmt.Errorf("%s is not a built in converter", tv)
+	case *ConvSpec:
+		return NewConverter(tv)
+	case map[string]any:
+		spec, err := convSpecFromMap(tv)
+		if err != nil {
+			return nil, err
+		}
+		return NewConverter(spec)
+	case []any:
+		convs := make([]*Converter, 0, len(tv))
+		for _, m := range tv {
+			c, err := LoadConverter(m)
+			if err != nil {
+				return nil, err
+			}
+			convs = append(convs, c)
+		}
+		return CombineConverters(convs...), nil
+	}
+	return nil, fmt.Errorf("a %T can not be loaded as a converter", v)
+}
+
+func convSpecFromMap(m map[string]any) (*ConvSpec, error) {
+	var spec ConvSpec
+	for k, v := range m {
+		var ok bool
+		switch strings.ToLower(k) {
+		case "type":
+			spec.Type, ok = v.(string)
+		case "key":
+			spec.Key, ok = v.(string)
+		case "single":
+			spec.Single, ok = v.(bool)
+		case "regexp":
+			spec.Regexp, ok = v.(string)
+		case "min":
+			var f float64
+			if f, ok = specNumber(v); ok {
+				spec.Min = &f
+			}
+		case "max":
+			var f float64
+			if f, ok = specNumber(v); ok {
+				spec.Max = &f
+			}
+		case "to":
+			spec.To, ok = v.(string)
+		case "layout":
+			var s string
+			if s, ok = v.(string); ok {
+				spec.Layouts = []string{s}
+			}
+		case "layouts":
+			var list []any
+			if list, ok = v.([]any); ok {
+				for _, lv := range list {
+					var s string
+					if s, ok = lv.(string); !ok {
+						break
+					}
+					spec.Layouts = append(spec.Layouts, s)
+				}
+			}
+		case "replace":
+			spec.Replace, ok = v.(string)
+		case "unit":
+			spec.Unit, ok = v.(string)
+		default:
+			return nil, fmt.Errorf("%s is not a converter spec field", k)
+		}
+		if !ok {
+			return nil, fmt.Errorf("%v is not a valid converter spec %s", v, k)
+		}
+	}
+	return &spec, nil
+}
+
+func specNumber(v any) (f float64, ok bool) {
+	switch tv := v.(type) {
+	case int64:
+		f, ok = float64(tv), true
+	case int:
+		f, ok = float64(tv), true
+	case float64:
+		f, ok = tv, true
+	}
+	return
+}
+
+func (spec *ConvSpec) addTo(c *Converter) error {
+	var rx *regexp.Regexp
+	if 0 < len(spec.Regexp) {
+		var err error
+		if rx, err = regexp.Compile(spec.Regexp); err != nil {
+			return err
+		}
+	}
+	var scale float64
+	switch strings.ToLower(spec.Unit) {
+	case "", "ns":
+		scale = 1.0
+	case "us":
+		scale = 1e3
+	case "ms":
+		scale = 1e6
+	case "s":
+		scale = 1e9
+	default:
+		return fmt.Errorf("%s is not a valid converter unit", spec.Unit)
+	}
+	layouts := spec.Layouts
+	if len(layouts) == 0 {
+		layouts = []string{time.RFC3339Nano, time.RFC3339, "2006-01-02"}
+	}
+	to := strings.ToLower(spec.To)
+	switch to {
+	case "time", "int", "float", "string", "bool", "value":
+	default:
+		return fmt.Errorf("%s is not a valid converter target", spec.To)
+	}
+	lo := spec.Min
+	hi := spec.Max
+	inRange := func(f float64) bool {
+		return (lo == nil || *lo <= f) && (hi == nil || f <= *hi)
+	}
+	replace := spec.Replace
+	convert := func(v any) (any, bool) {
+		switch tv := v.(type) {
+		case string:
+			if rx != nil && !rx.MatchString(tv) {
+				return nil, false
+			}
+		case int64:
+			if rx != nil || !inRange(float64(tv)) {
+				return nil, false
+			}
+		case float64:
+			if rx != nil || !inRange(tv) {
+				return nil, false
+			}
+		default:
+			if rx != nil || lo != nil || hi != nil {
+				return nil, false
+			}
+		}
+		if s, ok := v.(string); ok && rx != nil && 0 < len(replace) && to == "string" {
+			return rx.ReplaceAllString(s, replace), true
+		}
+		return convertTo(v, to, layouts, scale)
+	}
+	switch strings.ToLower(spec.Type) {
+	case "int":
+		c.Int = append(c.Int, specIntFunc(convert))
+	case "float":
+		c.Float = append(c.Float, specFloatFunc(convert))
+	case "number":
+		c.Int = append(c.Int, specIntFunc(convert))
+		c.Float = append(c.Float, specFloatFunc(convert))
+	case "string":
+		c.String = append(c.String, func(val string) (any, bool) {
+			if cv, ok := convert(val); ok {
+				return cv, true
+			}
+			return val, false
+		})
+	case "bool":
+		c.Bool = append(c.Bool, func(val bool) (any, bool) {
+			if cv, ok := convert(val); ok {
+				return cv, true
+			}
+			return val, false
+		})
+	case "time":
+		c.Time = append(c.Time, func(val time.Time) (any, bool) {
+			if cv, ok := convert(val); ok {
+				return cv, true
+			}
+			return val, false
+		})
+	case "key":
+		if to != "string" {
+			return fmt.Errorf("a key converter spec must have a string target")
+		}
+		c.Key = append(c.Key, func(key string) (string, bool) {
+			if cv, ok := convert(key); ok {
+				return cv.(string), true
+			}
+			return key, false
+		})
+	case "object":
+		if len(spec.Key) == 0 {
+			return fmt.Errorf("an object converter spec requires a key")
+		}
+		key := spec.Key
+		single := spec.Single
+		c.Map = append(c.Map, func(val map[string]any) (any, bool) {
+			if mv, has := val[key]; has && (!single || len(val) == 1) {
+				if cv, ok := convert(mv); ok {
+					return cv, true
+				}
+			}
+			return val, false
+		})
+	default:
+		return fmt.Errorf("%s is not a valid converter type", spec.Type)
+	}
+	return nil
+}
+
+func specIntFunc(convert func(v any) (any, bool)) func(val int64) (any, bool) {
+	return func(val int64) (any, bool) {
+		if cv, ok := convert(val); ok {
+			return cv, true
+		}
+		return val, false
+	}
+}
+
+func specFloatFunc(convert func(v any) (any, bool)) func(val float64) (any, bool) {
+	return func(val float64) (any, bool) {
+		if cv, ok := convert(val); ok {
+			return cv, true
+		}
+		return val, false
+	}
+}
+
+func convertTo(v any, to string, layouts []string, scale float64) (any, bool) {
+	switch to {
+	case "value":
+		return v, true
+	case "time":
+		switch tv := v.(type) {
+		case string:
+			for _, layout := range layouts {
+				if t, err := time.ParseInLocation(layout, tv, time.UTC); err == nil {
+					return t, true
+				}
+			}
+		case int64:
+			if scale == 1.0 {
+				return time.Unix(0, tv).UTC(), true
+			}
+			return time.Unix(0, tv*int64(scale)).UTC(), true
+		case float64:
+			return time.Unix(0, int64(math.Round(tv*scale))).UTC(), true
+		case time.Time:
+			return tv, true
+		}
+	case "int":
+		switch tv := v.(type) {
+		case string:
+			if i, err := strconv.ParseInt(tv, 0, 64); err == nil {
+				return i, true
+			}
+		case int64:
+			return tv, true
+		case float64:
+			if float64(int64(tv)) == tv {
+				return int64(tv), true
+			}
+		case time.Time:
+			return tv.UnixNano() / int64(scale), true
+		}
+	case "float":
+		switch tv := v.(type) {
+		case string:
+			if f, err := strconv.ParseFloat(tv, 64); err == nil {
+				return f, true
+			}
+		case int64:
+			return float64(tv), true
+		case float64:
+			return tv, true
+		case time.Time:
+			return float64(tv.UnixNano()) / scale, true
+		}
+	case "string":
+		switch tv := v.(type) {
+		case string:
+			return tv, true
+		case int64:
+			return strconv.FormatInt(tv, 10), true
+		case float64:
+			return strconv.FormatFloat(tv, 'g', -1, 64), true
+		case bool:
+			return strconv.FormatBool(tv), true
+		case time.Time:
+			return tv.Format(layouts[0]), true
+		}
+	case "bool":
+		switch tv := v.(type) {
+		case string:
+			if b, err := strconv.ParseBool(tv); err == nil {
+				return b, true
+			}
+		case bool:
+			return tv, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package ojg_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestConvSpecString(t *testing.T) {
+	c, err := ojg.NewConverter(
+		&ojg.ConvSpec{Type: "string", Regexp: "^0x[0-9a-f]+$", To: "int"},
+		&ojg.ConvSpec{Type: "string", Regexp: "^(true|false)$", To: "bool"},
+		&ojg.ConvSpec{Type: "string", Regexp: "^[0-9]+\\.[0-9]+$", To: "float"},
+		&ojg.ConvSpec{Type: "string", To: "time", Layouts: []string{"Jan 2 2006"}},
+	)
+	tt.Nil(t, err)
+	v := c.Convert([]any{"0x1f", "true", "1.5", "Mar 5 2021", "0xzz", "other"})
+	tt.Equal(t, []any{
+		int64(31),
+		true,
+		1.5,
+		time.Date(2021, time.March, 5, 0, 0, 0, 0, time.UTC),
+		"0xzz",
+		"other",
+	}, v)
+}
+
+func TestConvSpecNumber(t *testing.T) {
+	lo := 946684800.0
+	hi := 4102444800.0
+	c, err := ojg.NewConverter(
+		&ojg.ConvSpec{Type: "int", Min: &lo, Max: &hi, To: "time", Unit: "s"},
+		&ojg.ConvSpec{Type: "float", Min: &lo, To: "time", Unit: "ms"},
+		&ojg.ConvSpec{Type: "number", Max: &lo, To: "string"},
+	)
+	tt.Nil(t, err)
+	v := c.Convert([]any{int64(1700000000), 1700000000000.0, int64(7), 2.5})
+	tt.Equal(t, []any{
+		time.Unix(1700000000, 0).UTC(),
+		time.Unix(1700000000, 0).UTC(),
+		"7",
+		"2.5",
+	}, v)
+}
+
+func TestConvSpecObject(t *testing.T) {
+	c, err := ojg.NewConverter(
+		&ojg.ConvSpec{Type: "object", Key: "$ms", Single: true, To: "time", Unit: "ms"},
+		&ojg.ConvSpec{Type: "object", Key: "$value", To: "value"},
+	)
+	tt.Nil(t, err)
+	v := c.Convert(map[string]any{
+		"a": map[string]any{"$ms": int64(1000)},
+		"b": map[string]any{"$ms": int64(1000), "x": 1},
+		"c": map[string]any{"$value": []any{1}, "x": 1},
+	})
+	tt.Equal(t, map[string]any{
+		"a": time.Unix(1, 0).UTC(),
+		"b": map[string]any{"$ms": int64(1000), "x": 1},
+		"c": []any{1},
+	}, v)
+}
+
+func TestLoadConverter(t *testing.T) {
+	c, err := ojg.LoadConverter(sen.MustParse([]byte(`[
+  rfc3339
+  {type: string regexp: "^[0-9]+$" to: int}
+  {type: object key: "$n" single: true to: float}
+]`)))
+	tt.Nil(t, err)
+	v := c.Convert([]any{"2021-03-05", "123", map[string]any{"$n": "1.5"}, "x"})
+	tt.Equal(t, []any{time.Date(2021, time.March, 5, 0, 0, 0, 0, time.UTC), int64(123), 1.5, "x"}, v)
+
+	c, err = ojg.LoadConverter("nano")
+	tt.Nil(t, err)
+	tt.Equal(t, &ojg.TimeNanoConverter, c)
+
+	c, err = ojg.LoadConverter(sen.MustParse([]byte(`{type: int min: 10 layouts: ["2006"] to: time unit: s}`)))
+	tt.Nil(t, err)
+	tt.Equal(t, int64(3), c.Convert(int64(3)))
+
+	for _, src := range []string{
+		`unknown`,
+		`3`,
+		`{type: int to: nothing}`,
+		`{type: nothing to: int}`,
+		`{type: object to: int}`,
+		`{type: int to: time unit: days}`,
+		`{type: string regexp: "[" to: int}`,
+		`{type: int bad: 1 to: int}`,
+		`{type: int min: x to: int}`,
+		`{type: string layouts: [1] to: time}`,
+		`[rfc3339 nothing]`,
+	} {
+		_, err = ojg.LoadConverter(sen.MustParse([]byte(src)))
+		tt.NotNil(t, err, src)
+	}
+}
+
+func TestCombineConverters(t *testing.T) {
+	hex, err := ojg.NewConverter(&ojg.ConvSpec{Type: "string", Regexp: "^0x", To: "int"})
+	tt.Nil(t, err)
+	c := ojg.CombineConverters(ojg.BuiltinConverter("RFC3339"), nil, hex, &ojg.MongoConverter)
+	v := c.Convert([]any{"2021-03-05", "0x10", map[string]any{"$numberLong": "12"}})
+	tt.Equal(t, []any{time.Date(2021, time.March, 5, 0, 0, 0, 0, time.UTC), int64(16), int64(12)}, v)
+	tt.Nil(t, ojg.BuiltinConverter("nothing"))
+}
+
+func TestConvSpecWrite(t *testing.T) {
+	c, err := ojg.LoadConverter(sen.MustParse([]byte(`[
+  {type: string regexp: "^([0-9]{4})[0-9]{8}([0-9]{4})$" replace: "$1********$2" to: string}
+  {type: time to: string layout: "2006-01-02"}
+  {type: bool to: string}
+  {type: key regexp: "^x$" to: string replace: "y"}
+]`)))
+	tt.Nil(t, err)
+	v := c.Convert(map[string]any{
+		"card": "1234567812345678",
+		"when": time.Date(2021, time.March, 5, 10, 11, 12, 0, time.UTC),
+		"ok":   true,
+		"x":    1,
+	})
+	tt.Equal(t, map[string]any{
+		"card": "1234********5678",
+		"when": "2021-03-05",
+		"ok":   "true",
+		"y":    1,
+	}, v)
+
+	tm := time.Unix(1700000000, 0).UTC()
+	for _, pair := range [][]any{
+		{&ojg.ConvSpec{Type: "time", To: "int", Unit: "s"}, int64(1700000000)},
+		{&ojg.ConvSpec{Type: "time", To: "float", Unit: "ms"}, 1700000000000.0},
+		{&ojg.ConvSpec{Type: "time", To: "time"}, tm},
+	} {
+		c, err = ojg.NewConverter(pair[0].(*ojg.ConvSpec))
+		tt.Nil(t, err)
+		tt.Equal(t, pair[1], c.Convert(tm))
+	}
+	_, err = ojg.NewConverter(&ojg.ConvSpec{Type: "key", To: "int"})
+	tt.NotNil(t, err)
+}
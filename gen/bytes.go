@@ -0,0 +1,36 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package gen
+
+import (
+	"encoding/base64"
+)
+
+// Bytes is a []byte Node for binary data. The writers encode Bytes
+// according to the BytesAs option.
+type Bytes []byte
+
+// String returns the base64 encoding of the bytes as a JSON string.
+func (n Bytes) String() string {
+	return `"` + base64.StdEncoding.EncodeToString(n) + `"`
+}
+
+// Alter returns the backing []byte of the Node.
+func (n Bytes) Alter() any {
+	return []byte(n)
+}
+
+// Simplify returns a copy of the backing []byte of the Node.
+func (n Bytes) Simplify() any {
+	return append([]byte{}, n...)
+}
+
+// Dup returns a copy of the Node.
+func (n Bytes) Dup() Node {
+	return append(Bytes{}, n...)
+}
+
+// Empty returns true if there are no bytes.
+func (n Bytes) Empty() bool {
+	return len(n) == 0
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package gen_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/khaf/ojg/gen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestBytesString(t *testing.T) {
+	tt.Equal(t, `"aGVsbG8="`, gen.Bytes("hello").String())
+}
+
+func TestBytesSimplify(t *testing.T) {
+	b := gen.Bytes("abc")
+	simple := b.Simplify()
+	tt.Equal(t, "[]uint8 [97 98 99]", fmt.Sprintf("%T %v", simple, simple))
+
+	// The simplified value is a copy.
+	simple.([]byte)[0] = 'x'
+	tt.Equal(t, "abc", string(b))
+}
+
+func TestBytesAlter(t *testing.T) {
+	b := gen.Bytes("abc")
+	alt := b.Alter()
+	tt.Equal(t, "[]uint8 [97 98 99]", fmt.Sprintf("%T %v", alt, alt))
+
+	alt.([]byte)[0] = 'x'
+	tt.Equal(t, "xbc", string(b))
+}
+
+func TestBytesDup(t *testing.T) {
+	b := gen.Bytes("abc")
+	dup := b.Dup()
+	b[0] = 'x'
+
+	tt.Equal(t, "abc", string(dup.(gen.Bytes)))
+}
+
+func TestBytesEmpty(t *testing.T) {
+	tt.Equal(t, true, gen.Bytes{}.Empty())
+	tt.Equal(t, false, gen.Bytes("a").Empty())
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact decimal number Node. The number is kept as the
+// decimal text of the number as it would appear in a JSON document so no
+// precision is lost as can happen with a Float. The Parser returns a Decimal
+// for numbers with a fraction or exponent and for very large integers if the
+// Decimals option is set.
+type Decimal string
+
+// ParseDecimal returns a Decimal if the string is a valid JSON number and
+// an error otherwise.
+func ParseDecimal(s string) (Decimal, error) {
+	if !isJSONNumber(s) {
+		return "", fmt.Errorf("%q is not a valid decimal number", s)
+	}
+	return Decimal(s), nil
+}
+
+// String returns the decimal text of the number.
+func (n Decimal) String() string {
+	return string(n)
+}
+
+// Alter returns the number as a json.Number.
+func (n Decimal) Alter() any {
+	return json.Number(n)
+}
+
+// Simplify returns the number as a json.Number.
+func (n Decimal) Simplify() any {
+	return json.Number(n)
+}
+
+// Dup returns itself since it is immutable.
+func (n Decimal) Dup() Node {
+	return n
+}
+
+// Empty returns true if the backing string is empty.
+func (n Decimal) Empty() bool {
+	return len(string(n)) == 0
+}
+
+// Rat returns the exact value of the number or nil if the number is not
+// valid or the exponent is too large for a big.Rat.
+func (n Decimal) Rat() *big.Rat {
+	r, ok := new(big.Rat).SetString(string(n))
+	if !ok {
+		return nil
+	}
+	return r
+}
+
+// Float64 returns the nearest float64 to the number.
+func (n Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(string(n), 64)
+
+	return f
+}
+
+// Cmp compares the values of two decimals and returns -1 if n is less than
+// d, 0 if equal, and +1 if n is greater than d. Invalid numbers are treated
+// as zero. The comparison is made on the digits and exponents so numbers
+// with any size exponent are compared exactly.
+func (n Decimal) Cmp(d Decimal) int {
+	sign1, digits1, exp1 := n.normalize()
+	sign2, digits2, exp2 := d.normalize()
+	switch {
+	case sign1 < sign2:
+		return -1
+	case sign2 < sign1:
+		return 1
+	case sign1 == 0:
+		return 0
+	}
+	c := exp1.Cmp(exp2)
+	if c == 0 {
+		switch {
+		case digits1 < digits2:
+			c = -1
+		case digits2 < digits1:
+			c = 1
+		}
+	}
+	return c * sign1
+}
+
+// normalize returns the sign, the significant digits, and the exponent of
+// the number such that the value is 0.digits * 10^exp. The sign is 0 for
+// zero and invalid numbers.
+func (n Decimal) normalize() (sign int, digits string, exp *big.Int) {
+	s := string(n)
+	exp = new(big.Int)
+	if !isJSONNumber(s) {
+		return
+	}
+	sign = 1
+	if s[0] == '-' {
+		sign = -1
+		s = s[1:]
+	}
+	if i := strings.IndexAny(s, "eE"); 0 <= i {
+		exp.SetString(strings.TrimPrefix(s[i+1:], "+"), 10)
+		s = s[:i]
+	}
+	point := len(s)
+	if i := strings.IndexByte(s, '.'); 0 <= i {
+		point = i
+		s = s[:i] + s[i+1:]
+	}
+	trimmed := strings.TrimLeft(s, "0")
+	point -= len(s) - len(trimmed)
+	digits = strings.TrimRight(trimmed, "0")
+	if len(digits) == 0 {
+		return 0, "", exp.SetInt64(0)
+	}
+	exp.Add(exp, big.NewInt(int64(point)))
+
+	return
+}
+
+func isJSONNumber(s string) bool {
+	i := 0
+	if i < len(s) && s[i] == '-' {
+		i++
+	}
+	start := i
+	for i < len(s) && '0' <= s[i] && s[i] <= '9' {
+		i++
+	}
+	if i == start || (s[start] == '0' && 1 < i-start) {
+		return false
+	}
+	if i < len(s) && s[i] == '.' {
+		i++
+		start = i
+		for i < len(s) && '0' <= s[i] && s[i] <= '9' {
+			i++
+		}
+		if i == start {
+			return false
+		}
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '-' || s[i] == '+') {
+			i++
+		}
+		start = i
+		for i < len(s) && '0' <= s[i] && s[i] <= '9' {
+			i++
+		}
+		if i == start {
+			return false
+		}
+	}
+	return i == len(s)
+}
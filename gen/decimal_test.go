@@ -0,0 +1,75 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package gen_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/khaf/ojg/gen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestDecimalParse(t *testing.T) {
+	for _, s := range []string{"0", "-1", "12.50", "1e3", "-0.5E-7", "123456789012345678901234567890.1"} {
+		d, err := gen.ParseDecimal(s)
+		tt.Nil(t, err, s)
+		tt.Equal(t, s, d.String())
+	}
+	for _, s := range []string{"", "-", "01", "1.", ".5", "1e", "1e+", "0x10", "1.5a", "NaN"} {
+		_, err := gen.ParseDecimal(s)
+		tt.NotNil(t, err, s)
+	}
+}
+
+func TestDecimalSimplify(t *testing.T) {
+	simple := gen.Decimal("0.10").Simplify()
+	tt.Equal(t, "json.Number 0.10", fmt.Sprintf("%T %v", simple, simple))
+
+	alt := gen.Decimal("0.10").Alter()
+	tt.Equal(t, "json.Number 0.10", fmt.Sprintf("%T %v", alt, alt))
+}
+
+func TestDecimalDup(t *testing.T) {
+	dup := gen.Decimal("1.5").Dup()
+
+	tt.Equal(t, "gen.Decimal 1.5", fmt.Sprintf("%T %v", dup, dup))
+}
+
+func TestDecimalEmpty(t *testing.T) {
+	tt.Equal(t, true, gen.Decimal("").Empty())
+	tt.Equal(t, false, gen.Decimal("0").Empty())
+}
+
+func TestDecimalValue(t *testing.T) {
+	d := gen.Decimal("0.1")
+	tt.Equal(t, "1/10", d.Rat().String())
+	tt.Equal(t, 0.1, d.Float64())
+	tt.Nil(t, gen.Decimal("x").Rat())
+
+	tt.Equal(t, 0, d.Cmp(gen.Decimal("1e-1")))
+	tt.Equal(t, -1, d.Cmp(gen.Decimal("0.10000000000000000001")))
+	tt.Equal(t, 1, d.Cmp(gen.Decimal("-5")))
+	tt.Equal(t, 1, d.Cmp(gen.Decimal("x")))
+
+	for _, c := range []struct {
+		a      string
+		b      string
+		expect int
+	}{
+		{a: "1e100000000", b: "1", expect: 1},
+		{a: "1e-100000000", b: "0", expect: 1},
+		{a: "-1e100000000", b: "-1e99999999", expect: -1},
+		{a: "1e99999999999999999999", b: "1e99999999999999999998", expect: 1},
+		{a: "12.5e1", b: "125", expect: 0},
+		{a: "0.00125E+5", b: "125.000", expect: 0},
+		{a: "0.0", b: "-0e10", expect: 0},
+		{a: "120", b: "12e1", expect: 0},
+		{a: "12.01", b: "12.1", expect: -1},
+		{a: "-3", b: "2", expect: -1},
+		{a: "99", b: "100", expect: -1},
+	} {
+		tt.Equal(t, c.expect, gen.Decimal(c.a).Cmp(gen.Decimal(c.b)), c.a, " <=> ", c.b)
+		tt.Equal(t, -c.expect, gen.Decimal(c.b).Cmp(gen.Decimal(c.a)), c.b, " <=> ", c.a)
+	}
+}
@@ -6,15 +6,23 @@
 //
 //	Bool
 //	Int
+//	Uint
 //	Float
+//	Decimal
 //	String
+//	Bytes
 //	Time
+//	Null
 //
 // The collection types are Array and Object. All the types implement the Node
 // interface which is relatively simple interface defined primarily to restrict
 // what can be in the collection types. The Node interface should not be used to
 // define new generic types.
 //
+// PObject and PArray are persistent, immutable versions of Object and Array.
+// Changes return a new value that shares unchanged parts with the original
+// so earlier versions remain valid snapshots.
+//
 // Also included in the package are a builder and parser that behave like the
 // parser and builder in the oj package except for gen types.
 package gen
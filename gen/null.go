@@ -0,0 +1,33 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package gen
+
+// Null is a typed null Node. It can be used in place of a nil Node when a
+// non-nil value is needed such as when calling methods on a Node or when
+// distinguishing a null member from a missing member. It simplifies to nil.
+type Null struct{}
+
+// String returns "null".
+func (n Null) String() string {
+	return "null"
+}
+
+// Alter returns nil.
+func (n Null) Alter() any {
+	return nil
+}
+
+// Simplify returns nil.
+func (n Null) Simplify() any {
+	return nil
+}
+
+// Dup returns itself.
+func (n Null) Dup() Node {
+	return n
+}
+
+// Empty returns true.
+func (n Null) Empty() bool {
+	return true
+}
@@ -0,0 +1,20 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package gen_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/gen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestNull(t *testing.T) {
+	var n gen.Node = gen.Null{}
+
+	tt.Equal(t, "null", n.String())
+	tt.Nil(t, n.Simplify())
+	tt.Nil(t, n.Alter())
+	tt.Equal(t, gen.Null{}, n.Dup())
+	tt.Equal(t, true, n.Empty())
+}
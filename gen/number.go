@@ -12,6 +12,9 @@ import (
 // instance. (9223372036854775807 / 10 = 922337203685477580)
 const BigLimit = math.MaxInt64 / 10
 
+// uintLimit is the limit before another digit would overflow a uint64.
+const uintLimit = math.MaxUint64 / 10
+
 // Number is used internally by parsers.
 type Number struct {
 	I          uint64
@@ -22,6 +25,9 @@ type Number struct {
 	NegExp     bool
 	BigBuf     []byte
 	ForceFloat bool
+	// Decimal if true results in AsNode returning a Decimal for numbers
+	// with a fraction or exponent and for integers too large for a Uint.
+	Decimal bool
 }
 
 // Reset the number.
@@ -42,11 +48,8 @@ func (n *Number) AddDigit(b byte) {
 	switch {
 	case 0 < len(n.BigBuf):
 		n.BigBuf = append(n.BigBuf, b)
-	case n.I <= BigLimit:
+	case n.I < uintLimit || (n.I == uintLimit && b <= '5'):
 		n.I = n.I*10 + uint64(b-'0')
-		if math.MaxInt64 < n.I {
-			n.FillBig()
-		}
 	default:
 		n.FillBig()
 		n.BigBuf = append(n.BigBuf, b)
@@ -58,12 +61,9 @@ func (n *Number) AddFrac(b byte) {
 	switch {
 	case 0 < len(n.BigBuf):
 		n.BigBuf = append(n.BigBuf, b)
-	case n.Frac <= BigLimit:
+	case n.Div <= BigLimit:
 		n.Frac = n.Frac*10 + uint64(b-'0')
 		n.Div *= 10.0
-		if math.MaxInt64 < n.Frac {
-			n.FillBig()
-		}
 	default: // big
 		n.FillBig()
 		n.BigBuf = append(n.BigBuf, b)
@@ -92,7 +92,7 @@ func (n *Number) FillBig() {
 		n.BigBuf = append(n.BigBuf, '-')
 	}
 	n.BigBuf = append(n.BigBuf, strconv.FormatUint(n.I, 10)...)
-	if 0 < n.Frac {
+	if 1 < n.Div {
 		n.BigBuf = append(n.BigBuf, '.')
 		if 1000000000000000000 <= n.Frac { // nearest multiple of 10 below max int64
 			n.BigBuf = append(n.BigBuf, strconv.FormatUint(n.Frac, 10)...)
@@ -110,11 +110,19 @@ func (n *Number) FillBig() {
 	}
 }
 
-// AsNum returns the number as best fit.
+func (n *Number) fitsInt() bool {
+	return n.I <= math.MaxInt64 || (n.Neg && n.I == 1<<63)
+}
+
+// AsNum returns the number as best fit. Numbers with an integer part that
+// does not fit in an int64 are returned as a json.Number.
 func (n *Number) AsNum() (num any) {
 	switch {
 	case 0 < len(n.BigBuf):
 		num = json.Number(n.BigBuf)
+	case !n.fitsInt():
+		n.FillBig()
+		num = json.Number(n.BigBuf)
 	case n.Div == 1 && n.Exp == 0:
 		i := int64(n.I)
 		if n.Neg {
@@ -145,17 +153,30 @@ func (n *Number) AsNum() (num any) {
 	return
 }
 
-// AsNode returns the number as best fit.
+// AsNode returns the number as best fit. Integers too large for an Int but
+// that fit in a uint64 are returned as a Uint.
 func (n *Number) AsNode() (num Node) {
 	switch {
 	case 0 < len(n.BigBuf):
-		num = Big(n.BigBuf)
-	case n.Frac == 0 && n.Exp == 0:
+		if n.Decimal {
+			num = Decimal(n.BigBuf)
+		} else {
+			num = Big(n.BigBuf)
+		}
+	case n.Frac == 0 && n.Exp == 0 && n.fitsInt():
 		i := int64(n.I)
 		if n.Neg {
 			i = -i
 		}
 		num = Int(i)
+	case n.Frac == 0 && n.Exp == 0 && !n.Neg:
+		num = Uint(n.I)
+	case n.Decimal:
+		n.FillBig()
+		num = Decimal(n.BigBuf)
+	case !n.fitsInt():
+		n.FillBig()
+		num = Big(n.BigBuf)
 	default:
 		f := float64(n.I)
 		if 0 < n.Frac {
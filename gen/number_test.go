@@ -22,6 +22,9 @@ func TestNumber(t *testing.T) {
 		{src: "12345678901234567890", value: json.Number("12345678901234567890")},
 		{src: "0.12345678901234567890", value: "0.12345678901234567890"},
 		{src: "0.9223372036854775808", value: "0.9223372036854775808"},
+		{src: "18446744073709551615", value: json.Number("18446744073709551615")},
+		{src: "-9223372036854775808", value: int64(-9223372036854775808)},
+		{src: "1.00000000000000000000001", value: json.Number("1.00000000000000000000001")},
 	} {
 		if testing.Verbose() {
 			fmt.Printf("... %d: %s\n", i, d.src)
@@ -70,3 +73,54 @@ func TestNumberForceFloat(t *testing.T) {
 	v := n.AsNum()
 	tt.Equal(t, 123.0, v)
 }
+
+func TestNumberAsNode(t *testing.T) {
+	for _, d := range []struct {
+		src     string
+		decimal bool
+		expect  string
+	}{
+		{src: "123", expect: "gen.Int 123"},
+		{src: "-9223372036854775808", expect: "gen.Int -9223372036854775808"},
+		{src: "9223372036854775808", expect: "gen.Uint 9223372036854775808"},
+		{src: "18446744073709551615", expect: "gen.Uint 18446744073709551615"},
+		{src: "18446744073709551616", expect: "gen.Big 18446744073709551616"},
+		{src: "-9223372036854775809", expect: "gen.Big -9223372036854775809"},
+		{src: "1.25", expect: "gen.Float 1.25"},
+		{src: "1.25", decimal: true, expect: "gen.Decimal 1.25"},
+		{src: "1.050e-3", decimal: true, expect: "gen.Decimal 1.050e-3"},
+		{src: "18446744073709551616", decimal: true, expect: "gen.Decimal 18446744073709551616"},
+		{src: "18446744073709551615", decimal: true, expect: "gen.Uint 18446744073709551615"},
+	} {
+		var n gen.Number
+		n.Reset()
+		n.Decimal = d.decimal
+		frac := false
+		exp := false
+		for _, b := range []byte(d.src) {
+			switch b {
+			case '.':
+				frac = true
+			case '-':
+				if exp {
+					n.NegExp = true
+				} else {
+					n.Neg = true
+				}
+			case 'e':
+				exp = true
+			default:
+				switch {
+				case exp:
+					n.AddExp(b)
+				case frac:
+					n.AddFrac(b)
+				default:
+					n.AddDigit(b)
+				}
+			}
+		}
+		v := n.AsNode()
+		tt.Equal(t, d.expect, fmt.Sprintf("%T %v", v, v), d.src)
+	}
+}
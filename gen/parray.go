@@ -0,0 +1,305 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package gen
+
+import (
+	"fmt"
+)
+
+// PArray is a persistent, immutable array Node implemented as a 32 way
+// trie. Methods that modify the array return a new PArray that shares all
+// unchanged parts of the trie with the original so a PArray can be kept as
+// a cheap snapshot. The zero value is an empty array.
+type PArray struct {
+	root  *vecNode
+	size  int
+	shift uint
+}
+
+// vecNode is either a branch with children or a leaf with values.
+type vecNode struct {
+	children []*vecNode
+	values   []Node
+}
+
+// NewPArray returns a PArray with the elements of the Array. Elements are
+// not converted. Use Persist to convert nested Objects and Arrays.
+func NewPArray(a Array) (pa PArray) {
+	for _, v := range a {
+		pa = pa.Append(v)
+	}
+	return
+}
+
+// Len returns the number of elements in the array.
+func (n PArray) Len() int {
+	return n.size
+}
+
+// Get returns the element at the index. Negative indexes are from the end
+// of the array. False is returned if the index is out of range.
+func (n PArray) Get(i int) (Node, bool) {
+	if i < 0 {
+		i += n.size
+	}
+	if i < 0 || n.size <= i {
+		return nil, false
+	}
+	node := n.root
+	for shift := n.shift; 0 < shift; shift -= hamtBits {
+		node = node.children[(i>>shift)&hamtMask]
+	}
+	return node.values[i&hamtMask], true
+}
+
+// Set returns a new PArray with the element at the index replaced by the
+// value. Negative indexes are from the end of the array. A panic is raised
+// if the index is out of range.
+func (n PArray) Set(i int, value Node) PArray {
+	if i < 0 {
+		i += n.size
+	}
+	if i < 0 || n.size <= i {
+		panic(fmt.Errorf("index %d out of range for a PArray of length %d", i, n.size))
+	}
+	n.root = n.root.set(n.shift, i, value)
+
+	return n
+}
+
+// Append returns a new PArray with the value added to the end.
+func (n PArray) Append(value Node) PArray {
+	switch {
+	case n.root == nil:
+		n.root = &vecNode{values: []Node{value}}
+	case n.size == 1<<(n.shift+hamtBits):
+		// The trie is full so add a level.
+		n.root = &vecNode{children: []*vecNode{n.root, newVecPath(n.shift, value)}}
+		n.shift += hamtBits
+	default:
+		n.root = n.root.push(n.shift, n.size, value)
+	}
+	n.size++
+
+	return n
+}
+
+// Delete returns a new PArray without the element at the index. Negative
+// indexes are from the end of the array. The original is returned if the
+// index is out of range. Elements before the index are shared but the
+// elements after it are copied.
+func (n PArray) Delete(i int) PArray {
+	if i < 0 {
+		i += n.size
+	}
+	if i < 0 || n.size <= i {
+		return n
+	}
+	var pa PArray
+	if 0 < i {
+		pa = n.truncate(i)
+	}
+	for j := i + 1; j < n.size; j++ {
+		v, _ := n.Get(j)
+		pa = pa.Append(v)
+	}
+	return pa
+}
+
+// truncate returns the first size elements. Full leading sub-tries are
+// shared.
+func (n PArray) truncate(size int) PArray {
+	if size == n.size {
+		return n
+	}
+	var pa PArray
+	for i := 0; i < size; {
+		// Reuse a full leaf when possible.
+		if i&hamtMask == 0 && i+hamtMask < size {
+			leaf := n.leaf(i)
+			pa = pa.appendLeaf(leaf)
+			i += len(leaf.values)
+			continue
+		}
+		v, _ := n.Get(i)
+		pa = pa.Append(v)
+		i++
+	}
+	return pa
+}
+
+func (n PArray) leaf(i int) *vecNode {
+	node := n.root
+	for shift := n.shift; 0 < shift; shift -= hamtBits {
+		node = node.children[(i>>shift)&hamtMask]
+	}
+	return node
+}
+
+// appendLeaf adds a full leaf. The size must be a multiple of 32.
+func (n PArray) appendLeaf(leaf *vecNode) PArray {
+	switch {
+	case n.root == nil:
+		n.root = leaf
+	case n.size == 1<<(n.shift+hamtBits):
+		n.root = &vecNode{children: []*vecNode{n.root, wrapVecNode(n.shift, leaf)}}
+		n.shift += hamtBits
+	default:
+		n.root = n.root.pushLeaf(n.shift, n.size, leaf)
+	}
+	n.size += len(leaf.values)
+
+	return n
+}
+
+// Each calls the function for each element of the array in order.
+func (n PArray) Each(f func(i int, value Node)) {
+	i := 0
+	n.root.each(func(v Node) {
+		f(i, v)
+		i++
+	})
+}
+
+// Array returns the elements as an Array. The elements are not copied.
+func (n PArray) Array() Array {
+	a := make(Array, 0, n.size)
+	n.root.each(func(v Node) { a = append(a, v) })
+
+	return a
+}
+
+// String returns a string representation of the Node.
+func (n PArray) String() string {
+	return n.Array().String()
+}
+
+// Alter returns the same as Simplify since a PArray can not be modified in
+// place.
+func (n PArray) Alter() any {
+	return n.Simplify()
+}
+
+// Simplify makes a copy of the array as a []any.
+func (n PArray) Simplify() any {
+	simple := make([]any, 0, n.size)
+	n.root.each(func(v Node) {
+		if v == nil {
+			simple = append(simple, nil)
+		} else {
+			simple = append(simple, v.Simplify())
+		}
+	})
+	return simple
+}
+
+// Dup returns itself since it is immutable. This makes taking a snapshot a
+// constant time operation.
+func (n PArray) Dup() Node {
+	return n
+}
+
+// Empty returns true if the array has no elements.
+func (n PArray) Empty() bool {
+	return n.size == 0
+}
+
+func (vn *vecNode) each(f func(value Node)) {
+	if vn == nil {
+		return
+	}
+	if vn.children == nil {
+		for _, v := range vn.values {
+			f(v)
+		}
+		return
+	}
+	for _, c := range vn.children {
+		c.each(f)
+	}
+}
+
+func (vn *vecNode) set(shift uint, i int, value Node) *vecNode {
+	if shift == 0 {
+		dup := &vecNode{values: make([]Node, len(vn.values))}
+		copy(dup.values, vn.values)
+		dup.values[i&hamtMask] = value
+		return dup
+	}
+	dup := &vecNode{children: make([]*vecNode, len(vn.children))}
+	copy(dup.children, vn.children)
+	ci := (i >> shift) & hamtMask
+	dup.children[ci] = vn.children[ci].set(shift-hamtBits, i, value)
+
+	return dup
+}
+
+// push adds a value at the index which must be the size of the trie.
+func (vn *vecNode) push(shift uint, i int, value Node) *vecNode {
+	if shift == 0 {
+		dup := &vecNode{values: make([]Node, len(vn.values), len(vn.values)+1)}
+		copy(dup.values, vn.values)
+		dup.values = append(dup.values, value)
+		return dup
+	}
+	ci := (i >> shift) & hamtMask
+	dup := &vecNode{children: make([]*vecNode, len(vn.children), len(vn.children)+1)}
+	copy(dup.children, vn.children)
+	if ci < len(vn.children) {
+		dup.children[ci] = vn.children[ci].push(shift-hamtBits, i, value)
+	} else {
+		dup.children = append(dup.children, newVecPath(shift-hamtBits, value))
+	}
+	return dup
+}
+
+// pushLeaf adds a full leaf at the index which must be the size of the trie.
+func (vn *vecNode) pushLeaf(shift uint, i int, leaf *vecNode) *vecNode {
+	if shift == hamtBits {
+		dup := &vecNode{children: make([]*vecNode, len(vn.children), len(vn.children)+1)}
+		copy(dup.children, vn.children)
+		dup.children = append(dup.children, leaf)
+		return dup
+	}
+	ci := (i >> shift) & hamtMask
+	dup := &vecNode{children: make([]*vecNode, len(vn.children), len(vn.children)+1)}
+	copy(dup.children, vn.children)
+	if ci < len(vn.children) {
+		dup.children[ci] = vn.children[ci].pushLeaf(shift-hamtBits, i, leaf)
+	} else {
+		dup.children = append(dup.children, wrapVecNode(shift-hamtBits, leaf))
+	}
+	return dup
+}
+
+func newVecPath(shift uint, value Node) *vecNode {
+	return wrapVecNode(shift, &vecNode{values: []Node{value}})
+}
+
+func wrapVecNode(shift uint, leaf *vecNode) *vecNode {
+	node := leaf
+	for ; 0 < shift; shift -= hamtBits {
+		node = &vecNode{children: []*vecNode{node}}
+	}
+	return node
+}
+
+func (vn *vecNode) equal(other *vecNode) bool {
+	if vn == other {
+		return true
+	}
+	if vn == nil || other == nil || len(vn.children) != len(other.children) || len(vn.values) != len(other.values) {
+		return false
+	}
+	for i, c := range vn.children {
+		if !c.equal(other.children[i]) {
+			return false
+		}
+	}
+	for i, v := range vn.values {
+		if !Equal(v, other.values[i]) {
+			return false
+		}
+	}
+	return true
+}
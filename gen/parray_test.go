@@ -0,0 +1,73 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package gen_test
+
+import (
+	"testing"
+
+	"github.com/khaf/ojg/gen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestPArrayBasic(t *testing.T) {
+	var a gen.PArray
+	tt.Equal(t, true, a.Empty())
+	tt.Equal(t, "[]", a.String())
+	_, has := a.Get(0)
+	tt.Equal(t, false, has)
+	tt.Equal(t, 0, a.Delete(0).Len())
+
+	a2 := a.Append(gen.Int(1)).Append(gen.String("x")).Append(nil)
+	tt.Equal(t, 0, a.Len())
+	tt.Equal(t, 3, a2.Len())
+	tt.Equal(t, `[1,"x",null]`, a2.String())
+	v, has := a2.Get(-2)
+	tt.Equal(t, true, has)
+	tt.Equal(t, gen.String("x"), v)
+	tt.Equal(t, []any{int64(1), "x", nil}, a2.Simplify())
+	tt.Equal(t, []any{int64(1), "x", nil}, a2.Alter())
+	tt.Equal(t, a2, a2.Dup())
+
+	a3 := a2.Set(-1, gen.True).Delete(0)
+	tt.Equal(t, `["x",true]`, a3.String())
+	tt.Equal(t, `[1,"x",null]`, a2.String())
+	tt.Panic(t, func() { _ = a2.Set(3, nil) })
+
+	var idx []int
+	a3.Each(func(i int, v gen.Node) { idx = append(idx, i) })
+	tt.Equal(t, []int{0, 1}, idx)
+
+	tt.Equal(t, gen.Array{gen.Int(1)}, gen.NewPArray(gen.Array{gen.Int(1)}).Array())
+}
+
+func TestPArrayLarge(t *testing.T) {
+	var a gen.PArray
+	var expect gen.Array
+	for i := 0; i < 40000; i++ {
+		a = a.Append(gen.Int(i))
+		expect = append(expect, gen.Int(i))
+	}
+	snapshot := a
+	for _, i := range []int{0, 31, 32, 1023, 1024, 1025, 32767, 32768, 39999} {
+		a = a.Set(i, gen.Int(-i))
+		expect[i] = gen.Int(-i)
+	}
+	tt.Equal(t, true, gen.Equal(expect, a))
+	v, _ := snapshot.Get(1024)
+	tt.Equal(t, gen.Int(1024), v)
+
+	for _, i := range []int{39999, 32768, 1024, 5, 0} {
+		a = a.Delete(i)
+		expect = append(expect[:i:i], expect[i+1:]...)
+	}
+	tt.Equal(t, len(expect), a.Len())
+	tt.Equal(t, true, gen.Equal(a, expect))
+	tt.Equal(t, true, gen.Equal(a, gen.NewPArray(expect)))
+	tt.Equal(t, false, gen.Equal(a, snapshot))
+
+	// Appending after a delete continues to work.
+	a = a.Append(gen.True)
+	v, _ = a.Get(-1)
+	tt.Equal(t, gen.True, v)
+	tt.Equal(t, len(expect)+1, a.Len())
+}
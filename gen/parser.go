@@ -43,6 +43,12 @@ type Parser struct {
 	// Reuse maps. Previously returned maps will no longer be valid or rather
 	// could be modified during parsing.
 	Reuse bool
+
+	// Decimals if true results in numbers with a fraction or exponent
+	// being returned as a Decimal instead of a Float so that no precision
+	// is lost. Integers too large for a Uint are also returned as a
+	// Decimal instead of a Big.
+	Decimals bool
 }
 
 // Parse a JSON string in to simple types. An error is returned if not valid JSON.
@@ -81,6 +87,7 @@ func (p *Parser) Parse(buf []byte, args ...any) (Node, error) {
 	p.line = 1
 	p.mode = valueMap
 	p.mi = 0
+	p.num.Decimal = p.Decimals
 	var err error
 	// Skip BOM if present.
 	if 3 < len(buf) && buf[0] == 0xEF {
@@ -136,6 +143,7 @@ func (p *Parser) ParseReader(r io.Reader, args ...any) (data Node, err error) {
 	p.noff = -1
 	p.line = 1
 	p.mi = 0
+	p.num.Decimal = p.Decimals
 	buf := make([]byte, readBufSize)
 	eof := false
 	var cnt int
@@ -329,7 +337,6 @@ func (p *Parser) parseBuffer(buf []byte, last bool) error {
 					break
 				}
 				if BigLimit <= p.num.I {
-					p.num.FillBig()
 					p.num.AddDigit(b)
 					break
 				}
@@ -34,17 +34,21 @@ func TestParseString(t *testing.T) {
 		{src: "-12.3e-5", value: -12.3e-5},
 		{src: "12.3e+5 ", value: 12.3e+5},
 		{src: "12.3e+5\n", value: 12.3e+5},
-		{src: `12345678901234567890`, value: gen.Big("12345678901234567890")},
-		{src: `9223372036854775807`, value: gen.Big("9223372036854775807")},   // max int
-		{src: `9223372036854775808`, value: gen.Big("9223372036854775808")},   // max int + 1
-		{src: `-9223372036854775807`, value: -9223372036854775807},            // min int
-		{src: `-9223372036854775808`, value: gen.Big("-9223372036854775808")}, // min int -1
+		{src: `12345678901234567890`, value: gen.Uint(12345678901234567890)},
+		{src: `9223372036854775807`, value: 9223372036854775807},              // max int
+		{src: `9223372036854775808`, value: gen.Uint(9223372036854775808)},    // max int + 1
+		{src: `-9223372036854775807`, value: -9223372036854775807},            // min int + 1
+		{src: `-9223372036854775808`, value: -9223372036854775808},            // min int
+		{src: `-9223372036854775809`, value: gen.Big("-9223372036854775809")}, // min int -1
+		{src: `18446744073709551615`, value: gen.Uint(18446744073709551615)},  // max uint
+		{src: `18446744073709551616`, value: gen.Big("18446744073709551616")}, // max uint + 1
+		{src: `1.00000000000000000000001`, value: gen.Big("1.00000000000000000000001")},
 		{src: `-0.9223372036854775808`, value: gen.Big("-0.9223372036854775808")},
 		{src: `0.9223372036854775808`, value: gen.Big("0.9223372036854775808")},
 		{src: `123456789012345678901234567890`, value: gen.Big("123456789012345678901234567890")},
 		{src: `0.123456789012345678901234567890`, value: gen.Big("0.123456789012345678901234567890")},
 		{src: `[12345678901234567890,12345678901234567891]`,
-			value: gen.Array{gen.Big("12345678901234567890"), gen.Big("12345678901234567891")}},
+			value: gen.Array{gen.Uint(12345678901234567890), gen.Uint(12345678901234567891)}},
 		{src: `0.1e20000`, value: gen.Big("0.1e20000")},
 		{src: `1.2e1025`, value: gen.Big("1.2e1025")},
 		{src: `-1.2e-1025`, value: gen.Big("-1.2e-1025")},
@@ -348,3 +352,21 @@ func TestParserParseReaderChan(t *testing.T) {
 	}
 	tt.Equal(t, `1 [2] {"x":3} true false 123`, string(results))
 }
+
+func TestParserDecimals(t *testing.T) {
+	src := `[0.1, 12.50e-3, 18446744073709551615, 123456789012345678901234567890, 3]`
+	p := gen.Parser{Decimals: true}
+	v, err := p.Parse([]byte(src))
+	tt.Nil(t, err)
+	tt.Equal(t, gen.Array{
+		gen.Decimal("0.1"),
+		gen.Decimal("12.50e-3"),
+		gen.Uint(18446744073709551615),
+		gen.Decimal("123456789012345678901234567890"),
+		gen.Int(3),
+	}, v)
+
+	v, err = p.ParseReader(strings.NewReader(src))
+	tt.Nil(t, err)
+	tt.Equal(t, "gen.Decimal 0.1", fmt.Sprintf("%T %v", v.(gen.Array)[0], v.(gen.Array)[0]))
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package gen
+
+import (
+	"bytes"
+	"time"
+)
+
+// Persist returns a copy of the node with all Objects converted to PObjects
+// and all Arrays converted to PArrays. Other nodes are returned as is.
+func Persist(n Node) Node {
+	switch tn := n.(type) {
+	case Object:
+		var po PObject
+		for k, v := range tn {
+			po = po.Set(k, Persist(v))
+		}
+		return po
+	case Array:
+		var pa PArray
+		for _, v := range tn {
+			pa = pa.Append(Persist(v))
+		}
+		return pa
+	}
+	return n
+}
+
+// Equal returns true if the two nodes are the same type and have the same
+// value. An Object and a PObject with the same members are equal as are an
+// Array and a PArray with the same elements. A nil and a Null are also
+// equal. The parts of persistent nodes that are shared are compared in
+// constant time so comparing a modified PObject or PArray with the original
+// only compares the modified paths.
+func Equal(n0, n1 Node) bool {
+	switch t0 := n0.(type) {
+	case nil, Null:
+		switch n1.(type) {
+		case nil, Null:
+			return true
+		}
+	case Object:
+		switch t1 := n1.(type) {
+		case Object:
+			if len(t0) != len(t1) {
+				return false
+			}
+			for k, v0 := range t0 {
+				if v1, has := t1[k]; !has || !Equal(v0, v1) {
+					return false
+				}
+			}
+			return true
+		case PObject:
+			return Equal(t1, t0)
+		}
+	case PObject:
+		switch t1 := n1.(type) {
+		case PObject:
+			return t0.size == t1.size && t0.root.equal(t1.root)
+		case Object:
+			if t0.size != len(t1) {
+				return false
+			}
+			for k, v1 := range t1 {
+				if v0, has := t0.Get(k); !has || !Equal(v0, v1) {
+					return false
+				}
+			}
+			return true
+		}
+	case Array:
+		switch t1 := n1.(type) {
+		case Array:
+			if len(t0) != len(t1) {
+				return false
+			}
+			for i, v0 := range t0 {
+				if !Equal(v0, t1[i]) {
+					return false
+				}
+			}
+			return true
+		case PArray:
+			return Equal(t1, t0)
+		}
+	case PArray:
+		switch t1 := n1.(type) {
+		case PArray:
+			return t0.size == t1.size && t0.root.equal(t1.root)
+		case Array:
+			if t0.size != len(t1) {
+				return false
+			}
+			for i, v1 := range t1 {
+				if v0, _ := t0.Get(i); !Equal(v0, v1) {
+					return false
+				}
+			}
+			return true
+		}
+	case Time:
+		if t1, ok := n1.(Time); ok {
+			return time.Time(t0).Equal(time.Time(t1))
+		}
+	case Bytes:
+		if t1, ok := n1.(Bytes); ok {
+			return bytes.Equal(t0, t1)
+		}
+	default:
+		switch n1.(type) {
+		case Object, Array, Bytes:
+			return false
+		}
+		return n0 == n1
+	}
+	return false
+}
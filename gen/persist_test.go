@@ -0,0 +1,68 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package gen_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/khaf/ojg/gen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestPersist(t *testing.T) {
+	var p gen.Parser
+	doc, err := p.Parse([]byte(`{"a": [1, {"b": true}], "c": "x"}`))
+	tt.Nil(t, err)
+	pd := gen.Persist(doc)
+	po, ok := pd.(gen.PObject)
+	tt.Equal(t, true, ok)
+	a, _ := po.Get("a")
+	pa, ok := a.(gen.PArray)
+	tt.Equal(t, true, ok)
+	b, _ := pa.Get(1)
+	_, ok = b.(gen.PObject)
+	tt.Equal(t, true, ok)
+
+	tt.Equal(t, true, gen.Equal(doc, pd))
+	tt.Equal(t, true, gen.Equal(pd, doc))
+	tt.Equal(t, true, gen.Equal(pd, pd.Dup()))
+	tt.Equal(t, false, gen.Equal(pd, po.Set("c", gen.String("y"))))
+	tt.Equal(t, false, gen.Equal(doc, po.Set("c", gen.String("y"))))
+}
+
+func TestEqual(t *testing.T) {
+	tm := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, d := range []struct {
+		n0     gen.Node
+		n1     gen.Node
+		expect bool
+	}{
+		{n0: nil, n1: nil, expect: true},
+		{n0: nil, n1: gen.Null{}, expect: true},
+		{n0: gen.Null{}, n1: gen.False, expect: false},
+		{n0: gen.Int(1), n1: gen.Int(1), expect: true},
+		{n0: gen.Int(1), n1: gen.Float(1), expect: false},
+		{n0: gen.String("a"), n1: gen.Array{}, expect: false},
+		{n0: gen.Time(tm), n1: gen.Time(tm.In(time.Local)), expect: true},
+		{n0: gen.Time(tm), n1: gen.Int(1), expect: false},
+		{n0: gen.Bytes("ab"), n1: gen.Bytes("ab"), expect: true},
+		{n0: gen.Bytes("ab"), n1: gen.String("ab"), expect: false},
+		{n0: gen.Object{"a": gen.Int(1)}, n1: gen.Object{"a": gen.Int(1)}, expect: true},
+		{n0: gen.Object{"a": gen.Int(1)}, n1: gen.Object{"b": gen.Int(1)}, expect: false},
+		{n0: gen.Object{"a": gen.Int(1)}, n1: gen.Object{}, expect: false},
+		{n0: gen.Object{}, n1: gen.Array{}, expect: false},
+		{n0: gen.Object{"a": gen.Int(1)}, n1: gen.NewPObject(gen.Object{"a": gen.Int(2)}), expect: false},
+		{n0: gen.NewPObject(gen.Object{"a": gen.Int(1)}), n1: gen.Object{}, expect: false},
+		{n0: gen.PObject{}, n1: gen.PArray{}, expect: false},
+		{n0: gen.Array{gen.Int(1)}, n1: gen.Array{gen.Int(1)}, expect: true},
+		{n0: gen.Array{gen.Int(1)}, n1: gen.Array{gen.Int(2)}, expect: false},
+		{n0: gen.Array{gen.Int(1)}, n1: gen.Array{}, expect: false},
+		{n0: gen.Array{}, n1: gen.Object{}, expect: false},
+		{n0: gen.Array{gen.Int(1)}, n1: gen.NewPArray(gen.Array{gen.Int(2)}), expect: false},
+		{n0: gen.NewPArray(gen.Array{gen.Int(1)}), n1: gen.Array{}, expect: false},
+		{n0: gen.PArray{}, n1: gen.PObject{}, expect: false},
+	} {
+		tt.Equal(t, d.expect, gen.Equal(d.n0, d.n1), d.n0, " ", d.n1)
+	}
+}
@@ -0,0 +1,334 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package gen
+
+import (
+	"math/bits"
+	"sort"
+)
+
+const (
+	hamtBits  = 5
+	hamtMask  = 1<<hamtBits - 1
+	hamtDepth = 60 // hash bits used before falling back to a collision list
+)
+
+// PObject is a persistent, immutable object Node implemented as a hash array
+// mapped trie. Methods that modify the object return a new PObject that
+// shares all unchanged parts of the trie with the original so a PObject can
+// be kept as a cheap snapshot. The zero value is an empty object.
+type PObject struct {
+	root *hamtNode
+	size int
+}
+
+type hamtNode struct {
+	bitmap  uint32
+	entries []hamtEntry
+}
+
+// hamtEntry is either a member with a key and value or a sub-node.
+type hamtEntry struct {
+	key   string
+	value Node
+	node  *hamtNode
+}
+
+// NewPObject returns a PObject with the members of the Object. Member values
+// are not converted. Use Persist to convert nested Objects and Arrays.
+func NewPObject(o Object) (po PObject) {
+	for k, v := range o {
+		po = po.Set(k, v)
+	}
+	return
+}
+
+// Len returns the number of members in the object.
+func (n PObject) Len() int {
+	return n.size
+}
+
+// Get returns the value of the member with the key and true if the member
+// exists.
+func (n PObject) Get(key string) (Node, bool) {
+	h := hashKey(key)
+	node := n.root
+	for shift := uint(0); node != nil; shift += hamtBits {
+		if hamtDepth <= shift {
+			for _, e := range node.entries {
+				if e.key == key {
+					return e.value, true
+				}
+			}
+			break
+		}
+		bit := uint32(1) << ((h >> shift) & hamtMask)
+		if node.bitmap&bit == 0 {
+			break
+		}
+		e := &node.entries[bits.OnesCount32(node.bitmap&(bit-1))]
+		if e.node == nil {
+			if e.key == key {
+				return e.value, true
+			}
+			break
+		}
+		node = e.node
+	}
+	return nil, false
+}
+
+// Set returns a new PObject with the member set to the value. The original
+// is not modified.
+func (n PObject) Set(key string, value Node) PObject {
+	root, added := n.root.set(hashKey(key), 0, key, value)
+	if added {
+		n.size++
+	}
+	n.root = root
+
+	return n
+}
+
+// Delete returns a new PObject without the member with the key. The
+// original is returned if there is no member with the key.
+func (n PObject) Delete(key string) PObject {
+	if n.root == nil {
+		return n
+	}
+	root, removed := n.root.del(hashKey(key), 0, key)
+	if removed {
+		n.size--
+		n.root = root
+	}
+	return n
+}
+
+// Keys returns the sorted keys of the object.
+func (n PObject) Keys() []string {
+	keys := make([]string, 0, n.size)
+	n.root.each(func(k string, _ Node) { keys = append(keys, k) })
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Each calls the function for each member of the object in no particular
+// order.
+func (n PObject) Each(f func(key string, value Node)) {
+	n.root.each(f)
+}
+
+// Object returns the members as an Object. The member values are not copied.
+func (n PObject) Object() Object {
+	o := make(Object, n.size)
+	n.root.each(func(k string, v Node) { o[k] = v })
+
+	return o
+}
+
+// String returns a string representation of the Node.
+func (n PObject) String() string {
+	return n.Object().String()
+}
+
+// Alter returns the same as Simplify since a PObject can not be modified in
+// place.
+func (n PObject) Alter() any {
+	return n.Simplify()
+}
+
+// Simplify makes a copy of the object as a map[string]any.
+func (n PObject) Simplify() any {
+	simple := make(map[string]any, n.size)
+	n.root.each(func(k string, v Node) {
+		if v == nil {
+			simple[k] = nil
+		} else {
+			simple[k] = v.Simplify()
+		}
+	})
+	return simple
+}
+
+// Dup returns itself since it is immutable. This makes taking a snapshot a
+// constant time operation.
+func (n PObject) Dup() Node {
+	return n
+}
+
+// Empty returns true if the object has no members.
+func (n PObject) Empty() bool {
+	return n.size == 0
+}
+
+// hashKey returns the 64 bit FNV-1a hash of the key.
+func hashKey(key string) uint64 {
+	h := uint64(14695981039346656037)
+	for i := 0; i < len(key); i++ {
+		h ^= uint64(key[i])
+		h *= 1099511628211
+	}
+	return h
+}
+
+func (hn *hamtNode) each(f func(key string, value Node)) {
+	if hn == nil {
+		return
+	}
+	for _, e := range hn.entries {
+		if e.node != nil {
+			e.node.each(f)
+		} else {
+			f(e.key, e.value)
+		}
+	}
+}
+
+// set returns a copy of the node with the member set along with true if
+// the member was added instead of replaced.
+func (hn *hamtNode) set(h uint64, shift uint, key string, value Node) (*hamtNode, bool) {
+	if hn == nil {
+		return newHamtLeaf(h, shift, key, value), true
+	}
+	if hamtDepth <= shift {
+		for i, e := range hn.entries {
+			if e.key == key {
+				dup := hn.copy()
+				dup.entries[i].value = value
+				return dup, false
+			}
+		}
+		dup := &hamtNode{entries: make([]hamtEntry, len(hn.entries), len(hn.entries)+1)}
+		copy(dup.entries, hn.entries)
+		dup.entries = append(dup.entries, hamtEntry{key: key, value: value})
+		return dup, true
+	}
+	bit := uint32(1) << ((h >> shift) & hamtMask)
+	i := bits.OnesCount32(hn.bitmap & (bit - 1))
+	if hn.bitmap&bit == 0 {
+		dup := &hamtNode{bitmap: hn.bitmap | bit, entries: make([]hamtEntry, len(hn.entries)+1)}
+		copy(dup.entries, hn.entries[:i])
+		dup.entries[i] = hamtEntry{key: key, value: value}
+		copy(dup.entries[i+1:], hn.entries[i:])
+		return dup, true
+	}
+	e := hn.entries[i]
+	var added bool
+	dup := hn.copy()
+	switch {
+	case e.node != nil:
+		dup.entries[i].node, added = e.node.set(h, shift+hamtBits, key, value)
+	case e.key == key:
+		dup.entries[i].value = value
+	default:
+		sub, _ := (*hamtNode)(nil).set(hashKey(e.key), shift+hamtBits, e.key, e.value)
+		sub, _ = sub.set(h, shift+hamtBits, key, value)
+		dup.entries[i] = hamtEntry{node: sub}
+		added = true
+	}
+	return dup, added
+}
+
+// del returns a copy of the node without the member along with true if the
+// member was found. A nil node is returned if the node becomes empty. A
+// sub-node with a single member is replaced by the member so that the shape
+// of the trie only depends on the keys present.
+func (hn *hamtNode) del(h uint64, shift uint, key string) (*hamtNode, bool) {
+	if hamtDepth <= shift {
+		for i, e := range hn.entries {
+			if e.key == key {
+				if len(hn.entries) == 1 {
+					return nil, true
+				}
+				dup := &hamtNode{entries: make([]hamtEntry, 0, len(hn.entries)-1)}
+				dup.entries = append(dup.entries, hn.entries[:i]...)
+				dup.entries = append(dup.entries, hn.entries[i+1:]...)
+				return dup, true
+			}
+		}
+		return hn, false
+	}
+	bit := uint32(1) << ((h >> shift) & hamtMask)
+	if hn.bitmap&bit == 0 {
+		return hn, false
+	}
+	i := bits.OnesCount32(hn.bitmap & (bit - 1))
+	e := hn.entries[i]
+	if e.node == nil {
+		if e.key != key {
+			return hn, false
+		}
+		if len(hn.entries) == 1 {
+			return nil, true
+		}
+		dup := &hamtNode{bitmap: hn.bitmap &^ bit, entries: make([]hamtEntry, 0, len(hn.entries)-1)}
+		dup.entries = append(dup.entries, hn.entries[:i]...)
+		dup.entries = append(dup.entries, hn.entries[i+1:]...)
+		return dup, true
+	}
+	sub, removed := e.node.del(h, shift+hamtBits, key)
+	if !removed {
+		return hn, false
+	}
+	dup := hn.copy()
+	if len(sub.entries) == 1 && sub.entries[0].node == nil {
+		dup.entries[i] = sub.entries[0]
+	} else {
+		dup.entries[i].node = sub
+	}
+	return dup, true
+}
+
+func (hn *hamtNode) copy() *hamtNode {
+	dup := &hamtNode{bitmap: hn.bitmap, entries: make([]hamtEntry, len(hn.entries))}
+	copy(dup.entries, hn.entries)
+
+	return dup
+}
+
+func newHamtLeaf(h uint64, shift uint, key string, value Node) *hamtNode {
+	if hamtDepth <= shift {
+		return &hamtNode{entries: []hamtEntry{{key: key, value: value}}}
+	}
+	return &hamtNode{
+		bitmap:  uint32(1) << ((h >> shift) & hamtMask),
+		entries: []hamtEntry{{key: key, value: value}},
+	}
+}
+
+func (hn *hamtNode) equal(other *hamtNode) bool {
+	if hn == other {
+		return true
+	}
+	if hn == nil || other == nil || hn.bitmap != other.bitmap || len(hn.entries) != len(other.entries) {
+		return false
+	}
+	if hn.bitmap == 0 { // collision list, order is not significant
+	top:
+		for _, e := range hn.entries {
+			for _, oe := range other.entries {
+				if e.key == oe.key {
+					if !Equal(e.value, oe.value) {
+						return false
+					}
+					continue top
+				}
+			}
+			return false
+		}
+		return true
+	}
+	for i, e := range hn.entries {
+		oe := other.entries[i]
+		switch {
+		case e.node != nil || oe.node != nil:
+			if !e.node.equal(oe.node) {
+				return false
+			}
+		case e.key != oe.key || !Equal(e.value, oe.value):
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package gen_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/khaf/ojg/gen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestPObjectBasic(t *testing.T) {
+	var o gen.PObject
+	tt.Equal(t, true, o.Empty())
+	tt.Equal(t, "{}", o.String())
+	_, has := o.Get("a")
+	tt.Equal(t, false, has)
+	tt.Equal(t, 0, o.Delete("a").Len())
+
+	o2 := o.Set("a", gen.Int(1)).Set("b", gen.String("x")).Set("c", nil)
+	tt.Equal(t, 0, o.Len())
+	tt.Equal(t, 3, o2.Len())
+	tt.Equal(t, []string{"a", "b", "c"}, o2.Keys())
+	v, has := o2.Get("b")
+	tt.Equal(t, true, has)
+	tt.Equal(t, gen.String("x"), v)
+	tt.Equal(t, map[string]any{"a": int64(1), "b": "x", "c": nil}, o2.Simplify())
+	tt.Equal(t, map[string]any{"a": int64(1), "b": "x", "c": nil}, o2.Alter())
+	tt.Equal(t, gen.Object{"a": gen.Int(1), "b": gen.String("x"), "c": nil}, o2.Object())
+	tt.Equal(t, o2, o2.Dup())
+
+	o3 := o2.Set("a", gen.Int(2)).Delete("c")
+	tt.Equal(t, 2, o3.Len())
+	v, _ = o3.Get("a")
+	tt.Equal(t, gen.Int(2), v)
+	v, _ = o2.Get("a")
+	tt.Equal(t, gen.Int(1), v)
+	tt.Equal(t, o3, o3.Delete("not-there"))
+
+	cnt := 0
+	o3.Each(func(k string, v gen.Node) { cnt++ })
+	tt.Equal(t, 2, cnt)
+
+	tt.Equal(t, gen.Object{"x": gen.Int(1)}, gen.NewPObject(gen.Object{"x": gen.Int(1)}).Object())
+}
+
+func TestPObjectRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	expect := map[string]int64{}
+	var o gen.PObject
+	snapshots := []gen.PObject{}
+	for i := 0; i < 20000; i++ {
+		k := fmt.Sprintf("k%d", r.Intn(3000))
+		if r.Intn(3) == 0 {
+			delete(expect, k)
+			o = o.Delete(k)
+		} else {
+			expect[k] = int64(i)
+			o = o.Set(k, gen.Int(i))
+		}
+		if i%5000 == 0 {
+			snapshots = append(snapshots, o)
+		}
+	}
+	tt.Equal(t, len(expect), o.Len())
+	for k, i := range expect {
+		v, has := o.Get(k)
+		tt.Equal(t, true, has, k)
+		tt.Equal(t, gen.Int(i), v, k)
+	}
+	cnt := 0
+	o.Each(func(k string, v gen.Node) {
+		cnt++
+		tt.Equal(t, gen.Int(expect[k]), v)
+	})
+	tt.Equal(t, len(expect), cnt)
+
+	// Deleting everything in a different order leaves an empty object.
+	o2 := o
+	for k := range expect {
+		o2 = o2.Delete(k)
+	}
+	tt.Equal(t, 0, o2.Len())
+	tt.Equal(t, true, gen.Equal(o2, gen.PObject{}))
+
+	// The same members in a different order result in an equal object.
+	tt.Equal(t, true, gen.Equal(o, gen.NewPObject(o.Object())))
+	tt.Equal(t, false, gen.Equal(o, snapshots[1]))
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package gen
+
+import (
+	"strconv"
+)
+
+// Uint is a uint64 Node. The parser returns a Uint for integers too large
+// for an int64 that fit in a uint64.
+type Uint uint64
+
+// String returns a string representation of the Node.
+func (n Uint) String() string {
+	return strconv.FormatUint(uint64(n), 10)
+}
+
+// Alter returns the backing uint64 value of the Node.
+func (n Uint) Alter() any {
+	return uint64(n)
+}
+
+// Simplify returns the backing uint64 value of the Node.
+func (n Uint) Simplify() any {
+	return uint64(n)
+}
+
+// Dup returns itself since it is immutable.
+func (n Uint) Dup() Node {
+	return n
+}
+
+// Empty returns false.
+func (n Uint) Empty() bool {
+	return false
+}
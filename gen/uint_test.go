@@ -0,0 +1,37 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package gen_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/khaf/ojg/gen"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestUintString(t *testing.T) {
+	tt.Equal(t, "18446744073709551615", gen.Uint(18446744073709551615).String())
+}
+
+func TestUintSimplify(t *testing.T) {
+	simple := gen.Uint(18446744073709551615).Simplify()
+
+	tt.Equal(t, "uint64 18446744073709551615", fmt.Sprintf("%T %v", simple, simple))
+}
+
+func TestUintAlter(t *testing.T) {
+	alt := gen.Uint(7).Alter()
+
+	tt.Equal(t, "uint64 7", fmt.Sprintf("%T %v", alt, alt))
+}
+
+func TestUintDup(t *testing.T) {
+	dup := gen.Uint(7).Dup()
+
+	tt.Equal(t, "gen.Uint 7", fmt.Sprintf("%T %v", dup, dup))
+}
+
+func TestUintEmpty(t *testing.T) {
+	tt.Equal(t, false, gen.Uint(0).Empty())
+}
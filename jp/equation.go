@@ -203,6 +203,8 @@ func (e *Equation) appendValue(buf []byte, v any) []byte {
 		buf = append(buf, '\'')
 	case int64:
 		buf = append(buf, strconv.FormatInt(tv, 10)...)
+	case uint64:
+		buf = append(buf, strconv.FormatUint(tv, 10)...)
 	case float64:
 		buf = append(buf, strconv.FormatFloat(tv, 'g', -1, 64)...)
 	case bool:
@@ -74,18 +74,8 @@ func (x Expr) Get(data any) (results []any) {
 				if int(fi) == len(x)-1 { // last one
 					results = append(results, v)
 				} else {
-					switch v.(type) {
-					case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
-						int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int:
-					case map[string]any, []any, gen.Object, gen.Array:
+					if valueKind(v) != leafValue {
 						stack = append(stack, v)
-					default:
-						if rt := reflect.TypeOf(v); rt != nil {
-							switch rt.Kind() {
-							case reflect.Ptr, reflect.Slice, reflect.Struct, reflect.Array, reflect.Map:
-								stack = append(stack, v)
-							}
-						}
 					}
 				}
 			}
@@ -115,22 +105,15 @@ func (x Expr) Get(data any) (results []any) {
 				if int(fi) == len(x)-1 { // last one
 					results = append(results, v)
 				} else {
-					switch v.(type) {
-					case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
-						int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int:
-					case map[string]any, []any, gen.Object, gen.Array:
+					if valueKind(v) != leafValue {
 						stack = append(stack, v)
-					default:
-						if rt := reflect.TypeOf(v); rt != nil {
-							switch rt.Kind() {
-							case reflect.Ptr, reflect.Slice, reflect.Struct, reflect.Array, reflect.Map:
-								stack = append(stack, v)
-							}
-						}
 					}
 				}
 			}
 		case Wildcard:
+			if pa, ok := prev.(gen.PArray); ok {
+				prev = pa.Array()
+			}
 			switch tv := prev.(type) {
 			case map[string]any:
 				if int(fi) == len(x)-1 { // last one
@@ -139,18 +122,8 @@ func (x Expr) Get(data any) (results []any) {
 					}
 				} else {
 					for _, v = range tv {
-						switch v.(type) {
-						case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
-							int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int:
-						case map[string]any, []any, gen.Object, gen.Array:
+						if valueKind(v) != leafValue {
 							stack = append(stack, v)
-						default:
-							if rt := reflect.TypeOf(v); rt != nil {
-								switch rt.Kind() {
-								case reflect.Ptr, reflect.Slice, reflect.Struct, reflect.Array, reflect.Map:
-									stack = append(stack, v)
-								}
-							}
 						}
 					}
 				}
@@ -160,18 +133,8 @@ func (x Expr) Get(data any) (results []any) {
 				} else {
 					for i := len(tv) - 1; 0 <= i; i-- {
 						v = tv[i]
-						switch v.(type) {
-						case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
-							int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int:
-						case map[string]any, []any, gen.Object, gen.Array:
+						if valueKind(v) != leafValue {
 							stack = append(stack, v)
-						default:
-							if rt := reflect.TypeOf(v); rt != nil {
-								switch rt.Kind() {
-								case reflect.Ptr, reflect.Slice, reflect.Struct, reflect.Array, reflect.Map:
-									stack = append(stack, v)
-								}
-							}
 						}
 					}
 				}
@@ -183,7 +146,7 @@ func (x Expr) Get(data any) (results []any) {
 				} else {
 					for _, v = range tv {
 						switch v.(type) {
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						}
 					}
@@ -197,7 +160,7 @@ func (x Expr) Get(data any) (results []any) {
 					for i := len(tv) - 1; 0 <= i; i-- {
 						v = tv[i]
 						switch v.(type) {
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						}
 					}
@@ -207,18 +170,8 @@ func (x Expr) Get(data any) (results []any) {
 					if int(fi) == len(x)-1 { // last one
 						results = append(results, v)
 					} else {
-						switch v.(type) {
-						case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
-							int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int:
-						case map[string]any, []any, gen.Object, gen.Array:
+						if valueKind(v) != leafValue {
 							stack = append(stack, v)
-						default:
-							if rt := reflect.TypeOf(v); rt != nil {
-								switch rt.Kind() {
-								case reflect.Ptr, reflect.Slice, reflect.Struct, reflect.Array, reflect.Map:
-									stack = append(stack, v)
-								}
-							}
 						}
 					}
 				}
@@ -239,19 +192,12 @@ func (x Expr) Get(data any) (results []any) {
 						}
 					}
 					for _, v = range tv {
-						switch v.(type) {
-						case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
-							int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int:
-						case map[string]any, []any, gen.Object, gen.Array:
+						switch valueKind(v) {
+						case containerValue:
 							stack = append(stack, v)
 							stack = append(stack, fi|descentChildFlag)
-						default:
-							if rt := reflect.TypeOf(v); rt != nil {
-								switch rt.Kind() {
-								case reflect.Ptr, reflect.Slice, reflect.Struct, reflect.Array, reflect.Map:
-									stack = append(stack, v)
-								}
-							}
+						case reflectValue:
+							stack = append(stack, v)
 						}
 					}
 				case []any:
@@ -263,19 +209,12 @@ func (x Expr) Get(data any) (results []any) {
 					}
 					for i := len(tv) - 1; 0 <= i; i-- {
 						v = tv[i]
-						switch v.(type) {
-						case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
-							int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int:
-						case map[string]any, []any, gen.Object, gen.Array:
+						switch valueKind(v) {
+						case containerValue:
 							stack = append(stack, v)
 							stack = append(stack, fi|descentChildFlag)
-						default:
-							if rt := reflect.TypeOf(v); rt != nil {
-								switch rt.Kind() {
-								case reflect.Ptr, reflect.Slice, reflect.Struct, reflect.Array, reflect.Map:
-									stack = append(stack, v)
-								}
-							}
+						case reflectValue:
+							stack = append(stack, v)
 						}
 					}
 				case gen.Object:
@@ -289,7 +228,7 @@ func (x Expr) Get(data any) (results []any) {
 					}
 					for _, v = range tv {
 						switch v.(type) {
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 							stack = append(stack, fi|descentChildFlag)
 						}
@@ -306,7 +245,39 @@ func (x Expr) Get(data any) (results []any) {
 					for i := len(tv) - 1; 0 <= i; i-- {
 						v = tv[i]
 						switch v.(type) {
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
+							stack = append(stack, v)
+							stack = append(stack, fi|descentChildFlag)
+						}
+					}
+				case gen.PObject:
+					// Put prev back and slide fi.
+					stack[len(stack)-1] = prev
+					stack = append(stack, di|descentFlag)
+					tv.Each(func(_ string, v gen.Node) {
+						if int(fi) == len(x)-1 { // last one
+							results = append(results, v)
+						}
+						switch v.(type) {
+						case gen.Object, gen.Array, gen.PObject, gen.PArray:
+							stack = append(stack, v)
+							stack = append(stack, fi|descentChildFlag)
+						}
+					})
+				case gen.PArray:
+					// Put prev back and slide fi.
+					stack[len(stack)-1] = prev
+					stack = append(stack, di|descentFlag)
+					a := tv.Array()
+					if int(fi) == len(x)-1 { // last one
+						for _, v = range a {
+							results = append(results, v)
+						}
+					}
+					for i := len(a) - 1; 0 <= i; i-- {
+						v = a[i]
+						switch v.(type) {
+						case gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 							stack = append(stack, fi|descentChildFlag)
 						}
@@ -412,18 +383,8 @@ func (x Expr) Get(data any) (results []any) {
 						}
 					}
 					if has {
-						switch v.(type) {
-						case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
-							int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int:
-						case map[string]any, []any, gen.Object, gen.Array:
+						if valueKind(v) != leafValue {
 							stack = append(stack, v)
-						default:
-							if rt := reflect.TypeOf(v); rt != nil {
-								switch rt.Kind() {
-								case reflect.Ptr, reflect.Slice, reflect.Struct, reflect.Array, reflect.Map:
-									stack = append(stack, v)
-								}
-							}
 						}
 					}
 				}
@@ -444,6 +405,9 @@ func (x Expr) Get(data any) (results []any) {
 					continue
 				}
 			}
+			if pa, ok := prev.(gen.PArray); ok {
+				prev = pa.Array()
+			}
 			switch tv := prev.(type) {
 			case []any:
 				if start < 0 {
@@ -470,18 +434,8 @@ func (x Expr) Get(data any) (results []any) {
 						end = start + (end-start-1)/step*step
 						for i := end; start <= i; i -= step {
 							v = tv[i]
-							switch v.(type) {
-							case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
-								int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int:
-							case map[string]any, []any, gen.Object, gen.Array:
+							if valueKind(v) != leafValue {
 								stack = append(stack, v)
-							default:
-								if rt := reflect.TypeOf(v); rt != nil {
-									switch rt.Kind() {
-									case reflect.Ptr, reflect.Slice, reflect.Struct, reflect.Array, reflect.Map:
-										stack = append(stack, v)
-									}
-								}
 							}
 						}
 					}
@@ -497,18 +451,8 @@ func (x Expr) Get(data any) (results []any) {
 						end = start - (start-end-1)/step*step
 						for i := end; i <= start; i -= step {
 							v = tv[i]
-							switch v.(type) {
-							case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
-								int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int:
-							case map[string]any, []any, gen.Object, gen.Array:
+							if valueKind(v) != leafValue {
 								stack = append(stack, v)
-							default:
-								if rt := reflect.TypeOf(v); rt != nil {
-									switch rt.Kind() {
-									case reflect.Ptr, reflect.Slice, reflect.Struct, reflect.Array, reflect.Map:
-										stack = append(stack, v)
-									}
-								}
 							}
 						}
 					}
@@ -539,7 +483,7 @@ func (x Expr) Get(data any) (results []any) {
 						for i := end; start <= i; i -= step {
 							v = tv[i]
 							switch v.(type) {
-							case map[string]any, []any, gen.Object, gen.Array:
+							case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 								stack = append(stack, v)
 							}
 						}
@@ -557,7 +501,7 @@ func (x Expr) Get(data any) (results []any) {
 						for i := end; i <= start; i -= step {
 							v = tv[i]
 							switch v.(type) {
-							case map[string]any, []any, gen.Object, gen.Array:
+							case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 								stack = append(stack, v)
 							}
 						}
@@ -568,18 +512,8 @@ func (x Expr) Get(data any) (results []any) {
 					if int(fi) == len(x)-1 { // last one
 						results = append(results, v)
 					} else {
-						switch v.(type) {
-						case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
-							int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int:
-						case map[string]any, []any, gen.Object, gen.Array:
+						if valueKind(v) != leafValue {
 							stack = append(stack, v)
-						default:
-							if rt := reflect.TypeOf(v); rt != nil {
-								switch rt.Kind() {
-								case reflect.Ptr, reflect.Slice, reflect.Struct, reflect.Array, reflect.Map:
-									stack = append(stack, v)
-								}
-							}
 						}
 					}
 				}
@@ -658,18 +592,8 @@ func (x Expr) First(data any) any {
 				if int(fi) == len(x)-1 { // last one
 					return v
 				}
-				switch v.(type) {
-				case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
-					int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int:
-				case map[string]any, []any, gen.Object, gen.Array:
+				if valueKind(v) != leafValue {
 					stack = append(stack, v)
-				default:
-					if rt := reflect.TypeOf(v); rt != nil {
-						switch rt.Kind() {
-						case reflect.Ptr, reflect.Slice, reflect.Struct, reflect.Array, reflect.Map:
-							stack = append(stack, v)
-						}
-					}
 				}
 			}
 		case Nth:
@@ -698,21 +622,14 @@ func (x Expr) First(data any) any {
 				if int(fi) == len(x)-1 { // last one
 					return v
 				}
-				switch v.(type) {
-				case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
-					int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int:
-				case map[string]any, []any, gen.Object, gen.Array:
+				if valueKind(v) != leafValue {
 					stack = append(stack, v)
-				default:
-					if rt := reflect.TypeOf(v); rt != nil {
-						switch rt.Kind() {
-						case reflect.Ptr, reflect.Slice, reflect.Struct, reflect.Array, reflect.Map:
-							stack = append(stack, v)
-						}
-					}
 				}
 			}
 		case Wildcard:
+			if pa, ok := prev.(gen.PArray); ok {
+				prev = pa.Array()
+			}
 			switch tv := prev.(type) {
 			case map[string]any:
 				if int(fi) == len(x)-1 { // last one
@@ -721,18 +638,8 @@ func (x Expr) First(data any) any {
 					}
 				} else {
 					for _, v = range tv {
-						switch v.(type) {
-						case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
-							int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int:
-						case map[string]any, []any, gen.Object, gen.Array:
+						if valueKind(v) != leafValue {
 							stack = append(stack, v)
-						default:
-							if rt := reflect.TypeOf(v); rt != nil {
-								switch rt.Kind() {
-								case reflect.Ptr, reflect.Slice, reflect.Struct, reflect.Array, reflect.Map:
-									stack = append(stack, v)
-								}
-							}
 						}
 					}
 				}
@@ -744,18 +651,8 @@ func (x Expr) First(data any) any {
 				} else {
 					for i := len(tv) - 1; 0 <= i; i-- {
 						v = tv[i]
-						switch v.(type) {
-						case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
-							int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int:
-						case map[string]any, []any, gen.Object, gen.Array:
+						if valueKind(v) != leafValue {
 							stack = append(stack, v)
-						default:
-							if rt := reflect.TypeOf(v); rt != nil {
-								switch rt.Kind() {
-								case reflect.Ptr, reflect.Slice, reflect.Struct, reflect.Array, reflect.Map:
-									stack = append(stack, v)
-								}
-							}
 						}
 					}
 				}
@@ -767,7 +664,7 @@ func (x Expr) First(data any) any {
 				} else {
 					for _, v = range tv {
 						switch v.(type) {
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						}
 					}
@@ -781,7 +678,7 @@ func (x Expr) First(data any) any {
 					for i := len(tv) - 1; 0 <= i; i-- {
 						v = tv[i]
 						switch v.(type) {
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						}
 					}
@@ -791,18 +688,8 @@ func (x Expr) First(data any) any {
 					if int(fi) == len(x)-1 { // last one
 						return v
 					}
-					switch v.(type) {
-					case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
-						int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int:
-					case map[string]any, []any, gen.Object, gen.Array:
+					if valueKind(v) != leafValue {
 						stack = append(stack, v)
-					default:
-						if rt := reflect.TypeOf(v); rt != nil {
-							switch rt.Kind() {
-							case reflect.Ptr, reflect.Slice, reflect.Struct, reflect.Array, reflect.Map:
-								stack = append(stack, v)
-							}
-						}
 					}
 				}
 			}
@@ -821,19 +708,12 @@ func (x Expr) First(data any) any {
 						}
 					}
 					for _, v = range tv {
-						switch v.(type) {
-						case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
-							int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int:
-						case map[string]any, []any, gen.Object, gen.Array:
+						switch valueKind(v) {
+						case containerValue:
 							stack = append(stack, v)
 							stack = append(stack, fi|descentChildFlag)
-						default:
-							if rt := reflect.TypeOf(v); rt != nil {
-								switch rt.Kind() {
-								case reflect.Ptr, reflect.Slice, reflect.Struct, reflect.Array, reflect.Map:
-									stack = append(stack, v)
-								}
-							}
+						case reflectValue:
+							stack = append(stack, v)
 						}
 					}
 				case []any:
@@ -847,19 +727,12 @@ func (x Expr) First(data any) any {
 					}
 					for i := len(tv) - 1; 0 <= i; i-- {
 						v = tv[i]
-						switch v.(type) {
-						case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
-							int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int:
-						case map[string]any, []any, gen.Object, gen.Array:
+						switch valueKind(v) {
+						case containerValue:
 							stack = append(stack, v)
 							stack = append(stack, fi|descentChildFlag)
-						default:
-							if rt := reflect.TypeOf(v); rt != nil {
-								switch rt.Kind() {
-								case reflect.Ptr, reflect.Slice, reflect.Struct, reflect.Array, reflect.Map:
-									stack = append(stack, v)
-								}
-							}
+						case reflectValue:
+							stack = append(stack, v)
 						}
 					}
 				case gen.Object:
@@ -873,7 +746,7 @@ func (x Expr) First(data any) any {
 					}
 					for _, v = range tv {
 						switch v.(type) {
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 							stack = append(stack, fi|descentChildFlag)
 						}
@@ -890,7 +763,42 @@ func (x Expr) First(data any) any {
 					for i := len(tv) - 1; 0 <= i; i-- {
 						v = tv[i]
 						switch v.(type) {
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
+							stack = append(stack, v)
+							stack = append(stack, fi|descentChildFlag)
+						}
+					}
+				case gen.PObject:
+					// Put prev back and slide fi.
+					stack[len(stack)-1] = prev
+					stack = append(stack, di|descentFlag)
+					if int(fi) == len(x)-1 { // last one
+						if keys := tv.Keys(); 0 < len(keys) {
+							v, _ = tv.Get(keys[0])
+							return v
+						}
+					}
+					tv.Each(func(_ string, v gen.Node) {
+						switch v.(type) {
+						case gen.Object, gen.Array, gen.PObject, gen.PArray:
+							stack = append(stack, v)
+							stack = append(stack, fi|descentChildFlag)
+						}
+					})
+				case gen.PArray:
+					// Put prev back and slide fi.
+					stack[len(stack)-1] = prev
+					stack = append(stack, di|descentFlag)
+					if int(fi) == len(x)-1 { // last one
+						if v, has = tv.Get(0); has {
+							return v
+						}
+					}
+					a := tv.Array()
+					for i := len(a) - 1; 0 <= i; i-- {
+						v = a[i]
+						switch v.(type) {
+						case gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 							stack = append(stack, fi|descentChildFlag)
 						}
@@ -988,18 +896,8 @@ func (x Expr) First(data any) any {
 						}
 					}
 					if has {
-						switch v.(type) {
-						case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
-							int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int:
-						case map[string]any, []any, gen.Object, gen.Array:
+						if valueKind(v) != leafValue {
 							stack = append(stack, v)
-						default:
-							if rt := reflect.TypeOf(v); rt != nil {
-								switch rt.Kind() {
-								case reflect.Ptr, reflect.Slice, reflect.Struct, reflect.Array, reflect.Map:
-									stack = append(stack, v)
-								}
-							}
 						}
 					}
 				}
@@ -1020,6 +918,9 @@ func (x Expr) First(data any) any {
 					continue
 				}
 			}
+			if pa, ok := prev.(gen.PArray); ok {
+				prev = pa.Array()
+			}
 			switch tv := prev.(type) {
 			case []any:
 				if start < 0 {
@@ -1044,18 +945,8 @@ func (x Expr) First(data any) any {
 					end = start + (end-start-1)/step*step
 					for i := end; start <= i; i -= step {
 						v = tv[i]
-						switch v.(type) {
-						case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
-							int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int:
-						case map[string]any, []any, gen.Object, gen.Array:
+						if valueKind(v) != leafValue {
 							stack = append(stack, v)
-						default:
-							if rt := reflect.TypeOf(v); rt != nil {
-								switch rt.Kind() {
-								case reflect.Ptr, reflect.Slice, reflect.Struct, reflect.Array, reflect.Map:
-									stack = append(stack, v)
-								}
-							}
 						}
 					}
 				} else {
@@ -1068,18 +959,8 @@ func (x Expr) First(data any) any {
 					end = start - (start-end-1)/step*step
 					for i := end; i <= start; i -= step {
 						v = tv[i]
-						switch v.(type) {
-						case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
-							int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int:
-						case map[string]any, []any, gen.Object, gen.Array:
+						if valueKind(v) != leafValue {
 							stack = append(stack, v)
-						default:
-							if rt := reflect.TypeOf(v); rt != nil {
-								switch rt.Kind() {
-								case reflect.Ptr, reflect.Slice, reflect.Struct, reflect.Array, reflect.Map:
-									stack = append(stack, v)
-								}
-							}
 						}
 					}
 				}
@@ -1107,7 +988,7 @@ func (x Expr) First(data any) any {
 					for i := end; start <= i; i -= step {
 						v = tv[i]
 						switch v.(type) {
-						case gen.Object, gen.Array:
+						case gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						}
 					}
@@ -1122,7 +1003,7 @@ func (x Expr) First(data any) any {
 					for i := end; i <= start; i -= step {
 						v = tv[i]
 						switch v.(type) {
-						case gen.Object, gen.Array:
+						case gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						}
 					}
@@ -1132,18 +1013,8 @@ func (x Expr) First(data any) any {
 					if int(fi) == len(x)-1 { // last one
 						return v
 					}
-					switch v.(type) {
-					case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
-						int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int:
-					case map[string]any, []any, gen.Object, gen.Array:
+					if valueKind(v) != leafValue {
 						stack = append(stack, v)
-					default:
-						if rt := reflect.TypeOf(v); rt != nil {
-							switch rt.Kind() {
-							case reflect.Ptr, reflect.Slice, reflect.Struct, reflect.Array, reflect.Map:
-								stack = append(stack, v)
-							}
-						}
 					}
 				}
 			}
@@ -1170,6 +1041,12 @@ func (x Expr) First(data any) any {
 }
 
 func (x Expr) reflectGetChild(data any, key string) (v any, has bool) {
+	switch td := data.(type) {
+	case gen.PObject:
+		return td.Get(key)
+	case gen.PArray:
+		return nil, false
+	}
 	if !isNil(data) {
 		rd := reflect.ValueOf(data)
 		rt := rd.Type()
@@ -1196,6 +1073,12 @@ func (x Expr) reflectGetChild(data any, key string) (v any, has bool) {
 }
 
 func (x Expr) reflectGetNth(data any, i int) (v any, has bool) {
+	switch td := data.(type) {
+	case gen.PArray:
+		return td.Get(i)
+	case gen.PObject:
+		return nil, false
+	}
 	if !isNil(data) {
 		rd := reflect.ValueOf(data)
 		rt := rd.Type()
@@ -1218,6 +1101,13 @@ func (x Expr) reflectGetNth(data any, i int) (v any, has bool) {
 }
 
 func (x Expr) reflectGetWild(data any) (va []any) {
+	switch td := data.(type) {
+	case gen.PObject:
+		td.Each(func(_ string, v gen.Node) { va = append(va, v) })
+		return
+	case gen.PArray:
+		return x.reflectGetWild(td.Array())
+	}
 	if !isNil(data) {
 		rd := reflect.ValueOf(data)
 		rt := rd.Type()
@@ -1247,6 +1137,15 @@ func (x Expr) reflectGetWild(data any) (va []any) {
 }
 
 func (x Expr) reflectGetWildOne(data any) (any, bool) {
+	switch td := data.(type) {
+	case gen.PObject:
+		if keys := td.Keys(); 0 < len(keys) {
+			return td.Get(keys[0])
+		}
+		return nil, false
+	case gen.PArray:
+		return td.Get(0)
+	}
 	if !isNil(data) {
 		rd := reflect.ValueOf(data)
 		rt := rd.Type()
@@ -1276,6 +1175,12 @@ func (x Expr) reflectGetWildOne(data any) (any, bool) {
 }
 
 func (x Expr) reflectGetSlice(data any, start, end, step int) (va []any) {
+	switch td := data.(type) {
+	case gen.PArray:
+		return x.reflectGetSlice(td.Array(), start, end, step)
+	case gen.PObject:
+		return nil
+	}
 	if !isNil(data) {
 		rd := reflect.ValueOf(data)
 		rt := rd.Type()
@@ -1318,3 +1223,29 @@ func (x Expr) reflectGetSlice(data any, start, end, step int) (va []any) {
 	}
 	return
 }
+
+const (
+	leafValue = iota
+	containerValue
+	reflectValue
+)
+
+// valueKind returns containerValue for the simple and gen containers,
+// reflectValue for values that can only be followed using reflection, and
+// leafValue for values that have no children.
+func valueKind(v any) int {
+	switch v.(type) {
+	case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
+		int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int, gen.Uint, gen.Decimal, gen.Bytes, gen.Null:
+		return leafValue
+	case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
+		return containerValue
+	}
+	if rt := reflect.TypeOf(v); rt != nil {
+		switch rt.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Struct, reflect.Array, reflect.Map:
+			return reflectValue
+		}
+	}
+	return leafValue
+}
@@ -60,7 +60,7 @@ func (x Expr) Has(data any) bool {
 				switch v.(type) {
 				case nil, bool, string, float64, float32,
 					int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64,
-					gen.Bool, gen.Int, gen.Float, gen.String:
+					gen.Bool, gen.Int, gen.Uint, gen.Float, gen.Decimal, gen.String, gen.Bytes, gen.Null:
 				case map[string]any, []any, gen.Object, gen.Array:
 					stack = append(stack, v)
 				default:
@@ -102,7 +102,7 @@ func (x Expr) Has(data any) bool {
 				}
 				switch v.(type) {
 				case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
-					int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int:
+					int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int, gen.Uint, gen.Decimal, gen.Bytes, gen.Null:
 				case map[string]any, []any, gen.Object, gen.Array:
 					stack = append(stack, v)
 				default:
@@ -126,7 +126,7 @@ func (x Expr) Has(data any) bool {
 						switch v.(type) {
 						case nil, bool, string, float64, float32,
 							int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64,
-							gen.Bool, gen.Int, gen.Float, gen.String:
+							gen.Bool, gen.Int, gen.Uint, gen.Float, gen.Decimal, gen.String, gen.Bytes, gen.Null:
 						case map[string]any, []any, gen.Object, gen.Array:
 							stack = append(stack, v)
 						default:
@@ -150,7 +150,7 @@ func (x Expr) Has(data any) bool {
 						switch v.(type) {
 						case nil, bool, string, float64, float32,
 							int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64,
-							gen.Bool, gen.Int, gen.Float, gen.String:
+							gen.Bool, gen.Int, gen.Uint, gen.Float, gen.Decimal, gen.String, gen.Bytes, gen.Null:
 						case map[string]any, []any, gen.Object, gen.Array:
 							stack = append(stack, v)
 						default:
@@ -198,7 +198,7 @@ func (x Expr) Has(data any) bool {
 					switch v.(type) {
 					case nil, bool, string, float64, float32,
 						int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64,
-						gen.Bool, gen.Int, gen.Float, gen.String:
+						gen.Bool, gen.Int, gen.Uint, gen.Float, gen.Decimal, gen.String, gen.Bytes, gen.Null:
 					case map[string]any, []any, gen.Object, gen.Array:
 						stack = append(stack, v)
 					default:
@@ -229,7 +229,7 @@ func (x Expr) Has(data any) bool {
 						switch v.(type) {
 						case nil, bool, string, float64, float32,
 							int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64,
-							gen.Bool, gen.Int, gen.Float, gen.String:
+							gen.Bool, gen.Int, gen.Uint, gen.Float, gen.Decimal, gen.String, gen.Bytes, gen.Null:
 						case map[string]any, []any, gen.Object, gen.Array:
 							stack = append(stack, v)
 							stack = append(stack, fi|descentChildFlag)
@@ -256,7 +256,7 @@ func (x Expr) Has(data any) bool {
 						switch v.(type) {
 						case nil, bool, string, float64, float32,
 							int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64,
-							gen.Bool, gen.Int, gen.Float, gen.String:
+							gen.Bool, gen.Int, gen.Uint, gen.Float, gen.Decimal, gen.String, gen.Bytes, gen.Null:
 						case map[string]any, []any, gen.Object, gen.Array:
 							stack = append(stack, v)
 							stack = append(stack, fi|descentChildFlag)
@@ -397,7 +397,7 @@ func (x Expr) Has(data any) bool {
 					if has {
 						switch v.(type) {
 						case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
-							int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int:
+							int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int, gen.Uint, gen.Decimal, gen.Bytes, gen.Null:
 						case map[string]any, []any, gen.Object, gen.Array:
 							stack = append(stack, v)
 						default:
@@ -453,7 +453,7 @@ func (x Expr) Has(data any) bool {
 						v = tv[i]
 						switch v.(type) {
 						case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
-							int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int:
+							int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int, gen.Uint, gen.Decimal, gen.Bytes, gen.Null:
 						case map[string]any, []any, gen.Object, gen.Array:
 							stack = append(stack, v)
 						default:
@@ -477,7 +477,7 @@ func (x Expr) Has(data any) bool {
 						v = tv[i]
 						switch v.(type) {
 						case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
-							int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int:
+							int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int, gen.Uint, gen.Decimal, gen.Bytes, gen.Null:
 						case map[string]any, []any, gen.Object, gen.Array:
 							stack = append(stack, v)
 						default:
@@ -541,7 +541,7 @@ func (x Expr) Has(data any) bool {
 					}
 					switch v.(type) {
 					case nil, bool, string, float64, float32, gen.Bool, gen.Float, gen.String,
-						int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int:
+						int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64, gen.Int, gen.Uint, gen.Decimal, gen.Bytes, gen.Null:
 					case map[string]any, []any, gen.Object, gen.Array:
 						stack = append(stack, v)
 					default:
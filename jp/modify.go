@@ -107,6 +107,10 @@ done:
 		stack[len(stack)-2] = stack[len(stack)-1]
 		stack[len(stack)-1] = nil
 		stack = stack[:len(stack)-1]
+		switch prev.(type) {
+		case gen.PObject, gen.PArray:
+			panic(fmt.Sprintf("can not modify a %T in place, use SetNode or RemoveNode instead", prev))
+		}
 
 		switch tf := f.(type) {
 		case Child:
@@ -124,7 +128,7 @@ done:
 						}
 					} else {
 						switch v.(type) {
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						default:
 							kind := reflect.Invalid
@@ -149,7 +153,7 @@ done:
 						}
 					} else {
 						switch v.(type) {
-						case gen.Object, gen.Array:
+						case gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						}
 					}
@@ -165,7 +169,7 @@ done:
 						}
 					} else {
 						switch v.(type) {
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						default:
 							kind := reflect.Invalid
@@ -198,7 +202,7 @@ done:
 					} else {
 						v = tv[i]
 						switch v.(type) {
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						default:
 							kind := reflect.Invalid
@@ -227,7 +231,7 @@ done:
 					} else {
 						v = tv[i]
 						switch v.(type) {
-						case gen.Object, gen.Array:
+						case gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						}
 					}
@@ -244,7 +248,7 @@ done:
 						}
 					} else {
 						switch v.(type) {
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						default:
 							kind := reflect.Invalid
@@ -275,7 +279,7 @@ done:
 				} else {
 					for _, v = range tv {
 						switch v.(type) {
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						default:
 							kind := reflect.Invalid
@@ -302,7 +306,7 @@ done:
 				} else {
 					for _, v = range tv {
 						switch v.(type) {
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						default:
 							kind := reflect.Invalid
@@ -330,7 +334,7 @@ done:
 				} else {
 					for _, v = range tv {
 						switch v.(type) {
-						case gen.Object, gen.Array:
+						case gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						}
 					}
@@ -348,7 +352,7 @@ done:
 				} else {
 					for _, v = range tv {
 						switch v.(type) {
-						case gen.Object, gen.Array:
+						case gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						}
 					}
@@ -386,7 +390,7 @@ done:
 				} else {
 					for _, v := range wx.reflectGetWild(tv) {
 						switch v.(type) {
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						default:
 							kind := reflect.Invalid
@@ -418,7 +422,7 @@ done:
 								}
 							} else {
 								switch v.(type) {
-								case map[string]any, []any, gen.Object, gen.Array:
+								case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 									stack = append(stack, v)
 								default:
 									kind := reflect.Invalid
@@ -443,7 +447,7 @@ done:
 								}
 							} else {
 								switch v.(type) {
-								case gen.Object, gen.Array:
+								case gen.Object, gen.Array, gen.PObject, gen.PArray:
 									stack = append(stack, v)
 								}
 							}
@@ -460,7 +464,7 @@ done:
 								}
 							} else {
 								switch v.(type) {
-								case map[string]any, []any, gen.Object, gen.Array:
+								case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 									stack = append(stack, v)
 								default:
 									kind := reflect.Invalid
@@ -493,7 +497,7 @@ done:
 								}
 							} else {
 								switch v.(type) {
-								case map[string]any, []any, gen.Object, gen.Array:
+								case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 									stack = append(stack, v)
 								default:
 									kind := reflect.Invalid
@@ -522,7 +526,7 @@ done:
 							} else {
 								v = tv[i]
 								switch v.(type) {
-								case gen.Object, gen.Array:
+								case gen.Object, gen.Array, gen.PObject, gen.PArray:
 									stack = append(stack, v)
 								}
 							}
@@ -549,7 +553,7 @@ done:
 							var has bool
 							if v, has = wx.reflectGetNth(tv, i); has {
 								switch v.(type) {
-								case map[string]any, []any, gen.Object, gen.Array:
+								case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 									stack = append(stack, v)
 								default:
 									kind := reflect.Invalid
@@ -605,7 +609,7 @@ done:
 							}
 						} else {
 							switch v.(type) {
-							case map[string]any, []any, gen.Object, gen.Array:
+							case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 								stack = append(stack, v)
 							}
 						}
@@ -622,7 +626,7 @@ done:
 							}
 						} else {
 							switch v.(type) {
-							case map[string]any, []any, gen.Object, gen.Array:
+							case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 								stack = append(stack, v)
 							}
 						}
@@ -653,7 +657,7 @@ done:
 						} else {
 							v = tv[i]
 							switch v.(type) {
-							case gen.Object, gen.Array:
+							case gen.Object, gen.Array, gen.PObject, gen.PArray:
 								stack = append(stack, v)
 							}
 						}
@@ -670,7 +674,7 @@ done:
 						} else {
 							v = tv[i]
 							switch v.(type) {
-							case gen.Object, gen.Array:
+							case gen.Object, gen.Array, gen.PObject, gen.PArray:
 								stack = append(stack, v)
 							}
 						}
@@ -718,7 +722,7 @@ done:
 				} else {
 					for _, v := range wx.reflectGetSlice(tv, start, end, step) {
 						switch v.(type) {
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						default:
 							kind := reflect.Invalid
@@ -808,9 +812,9 @@ done:
 					stack = append(stack, di|descentFlag)
 					for _, v = range tv {
 						switch v.(type) {
-						case nil, gen.Bool, gen.Int, gen.Float, gen.String,
+						case nil, gen.Bool, gen.Int, gen.Uint, gen.Float, gen.Decimal, gen.String, gen.Bytes, gen.Null,
 							bool, string, float64, float32, int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64:
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 							stack = append(stack, fi|descentChildFlag)
 						default:
@@ -831,9 +835,9 @@ done:
 					for i := len(tv) - 1; 0 <= i; i-- {
 						v = tv[i]
 						switch v.(type) {
-						case nil, gen.Bool, gen.Int, gen.Float, gen.String,
+						case nil, gen.Bool, gen.Int, gen.Uint, gen.Float, gen.Decimal, gen.String, gen.Bytes, gen.Null,
 							bool, string, float64, float32, int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64:
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 							stack = append(stack, fi|descentChildFlag)
 						default:
@@ -853,7 +857,7 @@ done:
 					stack = append(stack, di|descentFlag)
 					for _, v = range tv {
 						switch v.(type) {
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 							stack = append(stack, fi|descentChildFlag)
 						}
@@ -865,7 +869,7 @@ done:
 					for i := len(tv) - 1; 0 <= i; i-- {
 						v = tv[i]
 						switch v.(type) {
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 							stack = append(stack, fi|descentChildFlag)
 						}
@@ -325,6 +325,11 @@ func (p *parser) readNum(b byte) any {
 	default:
 		i, err := strconv.ParseInt(string(num), 10, 64)
 		if err != nil {
+			// Integers too large for an int64 but that fit in a uint64 are
+			// allowed.
+			if u, err2 := strconv.ParseUint(string(num), 10, 64); err2 == nil {
+				return u
+			}
 			p.raise(err.Error())
 		}
 		return i
@@ -56,6 +56,7 @@ func TestParse(t *testing.T) {
 		{src: "$[1,'a',2,'b']", expect: "$[1,'a',2,'b']"},
 		{src: "$[ 1, 'a' , 2 ,'b' ]", expect: "$[1,'a',2,'b']"},
 		{src: "$[?(@.x == 'abc')]", expect: "$[?(@.x == 'abc')]"},
+		{src: "$[?(@.x == 18446744073709551615)]", expect: "$[?(@.x == 18446744073709551615)]"},
 		{src: "$[?(1==1)]", expect: "$[?(1 == 1)]"},
 		{src: `['a\\b']`, expect: `['a\\b']`},
 		{src: `[:]`, expect: `[:]`},
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/gen"
 )
 
 type remover interface {
@@ -17,6 +18,18 @@ type oneRemover interface {
 	removeOne(element any) (altered any, changed bool)
 }
 
+// persistentGuard wraps a remove function so that it panics instead of
+// silently ignoring a persistent node.
+func persistentGuard(remove func(element any) (any, bool)) func(element any) (any, bool) {
+	return func(element any) (any, bool) {
+		switch element.(type) {
+		case gen.PObject, gen.PArray:
+			panic(fmt.Sprintf("can not remove from a %T in place, use RemoveNode instead", element))
+		}
+		return remove(element)
+	}
+}
+
 // MustRemove removes matching nodes and panics on an expression error but
 // silently makes no changes if there is no match for the expression. Removed
 // slice elements are removed and the remaining elements are moveed to fill in
@@ -32,7 +45,7 @@ func (x Expr) MustRemove(data any) any {
 		sx = Expr{Root(0)}
 	}
 	if r, ok := last.(remover); ok {
-		return sx.modify(data, r.remove, false)
+		return sx.modify(data, persistentGuard(r.remove), false)
 	}
 	ta := strings.Split(fmt.Sprintf("%T", last), ".")
 	panic(fmt.Sprintf("can not remove with an expression where the last fragment is a %s", ta[len(ta)-1]))
@@ -53,10 +66,10 @@ func (x Expr) MustRemoveOne(data any) any {
 		sx = Expr{Root(0)}
 	}
 	if r, ok := last.(oneRemover); ok {
-		return sx.modify(data, r.removeOne, true)
+		return sx.modify(data, persistentGuard(r.removeOne), true)
 	}
 	if r, ok := last.(remover); ok {
-		return sx.modify(data, r.remove, true)
+		return sx.modify(data, persistentGuard(r.remove), true)
 	}
 	ta := strings.Split(fmt.Sprintf("%T", last), ".")
 	panic(fmt.Sprintf("can not remove with an expression where the last fragment is a %s", ta[len(ta)-1]))
@@ -3,7 +3,11 @@
 package jp
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"math/big"
 	"reflect"
 	"regexp"
 	"strconv"
@@ -170,6 +174,14 @@ func (s *Script) Eval(stack any, data any) any {
 			da = append(da, v)
 		}
 		data = da
+	case gen.PObject:
+		dlen = td.Len()
+		da := make(gen.Array, 0, dlen)
+		td.Each(func(_ string, v gen.Node) { da = append(da, v) })
+		data = da
+	case gen.PArray:
+		dlen = td.Len()
+		data = td.Array()
 	default:
 		rv := reflect.ValueOf(td)
 		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
@@ -227,7 +239,7 @@ func (s *Script) Eval(stack any, data any) any {
 			case int32:
 				sstack[i] = int64(x)
 			case uint:
-				sstack[i] = int64(x)
+				sstack[i] = normalUint(uint64(x))
 			case uint8:
 				sstack[i] = int64(x)
 			case uint16:
@@ -235,17 +247,29 @@ func (s *Script) Eval(stack any, data any) any {
 			case uint32:
 				sstack[i] = int64(x)
 			case uint64:
-				sstack[i] = int64(x)
+				sstack[i] = normalUint(x)
 			case float32:
 				sstack[i] = float64(x)
+			case json.Number:
+				sstack[i] = normalDecimal(string(x))
+			case []byte:
+				sstack[i] = string(x)
 			case gen.Bool:
 				sstack[i] = bool(x)
 			case gen.String:
 				sstack[i] = string(x)
 			case gen.Int:
 				sstack[i] = int64(x)
+			case gen.Uint:
+				sstack[i] = normalUint(uint64(x))
 			case gen.Float:
 				sstack[i] = float64(x)
+			case gen.Decimal:
+				sstack[i] = normalDecimal(string(x))
+			case gen.Bytes:
+				sstack[i] = string(x)
+			case gen.Null:
+				sstack[i] = nil
 
 			default:
 				// Any other type are already simplified or are not
@@ -267,6 +291,12 @@ func (s *Script) Eval(stack any, data any) any {
 				right = sstack[i+2]
 			}
 			switch o.code {
+			case eq.code, neq.code, lt.code, gt.code, lte.code, gte.code:
+				left, right = ratOperands(left, right, true)
+			default:
+				left, right = ratOperands(left, right, false)
+			}
+			switch o.code {
 			case eq.code:
 				if left == right {
 					sstack[i] = true
@@ -280,6 +310,9 @@ func (s *Script) Eval(stack any, data any) any {
 					case float64:
 						tr, ok := right.(int64)
 						sstack[i] = ok && tl == float64(tr)
+					case *big.Rat:
+						tr, ok := right.(*big.Rat)
+						sstack[i] = ok && tl.Cmp(tr) == 0
 					}
 				}
 			case neq.code:
@@ -295,6 +328,9 @@ func (s *Script) Eval(stack any, data any) any {
 					case float64:
 						tr, ok := right.(int64)
 						sstack[i] = ok && tl != float64(tr)
+					case *big.Rat:
+						tr, ok := right.(*big.Rat)
+						sstack[i] = !ok || tl.Cmp(tr) != 0
 					}
 				}
 			case lt.code:
@@ -317,6 +353,9 @@ func (s *Script) Eval(stack any, data any) any {
 				case string:
 					tr, ok := right.(string)
 					sstack[i] = ok && tl < tr
+				case *big.Rat:
+					tr, ok := right.(*big.Rat)
+					sstack[i] = ok && tl.Cmp(tr) < 0
 				}
 			case gt.code:
 				sstack[i] = false
@@ -338,6 +377,9 @@ func (s *Script) Eval(stack any, data any) any {
 				case string:
 					tr, ok := right.(string)
 					sstack[i] = ok && tl > tr
+				case *big.Rat:
+					tr, ok := right.(*big.Rat)
+					sstack[i] = ok && tl.Cmp(tr) > 0
 				}
 			case lte.code:
 				sstack[i] = false
@@ -359,6 +401,9 @@ func (s *Script) Eval(stack any, data any) any {
 				case string:
 					tr, ok := right.(string)
 					sstack[i] = ok && tl <= tr
+				case *big.Rat:
+					tr, ok := right.(*big.Rat)
+					sstack[i] = ok && tl.Cmp(tr) <= 0
 				}
 			case gte.code:
 				sstack[i] = false
@@ -380,6 +425,9 @@ func (s *Script) Eval(stack any, data any) any {
 				case string:
 					tr, ok := right.(string)
 					sstack[i] = ok && tl >= tr
+				case *big.Rat:
+					tr, ok := right.(*big.Rat)
+					sstack[i] = ok && tl.Cmp(tr) >= 0
 				}
 			case or.code:
 				// If one is a boolean true then true.
@@ -592,6 +640,8 @@ func (s *Script) appendValue(buf []byte, v any, prec byte) []byte {
 		buf = append(buf, '\'')
 	case int64:
 		buf = append(buf, strconv.FormatInt(tv, 10)...)
+	case uint64:
+		buf = append(buf, strconv.FormatUint(tv, 10)...)
 		// TBD verify this is never reached
 	// case int:
 	//	buf = append(buf, strconv.FormatInt(int64(tv), 10)...)
@@ -629,3 +679,66 @@ func (s *Script) appendValue(buf []byte, v any, prec byte) []byte {
 	}
 	return buf
 }
+
+// normalUint returns an int64 if the value fits in an int64 and a *big.Rat
+// otherwise so that comparisons are exact.
+func normalUint(u uint64) any {
+	if u <= math.MaxInt64 {
+		return int64(u)
+	}
+	return new(big.Rat).SetUint64(u)
+}
+
+// normalDecimal returns an int64 if the decimal string is an integer that
+// fits in an int64 and a *big.Rat otherwise. Numbers outside the range of a
+// float64 are returned as a float64 to avoid huge big.Rat values and
+// invalid numbers are returned as nil.
+func normalDecimal(s string) any {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return f
+		}
+		return nil
+	}
+	if r, ok := new(big.Rat).SetString(s); ok {
+		return r
+	}
+	return f
+}
+
+// ratOperands converts operands when either is a *big.Rat. Exact conversion
+// converts an int64 to a *big.Rat so the comparison is exact. Otherwise, or
+// if the other operand is a float64, the *big.Rat values are converted to
+// float64 values.
+func ratOperands(left, right any, exact bool) (any, any) {
+	lr, lok := left.(*big.Rat)
+	rr, rok := right.(*big.Rat)
+	if !lok && !rok {
+		return left, right
+	}
+	if exact {
+		switch {
+		case lok && rok:
+			return left, right
+		case lok:
+			if ri, ok := right.(int64); ok {
+				return left, new(big.Rat).SetInt64(ri)
+			}
+		case rok:
+			if li, ok := left.(int64); ok {
+				return new(big.Rat).SetInt64(li), right
+			}
+		}
+	}
+	if lok {
+		left, _ = lr.Float64()
+	}
+	if rok {
+		right, _ = rr.Float64()
+	}
+	return left, right
+}
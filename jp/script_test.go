@@ -3,7 +3,9 @@
 package jp_test
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
 	"testing"
 
 	"github.com/khaf/ojg/gen"
@@ -144,6 +146,28 @@ func TestScriptNormalizeEval(t *testing.T) {
 	tt.Equal(t, 1, len(result), "bool normalize")
 }
 
+func TestScriptExtendedNodeEval(t *testing.T) {
+	for _, d := range []struct {
+		src    string
+		values []any
+		expect int
+	}{
+		{src: "(@ == 3)", values: []any{gen.Uint(3), gen.Decimal("3"), json.Number("3")}, expect: 3},
+		{src: "(@ == 1.5)", values: []any{gen.Decimal("1.5"), json.Number("1.50"), gen.Decimal("1.25")}, expect: 2},
+		{src: "(@ > 9223372036854775807)", values: []any{uint64(math.MaxUint64), gen.Uint(9223372036854775808), gen.Int(1)}, expect: 2},
+		{src: "(@ == 18446744073709551615)", values: []any{gen.Uint(math.MaxUint64), gen.Uint(math.MaxUint64 - 1)}, expect: 1},
+		{src: "(@ < 2)", values: []any{gen.Decimal("1.99999999999999999999"), gen.Decimal("2.00000000000000000001")}, expect: 1},
+		{src: "(@ == 'abc')", values: []any{gen.Bytes("abc"), []byte("abc"), gen.Bytes("abd")}, expect: 2},
+		{src: "(@ == null)", values: []any{gen.Null{}, gen.Int(0)}, expect: 1},
+		{src: "(@ + 1 == 2.5)", values: []any{gen.Decimal("1.5")}, expect: 1},
+	} {
+		s, err := jp.NewScript(d.src)
+		tt.Nil(t, err)
+		result, _ := s.Eval([]any{}, d.values).([]any)
+		tt.Equal(t, d.expect, len(result), d.src)
+	}
+}
+
 func TestScriptNonListEval(t *testing.T) {
 	s, err := jp.NewScript("(@ == 3)")
 	tt.Nil(t, err)
@@ -284,8 +308,8 @@ func TestScriptInspect(t *testing.T) {
 	for i, d := range []idata{
 		{src: "(@ == 3)", expect: `{left: @ op: "==" right: 3}`},
 		{src: "(3 == @)", expect: `{left: 3 op: "==" right: @}`},
-		{src: "(@.x - @.y == 0)", expect: `{left: {left: @.x op: - right: @.y} op: "==" right: 0}`},
-		{src: "(0 == @.x - @.y)", expect: `{left: 0 op: "==" right: {left: @.x op: - right: @.y}}`},
+		{src: "(@.x - @.y == 0)", expect: `{left: {left: @.x op: "-" right: @.y} op: "==" right: 0}`},
+		{src: "(0 == @.x - @.y)", expect: `{left: 0 op: "==" right: {left: @.x op: "-" right: @.y}}`},
 		{src: "(!@.x)", expect: `{left: @.x op: "!" right: null}`},
 	} {
 		if testing.Verbose() {
@@ -109,6 +109,9 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 		stack[len(stack)-2] = stack[len(stack)-1]
 		stack[len(stack)-1] = nil
 		stack = stack[:len(stack)-1]
+		if err := persistentCheck(prev, fun); err != nil {
+			return err
+		}
 		switch tf := f.(type) {
 		case Child:
 			var has bool
@@ -125,10 +128,10 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 					}
 				} else if v, has = tv[string(tf)]; has {
 					switch v.(type) {
-					case nil, gen.Bool, gen.Int, gen.Float, gen.String,
+					case nil, gen.Bool, gen.Int, gen.Uint, gen.Float, gen.Decimal, gen.String, gen.Bytes, gen.Null,
 						bool, string, float64, float32, int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64:
 						return fmt.Errorf("can not follow a %T at '%s'", v, x[:fi+1])
-					case map[string]any, []any, gen.Object, gen.Array:
+					case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 						stack = append(stack, v)
 					default:
 						kind := reflect.Invalid
@@ -171,7 +174,7 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 					}
 				} else if v, has = tv[string(tf)]; has {
 					switch v.(type) {
-					case gen.Object, gen.Array:
+					case gen.Object, gen.Array, gen.PObject, gen.PArray:
 						stack = append(stack, v)
 					default:
 						return fmt.Errorf("can not follow a %T at '%s'", v, x[:fi+1])
@@ -202,10 +205,10 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 					}
 				} else if v, has = x.reflectGetChild(tv, string(tf)); has {
 					switch v.(type) {
-					case nil, gen.Bool, gen.Int, gen.Float, gen.String,
+					case nil, gen.Bool, gen.Int, gen.Uint, gen.Float, gen.Decimal, gen.String, gen.Bytes, gen.Null,
 						bool, string, float64, float32, int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64:
 						return fmt.Errorf("can not follow a %T at '%s'", v, x[:fi+1])
-					case map[string]any, []any, gen.Object, gen.Array:
+					case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 						stack = append(stack, v)
 					default:
 						kind := reflect.Invalid
@@ -242,9 +245,9 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 						v = tv[i]
 						switch v.(type) {
 						case bool, string, float64, float32, int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64,
-							nil, gen.Bool, gen.Int, gen.Float, gen.String:
+							nil, gen.Bool, gen.Int, gen.Uint, gen.Float, gen.Decimal, gen.String, gen.Bytes, gen.Null:
 							return fmt.Errorf("can not follow a %T at '%s'", v, x[:fi+1])
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						default:
 							kind := reflect.Invalid
@@ -279,7 +282,7 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 					} else {
 						v = tv[i]
 						switch v.(type) {
-						case gen.Object, gen.Array:
+						case gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						default:
 							return fmt.Errorf("can not follow a %T at '%s'", v, x[:fi+1])
@@ -299,9 +302,9 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 				} else if v, has = x.reflectGetNth(tv, i); has {
 					switch v.(type) {
 					case bool, string, float64, float32, int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64,
-						nil, gen.Bool, gen.Int, gen.Float, gen.String:
+						nil, gen.Bool, gen.Int, gen.Uint, gen.Float, gen.Decimal, gen.String, gen.Bytes, gen.Null:
 						return fmt.Errorf("can not follow a %T at '%s'", v, x[:fi+1])
-					case map[string]any, []any, gen.Object, gen.Array:
+					case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 						stack = append(stack, v)
 					default:
 						kind := reflect.Invalid
@@ -340,9 +343,9 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 				} else {
 					for _, v = range tv {
 						switch v.(type) {
-						case nil, gen.Bool, gen.Int, gen.Float, gen.String,
+						case nil, gen.Bool, gen.Int, gen.Uint, gen.Float, gen.Decimal, gen.String, gen.Bytes, gen.Null,
 							bool, string, float64, float32, int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64:
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						default:
 							kind := reflect.Invalid
@@ -375,9 +378,9 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 					for i := len(tv) - 1; 0 <= i; i-- {
 						v = tv[i]
 						switch v.(type) {
-						case nil, gen.Bool, gen.Int, gen.Float, gen.String,
+						case nil, gen.Bool, gen.Int, gen.Uint, gen.Float, gen.Decimal, gen.String, gen.Bytes, gen.Null,
 							bool, string, float64, float32, int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64:
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						default:
 							kind := reflect.Invalid
@@ -412,7 +415,7 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 				} else {
 					for _, v = range tv {
 						switch v.(type) {
-						case gen.Object, gen.Array:
+						case gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						}
 					}
@@ -436,7 +439,7 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 					for i := len(tv) - 1; 0 <= i; i-- {
 						v = tv[i]
 						switch v.(type) {
-						case gen.Object, gen.Array:
+						case gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						}
 					}
@@ -453,9 +456,9 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 					}
 					for _, v := range va {
 						switch v.(type) {
-						case nil, gen.Bool, gen.Int, gen.Float, gen.String,
+						case nil, gen.Bool, gen.Int, gen.Uint, gen.Float, gen.Decimal, gen.String, gen.Bytes, gen.Null,
 							bool, string, float64, float32, int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64:
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						default:
 							kind := reflect.Invalid
@@ -481,9 +484,9 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 					stack = append(stack, di|descentFlag)
 					for _, v = range tv {
 						switch v.(type) {
-						case nil, gen.Bool, gen.Int, gen.Float, gen.String,
+						case nil, gen.Bool, gen.Int, gen.Uint, gen.Float, gen.Decimal, gen.String, gen.Bytes, gen.Null,
 							bool, string, float64, float32, int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64:
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 							stack = append(stack, fi|descentChildFlag)
 						default:
@@ -504,9 +507,9 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 					for i := len(tv) - 1; 0 <= i; i-- {
 						v = tv[i]
 						switch v.(type) {
-						case nil, gen.Bool, gen.Int, gen.Float, gen.String,
+						case nil, gen.Bool, gen.Int, gen.Uint, gen.Float, gen.Decimal, gen.String, gen.Bytes, gen.Null,
 							bool, string, float64, float32, int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64:
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 							stack = append(stack, fi|descentChildFlag)
 						default:
@@ -526,7 +529,7 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 					stack = append(stack, di|descentFlag)
 					for _, v = range tv {
 						switch v.(type) {
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 							stack = append(stack, fi|descentChildFlag)
 						}
@@ -538,7 +541,7 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 					for i := len(tv) - 1; 0 <= i; i-- {
 						v = tv[i]
 						switch v.(type) {
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 							stack = append(stack, fi|descentChildFlag)
 						}
@@ -565,9 +568,9 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 							}
 						} else if v, has = tv[tu]; has {
 							switch v.(type) {
-							case nil, gen.Bool, gen.Int, gen.Float, gen.String,
+							case nil, gen.Bool, gen.Int, gen.Uint, gen.Float, gen.Decimal, gen.String, gen.Bytes, gen.Null,
 								bool, string, float64, float32, int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64:
-							case map[string]any, []any, gen.Object, gen.Array:
+							case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 								stack = append(stack, v)
 							default:
 								kind := reflect.Invalid
@@ -592,7 +595,7 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 							}
 						} else if v, has = tv[tu]; has {
 							switch v.(type) {
-							case map[string]any, []any, gen.Object, gen.Array:
+							case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 								stack = append(stack, v)
 							}
 						}
@@ -607,9 +610,9 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 
 						} else if v, has = x.reflectGetChild(tv, tu); has {
 							switch v.(type) {
-							case nil, gen.Bool, gen.Int, gen.Float, gen.String,
+							case nil, gen.Bool, gen.Int, gen.Uint, gen.Float, gen.Decimal, gen.String, gen.Bytes, gen.Null,
 								bool, string, float64, float32, int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64:
-							case map[string]any, []any, gen.Object, gen.Array:
+							case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 								stack = append(stack, v)
 							default:
 								kind := reflect.Invalid
@@ -643,9 +646,9 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 								}
 							} else {
 								switch v.(type) {
-								case nil, gen.Bool, gen.Int, gen.Float, gen.String,
+								case nil, gen.Bool, gen.Int, gen.Uint, gen.Float, gen.Decimal, gen.String, gen.Bytes, gen.Null,
 									bool, string, float64, float32, int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64:
-								case map[string]any, []any, gen.Object, gen.Array:
+								case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 									stack = append(stack, v)
 								default:
 									kind := reflect.Invalid
@@ -677,7 +680,7 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 							}
 						} else {
 							switch v.(type) {
-							case map[string]any, []any, gen.Object, gen.Array:
+							case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 								stack = append(stack, v)
 							}
 						}
@@ -691,9 +694,9 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 							}
 						} else if v, has = x.reflectGetNth(tv, i); has {
 							switch v.(type) {
-							case nil, gen.Bool, gen.Int, gen.Float, gen.String,
+							case nil, gen.Bool, gen.Int, gen.Uint, gen.Float, gen.Decimal, gen.String, gen.Bytes, gen.Null,
 								bool, string, float64, float32, int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64:
-							case map[string]any, []any, gen.Object, gen.Array:
+							case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 								stack = append(stack, v)
 							default:
 								kind := reflect.Invalid
@@ -741,9 +744,9 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 					for i := end; start <= i; i -= step {
 						v = tv[i]
 						switch v.(type) {
-						case nil, gen.Bool, gen.Int, gen.Float, gen.String,
+						case nil, gen.Bool, gen.Int, gen.Uint, gen.Float, gen.Decimal, gen.String, gen.Bytes, gen.Null,
 							bool, string, float64, float32, int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64:
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						default:
 							kind := reflect.Invalid
@@ -760,9 +763,9 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 					for i := end; i <= start; i -= step {
 						v = tv[i]
 						switch v.(type) {
-						case nil, gen.Bool, gen.Int, gen.Float, gen.String,
+						case nil, gen.Bool, gen.Int, gen.Uint, gen.Float, gen.Decimal, gen.String, gen.Bytes, gen.Null,
 							bool, string, float64, float32, int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64:
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						default:
 							kind := reflect.Invalid
@@ -794,7 +797,7 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 					for i := end; start <= i; i -= step {
 						v = tv[i]
 						switch v.(type) {
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						}
 					}
@@ -802,7 +805,7 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 					for i := end; i <= start; i -= step {
 						v = tv[i]
 						switch v.(type) {
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						}
 					}
@@ -811,9 +814,9 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 				if int(fi) != len(x)-1 {
 					for _, v := range x.reflectGetSlice(tv, start, end, step) {
 						switch v.(type) {
-						case nil, gen.Bool, gen.Int, gen.Float, gen.String,
+						case nil, gen.Bool, gen.Int, gen.Uint, gen.Float, gen.Decimal, gen.String, gen.Bytes, gen.Null,
 							bool, string, float64, float32, int, uint, int8, int16, int32, int64, uint8, uint16, uint32, uint64:
-						case map[string]any, []any, gen.Object, gen.Array:
+						case map[string]any, []any, gen.Object, gen.Array, gen.PObject, gen.PArray:
 							stack = append(stack, v)
 						default:
 							kind := reflect.Invalid
@@ -846,6 +849,19 @@ func (x Expr) set(data, value any, fun string, one bool) error {
 	return nil
 }
 
+// persistentCheck returns an error if v is a persistent node since those
+// can only be modified by creating a new node.
+func persistentCheck(v any, fun string) error {
+	switch v.(type) {
+	case gen.PObject, gen.PArray:
+		if fun == "set" {
+			return fmt.Errorf("can not set in a %T, use SetNode instead", v)
+		}
+		return fmt.Errorf("can not %s in a %T, use RemoveNode instead", fun, v)
+	}
+	return nil
+}
+
 func (x Expr) reflectSetChild(data any, key string, v any) bool {
 	if !isNil(data) {
 		rd := reflect.ValueOf(data)
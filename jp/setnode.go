@@ -0,0 +1,382 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package jp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/gen"
+)
+
+// SetNode returns a new root with all nodes that match the expression set to
+// the value. Unlike Set the data is not modified. Any gen.Object or gen.Array
+// along a modified path is copied and any gen.PObject or gen.PArray is
+// updated so the new root shares all unchanged nodes with the original. When
+// used with persistent data (see gen.Persist) the original remains a valid
+// snapshot. If the path to a Child does not exist then objects of the same
+// type as the parent are added and if the path to an Nth does not exist an
+// array is added as with Set. If nothing matches the data is returned. As
+// with Set an error is returned if an Nth fragment is out of bounds.
+func (x Expr) SetNode(data, value gen.Node) (result gen.Node, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = ojg.NewError(r)
+		}
+	}()
+	x.checkNodeExpr("set")
+	result, _ = x.setNode(data, 0, value, false)
+
+	return
+}
+
+// RemoveNode returns a new root with all nodes that match the expression
+// removed. As with SetNode the data is not modified and unchanged nodes are
+// shared with the original. If nothing matches the data is returned.
+func (x Expr) RemoveNode(data gen.Node) (result gen.Node, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = ojg.NewError(r)
+		}
+	}()
+	x.checkNodeExpr("remove")
+	switch x[len(x)-1].(type) {
+	case Root, At, Bracket:
+		ta := strings.Split(fmt.Sprintf("%T", x[len(x)-1]), ".")
+		panic(fmt.Errorf("can not remove with an expression ending with a %s", ta[len(ta)-1]))
+	}
+	result, _ = x.setNode(data, 0, nil, true)
+
+	return
+}
+
+func (x Expr) checkNodeExpr(fun string) {
+	if len(x) == 0 {
+		panic(fmt.Errorf("can not %s with an empty expression", fun))
+	}
+	if _, ok := x[len(x)-1].(Descent); ok {
+		panic(fmt.Errorf("can not %s with an expression ending with a Descent", fun))
+	}
+}
+
+// setNode applies the fragment at fi to the node and returns the new node
+// along with true if it was changed.
+func (x Expr) setNode(n gen.Node, fi int, value gen.Node, remove bool) (gen.Node, bool) {
+	switch tf := x[fi].(type) {
+	case Root, At, Bracket:
+		if fi == len(x)-1 {
+			return value, true
+		}
+		return x.setNode(n, fi+1, value, remove)
+	case Descent:
+		// Descendants are modified first so that the rest of the expression
+		// is applied to the original children and not to any added by the
+		// expression at this level.
+		var changed bool
+		switch tn := n.(type) {
+		case gen.Object, gen.PObject:
+			n, changed = x.setMembers(n, memberKeys(tn, Wildcard('*')), fi, value, remove)
+		case gen.Array, gen.PArray:
+			n, changed = x.setElements(n, elementIndexes(tn, Wildcard('*')), fi, value, remove)
+		}
+		n2, changed2 := x.setNode(n, fi+1, value, remove)
+		return n2, changed || changed2
+	case Child, Nth, Union, Wildcard, Slice, *Filter:
+		switch tn := n.(type) {
+		case gen.Object, gen.PObject:
+			return x.setMembers(n, memberKeys(tn, tf), fi+1, value, remove)
+		case gen.Array, gen.PArray:
+			indexes := elementIndexes(tn, tf)
+			if _, ok := tf.(Nth); ok && len(indexes) == 0 && !remove {
+				panic(fmt.Errorf("can not follow out of bounds array index at '%s'", x[:fi+1]))
+			}
+			return x.setElements(n, indexes, fi+1, value, remove)
+		}
+		return n, false
+	default:
+		panic(fmt.Errorf("can not modify a node with a %T fragment", tf))
+	}
+}
+
+// setMembers applies the fragment at next to the members of an object with
+// the keys. If next is past the end of the expression the members are set
+// or removed.
+func (x Expr) setMembers(n gen.Node, keys []string, next int, value gen.Node, remove bool) (gen.Node, bool) {
+	po, persist := n.(gen.PObject)
+	o, _ := n.(gen.Object)
+	var dup gen.Object
+	var changed bool
+	for _, k := range keys {
+		var v gen.Node
+		var has bool
+		if persist {
+			v, has = po.Get(k)
+		} else {
+			v, has = o[k]
+		}
+		switch {
+		case next == len(x) && remove:
+			if !has {
+				continue
+			}
+		case next == len(x):
+			v = value
+		default:
+			if !has {
+				if remove {
+					continue
+				}
+				// As with Set, an object is added if the next fragment is a
+				// Child and an array is added if it is an Nth.
+				switch tf := x[next].(type) {
+				case Child:
+					if persist {
+						v = gen.PObject{}
+					} else {
+						v = gen.Object{}
+					}
+				case Nth:
+					if tf < 0 {
+						panic(fmt.Errorf("can not deduce the length of the array to add at '%s'", x[:next+1]))
+					}
+					v = newNodeArray(int(tf)+1, persist)
+				default:
+					continue
+				}
+			}
+			var c bool
+			if v, c = x.setNode(v, next, value, remove); !c {
+				continue
+			}
+		}
+		if !changed && !persist {
+			dup = make(gen.Object, len(o))
+			for k2, v2 := range o {
+				dup[k2] = v2
+			}
+		}
+		changed = true
+		switch {
+		case next == len(x) && remove && persist:
+			po = po.Delete(k)
+		case next == len(x) && remove:
+			delete(dup, k)
+		case persist:
+			po = po.Set(k, v)
+		default:
+			dup[k] = v
+		}
+	}
+	switch {
+	case !changed:
+		return n, false
+	case persist:
+		return po, true
+	}
+	return dup, true
+}
+
+// newNodeArray returns an array of the size filled with nil values.
+func newNodeArray(size int, persist bool) gen.Node {
+	if !persist {
+		return make(gen.Array, size)
+	}
+	var pa gen.PArray
+	for i := 0; i < size; i++ {
+		pa = pa.Append(nil)
+	}
+	return pa
+}
+
+// setElements applies the fragment at next to the elements of an array at
+// the indexes. If next is past the end of the expression the elements are
+// set or removed.
+func (x Expr) setElements(n gen.Node, indexes []int, next int, value gen.Node, remove bool) (gen.Node, bool) {
+	pa, persist := n.(gen.PArray)
+	a, _ := n.(gen.Array)
+	if next == len(x) && remove {
+		// Remove from the end so the remaining indexes are not shifted.
+		sort.Sort(sort.Reverse(sort.IntSlice(indexes)))
+	}
+	var dup gen.Array
+	var changed bool
+	prev := -1
+	for _, i := range indexes {
+		if i == prev {
+			continue
+		}
+		prev = i
+		var v gen.Node
+		if persist {
+			v, _ = pa.Get(i)
+		} else {
+			v = a[i]
+		}
+		switch {
+		case next == len(x) && remove:
+		case next == len(x):
+			v = value
+		default:
+			var c bool
+			if v, c = x.setNode(v, next, value, remove); !c {
+				continue
+			}
+		}
+		if !changed && !persist {
+			dup = make(gen.Array, len(a))
+			copy(dup, a)
+		}
+		changed = true
+		switch {
+		case next == len(x) && remove && persist:
+			pa = pa.Delete(i)
+		case next == len(x) && remove:
+			dup = append(dup[:i], dup[i+1:]...)
+		case persist:
+			pa = pa.Set(i, v)
+		default:
+			dup[i] = v
+		}
+	}
+	switch {
+	case !changed:
+		return n, false
+	case persist:
+		return pa, true
+	}
+	return dup, true
+}
+
+// memberKeys returns the keys of the object members selected by the
+// fragment.
+func memberKeys(n gen.Node, f Frag) (keys []string) {
+	var all []string
+	switch tn := n.(type) {
+	case gen.PObject:
+		all = tn.Keys()
+	case gen.Object:
+		all = make([]string, 0, len(tn))
+		for k := range tn {
+			all = append(all, k)
+		}
+		sort.Strings(all)
+	}
+	switch tf := f.(type) {
+	case Child:
+		keys = append(keys, string(tf))
+	case Union:
+		for _, u := range tf {
+			if k, ok := u.(string); ok {
+				keys = append(keys, k)
+			}
+		}
+	case Wildcard:
+		keys = all
+	case *Filter:
+		for _, k := range all {
+			if tf.Match(memberValue(n, k)) {
+				keys = append(keys, k)
+			}
+		}
+	}
+	return
+}
+
+func memberValue(n gen.Node, key string) (v gen.Node) {
+	switch tn := n.(type) {
+	case gen.PObject:
+		v, _ = tn.Get(key)
+	case gen.Object:
+		v = tn[key]
+	}
+	return
+}
+
+// elementIndexes returns the indexes of the array elements selected by the
+// fragment. Negative indexes are converted and indexes out of range are
+// dropped.
+func elementIndexes(n gen.Node, f Frag) (indexes []int) {
+	var a gen.Array
+	var size int
+	switch tn := n.(type) {
+	case gen.PArray:
+		size = tn.Len()
+	case gen.Array:
+		a = tn
+		size = len(tn)
+	}
+	add := func(i int) {
+		if i < 0 {
+			i += size
+		}
+		if 0 <= i && i < size {
+			indexes = append(indexes, i)
+		}
+	}
+	switch tf := f.(type) {
+	case Nth:
+		add(int(tf))
+	case Union:
+		for _, u := range tf {
+			if i, ok := u.(int64); ok {
+				add(int(i))
+			}
+		}
+	case Wildcard:
+		for i := 0; i < size; i++ {
+			indexes = append(indexes, i)
+		}
+	case Slice:
+		start := 0
+		end := maxEnd
+		step := 1
+		if 0 < len(tf) {
+			start = tf[0]
+		}
+		if 1 < len(tf) {
+			end = tf[1]
+		}
+		if 2 < len(tf) {
+			step = tf[2]
+		}
+		if start < 0 {
+			if start += size; start < 0 {
+				start = 0
+			}
+		}
+		if end < 0 {
+			if end += size; end < -1 {
+				end = -1
+			}
+		}
+		if size < end {
+			end = size
+		}
+		switch {
+		case size <= start || step == 0:
+		case 0 < step:
+			for i := start; i < end; i += step {
+				indexes = append(indexes, i)
+			}
+		default:
+			for i := start; end < i; i += step {
+				indexes = append(indexes, i)
+			}
+		}
+	case *Filter:
+		for i := 0; i < size; i++ {
+			var v gen.Node
+			if a != nil {
+				v = a[i]
+			} else {
+				v, _ = n.(gen.PArray).Get(i)
+			}
+			if tf.Match(v) {
+				indexes = append(indexes, i)
+			}
+		}
+	}
+	return
+}
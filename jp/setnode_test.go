@@ -0,0 +1,208 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package jp_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/alt"
+	"github.com/khaf/ojg/gen"
+	"github.com/khaf/ojg/jp"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+type setNodeData struct {
+	path   string
+	data   string
+	value  gen.Node
+	expect string
+	err    string
+}
+
+func TestExprSetNode(t *testing.T) {
+	for i, d := range []setNodeData{
+		{path: "a", data: `{a:1}`, value: gen.Int(2), expect: `{a:2}`},
+		{path: "b", data: `{a:1}`, value: gen.Int(2), expect: `{a:1 b:2}`},
+		{path: "$", data: `{a:1}`, value: gen.Int(2), expect: `2`},
+		{path: "a.b.c", data: `{a:1}`, value: gen.Int(2), expect: `{a:1}`},
+		{path: "x.y.z", data: `{a:1}`, value: gen.Int(2), expect: `{a:1 x:{y:{z:2}}}`},
+		{path: "x[1]", data: `{a:1}`, value: gen.Int(2), expect: `{a:1 x:[null 2]}`},
+		{path: "new.deep[1]", data: `{}`, value: gen.Int(7), expect: `{new:{deep:[null 7]}}`},
+		{path: "x[1].y", data: `{}`, value: gen.Int(7), expect: `{}`},
+		{path: "a[1]", data: `{a:[1 2 3]}`, value: gen.True, expect: `{a:[1 true 3]}`},
+		{path: "a[-1]", data: `{a:[1 2 3]}`, value: gen.True, expect: `{a:[1 2 true]}`},
+		{path: "a[*]", data: `{a:[1 2 3]}`, value: gen.True, expect: `{a:[true true true]}`},
+		{path: "*", data: `{a:1 b:2}`, value: gen.True, expect: `{a:true b:true}`},
+		{path: "['a','c']", data: `{a:1 b:2}`, value: gen.True, expect: `{a:true b:2 c:true}`},
+		{path: "[0,-1]", data: `[1 2 3]`, value: gen.True, expect: `[true 2 true]`},
+		{path: "[1:]", data: `[1 2 3]`, value: gen.True, expect: `[1 true true]`},
+		{path: "[2:0:-1]", data: `[1 2 3]`, value: gen.True, expect: `[1 true true]`},
+		{path: "[?(@.x == 1)].y", data: `[{x:1 y:2} {x:2 y:2}]`, value: gen.True, expect: `[{x:1 y:true}{x:2 y:2}]`},
+		{path: "$..x", data: `{x:1 a:{x:2 b:[{x:3}]}}`, value: gen.True, expect: `{a:{b:[{x:true}] x:true} x:true}`},
+		{path: "", data: `{a:1}`, value: gen.Int(2), err: "can not set with an empty expression"},
+		{path: "a..", data: `{a:1}`, value: gen.Int(2), err: "can not set with an expression ending with a Descent"},
+		{path: "a[5]", data: `{a:[1 2]}`, value: gen.True, err: "can not follow out of bounds array index at 'a[5]'"},
+		{path: "a[-3].b", data: `{a:[{} {}]}`, value: gen.True, err: "can not follow out of bounds array index at 'a[-3]'"},
+		{path: "x[-1]", data: `{a:1}`, value: gen.True, err: "can not deduce the length of the array to add at 'x[-1]'"},
+	} {
+		for _, persist := range []bool{false, true} {
+			data := senNode(d.data)
+			if persist {
+				data = gen.Persist(data)
+			}
+			orig := sen.String(data, &ojg.Options{Sort: true})
+			x := jp.MustParseString(d.path)
+			result, err := x.SetNode(data, d.value)
+			if 0 < len(d.err) {
+				tt.NotNil(t, err, i, ": ", d.path)
+				tt.Equal(t, d.err, err.Error(), i, ": ", d.path)
+				continue
+			}
+			tt.Nil(t, err, i, ": ", d.path)
+			tt.Equal(t, d.expect, sen.String(result, &ojg.Options{Sort: true}), i, ": ", d.path)
+			// The original must not be modified.
+			tt.Equal(t, orig, sen.String(data, &ojg.Options{Sort: true}), i, ": ", d.path)
+		}
+	}
+}
+
+func TestExprSetNodeOutOfBounds(t *testing.T) {
+	x := jp.MustParseString("$.a[5]")
+	_, err := x.SetNode(senNode(`{a:[1 2]}`), gen.True)
+	tt.NotNil(t, err)
+	setErr := x.Set(map[string]any{"a": []any{1, 2}}, true)
+	tt.NotNil(t, setErr)
+	tt.Equal(t, setErr.Error(), err.Error())
+}
+
+func TestExprSetNodeMatchesSet(t *testing.T) {
+	x := jp.MustParseString("$.new.deep[1]")
+	result, err := x.SetNode(gen.Object{}, gen.Int(7))
+	tt.Nil(t, err)
+	data := map[string]any{}
+	tt.Nil(t, x.Set(data, 7))
+	tt.Equal(t, sen.String(data), sen.String(result))
+}
+
+func TestExprSetPersistent(t *testing.T) {
+	data := gen.Object{"a": gen.Persist(senNode(`{b:1 c:[1 2]}`))}
+	err := jp.MustParseString("a.b").Set(data, gen.Int(2))
+	tt.NotNil(t, err)
+	tt.Equal(t, "can not set in a gen.PObject, use SetNode instead", err.Error())
+
+	err = jp.MustParseString("a.c[0]").Del(data)
+	tt.NotNil(t, err)
+	tt.Equal(t, "can not delete in a gen.PObject, use RemoveNode instead", err.Error())
+
+	_, err = jp.MustParseString("a.b").Remove(data)
+	tt.NotNil(t, err)
+	tt.Equal(t, "can not remove from a gen.PObject in place, use RemoveNode instead", err.Error())
+
+	_, err = jp.MustParseString("a.c[0]").Remove(data)
+	tt.NotNil(t, err)
+
+	tt.Equal(t, "{a:{b:1 c:[1 2]}}", sen.String(data, &ojg.Options{Sort: true}))
+}
+
+func TestExprRemoveNode(t *testing.T) {
+	for i, d := range []setNodeData{
+		{path: "a", data: `{a:1 b:2}`, expect: `{b:2}`},
+		{path: "c", data: `{a:1 b:2}`, expect: `{a:1 b:2}`},
+		{path: "a.b", data: `{a:{b:1 c:2}}`, expect: `{a:{c:2}}`},
+		{path: "x.y", data: `{a:1}`, expect: `{a:1}`},
+		{path: "a[1]", data: `{a:[1 2 3]}`, expect: `{a:[1 3]}`},
+		{path: "a[*]", data: `{a:[1 2 3]}`, expect: `{a:[]}`},
+		{path: "[0,-1,0]", data: `[1 2 3]`, expect: `[2]`},
+		{path: "[::2]", data: `[1 2 3 4 5]`, expect: `[2 4]`},
+		{path: "[?(@ > 2)]", data: `[1 2 3 4 5]`, expect: `[1 2]`},
+		{path: "[?(@.x == 1)]", data: `{a:{x:1} b:{x:2}}`, expect: `{b:{x:2}}`},
+		{path: "$..x", data: `{x:1 a:{x:2 b:[{x:3}]}}`, expect: `{a:{b:[{}]}}`},
+		{path: "$", data: `{a:1}`, err: "can not remove with an expression ending with a Root"},
+	} {
+		for _, persist := range []bool{false, true} {
+			data := senNode(d.data)
+			if persist {
+				data = gen.Persist(data)
+			}
+			orig := sen.String(data, &ojg.Options{Sort: true})
+			x := jp.MustParseString(d.path)
+			result, err := x.RemoveNode(data)
+			if 0 < len(d.err) {
+				tt.NotNil(t, err, i, ": ", d.path)
+				tt.Equal(t, d.err, err.Error(), i, ": ", d.path)
+				continue
+			}
+			tt.Nil(t, err, i, ": ", d.path)
+			tt.Equal(t, d.expect, sen.String(result, &ojg.Options{Sort: true}), i, ": ", d.path)
+			tt.Equal(t, orig, sen.String(data, &ojg.Options{Sort: true}), i, ": ", d.path)
+		}
+	}
+}
+
+func TestExprSetNodeShared(t *testing.T) {
+	data := gen.Persist(senNode(`{a:{x:1 y:[1 2 3]} b:{c:{d:[4 5 6]}}}`))
+	result, err := jp.C("a").C("x").SetNode(data, gen.Int(2))
+	tt.Nil(t, err)
+
+	// The b member was not changed so it is the same in both.
+	b0 := jp.C("b").First(data)
+	b1 := jp.C("b").First(result)
+	tt.Equal(t, true, gen.Equal(b0.(gen.Node), b1.(gen.Node)))
+	tt.Equal(t, false, gen.Equal(data, result))
+	tt.Equal(t, gen.Int(1), jp.C("a").C("x").First(data))
+	tt.Equal(t, gen.Int(2), jp.C("a").C("x").First(result))
+
+	result, err = jp.C("a").C("x").SetNode(result, gen.Int(1))
+	tt.Nil(t, err)
+	tt.Equal(t, true, gen.Equal(data, result))
+
+	result, err = jp.C("b").RemoveNode(result)
+	tt.Nil(t, err)
+	tt.Equal(t, []any{gen.Int(1), gen.Int(2), gen.Int(3)}, jp.MustParseString("a.y[*]").Get(result))
+	tt.Nil(t, jp.C("b").First(result))
+	tt.Equal(t, 1, len(jp.C("b").Get(data)))
+}
+
+func TestExprGetPersistent(t *testing.T) {
+	data := gen.Persist(senNode(`{a:{x:1 y:[1 2 3]} b:[{x:2} {x:3 z:[{x:4}]}]}`))
+	for _, d := range []struct {
+		path   string
+		expect string
+	}{
+		{path: "a.x", expect: `[1]`},
+		{path: "a.y[1]", expect: `[2]`},
+		{path: "a.y[1:]", expect: `[2 3]`},
+		{path: "a.y[*]", expect: `[1 2 3]`},
+		{path: "b[?(@.x > 2)].x", expect: `[3]`},
+		{path: "$..x", expect: `[1 2 3 4]`},
+		{path: "b..x", expect: `[2 3 4]`},
+		{path: "['a','b'][0].x", expect: `[2]`},
+		{path: "*.x", expect: `[1]`},
+		{path: "a.*", expect: `[1 [1 2 3]]`},
+		{path: "a[?(@ == 1)]", expect: `[1]`},
+		{path: "a[0]", expect: `[]`},
+		{path: "a[1:]", expect: `[]`},
+		{path: "b.x", expect: `[]`},
+	} {
+		result := jp.MustParseString(d.path).Get(data)
+		// Members of a PObject are not ordered so sort the results.
+		sort.Slice(result, func(i, j int) bool {
+			return result[i].(gen.Node).String() < result[j].(gen.Node).String()
+		})
+		tt.Equal(t, d.expect, sen.String(result, &ojg.Options{Sort: true}), d.path)
+		first := jp.MustParseString(d.path).First(data)
+		if d.expect == "[]" {
+			tt.Nil(t, first, d.path)
+		} else {
+			tt.NotNil(t, first, d.path)
+		}
+	}
+	tt.Equal(t, gen.Int(1), jp.MustParseString("$..[0]").First(gen.Persist(senNode(`[[1]]`))))
+}
+
+func senNode(s string) gen.Node {
+	return alt.Generify(sen.MustParse([]byte(s)))
+}
@@ -0,0 +1,253 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package jws
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"math/big"
+
+	// Register the hash functions used by the algorithms.
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+)
+
+// Algorithm names as registered in RFC 7518 and RFC 8037.
+const (
+	HS256 = "HS256"
+	HS384 = "HS384"
+	HS512 = "HS512"
+	RS256 = "RS256"
+	RS384 = "RS384"
+	RS512 = "RS512"
+	PS256 = "PS256"
+	PS384 = "PS384"
+	PS512 = "PS512"
+	ES256 = "ES256"
+	ES384 = "ES384"
+	ES512 = "ES512"
+	EdDSA = "EdDSA"
+)
+
+// minRSABits is the minimum RSA key size allowed by RFC 7518.
+const minRSABits = 2048
+
+type algorithm struct {
+	hash  crypto.Hash
+	curve elliptic.Curve
+	sign  func(a *algorithm, key any, input []byte) ([]byte, error)
+	check func(a *algorithm, key any, input, sig []byte) error
+}
+
+var algorithms = map[string]*algorithm{
+	HS256: {hash: crypto.SHA256, sign: hmacSign, check: hmacCheck},
+	HS384: {hash: crypto.SHA384, sign: hmacSign, check: hmacCheck},
+	HS512: {hash: crypto.SHA512, sign: hmacSign, check: hmacCheck},
+	RS256: {hash: crypto.SHA256, sign: rsaSign, check: rsaCheck},
+	RS384: {hash: crypto.SHA384, sign: rsaSign, check: rsaCheck},
+	RS512: {hash: crypto.SHA512, sign: rsaSign, check: rsaCheck},
+	PS256: {hash: crypto.SHA256, sign: pssSign, check: pssCheck},
+	PS384: {hash: crypto.SHA384, sign: pssSign, check: pssCheck},
+	PS512: {hash: crypto.SHA512, sign: pssSign, check: pssCheck},
+	ES256: {hash: crypto.SHA256, curve: elliptic.P256(), sign: ecdsaSign, check: ecdsaCheck},
+	ES384: {hash: crypto.SHA384, curve: elliptic.P384(), sign: ecdsaSign, check: ecdsaCheck},
+	ES512: {hash: crypto.SHA512, curve: elliptic.P521(), sign: ecdsaSign, check: ecdsaCheck},
+	EdDSA: {sign: edSign, check: edCheck},
+}
+
+func findAlgorithm(name string) (*algorithm, error) {
+	if a := algorithms[name]; a != nil {
+		return a, nil
+	}
+	return nil, fmt.Errorf("algorithm %q is not supported", name)
+}
+
+func (a *algorithm) digest(input []byte) []byte {
+	h := a.hash.New()
+	_, _ = h.Write(input)
+
+	return h.Sum(nil)
+}
+
+func hmacKey(a *algorithm, key any) ([]byte, error) {
+	secret, ok := key.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("a %T key can not be used with HMAC", key)
+	}
+	if len(secret) < a.hash.Size() {
+		return nil, fmt.Errorf("an HMAC key must be at least %d bytes", a.hash.Size())
+	}
+	return secret, nil
+}
+
+func hmacSign(a *algorithm, key any, input []byte) ([]byte, error) {
+	secret, err := hmacKey(a, key)
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(a.hash.New, secret)
+	_, _ = mac.Write(input)
+
+	return mac.Sum(nil), nil
+}
+
+func hmacCheck(a *algorithm, key any, input, sig []byte) error {
+	expect, err := hmacSign(a, key, input)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal(expect, sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func rsaPrivateKey(key any) (*rsa.PrivateKey, error) {
+	priv, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("a %T key can not be used to sign with RSA", key)
+	}
+	if priv.N.BitLen() < minRSABits {
+		return nil, fmt.Errorf("an RSA key must be at least %d bits", minRSABits)
+	}
+	return priv, nil
+}
+
+func rsaPublicKey(key any) (*rsa.PublicKey, error) {
+	var pub *rsa.PublicKey
+	switch tk := key.(type) {
+	case *rsa.PublicKey:
+		pub = tk
+	case *rsa.PrivateKey:
+		pub = &tk.PublicKey
+	default:
+		return nil, fmt.Errorf("a %T key can not be used to verify with RSA", key)
+	}
+	if pub.N.BitLen() < minRSABits {
+		return nil, fmt.Errorf("an RSA key must be at least %d bits", minRSABits)
+	}
+	return pub, nil
+}
+
+func rsaSign(a *algorithm, key any, input []byte) ([]byte, error) {
+	priv, err := rsaPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return rsa.SignPKCS1v15(rand.Reader, priv, a.hash, a.digest(input))
+}
+
+func rsaCheck(a *algorithm, key any, input, sig []byte) error {
+	pub, err := rsaPublicKey(key)
+	if err != nil {
+		return err
+	}
+	if rsa.VerifyPKCS1v15(pub, a.hash, a.digest(input), sig) != nil {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func pssSign(a *algorithm, key any, input []byte) ([]byte, error) {
+	priv, err := rsaPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return rsa.SignPSS(rand.Reader, priv, a.hash, a.digest(input), &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+}
+
+func pssCheck(a *algorithm, key any, input, sig []byte) error {
+	pub, err := rsaPublicKey(key)
+	if err != nil {
+		return err
+	}
+	if rsa.VerifyPSS(pub, a.hash, a.digest(input), sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) != nil {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ecdsaSize returns the number of bytes in each of the R and S values of a
+// signature.
+func (a *algorithm) ecdsaSize() int {
+	return (a.curve.Params().BitSize + 7) / 8
+}
+
+func ecdsaSign(a *algorithm, key any, input []byte) ([]byte, error) {
+	priv, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("a %T key can not be used to sign with ECDSA", key)
+	}
+	if priv.Curve != a.curve {
+		return nil, fmt.Errorf("a %s key can not be used with this algorithm", priv.Curve.Params().Name)
+	}
+	r, s, err := ecdsa.Sign(rand.Reader, priv, a.digest(input))
+	if err != nil {
+		return nil, err
+	}
+	// The signature is the fixed size big endian R followed by S.
+	size := a.ecdsaSize()
+	sig := make([]byte, size*2)
+	r.FillBytes(sig[:size])
+	s.FillBytes(sig[size:])
+
+	return sig, nil
+}
+
+func ecdsaCheck(a *algorithm, key any, input, sig []byte) error {
+	var pub *ecdsa.PublicKey
+	switch tk := key.(type) {
+	case *ecdsa.PublicKey:
+		pub = tk
+	case *ecdsa.PrivateKey:
+		pub = &tk.PublicKey
+	default:
+		return fmt.Errorf("a %T key can not be used to verify with ECDSA", key)
+	}
+	if pub.Curve != a.curve {
+		return fmt.Errorf("a %s key can not be used with this algorithm", pub.Curve.Params().Name)
+	}
+	size := a.ecdsaSize()
+	if len(sig) != size*2 {
+		return ErrInvalidSignature
+	}
+	r := new(big.Int).SetBytes(sig[:size])
+	s := new(big.Int).SetBytes(sig[size:])
+	if !ecdsa.Verify(pub, a.digest(input), r, s) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func edSign(_ *algorithm, key any, input []byte) ([]byte, error) {
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok || len(priv) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("a %T key can not be used to sign with EdDSA", key)
+	}
+	return ed25519.Sign(priv, input), nil
+}
+
+func edCheck(_ *algorithm, key any, input, sig []byte) error {
+	var pub ed25519.PublicKey
+	switch tk := key.(type) {
+	case ed25519.PublicKey:
+		pub = tk
+	case ed25519.PrivateKey:
+		if len(tk) == ed25519.PrivateKeySize {
+			pub, _ = tk.Public().(ed25519.PublicKey)
+		}
+	}
+	if len(pub) != ed25519.PublicKeySize {
+		return fmt.Errorf("a %T key can not be used to verify with EdDSA", key)
+	}
+	if !ed25519.Verify(pub, input, sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package jws_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"strings"
+	"testing"
+
+	"github.com/khaf/ojg/jws"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestAlgorithms(t *testing.T) {
+	secret := []byte(strings.Repeat("secret..", 8))
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	tt.Nil(t, err)
+	ec256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ec384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	ec521, _ := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	edPub, edPriv, _ := ed25519.GenerateKey(rand.Reader)
+
+	for _, d := range []struct {
+		alg  string
+		priv any
+		pub  any
+	}{
+		{alg: jws.HS256, priv: secret, pub: secret},
+		{alg: jws.HS384, priv: secret, pub: secret},
+		{alg: jws.HS512, priv: secret, pub: secret},
+		{alg: jws.RS256, priv: rsaKey, pub: &rsaKey.PublicKey},
+		{alg: jws.RS384, priv: rsaKey, pub: &rsaKey.PublicKey},
+		{alg: jws.RS512, priv: rsaKey, pub: rsaKey},
+		{alg: jws.PS256, priv: rsaKey, pub: &rsaKey.PublicKey},
+		{alg: jws.PS384, priv: rsaKey, pub: &rsaKey.PublicKey},
+		{alg: jws.PS512, priv: rsaKey, pub: rsaKey},
+		{alg: jws.ES256, priv: ec256, pub: &ec256.PublicKey},
+		{alg: jws.ES384, priv: ec384, pub: &ec384.PublicKey},
+		{alg: jws.ES512, priv: ec521, pub: ec521},
+		{alg: jws.EdDSA, priv: edPriv, pub: edPub},
+		{alg: jws.EdDSA, priv: edPriv, pub: edPriv},
+	} {
+		token, err := jws.Sign(map[string]any{"alg": d.alg}, d.alg, d.priv, map[string]any{"kid": "x"})
+		tt.Nil(t, err, d.alg)
+		j, err := jws.Verify(token, d.pub)
+		tt.Nil(t, err, d.alg)
+		tt.Equal(t, d.alg, j.Alg())
+		tt.Equal(t, `{"alg":"`+d.alg+`"}`, string(j.Payload))
+
+		// Change the payload.
+		parts := strings.Split(token, ".")
+		other, _ := jws.Sign(map[string]any{"alg": "other"}, d.alg, d.priv, map[string]any{"kid": "x"})
+		_, err = jws.Verify(parts[0]+"."+strings.Split(other, ".")[1]+"."+parts[2], d.pub)
+		tt.Equal(t, jws.ErrInvalidSignature, err, d.alg)
+
+		// A key of the wrong type can not be used.
+		_, err = jws.Sign(nil, d.alg, "key", nil)
+		tt.NotNil(t, err, d.alg)
+		_, err = jws.Verify(token, "key")
+		tt.NotNil(t, err, d.alg)
+	}
+}
+
+func TestAlgorithmKeys(t *testing.T) {
+	smallRSA, err := rsa.GenerateKey(rand.Reader, 1024)
+	tt.Nil(t, err)
+	ec256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ec384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+
+	_, err = jws.Sign(nil, jws.HS256, []byte("short"), nil)
+	tt.NotNil(t, err)
+	_, err = jws.Sign(nil, jws.RS256, smallRSA, nil)
+	tt.NotNil(t, err)
+	_, err = jws.Sign(nil, jws.ES256, ec384, nil)
+	tt.NotNil(t, err)
+	_, err = jws.Sign(nil, jws.EdDSA, ed25519.PrivateKey([]byte("short")), nil)
+	tt.NotNil(t, err)
+
+	token, err := jws.Sign(nil, jws.ES256, ec256, nil)
+	tt.Nil(t, err)
+	_, err = jws.Verify(token, &ec384.PublicKey)
+	tt.NotNil(t, err)
+	_, err = jws.Verify(token[:len(token)-4], ec256)
+	tt.Equal(t, jws.ErrInvalidSignature, err)
+	_, err = jws.Verify(token, ed25519.PrivateKey([]byte("short")), jws.ES256)
+	tt.NotNil(t, err)
+
+	// Build a token with a small key to make sure it is rejected.
+	token, err = jws.Sign(nil, jws.HS256, []byte(strings.Repeat("x", 32)), nil)
+	tt.Nil(t, err)
+	parts := strings.Split(token, ".")
+	for _, alg := range []string{jws.RS256, jws.PS256} {
+		header := map[string]string{jws.RS256: "eyJhbGciOiJSUzI1NiJ9", jws.PS256: "eyJhbGciOiJQUzI1NiJ9"}[alg]
+		_, err = jws.Verify(header+"."+parts[1]+"."+parts[2], &smallRSA.PublicKey)
+		tt.NotNil(t, err, alg)
+	}
+	edPub, _, _ := ed25519.GenerateKey(rand.Reader)
+	_, err = jws.Verify("eyJhbGciOiJFZERTQSJ9."+parts[1]+"."+parts[2], edPub)
+	tt.Equal(t, jws.ErrInvalidSignature, err)
+}
@@ -0,0 +1,20 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+/*
+Package jws signs and verifies JSON Web Signatures (RFC 7515) in the
+compact and flattened JSON serializations. Headers and payloads are
+encoded as canonical JSON with the oj package so a JWT is no more than a
+JWS with a claims payload.
+
+	token, err := jws.Sign(map[string]any{"sub": "abc", "exp": 1700000000},
+		jws.HS256, secret, map[string]any{"typ": "JWT"})
+	j, err := jws.Verify(token, secret, jws.HS256)
+	claims, err := j.Value()
+
+The HMAC (HS256, HS384, HS512), RSA PKCS #1 v1.5 (RS256, RS384, RS512),
+RSA PSS (PS256, PS384, PS512), ECDSA (ES256, ES384, ES512), and Ed25519
+(EdDSA) algorithms are supported using only the standard library. The
+none algorithm is not supported and a JWS with critical header extensions
+is rejected.
+*/
+package jws
@@ -0,0 +1,234 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package jws
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/khaf/ojg/oj"
+)
+
+// ErrInvalidSignature is returned when a signature does not match the
+// signed content.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+var b64 = base64.RawURLEncoding
+
+// KeyFunc returns the key to verify a JWS with given the merged protected
+// and unprotected header. It can be passed as the key to Verify and
+// VerifyFlattened to select a key by the kid header for example.
+type KeyFunc func(header map[string]any) (any, error)
+
+// JWS is a verified JSON Web Signature.
+type JWS struct {
+	// Protected is the integrity protected header.
+	Protected map[string]any
+
+	// Header is the unprotected header of a flattened JWS. It is always nil
+	// for a compact JWS.
+	Header map[string]any
+
+	// Payload is the decoded payload.
+	Payload []byte
+}
+
+// Alg returns the alg header value.
+func (j *JWS) Alg() (alg string) {
+	alg, _ = j.Protected["alg"].(string)
+
+	return
+}
+
+// Value returns the payload parsed as JSON.
+func (j *JWS) Value() (any, error) {
+	return oj.Parse(j.Payload)
+}
+
+// Unmarshal the payload into the value pointed to by vp.
+func (j *JWS) Unmarshal(vp any) error {
+	return oj.Unmarshal(j.Payload, vp)
+}
+
+// Sign returns the compact serialization of a JWS of the payload signed
+// with the key using the algorithm. A []byte payload is signed as is. Any
+// other payload, such as JWT claims, is encoded as canonical JSON with
+// oj.Canonical(). The header members are included in the protected header
+// along with the alg which is always set to the algorithm. Keys are a []byte
+// for HMAC, an *rsa.PrivateKey for RSA, an *ecdsa.PrivateKey for ECDSA,
+// and an ed25519.PrivateKey for EdDSA.
+func Sign(payload any, alg string, key any, header map[string]any) (string, error) {
+	protected, body, sig, err := sign(payload, alg, key, header)
+	if err != nil {
+		return "", err
+	}
+	return protected + "." + body + "." + sig, nil
+}
+
+// SignFlattened returns the flattened JSON serialization of a JWS of the
+// payload. The protected header members are integrity protected while the
+// members of the header are not. The payload, algorithm, and key are the
+// same as for Sign.
+func SignFlattened(payload any, alg string, key any, protected, header map[string]any) ([]byte, error) {
+	for k := range header {
+		if _, has := protected[k]; has || k == "alg" {
+			return nil, fmt.Errorf("the %s header member can not be both protected and unprotected", k)
+		}
+	}
+	ph, body, sig, err := sign(payload, alg, key, protected)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]any{
+		"protected": ph,
+		"payload":   body,
+		"signature": sig,
+	}
+	if 0 < len(header) {
+		out["header"] = header
+	}
+	return oj.Canonical(out)
+}
+
+// Verify a compact serialization JWS with the key and return the JWS if
+// the signature is valid. The key must match the alg header. Public keys
+// or private keys can be used for the asymmetric algorithms. If any algs
+// are given then the alg header must be one of them. The key can also be a
+// KeyFunc.
+func Verify(token string, key any, algs ...string) (*JWS, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("a compact JWS must have three parts")
+	}
+	return verify(parts[0], parts[1], parts[2], nil, key, algs)
+}
+
+// VerifyFlattened verifies a flattened JSON serialization JWS. The key and
+// algs are the same as for Verify.
+func VerifyFlattened(data []byte, key any, algs ...string) (*JWS, error) {
+	v, err := oj.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("a flattened JWS must be a JSON object")
+	}
+	if _, has := m["signatures"]; has {
+		return nil, fmt.Errorf("general JWS JSON serialization is not supported")
+	}
+	var parts [3]string
+	for i, name := range []string{"protected", "payload", "signature"} {
+		if parts[i], ok = m[name].(string); !ok {
+			return nil, fmt.Errorf("the %s member of a flattened JWS must be a string", name)
+		}
+	}
+	var header map[string]any
+	if h, has := m["header"]; has {
+		if header, ok = h.(map[string]any); !ok {
+			return nil, fmt.Errorf("the header member of a flattened JWS must be an object")
+		}
+	}
+	return verify(parts[0], parts[1], parts[2], header, key, algs)
+}
+
+func sign(payload any, alg string, key any, header map[string]any) (protected, body, sig string, err error) {
+	var a *algorithm
+	if a, err = findAlgorithm(alg); err != nil {
+		return
+	}
+	ph := make(map[string]any, len(header)+1)
+	for k, v := range header {
+		ph[k] = v
+	}
+	ph["alg"] = alg
+	var js []byte
+	if js, err = oj.Canonical(ph); err != nil {
+		return
+	}
+	protected = b64.EncodeToString(js)
+	if b, ok := payload.([]byte); ok {
+		js = b
+	} else if js, err = oj.Canonical(payload); err != nil {
+		return
+	}
+	body = b64.EncodeToString(js)
+	var raw []byte
+	if raw, err = a.sign(a, key, []byte(protected+"."+body)); err != nil {
+		return
+	}
+	sig = b64.EncodeToString(raw)
+
+	return
+}
+
+func verify(protected, body, sig string, header map[string]any, key any, algs []string) (*JWS, error) {
+	js, err := b64.DecodeString(protected)
+	if err != nil {
+		return nil, fmt.Errorf("invalid protected header encoding: %w", err)
+	}
+	v, err := oj.Parse(js)
+	if err != nil {
+		return nil, fmt.Errorf("invalid protected header: %w", err)
+	}
+	ph, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("the protected header must be a JSON object")
+	}
+	merged := make(map[string]any, len(ph)+len(header))
+	for k, v := range ph {
+		merged[k] = v
+	}
+	for k, v := range header {
+		if _, has := ph[k]; has {
+			return nil, fmt.Errorf("the %s header member can not be both protected and unprotected", k)
+		}
+		merged[k] = v
+	}
+	if _, has := ph["crit"]; has {
+		return nil, fmt.Errorf("critical header extensions are not supported")
+	}
+	alg, _ := ph["alg"].(string)
+	if len(alg) == 0 {
+		return nil, fmt.Errorf("the alg header member must be protected")
+	}
+	if 0 < len(algs) {
+		allowed := false
+		for _, a := range algs {
+			if a == alg {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return nil, fmt.Errorf("algorithm %q is not allowed", alg)
+		}
+	}
+	a, err := findAlgorithm(alg)
+	if err != nil {
+		return nil, err
+	}
+	switch tk := key.(type) {
+	case KeyFunc:
+		key, err = tk(merged)
+	case func(header map[string]any) (any, error):
+		key, err = tk(merged)
+	}
+	if err != nil {
+		return nil, err
+	}
+	raw, err := b64.DecodeString(sig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	if err = a.check(a, key, []byte(protected+"."+body), raw); err != nil {
+		return nil, err
+	}
+	payload, err := b64.DecodeString(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload encoding: %w", err)
+	}
+	return &JWS{Protected: ph, Header: header, Payload: payload}, nil
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package jws_test
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/khaf/ojg/jws"
+	"github.com/khaf/ojg/oj"
+	"github.com/khaf/ojg/tt"
+)
+
+func b64(s string) []byte {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestVerifyRFC7515(t *testing.T) {
+	// Example from RFC 7515 Appendix A.1.
+	key := b64("AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow")
+	token := "eyJ0eXAiOiJKV1QiLA0KICJhbGciOiJIUzI1NiJ9." +
+		"eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ." +
+		"dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	j, err := jws.Verify(token, key)
+	tt.Nil(t, err)
+	tt.Equal(t, jws.HS256, j.Alg())
+	tt.Equal(t, "JWT", j.Protected["typ"])
+	tt.Nil(t, j.Header)
+	v, err := j.Value()
+	tt.Nil(t, err)
+	tt.Equal(t, map[string]any{"iss": "joe", "exp": int64(1300819380), "http://example.com/is_root": true}, v)
+
+	var claims struct {
+		Iss string
+		Exp int64
+	}
+	tt.Nil(t, j.Unmarshal(&claims))
+	tt.Equal(t, "joe", claims.Iss)
+	tt.Equal(t, int64(1300819380), claims.Exp)
+
+	_, err = jws.Verify(token[:len(token)-1]+"Y", key)
+	tt.Equal(t, jws.ErrInvalidSignature, err)
+	_, err = jws.Verify(token, key, jws.HS512, jws.RS256)
+	tt.NotNil(t, err)
+	_, err = jws.Verify(token, key[:16])
+	tt.NotNil(t, err)
+}
+
+func TestSignRFC8037(t *testing.T) {
+	// Example from RFC 8037 Appendix A.4. Ed25519 signatures are
+	// deterministic so the same token is produced.
+	priv := ed25519.NewKeyFromSeed(b64("nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A"))
+	pub := ed25519.PublicKey(b64("11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"))
+	expect := "eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc." +
+		"hgyY0il_MGCjP0JzlnLWG1PPOt7-09PGcvMg3AIbQR6dWbhijcNR4ki4iylGjg5BhVsPt9g7sVvpAr_MuM0KAg"
+	token, err := jws.Sign([]byte("Example of Ed25519 signing"), jws.EdDSA, priv, nil)
+	tt.Nil(t, err)
+	tt.Equal(t, expect, token)
+
+	j, err := jws.Verify(token, pub)
+	tt.Nil(t, err)
+	tt.Equal(t, "Example of Ed25519 signing", string(j.Payload))
+}
+
+func TestSignFlattened(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	claims := map[string]any{"sub": "abc", "n": 1.50}
+	out, err := jws.SignFlattened(claims, jws.HS256, key, map[string]any{"typ": "JWT"}, map[string]any{"kid": "k1"})
+	tt.Nil(t, err)
+	tt.Equal(t, true, strings.HasPrefix(string(out), `{"header":{"kid":"k1"},"payload":"`))
+
+	keyFunc := jws.KeyFunc(func(header map[string]any) (any, error) {
+		if header["kid"] == "k1" {
+			return key, nil
+		}
+		return nil, fmt.Errorf("unknown key %v", header["kid"])
+	})
+	j, err := jws.VerifyFlattened(out, keyFunc, jws.HS256)
+	tt.Nil(t, err)
+	tt.Equal(t, `{"n":1.5,"sub":"abc"}`, string(j.Payload))
+	tt.Equal(t, map[string]any{"kid": "k1"}, j.Header)
+	tt.Equal(t, map[string]any{"alg": "HS256", "typ": "JWT"}, j.Protected)
+
+	out = []byte(strings.Replace(string(out), `"kid":"k1"`, `"kid":"k2"`, 1))
+	_, err = jws.VerifyFlattened(out, keyFunc)
+	tt.NotNil(t, err)
+
+	// The unprotected header is not part of the signature.
+	_, err = jws.VerifyFlattened(out, key)
+	tt.Nil(t, err)
+
+	_, err = jws.SignFlattened(claims, jws.HS256, key, map[string]any{"kid": "x"}, map[string]any{"kid": "k1"})
+	tt.NotNil(t, err)
+	_, err = jws.SignFlattened(claims, jws.HS256, key, nil, map[string]any{"alg": "none"})
+	tt.NotNil(t, err)
+	_, err = jws.SignFlattened(claims, "none", key, nil, nil)
+	tt.NotNil(t, err)
+
+	// The compact form of the same signature can be verified.
+	out, _ = jws.SignFlattened(claims, jws.HS256, key, nil, nil)
+	m, _ := oj.MustParse(out).(map[string]any)
+	_, err = jws.Verify(fmt.Sprintf("%s.%s.%s", m["protected"], m["payload"], m["signature"]), key)
+	tt.Nil(t, err)
+}
+
+func TestVerifyErrors(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	enc := base64.RawURLEncoding.EncodeToString
+	token, err := jws.Sign(map[string]any{"a": 1}, jws.HS256, key, nil)
+	tt.Nil(t, err)
+	parts := strings.Split(token, ".")
+
+	for i, d := range []struct {
+		token  string
+		expect string
+	}{
+		{token: "a.b", expect: "three parts"},
+		{token: "!." + parts[1] + "." + parts[2], expect: "protected header encoding"},
+		{token: enc([]byte("{")) + "." + parts[1] + "." + parts[2], expect: "invalid protected header"},
+		{token: enc([]byte("[]")) + "." + parts[1] + "." + parts[2], expect: "must be a JSON object"},
+		{token: enc([]byte(`{"alg":"HS256","crit":["b64"]}`)) + "." + parts[1] + "." + parts[2], expect: "critical"},
+		{token: enc([]byte(`{"typ":"JWT"}`)) + "." + parts[1] + "." + parts[2], expect: "alg header member"},
+		{token: enc([]byte(`{"alg":"none"}`)) + "." + parts[1] + ".", expect: "not supported"},
+		{token: parts[0] + "." + parts[1] + ".!", expect: "signature encoding"},
+		{token: parts[0] + "." + parts[1] + "x." + parts[2], expect: "invalid signature"},
+	} {
+		_, err := jws.Verify(d.token, key)
+		tt.NotNil(t, err, i)
+		tt.Equal(t, true, strings.Contains(err.Error(), d.expect), i, ": ", err)
+	}
+	for i, d := range []struct {
+		src    string
+		expect string
+	}{
+		{src: `{`, expect: "incomplete"},
+		{src: `[]`, expect: "must be a JSON object"},
+		{src: `{"signatures":[]}`, expect: "not supported"},
+		{src: `{"payload":"x","signature":"x"}`, expect: "protected member"},
+		{src: `{"protected":"` + parts[0] + `","payload":"` + parts[1] + `","signature":"` + parts[2] + `","header":1}`,
+			expect: "header member"},
+		{src: `{"protected":"` + parts[0] + `","payload":"` + parts[1] + `","signature":"` + parts[2] + `","header":{"alg":"x"}}`,
+			expect: "both protected and unprotected"},
+	} {
+		_, err := jws.VerifyFlattened([]byte(d.src), key)
+		tt.NotNil(t, err, i)
+		tt.Equal(t, true, strings.Contains(err.Error(), d.expect), i, ": ", err)
+	}
+	_, err = jws.Sign(func() {}, jws.HS256, key, nil)
+	tt.NotNil(t, err)
+	_, err = jws.Sign(nil, jws.HS256, key, map[string]any{"bad": func() {}})
+	tt.NotNil(t, err)
+	_, err = jws.Verify(token, func(header map[string]any) (any, error) { return nil, fmt.Errorf("no key") })
+	tt.NotNil(t, err)
+}
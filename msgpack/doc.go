@@ -0,0 +1,26 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+/*
+Package msgpack reads and writes MessagePack with the same options and
+much the same API as the oj package. Data is parsed into simple types or
+into gen.Node and any value that can be written as JSON can be written as
+MessagePack.
+
+	b, err := msgpack.Marshal(map[string]any{"a": []any{1, 2.5, "x"}})
+	v, err := msgpack.Parse(b)
+
+Values other than simple types and gen.Node are decomposed using the same
+cached struct information as alt.Decompose so the ojg.Options such as
+OmitNil, CreateKey, UseTags, and KeyExact apply. Unmarshal recomposes
+parsed data into a Go value.
+
+Times are encoded according to the TimeFormat option. A TimeFormat of
+"time", the default for this package, encodes times with the timestamp
+extension type which is parsed into time.Time values. Since MessagePack
+does not have big numbers, numbers that do not fit in 64 bits are
+written as floats.
+
+A Tokenizer calls the functions of an oj.TokenHandler for each value so
+handlers written for JSON can be used with MessagePack.
+*/
+package msgpack
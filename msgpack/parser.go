@@ -0,0 +1,370 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package msgpack
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/alt"
+	"github.com/khaf/ojg/gen"
+)
+
+// maxDepth limits the nesting of arrays and maps so that a small malicious
+// input can not exhaust the stack.
+const maxDepth = 10000
+
+// Parser is a reusable MessagePack parser. A Parser is not safe for
+// concurrent use.
+type Parser struct {
+	buf        []byte
+	pos        int
+	cb         func(any)
+	resultChan chan any
+}
+
+// Parse MessagePack into simple types. Arguments are optional and can be a
+// func(any) bool or func(any) for callbacks, or a chan any for chan based
+// result delivery. If a callback or chan is provided all the values in the
+// data are parsed, otherwise the data must be a single value.
+//
+// Unsigned integers that do not fit in an int64 are returned as a
+// json.Number, bin data as a []byte, and timestamps as a time.Time. The
+// data of other extension types is returned as a []byte.
+func (p *Parser) Parse(buf []byte, args ...any) (result any, err error) {
+	p.cb = nil
+	p.resultChan = nil
+	for _, a := range args {
+		switch ta := a.(type) {
+		case func(any) bool:
+			p.cb = func(x any) { _ = ta(x) }
+		case func(any):
+			p.cb = ta
+		case chan any:
+			p.resultChan = ta
+		default:
+			return nil, fmt.Errorf("a %T is not a valid option type", a)
+		}
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			result = nil
+			err = ojg.NewError(r)
+		}
+	}()
+	p.buf = buf
+	p.pos = 0
+	if p.cb == nil && p.resultChan == nil {
+		result = p.value(0)
+		p.checkEnd()
+		return
+	}
+	for p.pos < len(p.buf) {
+		v := p.value(0)
+		if p.cb != nil {
+			p.cb(v)
+		}
+		if p.resultChan != nil {
+			p.resultChan <- v
+		}
+	}
+	return
+}
+
+// ParseReader reads MessagePack from an io.Reader. All the data is read
+// before parsing. The arguments are the same as for Parse.
+func (p *Parser) ParseReader(r io.Reader, args ...any) (any, error) {
+	buf, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return p.Parse(buf, args...)
+}
+
+// ParseNode parses a single MessagePack value into a gen.Node. Unsigned
+// integers that do not fit in an int64 are returned as a gen.Uint, bin
+// data as a gen.Bytes, and timestamps as a gen.Time.
+func (p *Parser) ParseNode(buf []byte) (result gen.Node, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = nil
+			err = ojg.NewError(r)
+		}
+	}()
+	p.buf = buf
+	p.pos = 0
+	result = p.node(0)
+	p.checkEnd()
+
+	return
+}
+
+// Unmarshal parses the provided MessagePack and stores the result in the
+// value pointed to by vp.
+func (p *Parser) Unmarshal(data []byte, vp any, recomposer ...*alt.Recomposer) (err error) {
+	var v any
+	if v, err = p.Parse(data); err == nil {
+		if 0 < len(recomposer) {
+			_, err = recomposer[0].Recompose(v, vp)
+		} else {
+			_, err = alt.Recompose(v, vp)
+		}
+	}
+	return
+}
+
+func (p *Parser) value(depth int) (v any) {
+	start := p.pos
+	b := p.read(1)[0]
+	switch {
+	case b <= math.MaxInt8:
+		v = int64(b)
+	case 0xe0 <= b:
+		v = int64(int8(b))
+	case b&0xf0 == fixMap:
+		v = p.object(int(b&0x0f), depth)
+	case b&0xf0 == fixArray:
+		v = p.array(int(b&0x0f), depth)
+	case b&0xe0 == fixStr:
+		v = p.text(int(b & 0x1f))
+	default:
+		switch b {
+		case mpNil:
+		case mpFalse:
+			v = false
+		case mpTrue:
+			v = true
+		case mpBin8, mpBin16, mpBin32:
+			v = append([]byte{}, p.read(uint64(p.size(b-mpBin8)))...)
+		case mpExt8, mpExt16, mpExt32:
+			v = p.ext(start, p.size(b-mpExt8))
+		case mpFloat32:
+			v = float64(math.Float32frombits(binary.BigEndian.Uint32(p.read(4))))
+		case mpFloat64:
+			v = math.Float64frombits(binary.BigEndian.Uint64(p.read(8)))
+		case mpUint8, mpUint16, mpUint32, mpUint64:
+			if u := p.uint(b - mpUint8); u <= math.MaxInt64 {
+				v = int64(u)
+			} else {
+				v = json.Number(strconv.FormatUint(u, 10))
+			}
+		case mpInt8, mpInt16, mpInt32, mpInt64:
+			v = p.int(b - mpInt8)
+		case mpFixExt1, mpFixExt2, mpFixExt4, mpFixExt8, mpFixExt:
+			v = p.ext(start, 1<<(b-mpFixExt1))
+		case mpStr8, mpStr16, mpStr32:
+			v = p.text(p.size(b - mpStr8))
+		case mpArray16, mpArray32:
+			v = p.array(p.size(b-mpArray16+1), depth)
+		case mpMap16, mpMap32:
+			v = p.object(p.size(b-mpMap16+1), depth)
+		default:
+			panic(fmt.Errorf("invalid format byte 0x%02x at %d", b, start))
+		}
+	}
+	return
+}
+
+func (p *Parser) array(size, depth int) []any {
+	p.checkDepth(depth)
+	p.checkCount(size)
+	a := make([]any, 0, size)
+	for i := 0; i < size; i++ {
+		a = append(a, p.value(depth+1))
+	}
+	return a
+}
+
+func (p *Parser) object(size, depth int) map[string]any {
+	p.checkDepth(depth)
+	p.checkCount(size)
+	obj := make(map[string]any, size)
+	for i := 0; i < size; i++ {
+		k := p.key(depth)
+		obj[k] = p.value(depth + 1)
+	}
+	return obj
+}
+
+func (p *Parser) node(depth int) (v gen.Node) {
+	if p.pos < len(p.buf) {
+		// Maps and arrays are handled here so the members are nodes.
+		b := p.buf[p.pos]
+		switch {
+		case b&0xf0 == fixMap:
+			p.pos++
+			return p.nodeObject(int(b&0x0f), depth)
+		case b == mpMap16 || b == mpMap32:
+			p.pos++
+			return p.nodeObject(p.size(b-mpMap16+1), depth)
+		case b&0xf0 == fixArray:
+			p.pos++
+			return p.nodeArray(int(b&0x0f), depth)
+		case b == mpArray16 || b == mpArray32:
+			p.pos++
+			return p.nodeArray(p.size(b-mpArray16+1), depth)
+		}
+	}
+	switch tv := p.value(depth).(type) {
+	case bool:
+		v = gen.Bool(tv)
+	case int64:
+		v = gen.Int(tv)
+	case json.Number:
+		// Only integers too large for an int64 are returned as a number.
+		u, _ := strconv.ParseUint(string(tv), 10, 64)
+		v = gen.Uint(u)
+	case float64:
+		v = gen.Float(tv)
+	case string:
+		v = gen.String(tv)
+	case []byte:
+		v = gen.Bytes(tv)
+	case time.Time:
+		v = gen.Time(tv)
+	}
+	return
+}
+
+func (p *Parser) nodeArray(size, depth int) gen.Array {
+	p.checkDepth(depth)
+	p.checkCount(size)
+	a := make(gen.Array, 0, size)
+	for i := 0; i < size; i++ {
+		a = append(a, p.node(depth+1))
+	}
+	return a
+}
+
+func (p *Parser) nodeObject(size, depth int) gen.Object {
+	p.checkDepth(depth)
+	p.checkCount(size)
+	obj := make(gen.Object, size)
+	for i := 0; i < size; i++ {
+		k := p.key(depth)
+		obj[k] = p.node(depth + 1)
+	}
+	return obj
+}
+
+// key reads a map key. String keys are used as is while other keys are
+// converted to a string.
+func (p *Parser) key(depth int) string {
+	k := p.value(depth + 1)
+	if s, ok := k.(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%v", k)
+}
+
+// ext reads an extension type and data. Timestamps are returned as a
+// time.Time and the data of other types as a []byte.
+func (p *Parser) ext(start, size int) any {
+	typ := int8(p.read(1)[0])
+	data := p.read(uint64(size))
+	if typ != extTime {
+		return append([]byte{}, data...)
+	}
+	switch size {
+	case 4:
+		return time.Unix(int64(binary.BigEndian.Uint32(data)), 0).UTC()
+	case 8:
+		v := binary.BigEndian.Uint64(data)
+		if nsecs := int64(v >> 34); nsecs < int64(time.Second) {
+			return time.Unix(int64(v&0x3ffffffff), nsecs).UTC()
+		}
+	case 12:
+		if nsecs := int64(binary.BigEndian.Uint32(data)); nsecs < int64(time.Second) {
+			return time.Unix(int64(binary.BigEndian.Uint64(data[4:])), nsecs).UTC()
+		}
+	}
+	panic(fmt.Errorf("invalid timestamp at %d", start))
+}
+
+// size reads a 1, 2, or 4 byte length where the index is 0, 1, or 2.
+func (p *Parser) size(index byte) int {
+	switch index {
+	case 0:
+		return int(p.read(1)[0])
+	case 1:
+		return int(binary.BigEndian.Uint16(p.read(2)))
+	default:
+		return int(binary.BigEndian.Uint32(p.read(4)))
+	}
+}
+
+// uint reads a 1, 2, 4, or 8 byte unsigned integer where the index is 0,
+// 1, 2, or 3.
+func (p *Parser) uint(index byte) uint64 {
+	switch index {
+	case 0:
+		return uint64(p.read(1)[0])
+	case 1:
+		return uint64(binary.BigEndian.Uint16(p.read(2)))
+	case 2:
+		return uint64(binary.BigEndian.Uint32(p.read(4)))
+	default:
+		return binary.BigEndian.Uint64(p.read(8))
+	}
+}
+
+// int reads a 1, 2, 4, or 8 byte signed integer where the index is 0, 1,
+// 2, or 3.
+func (p *Parser) int(index byte) int64 {
+	switch index {
+	case 0:
+		return int64(int8(p.read(1)[0]))
+	case 1:
+		return int64(int16(binary.BigEndian.Uint16(p.read(2))))
+	case 2:
+		return int64(int32(binary.BigEndian.Uint32(p.read(4))))
+	default:
+		return int64(binary.BigEndian.Uint64(p.read(8)))
+	}
+}
+
+func (p *Parser) text(size int) string {
+	start := p.pos
+	b := p.read(uint64(size))
+	if !utf8.Valid(b) {
+		panic(fmt.Errorf("invalid UTF-8 string at %d", start))
+	}
+	return string(b)
+}
+
+func (p *Parser) read(n uint64) []byte {
+	if uint64(len(p.buf)-p.pos) < n {
+		panic(fmt.Errorf("incomplete MessagePack at %d", p.pos))
+	}
+	b := p.buf[p.pos : p.pos+int(n)]
+	p.pos += int(n)
+
+	return b
+}
+
+func (p *Parser) checkDepth(depth int) {
+	if maxDepth <= depth {
+		panic(fmt.Errorf("too deeply nested at %d", p.pos))
+	}
+}
+
+// checkCount verifies there are at least as many bytes remaining as values
+// expected so that a bogus count does not cause a huge allocation.
+func (p *Parser) checkCount(n int) {
+	if len(p.buf)-p.pos < n {
+		panic(fmt.Errorf("incomplete MessagePack at %d", p.pos))
+	}
+}
+
+func (p *Parser) checkEnd() {
+	if p.pos < len(p.buf) {
+		panic(fmt.Errorf("extra data after MessagePack value at %d", p.pos))
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package msgpack_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khaf/ojg/alt"
+	"github.com/khaf/ojg/gen"
+	"github.com/khaf/ojg/msgpack"
+	"github.com/khaf/ojg/tt"
+)
+
+func unhex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestParseFormats(t *testing.T) {
+	for _, d := range []struct {
+		src    string
+		expect any
+	}{
+		{src: "c0", expect: nil},
+		{src: "c2", expect: false},
+		{src: "c3", expect: true},
+		{src: "7f", expect: int64(127)},
+		{src: "e0", expect: int64(-32)},
+		{src: "cc80", expect: int64(128)},
+		{src: "cd0100", expect: int64(256)},
+		{src: "ce00010000", expect: int64(65536)},
+		{src: "cf0000000100000000", expect: int64(1) << 32},
+		{src: "cfffffffffffffffff", expect: json.Number("18446744073709551615")},
+		{src: "d0df", expect: int64(-33)},
+		{src: "d1ff7f", expect: int64(-129)},
+		{src: "d2ffff7fff", expect: int64(-32769)},
+		{src: "d3ffffffff7fffffff", expect: int64(-2147483649)},
+		{src: "ca3fc00000", expect: 1.5},
+		{src: "cb3ff8000000000000", expect: 1.5},
+		{src: "a3616263", expect: "abc"},
+		{src: "d903616263", expect: "abc"},
+		{src: "da0003616263", expect: "abc"},
+		{src: "db00000003616263", expect: "abc"},
+		{src: "c4020102", expect: []byte{1, 2}},
+		{src: "c500020102", expect: []byte{1, 2}},
+		{src: "c6000000020102", expect: []byte{1, 2}},
+		{src: "920102", expect: []any{int64(1), int64(2)}},
+		{src: "dc00020102", expect: []any{int64(1), int64(2)}},
+		{src: "dd000000020102", expect: []any{int64(1), int64(2)}},
+		{src: "81a16101", expect: map[string]any{"a": int64(1)}},
+		{src: "de0001a16101", expect: map[string]any{"a": int64(1)}},
+		{src: "df00000001a16101", expect: map[string]any{"a": int64(1)}},
+		{src: "820102c3c0", expect: map[string]any{"1": int64(2), "true": nil}},
+		{src: "d6ff00000001", expect: time.Unix(1, 0).UTC()},
+		{src: "d7ff0000000400000001", expect: time.Unix(1, 1).UTC()},
+		{src: "c70cff00000005ffffffffffffffff", expect: time.Unix(-1, 5).UTC()},
+		{src: "d40101", expect: []byte{1}},
+		{src: "d5010102", expect: []byte{1, 2}},
+		{src: "d8010102030405060708090a0b0c0d0e0f10", expect: []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}},
+		{src: "c7020a0102", expect: []byte{1, 2}},
+		{src: "c800020a0102", expect: []byte{1, 2}},
+		{src: "c9000000020a0102", expect: []byte{1, 2}},
+	} {
+		v, err := msgpack.Parse(unhex(d.src))
+		tt.Nil(t, err, d.src)
+		tt.Equal(t, d.expect, v, d.src)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, d := range []struct {
+		src    string
+		expect string
+	}{
+		{src: "", expect: "incomplete"},
+		{src: "cd01", expect: "incomplete"},
+		{src: "0000", expect: "extra data"},
+		{src: "a2c328", expect: "UTF-8"},
+		{src: "c1", expect: "invalid format"},
+		{src: "dd7fffffff", expect: "incomplete"},
+		{src: "df7fffffff", expect: "incomplete"},
+		{src: "d7ff" + "ffffffff00000000", expect: "invalid timestamp"},
+		{src: "c70cff" + "ffffffff" + "0000000000000000", expect: "invalid timestamp"},
+		{src: "d5ff0000", expect: "invalid timestamp"},
+		{src: strings.Repeat("91", 10001) + "00", expect: "nested"},
+		{src: strings.Repeat("81a0", 10001) + "00", expect: "nested"},
+	} {
+		_, err := msgpack.Parse(unhex(d.src))
+		tt.NotNil(t, err, d.src)
+		tt.Equal(t, true, strings.Contains(err.Error(), d.expect), d.src, ": ", err)
+		_, err = msgpack.ParseNode(unhex(d.src))
+		tt.NotNil(t, err, d.src)
+	}
+	_, err := msgpack.Parse([]byte{0}, 7)
+	tt.NotNil(t, err)
+	tt.Panic(t, func() { msgpack.MustParse([]byte{0xc1}) })
+	tt.Panic(t, func() { msgpack.MustLoad(bytes.NewReader([]byte{0xc1})) })
+}
+
+func TestParseSequence(t *testing.T) {
+	src := unhex("0102c3")
+	var items []any
+	_, err := msgpack.Parse(src, func(v any) bool { items = append(items, v); return false })
+	tt.Nil(t, err)
+	tt.Equal(t, []any{int64(1), int64(2), true}, items)
+
+	items = items[:0]
+	_, err = msgpack.Load(bytes.NewReader(src), func(v any) { items = append(items, v) })
+	tt.Nil(t, err)
+	tt.Equal(t, []any{int64(1), int64(2), true}, items)
+
+	ch := make(chan any, 3)
+	_, err = msgpack.Parse(src, ch)
+	tt.Nil(t, err)
+	tt.Equal(t, int64(1), <-ch)
+
+	v, err := msgpack.Load(bytes.NewReader(unhex("9101")))
+	tt.Nil(t, err)
+	tt.Equal(t, []any{int64(1)}, v)
+	tt.Equal(t, []any{int64(1)}, msgpack.MustLoad(bytes.NewReader(unhex("9101"))))
+}
+
+func TestParseNode(t *testing.T) {
+	src := unhex("87a16193ffcfffffffffffffffffc0a162c40102a163de0001a164c2a165d6ff00000000a166cb3ff8000000000000" +
+		"a167a161a168dc0001c3")
+	n, err := msgpack.ParseNode(src)
+	tt.Nil(t, err)
+	tt.Equal(t, gen.Object{
+		"a": gen.Array{gen.Int(-1), gen.Uint(18446744073709551615), nil},
+		"b": gen.Bytes{2},
+		"c": gen.Object{"d": gen.Bool(false)},
+		"e": gen.Time(time.Unix(0, 0).UTC()),
+		"f": gen.Float(1.5),
+		"g": gen.String("a"),
+		"h": gen.Array{gen.Bool(true)},
+	}, n)
+
+	// Round trip through the writer.
+	out, err := msgpack.Marshal(n, &msgpack.DefaultOptions)
+	tt.Nil(t, err)
+	n2, err := msgpack.ParseNode(out)
+	tt.Nil(t, err)
+	tt.Equal(t, n, n2)
+}
+
+func TestUnmarshal(t *testing.T) {
+	when := time.Date(2023, 1, 2, 3, 4, 5, 6, time.UTC)
+	src := &sample{Name: "x", Count: 2, When: when, Data: []byte{1, 2}, Ptr: &sample{Name: "y"}, Other: []any{1.5}}
+	out, err := msgpack.Marshal(src)
+	tt.Nil(t, err)
+
+	var s sample
+	err = msgpack.Unmarshal(out, &s)
+	tt.Nil(t, err)
+	tt.Equal(t, "x", s.Name)
+	tt.Equal(t, 2, s.Count)
+	tt.Equal(t, when, s.When)
+	tt.Equal(t, []byte{1, 2}, s.Data)
+	tt.Equal(t, "y", s.Ptr.Name)
+	tt.Equal(t, []any{1.5}, s.Other)
+
+	var p msgpack.Parser
+	var s2 sample
+	err = p.Unmarshal(out, &s2, &alt.DefaultRecomposer)
+	tt.Nil(t, err)
+	tt.Equal(t, "x", s2.Name)
+
+	err = msgpack.Unmarshal([]byte{0xc1}, &s)
+	tt.NotNil(t, err)
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package msgpack
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/oj"
+)
+
+// Tokenizer is used to tokenize MessagePack. The same oj.TokenHandler used
+// with the JSON tokenizer can be used with the MessagePack tokenizer. Since
+// the handler only has JSON types, bin and extension data is passed to
+// String() as base64, timestamps as RFC 3339 strings, and integers that do
+// not fit in an int64 to Number().
+type Tokenizer struct {
+	p       Parser
+	handler oj.TokenHandler
+}
+
+// Tokenize the provided MessagePack and call the TokenHandler functions for
+// each token. All the values in the data are tokenized.
+func Tokenize(data []byte, handler oj.TokenHandler) error {
+	t := Tokenizer{}
+	return t.Parse(data, handler)
+}
+
+// TokenizeLoad MessagePack from an io.Reader and call the TokenHandler
+// functions for each token.
+func TokenizeLoad(r io.Reader, handler oj.TokenHandler) error {
+	t := Tokenizer{}
+	return t.Load(r, handler)
+}
+
+// Parse the MessagePack and call the handler functions for each token.
+func (t *Tokenizer) Parse(buf []byte, handler oj.TokenHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = ojg.NewError(r)
+		}
+	}()
+	t.handler = handler
+	t.p.buf = buf
+	t.p.pos = 0
+	for t.p.pos < len(t.p.buf) {
+		t.token(0)
+	}
+	return
+}
+
+// Load and parse the MessagePack and call the handler functions for each
+// token. All the data is read before tokenizing.
+func (t *Tokenizer) Load(r io.Reader, handler oj.TokenHandler) error {
+	buf, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return t.Parse(buf, handler)
+}
+
+func (t *Tokenizer) token(depth int) {
+	p := &t.p
+	b := p.buf[p.pos]
+	switch {
+	case b&0xf0 == fixMap:
+		p.pos++
+		t.object(int(b&0x0f), depth)
+	case b == mpMap16 || b == mpMap32:
+		p.pos++
+		t.object(p.size(b-mpMap16+1), depth)
+	case b&0xf0 == fixArray:
+		p.pos++
+		t.array(int(b&0x0f), depth)
+	case b == mpArray16 || b == mpArray32:
+		p.pos++
+		t.array(p.size(b-mpArray16+1), depth)
+	default:
+		switch tv := p.value(depth).(type) {
+		case nil:
+			t.handler.Null()
+		case bool:
+			t.handler.Bool(tv)
+		case int64:
+			t.handler.Int(tv)
+		case json.Number:
+			t.handler.Number(string(tv))
+		case float64:
+			t.handler.Float(tv)
+		case string:
+			t.handler.String(tv)
+		case []byte:
+			t.handler.String(base64.StdEncoding.EncodeToString(tv))
+		case time.Time:
+			t.handler.String(tv.Format(time.RFC3339Nano))
+		}
+	}
+}
+
+func (t *Tokenizer) array(size, depth int) {
+	t.p.checkDepth(depth)
+	t.p.checkCount(size)
+	t.handler.ArrayStart()
+	for i := 0; i < size; i++ {
+		t.token(depth + 1)
+	}
+	t.handler.ArrayEnd()
+}
+
+func (t *Tokenizer) object(size, depth int) {
+	t.p.checkDepth(depth)
+	t.p.checkCount(size)
+	t.handler.ObjectStart()
+	for i := 0; i < size; i++ {
+		t.handler.Key(t.p.key(depth))
+		t.token(depth + 1)
+	}
+	t.handler.ObjectEnd()
+}
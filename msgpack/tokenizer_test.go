@@ -0,0 +1,98 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package msgpack_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/khaf/ojg/msgpack"
+	"github.com/khaf/ojg/oj"
+	"github.com/khaf/ojg/tt"
+)
+
+type testHandler struct {
+	buf []byte
+}
+
+func (h *testHandler) Null() {
+	h.buf = append(h.buf, "null "...)
+}
+
+func (h *testHandler) Bool(v bool) {
+	h.buf = append(h.buf, fmt.Sprintf("%t ", v)...)
+}
+
+func (h *testHandler) Int(v int64) {
+	h.buf = append(h.buf, fmt.Sprintf("%d ", v)...)
+}
+
+func (h *testHandler) Float(v float64) {
+	h.buf = append(h.buf, fmt.Sprintf("%g ", v)...)
+}
+
+func (h *testHandler) Number(v string) {
+	h.buf = append(h.buf, fmt.Sprintf("%s ", v)...)
+}
+
+func (h *testHandler) String(v string) {
+	h.buf = append(h.buf, fmt.Sprintf("%s ", v)...)
+}
+
+func (h *testHandler) ObjectStart() {
+	h.buf = append(h.buf, "{ "...)
+}
+
+func (h *testHandler) ObjectEnd() {
+	h.buf = append(h.buf, "} "...)
+}
+
+func (h *testHandler) Key(v string) {
+	h.buf = append(h.buf, fmt.Sprintf("%s: ", v)...)
+}
+
+func (h *testHandler) ArrayStart() {
+	h.buf = append(h.buf, "[ "...)
+}
+
+func (h *testHandler) ArrayEnd() {
+	h.buf = append(h.buf, "] "...)
+}
+
+type errReader struct{}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, errors.New("failed")
+}
+
+func TestTokenize(t *testing.T) {
+	var h testHandler
+	src := unhex("94c3c07bcb3ff8000000000000" + "81a178cfffffffffffffffff" + "dc0002ffca3fc00000" +
+		"de0002c4020102a161a162d40a00" + "d6ff514b67b0")
+	err := msgpack.Tokenize(src, &h)
+	tt.Nil(t, err)
+	tt.Equal(t, "[ true null 123 1.5 ] { x: 18446744073709551615 } [ -1 1.5 ] { [1 2]: a b: AA== } "+
+		"2013-03-21T20:04:00Z ",
+		string(h.buf))
+
+	h.buf = h.buf[:0]
+	err = msgpack.TokenizeLoad(bytes.NewReader(unhex("9201c2")), &h)
+	tt.Nil(t, err)
+	tt.Equal(t, "[ 1 false ] ", string(h.buf))
+
+	err = msgpack.TokenizeLoad(errReader{}, &h)
+	tt.NotNil(t, err)
+	err = msgpack.Tokenize(unhex("92"), &h)
+	tt.NotNil(t, err)
+	err = msgpack.Tokenize(unhex("dd7fffffff"), &h)
+	tt.NotNil(t, err)
+	err = msgpack.Tokenize(unhex(strings.Repeat("81a0", 10001)+"00"), &h)
+	tt.NotNil(t, err)
+
+	// Any oj.TokenHandler can be used.
+	err = msgpack.Tokenize(src, &oj.ZeroHandler{})
+	tt.Nil(t, err)
+}
@@ -0,0 +1,436 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package msgpack
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/alt"
+	"github.com/khaf/ojg/gen"
+)
+
+// Format bytes. The fix formats include the size or value in the low bits.
+const (
+	fixMap    = 0x80
+	fixArray  = 0x90
+	fixStr    = 0xa0
+	mpNil     = 0xc0
+	mpNever   = 0xc1
+	mpFalse   = 0xc2
+	mpTrue    = 0xc3
+	mpBin8    = 0xc4
+	mpBin16   = 0xc5
+	mpBin32   = 0xc6
+	mpExt8    = 0xc7
+	mpExt16   = 0xc8
+	mpExt32   = 0xc9
+	mpFloat32 = 0xca
+	mpFloat64 = 0xcb
+	mpUint8   = 0xcc
+	mpUint16  = 0xcd
+	mpUint32  = 0xce
+	mpUint64  = 0xcf
+	mpInt8    = 0xd0
+	mpInt16   = 0xd1
+	mpInt32   = 0xd2
+	mpInt64   = 0xd3
+	mpFixExt1 = 0xd4
+	mpFixExt2 = 0xd5
+	mpFixExt4 = 0xd6
+	mpFixExt8 = 0xd7
+	mpFixExt  = 0xd8
+	mpStr8    = 0xd9
+	mpStr16   = 0xda
+	mpStr32   = 0xdb
+	mpArray16 = 0xdc
+	mpArray32 = 0xdd
+	mpMap16   = 0xde
+	mpMap32   = 0xdf
+
+	// extTime is the timestamp extension type.
+	extTime = -1
+)
+
+// Writer is a MessagePack writer that includes a reused buffer for reduced
+// allocations for repeated encoding calls.
+type Writer struct {
+	ojg.Options
+	buf  []byte
+	w    io.Writer
+	dopt ojg.Options
+}
+
+// Marshal data as MessagePack. The returned slice is a copy and is not
+// reused.
+func (wr *Writer) Marshal(data any) (out []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			wr.buf = wr.buf[:0]
+			err = ojg.NewError(r)
+		}
+	}()
+	wr.MustMarshal(data)
+	out = make([]byte, len(wr.buf))
+	copy(out, wr.buf)
+
+	return
+}
+
+// MustMarshal data as MessagePack. On error a panic is called with the
+// error. The returned buffer is the Writer buffer and is reused on the next
+// call to write. If returned value is to be preserved past a second
+// invocation then the buffer should be copied.
+func (wr *Writer) MustMarshal(data any) []byte {
+	wr.w = nil
+	wr.prepare()
+	wr.appendValue(alt.ConvertForWrite(data, &wr.Options))
+
+	return wr.buf
+}
+
+// Write MessagePack for the data provided.
+func (wr *Writer) Write(w io.Writer, data any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			wr.buf = wr.buf[:0]
+			err = ojg.NewError(r)
+		}
+	}()
+	wr.MustWrite(w, data)
+	return
+}
+
+// MustWrite MessagePack for the data provided. If an error occurs panic is
+// called with the error.
+func (wr *Writer) MustWrite(w io.Writer, data any) {
+	wr.w = w
+	if wr.WriteLimit <= 0 {
+		wr.WriteLimit = 1024
+	}
+	wr.prepare()
+	wr.appendValue(alt.ConvertForWrite(data, &wr.Options))
+	if 0 < len(wr.buf) {
+		if _, err := wr.w.Write(wr.buf); err != nil {
+			panic(err)
+		}
+		wr.buf = wr.buf[:0]
+	}
+}
+
+func (wr *Writer) prepare() {
+	if wr.InitSize <= 0 {
+		wr.InitSize = 256
+	}
+	if cap(wr.buf) < wr.InitSize {
+		wr.buf = make([]byte, 0, wr.InitSize)
+	} else {
+		wr.buf = wr.buf[:0]
+	}
+	// Values that are not handled directly are decomposed with times left
+	// as time.Time and []byte left as is so they are encoded the same as
+	// top level values.
+	wr.dopt = wr.Options
+	wr.dopt.Converter = nil
+	wr.dopt.BytesAs = ojg.BytesAsBytes
+	wr.dopt.TimeFormat = "time"
+	wr.dopt.TimeMap = false
+	wr.dopt.TimeWrap = ""
+}
+
+func (wr *Writer) appendValue(data any) {
+	switch td := data.(type) {
+	case nil:
+		wr.buf = append(wr.buf, mpNil)
+	case bool:
+		wr.appendBool(td)
+	case int:
+		wr.appendInt(int64(td))
+	case int8:
+		wr.appendInt(int64(td))
+	case int16:
+		wr.appendInt(int64(td))
+	case int32:
+		wr.appendInt(int64(td))
+	case int64:
+		wr.appendInt(td)
+	case uint:
+		wr.appendUint(uint64(td))
+	case uint8:
+		wr.appendUint(uint64(td))
+	case uint16:
+		wr.appendUint(uint64(td))
+	case uint32:
+		wr.appendUint(uint64(td))
+	case uint64:
+		wr.appendUint(td)
+	case float32:
+		wr.buf = append(wr.buf, mpFloat32)
+		wr.buf = binary.BigEndian.AppendUint32(wr.buf, math.Float32bits(td))
+	case float64:
+		wr.appendFloat(td)
+	case string:
+		wr.appendString(td)
+	case []byte:
+		wr.appendBytes(td)
+	case time.Time:
+		wr.appendTime(td)
+	case json.Number:
+		wr.appendNumber(string(td))
+	case []any:
+		wr.appendArrayHead(len(td))
+		for _, v := range td {
+			wr.appendValue(v)
+		}
+	case map[string]any:
+		wr.appendObject(td)
+
+	case gen.Null:
+		wr.buf = append(wr.buf, mpNil)
+	case gen.Bool:
+		wr.appendBool(bool(td))
+	case gen.Int:
+		wr.appendInt(int64(td))
+	case gen.Uint:
+		wr.appendUint(uint64(td))
+	case gen.Float:
+		wr.appendFloat(float64(td))
+	case gen.String:
+		wr.appendString(string(td))
+	case gen.Bytes:
+		wr.appendBytes(td)
+	case gen.Time:
+		wr.appendTime(time.Time(td))
+	case gen.Big:
+		wr.appendNumber(string(td))
+	case gen.Decimal:
+		wr.appendNumber(string(td))
+	case gen.Array:
+		wr.appendArrayHead(len(td))
+		for _, v := range td {
+			wr.appendValue(v)
+		}
+	case gen.Object:
+		wr.appendGenObject(td)
+	default:
+		wr.appendDefault(data)
+	}
+	if wr.w != nil && wr.WriteLimit < len(wr.buf) {
+		if _, err := wr.w.Write(wr.buf); err != nil {
+			panic(err)
+		}
+		wr.buf = wr.buf[:0]
+	}
+}
+
+func (wr *Writer) appendDefault(data any) {
+	if simp, ok := data.(alt.Simplifier); ok {
+		wr.appendValue(simp.Simplify())
+		return
+	}
+	rv := reflect.ValueOf(data)
+	switch rv.Kind() {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		panic(fmt.Errorf("%T can not be encoded as a MessagePack element", data))
+	}
+	// Structs, maps, and slices are decomposed using the cached struct
+	// information of the alt package.
+	wr.appendValue(alt.Decompose(data, &wr.dopt))
+}
+
+func (wr *Writer) appendBool(b bool) {
+	if b {
+		wr.buf = append(wr.buf, mpTrue)
+	} else {
+		wr.buf = append(wr.buf, mpFalse)
+	}
+}
+
+// appendInt appends an integer using the smallest format.
+func (wr *Writer) appendInt(i int64) {
+	switch {
+	case 0 <= i:
+		wr.appendUint(uint64(i))
+	case -32 <= i:
+		wr.buf = append(wr.buf, byte(i))
+	case math.MinInt8 <= i:
+		wr.buf = append(wr.buf, mpInt8, byte(i))
+	case math.MinInt16 <= i:
+		wr.buf = append(wr.buf, mpInt16)
+		wr.buf = binary.BigEndian.AppendUint16(wr.buf, uint16(i))
+	case math.MinInt32 <= i:
+		wr.buf = append(wr.buf, mpInt32)
+		wr.buf = binary.BigEndian.AppendUint32(wr.buf, uint32(i))
+	default:
+		wr.buf = append(wr.buf, mpInt64)
+		wr.buf = binary.BigEndian.AppendUint64(wr.buf, uint64(i))
+	}
+}
+
+// appendUint appends an unsigned integer using the smallest format.
+func (wr *Writer) appendUint(u uint64) {
+	switch {
+	case u <= math.MaxInt8:
+		wr.buf = append(wr.buf, byte(u))
+	case u <= math.MaxUint8:
+		wr.buf = append(wr.buf, mpUint8, byte(u))
+	case u <= math.MaxUint16:
+		wr.buf = append(wr.buf, mpUint16)
+		wr.buf = binary.BigEndian.AppendUint16(wr.buf, uint16(u))
+	case u <= math.MaxUint32:
+		wr.buf = append(wr.buf, mpUint32)
+		wr.buf = binary.BigEndian.AppendUint32(wr.buf, uint32(u))
+	default:
+		wr.buf = append(wr.buf, mpUint64)
+		wr.buf = binary.BigEndian.AppendUint64(wr.buf, u)
+	}
+}
+
+func (wr *Writer) appendFloat(f float64) {
+	wr.buf = append(wr.buf, mpFloat64)
+	wr.buf = binary.BigEndian.AppendUint64(wr.buf, math.Float64bits(f))
+}
+
+func (wr *Writer) appendString(s string) {
+	size := len(s)
+	switch {
+	case size < 32:
+		wr.buf = append(wr.buf, fixStr|byte(size))
+	case size <= math.MaxUint8:
+		wr.buf = append(wr.buf, mpStr8, byte(size))
+	case size <= math.MaxUint16:
+		wr.buf = append(wr.buf, mpStr16)
+		wr.buf = binary.BigEndian.AppendUint16(wr.buf, uint16(size))
+	default:
+		wr.buf = append(wr.buf, mpStr32)
+		wr.buf = binary.BigEndian.AppendUint32(wr.buf, uint32(size))
+	}
+	wr.buf = append(wr.buf, s...)
+}
+
+func (wr *Writer) appendBytes(b []byte) {
+	size := len(b)
+	switch {
+	case size <= math.MaxUint8:
+		wr.buf = append(wr.buf, mpBin8, byte(size))
+	case size <= math.MaxUint16:
+		wr.buf = append(wr.buf, mpBin16)
+		wr.buf = binary.BigEndian.AppendUint16(wr.buf, uint16(size))
+	default:
+		wr.buf = append(wr.buf, mpBin32)
+		wr.buf = binary.BigEndian.AppendUint32(wr.buf, uint32(size))
+	}
+	wr.buf = append(wr.buf, b...)
+}
+
+func (wr *Writer) appendArrayHead(size int) {
+	switch {
+	case size < 16:
+		wr.buf = append(wr.buf, fixArray|byte(size))
+	case size <= math.MaxUint16:
+		wr.buf = append(wr.buf, mpArray16)
+		wr.buf = binary.BigEndian.AppendUint16(wr.buf, uint16(size))
+	default:
+		wr.buf = append(wr.buf, mpArray32)
+		wr.buf = binary.BigEndian.AppendUint32(wr.buf, uint32(size))
+	}
+}
+
+func (wr *Writer) appendMapHead(size int) {
+	switch {
+	case size < 16:
+		wr.buf = append(wr.buf, fixMap|byte(size))
+	case size <= math.MaxUint16:
+		wr.buf = append(wr.buf, mpMap16)
+		wr.buf = binary.BigEndian.AppendUint16(wr.buf, uint16(size))
+	default:
+		wr.buf = append(wr.buf, mpMap32)
+		wr.buf = binary.BigEndian.AppendUint32(wr.buf, uint32(size))
+	}
+}
+
+// appendTime encodes a time with the timestamp extension if the TimeFormat
+// is "time". Otherwise the time is encoded the same as it would be for JSON
+// including the TimeMap and TimeWrap options.
+func (wr *Writer) appendTime(t time.Time) {
+	if wr.TimeFormat != "time" {
+		wr.appendValue(wr.DecomposeTime(t))
+		return
+	}
+	secs := t.Unix()
+	nsecs := uint64(t.Nanosecond())
+	switch {
+	case secs>>34 != 0:
+		wr.buf = append(wr.buf, mpExt8, 12, byte(extTime&0xff))
+		wr.buf = binary.BigEndian.AppendUint32(wr.buf, uint32(nsecs))
+		wr.buf = binary.BigEndian.AppendUint64(wr.buf, uint64(secs))
+	case nsecs == 0 && secs <= math.MaxUint32:
+		wr.buf = append(wr.buf, mpFixExt4, byte(extTime&0xff))
+		wr.buf = binary.BigEndian.AppendUint32(wr.buf, uint32(secs))
+	default:
+		wr.buf = append(wr.buf, mpFixExt8, byte(extTime&0xff))
+		wr.buf = binary.BigEndian.AppendUint64(wr.buf, nsecs<<34|uint64(secs))
+	}
+}
+
+// appendNumber encodes a number string as an integer if it fits in 64 bits
+// and as a float otherwise since MessagePack does not have big numbers.
+func (wr *Writer) appendNumber(s string) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		wr.appendInt(i)
+		return
+	}
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+		wr.appendUint(u)
+		return
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		panic(fmt.Errorf("%q is not a valid number", s))
+	}
+	wr.appendFloat(f)
+}
+
+func (wr *Writer) appendObject(obj map[string]any) {
+	keys := make([]string, 0, len(obj))
+	for k, v := range obj {
+		if v == nil && wr.OmitNil {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	if wr.Sort {
+		sort.Strings(keys)
+	}
+	wr.appendMapHead(len(keys))
+	for _, k := range keys {
+		wr.appendString(k)
+		wr.appendValue(obj[k])
+	}
+}
+
+func (wr *Writer) appendGenObject(obj gen.Object) {
+	keys := make([]string, 0, len(obj))
+	for k, v := range obj {
+		if v == nil && wr.OmitNil {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	if wr.Sort {
+		sort.Strings(keys)
+	}
+	wr.appendMapHead(len(keys))
+	for _, k := range keys {
+		wr.appendString(k)
+		wr.appendValue(obj[k])
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package msgpack_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/gen"
+	"github.com/khaf/ojg/msgpack"
+	"github.com/khaf/ojg/tt"
+)
+
+type sample struct {
+	Name  string
+	Count int
+	When  time.Time
+	Data  []byte
+	Ptr   *sample
+	Skip  int `json:"-"`
+	Other any
+}
+
+type failWriter struct{}
+
+func (w failWriter) Write([]byte) (int, error) {
+	return 0, errors.New("failed")
+}
+
+func TestMarshalFormats(t *testing.T) {
+	opt := ojg.Options{Sort: true, TimeFormat: "time"}
+	for _, d := range []struct {
+		value  any
+		expect string
+	}{
+		{value: nil, expect: "c0"},
+		{value: false, expect: "c2"},
+		{value: true, expect: "c3"},
+		{value: 0, expect: "00"},
+		{value: int8(127), expect: "7f"},
+		{value: int16(128), expect: "cc80"},
+		{value: int32(256), expect: "cd0100"},
+		{value: uint(65536), expect: "ce00010000"},
+		{value: uint8(255), expect: "ccff"},
+		{value: uint16(256), expect: "cd0100"},
+		{value: uint32(1), expect: "01"},
+		{value: int64(1) << 32, expect: "cf0000000100000000"},
+		{value: uint64(18446744073709551615), expect: "cfffffffffffffffff"},
+		{value: -1, expect: "ff"},
+		{value: -32, expect: "e0"},
+		{value: -33, expect: "d0df"},
+		{value: -129, expect: "d1ff7f"},
+		{value: -32769, expect: "d2ffff7fff"},
+		{value: -2147483649, expect: "d3ffffffff7fffffff"},
+		{value: 1.5, expect: "cb3ff8000000000000"},
+		{value: float32(1.5), expect: "ca3fc00000"},
+		{value: json.Number("-7"), expect: "f9"},
+		{value: json.Number("18446744073709551615"), expect: "cfffffffffffffffff"},
+		{value: json.Number("18446744073709551616"), expect: "cb43f0000000000000"},
+		{value: "", expect: "a0"},
+		{value: "a", expect: "a161"},
+		{value: strings.Repeat("x", 32), expect: "d920" + strings.Repeat("78", 32)},
+		{value: strings.Repeat("x", 256), expect: "da0100" + strings.Repeat("78", 256)},
+		{value: strings.Repeat("x", 65536), expect: "db00010000" + strings.Repeat("78", 65536)},
+		{value: []byte{1}, expect: "c40101"},
+		{value: make([]byte, 256), expect: "c50100" + strings.Repeat("00", 256)},
+		{value: make([]byte, 65536), expect: "c600010000" + strings.Repeat("00", 65536)},
+		{value: []any{}, expect: "90"},
+		{value: []any{1, []int{2}}, expect: "92019102"},
+		{value: make([]any, 16), expect: "dc0010" + strings.Repeat("c0", 16)},
+		{value: make([]any, 65536), expect: "dd00010000" + strings.Repeat("c0", 65536)},
+		{value: map[string]any{}, expect: "80"},
+		{value: map[string]any{"a": 1, "b": "c"}, expect: "82a16101a162a163"},
+		{value: time.Unix(1, 0), expect: "d6ff00000001"},
+		{value: time.Unix(1, 1), expect: "d7ff0000000400000001"},
+		{value: time.Unix(1<<34, 0), expect: "c70cff000000000000000400000000"},
+		{value: time.Unix(-1, 5), expect: "c70cff00000005ffffffffffffffff"},
+	} {
+		out, err := msgpack.Marshal(d.value, &opt)
+		tt.Nil(t, err, d.expect)
+		tt.Equal(t, d.expect, hex.EncodeToString(out))
+	}
+	big := map[string]any{}
+	for i := 0; i < 65536; i++ {
+		big[strings.Repeat("k", i%7)+string(rune('A'+i%26))+strings.Repeat("z", i/26)] = i
+	}
+	out, err := msgpack.Marshal(big)
+	tt.Nil(t, err)
+	tt.Equal(t, "df00010000", hex.EncodeToString(out[:5]))
+	m := map[string]any{}
+	for i := 0; i < 16; i++ {
+		m[string(rune('a'+i))] = i
+	}
+	out, err = msgpack.Marshal(m)
+	tt.Nil(t, err)
+	tt.Equal(t, "de0010", hex.EncodeToString(out[:3]))
+}
+
+func TestMarshalNode(t *testing.T) {
+	opt := ojg.Options{Sort: true, OmitNil: true}
+	node := gen.Object{
+		"a": gen.Array{gen.Int(-1), gen.Uint(1 << 63), gen.Float(1.5), gen.Bool(true), gen.Null{}},
+		"b": gen.String("x"),
+		"c": gen.Bytes{1},
+		"d": gen.Big("18446744073709551616"),
+		"e": gen.Decimal("0.5"),
+		"f": nil,
+		"g": gen.Time(time.Unix(0, 5).UTC()),
+	}
+	out, err := msgpack.Marshal(node, &opt)
+	tt.Nil(t, err)
+	tt.Equal(t,
+		"86a16195ffcf8000000000000000cb3ff8000000000000c3c0a162a178a163c40101a164cb43f0000000000000"+
+			"a165cb3fe0000000000000a16705",
+		hex.EncodeToString(out))
+
+	pnode := gen.Persist(gen.Object{"a": gen.Array{gen.Int(1)}})
+	out, err = msgpack.Marshal(pnode)
+	tt.Nil(t, err)
+	tt.Equal(t, "81a1619101", hex.EncodeToString(out))
+
+	_, err = msgpack.Marshal(gen.Big("x"))
+	tt.NotNil(t, err)
+}
+
+func TestMarshalStruct(t *testing.T) {
+	when := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	s := &sample{Name: "x", Count: 2, When: when, Data: []byte("hi"), Ptr: &sample{Name: "y", When: when}, Skip: 3}
+
+	out, err := msgpack.Marshal(s, &ojg.Options{OmitNil: true, CreateKey: "^", TimeFormat: "time"})
+	tt.Nil(t, err)
+	v, err := msgpack.Parse(out)
+	tt.Nil(t, err)
+	// Without UseTags the json tags are ignored and without KeyExact the
+	// keys start with a lowercase letter.
+	tt.Equal(t, map[string]any{
+		"^":     "sample",
+		"name":  "x",
+		"count": 2,
+		"when":  when,
+		"data":  []byte("hi"),
+		"skip":  3,
+		"ptr":   map[string]any{"^": "sample", "name": "y", "count": 0, "when": when, "data": []byte{}, "skip": 0},
+	}, v)
+
+	// Times are encoded as for JSON unless the TimeFormat is "time".
+	out, err = msgpack.Marshal(map[string]any{"t": when}, &ojg.Options{TimeFormat: time.RFC3339})
+	tt.Nil(t, err)
+	tt.Equal(t, map[string]any{"t": "2023-01-02T03:04:05Z"}, msgpack.MustParse(out))
+	out, err = msgpack.Marshal(when, &ojg.Options{TimeFormat: "nano", TimeWrap: "@"})
+	tt.Nil(t, err)
+	tt.Equal(t, map[string]any{"@": when.UnixNano()}, msgpack.MustParse(out))
+
+	// The package defaults use the timestamp extension.
+	tt.Equal(t, "d6ff63b249a5", hex.EncodeToString(msgpack.MustMarshal(when)))
+
+	_, err = msgpack.Marshal(func() {})
+	tt.NotNil(t, err)
+	_, err = msgpack.Marshal([]any{make(chan int)})
+	tt.NotNil(t, err)
+	tt.Panic(t, func() { msgpack.MustMarshal(func() {}) })
+}
+
+func TestWrite(t *testing.T) {
+	var b strings.Builder
+	data := []any{strings.Repeat("x", 20), strings.Repeat("y", 20), map[string]any{"z": nil}}
+	wr := msgpack.Writer{Options: ojg.Options{WriteLimit: 8}}
+	err := wr.Write(&b, data)
+	tt.Nil(t, err)
+	v, err := msgpack.Parse([]byte(b.String()))
+	tt.Nil(t, err)
+	tt.Equal(t, data, v)
+
+	b.Reset()
+	err = msgpack.Write(&b, data, &wr)
+	tt.Nil(t, err)
+	tt.Equal(t, data, msgpack.MustParse([]byte(b.String())))
+
+	b.Reset()
+	err = msgpack.Write(&b, 1)
+	tt.Nil(t, err)
+	tt.Equal(t, "\x01", b.String())
+
+	err = msgpack.Write(failWriter{}, data, &ojg.Options{WriteLimit: 8})
+	tt.NotNil(t, err)
+	err = msgpack.Write(failWriter{}, 1, &ojg.Options{})
+	tt.NotNil(t, err)
+
+	out := wr.MustMarshal(true)
+	tt.Equal(t, "c3", hex.EncodeToString(out))
+}
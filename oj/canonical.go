@@ -0,0 +1,289 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package oj
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"math"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/alt"
+	"github.com/khaf/ojg/gen"
+)
+
+const hexDigits = "0123456789abcdef"
+
+// Canonical returns the RFC 8785 JSON Canonicalization Scheme (JCS) encoding
+// of the data. Object members are sorted by the UTF-16 code units of the
+// keys, numbers are written as ECMAScript would write the nearest float64,
+// and only the characters that must be escaped are escaped. The data can be
+// any value that can be written by the Writer including structs. The args,
+// if supplied, can be an *ojg.Options or a *Writer. Options that control
+// formatting are ignored but options such as UseTags and TimeFormat are
+// honored.
+func Canonical(data any, args ...any) (out []byte, err error) {
+	wr := &Writer{Options: ojg.DefaultOptions}
+	if 0 < len(args) {
+		if w := pickWriter(args[0], true); w != nil {
+			wr = &Writer{Options: w.Options}
+		}
+	}
+	wr.Canonical = true
+	wr.strict = true
+	defer func() {
+		if r := recover(); r != nil {
+			err = ojg.NewError(r)
+		}
+	}()
+	out = wr.MustJSON(data)
+
+	return
+}
+
+// CanonicalHash returns the hash of the canonical encoding of the data as
+// described for Canonical. If the hash argument is nil then SHA-256 is used.
+// Values that are equal ignoring member order and formatting, such as a
+// struct and the map[string]any or gen.Node it decomposes to, have the same
+// hash.
+func CanonicalHash(data any, h hash.Hash, args ...any) ([]byte, error) {
+	out, err := Canonical(data, args...)
+	if err != nil {
+		return nil, err
+	}
+	if h == nil {
+		h = sha256.New()
+	} else {
+		h.Reset()
+	}
+	_, _ = h.Write(out)
+
+	return h.Sum(nil), nil
+}
+
+func (wr *Writer) appendCanonical(data any) {
+	if wr.Converter != nil {
+		var converted bool
+		if data, converted = alt.ConvertValue(data, &wr.Options); converted {
+			wr.noConvert(func() { wr.appendCanonical(data) })
+			return
+		}
+	}
+	if enc := ojg.FindEncoder(data); enc != nil {
+		data = enc(data)
+	}
+	switch td := data.(type) {
+	case nil:
+		wr.buf = append(wr.buf, "null"...)
+	case bool:
+		if td {
+			wr.buf = append(wr.buf, "true"...)
+		} else {
+			wr.buf = append(wr.buf, "false"...)
+		}
+	case int:
+		wr.buf = appendCanonicalFloat(wr.buf, float64(td))
+	case int8:
+		wr.buf = appendCanonicalFloat(wr.buf, float64(td))
+	case int16:
+		wr.buf = appendCanonicalFloat(wr.buf, float64(td))
+	case int32:
+		wr.buf = appendCanonicalFloat(wr.buf, float64(td))
+	case int64:
+		wr.buf = appendCanonicalFloat(wr.buf, float64(td))
+	case uint:
+		wr.buf = appendCanonicalFloat(wr.buf, float64(td))
+	case uint8:
+		wr.buf = appendCanonicalFloat(wr.buf, float64(td))
+	case uint16:
+		wr.buf = appendCanonicalFloat(wr.buf, float64(td))
+	case uint32:
+		wr.buf = appendCanonicalFloat(wr.buf, float64(td))
+	case uint64:
+		wr.buf = appendCanonicalFloat(wr.buf, float64(td))
+	case float32:
+		// Use the float64 closest to the shortest float32 representation
+		// which is what a reader of the regular JSON output would see.
+		f, _ := strconv.ParseFloat(strconv.FormatFloat(float64(td), 'g', -1, 32), 64)
+		wr.buf = appendCanonicalFloat(wr.buf, f)
+	case float64:
+		wr.buf = appendCanonicalFloat(wr.buf, td)
+	case json.Number:
+		f, err := strconv.ParseFloat(string(td), 64)
+		if err != nil {
+			panic(fmt.Errorf("%s can not be represented in canonical JSON", td))
+		}
+		wr.buf = appendCanonicalFloat(wr.buf, f)
+	case gen.Big:
+		// A gen.Big is a number even though it simplifies to a string so
+		// it is canonicalized as a json.Number.
+		wr.appendCanonical(json.Number(td))
+	case gen.Array:
+		// Arrays and objects are not simplified so that any gen.Big
+		// elements remain numbers.
+		a := make([]any, len(td))
+		for i, v := range td {
+			a[i] = v
+		}
+		wr.appendCanonical(a)
+	case gen.Object:
+		m := make(map[string]any, len(td))
+		for k, v := range td {
+			m[k] = v
+		}
+		wr.appendCanonical(m)
+	case gen.PArray:
+		wr.appendCanonical(td.Array())
+	case gen.PObject:
+		wr.appendCanonical(td.Object())
+	case string:
+		wr.buf = appendCanonicalString(wr.buf, td)
+	case []any:
+		wr.buf = append(wr.buf, '[')
+		for i, v := range td {
+			if 0 < i {
+				wr.buf = append(wr.buf, ',')
+			}
+			wr.appendCanonical(v)
+		}
+		wr.buf = append(wr.buf, ']')
+	case map[string]any:
+		keys := make([]string, 0, len(td))
+		for k := range td {
+			keys = append(keys, k)
+		}
+		sortUTF16(keys)
+		wr.buf = append(wr.buf, '{')
+		for i, k := range keys {
+			if 0 < i {
+				wr.buf = append(wr.buf, ',')
+			}
+			wr.buf = appendCanonicalString(wr.buf, k)
+			wr.buf = append(wr.buf, ':')
+			wr.appendCanonical(td[k])
+		}
+		wr.buf = append(wr.buf, '}')
+	case alt.Simplifier:
+		wr.appendCanonical(td.Simplify())
+	case alt.Genericer:
+		wr.appendCanonical(td.Generic().Simplify())
+	default:
+		// Other types such as structs are written with the regular writer
+		// so that tags, codecs, and the options are honored. The result is
+		// then parsed and written in canonical form.
+		w := Writer{Options: wr.Options, strict: true}
+		w.Canonical = false
+		w.Color = false
+		w.Indent = 0
+		w.Tab = false
+		w.Converter = nil
+		var p Parser
+		v, err := p.Parse(w.MustJSON(td))
+		if err != nil {
+			panic(err)
+		}
+		wr.appendCanonical(v)
+	}
+	if wr.w != nil && wr.WriteLimit < len(wr.buf) {
+		if _, err := wr.w.Write(wr.buf); err != nil {
+			panic(err)
+		}
+		wr.buf = wr.buf[:0]
+	}
+}
+
+// appendCanonicalFloat appends the number as the ECMAScript
+// Number.prototype.toString() function would.
+func appendCanonicalFloat(buf []byte, f float64) []byte {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		panic(fmt.Errorf("%v can not be represented in canonical JSON", f))
+	}
+	if f == 0 { // also -0
+		return append(buf, '0')
+	}
+	format := byte('f')
+	if abs := math.Abs(f); abs < 1e-6 || 1e21 <= abs {
+		format = 'e'
+	}
+	buf = strconv.AppendFloat(buf, f, format, -1, 64)
+	if format == 'e' {
+		// The exponent is always at least two digits when formatted by
+		// strconv so change e-07 to e-7.
+		n := len(buf)
+		if 4 <= n && buf[n-4] == 'e' && buf[n-3] == '-' && buf[n-2] == '0' {
+			buf[n-2] = buf[n-1]
+			buf = buf[:n-1]
+		}
+	}
+	return buf
+}
+
+// appendCanonicalString appends a JSON string with only the characters that
+// must be escaped escaped. Invalid UTF-8 is not allowed.
+func appendCanonicalString(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		b := s[i]
+		if utf8.RuneSelf <= b {
+			if r, size := utf8.DecodeRuneInString(s[i:]); r == utf8.RuneError && size == 1 {
+				panic(fmt.Errorf("invalid UTF-8 in %q", s))
+			} else {
+				i += size
+			}
+			continue
+		}
+		if 0x20 <= b && b != '"' && b != '\\' {
+			i++
+			continue
+		}
+		buf = append(buf, s[start:i]...)
+		switch b {
+		case '"', '\\':
+			buf = append(buf, '\\', b)
+		case '\b':
+			buf = append(buf, '\\', 'b')
+		case '\t':
+			buf = append(buf, '\\', 't')
+		case '\n':
+			buf = append(buf, '\\', 'n')
+		case '\f':
+			buf = append(buf, '\\', 'f')
+		case '\r':
+			buf = append(buf, '\\', 'r')
+		default:
+			buf = append(buf, '\\', 'u', '0', '0', hexDigits[b>>4], hexDigits[b&0x0f])
+		}
+		i++
+		start = i
+	}
+	buf = append(buf, s[start:]...)
+
+	return append(buf, '"')
+}
+
+// sortUTF16 sorts the keys by their UTF-16 code units as required by RFC
+// 8785. This differs from a byte sort of UTF-8 only for characters outside
+// the basic multilingual plane.
+func sortUTF16(keys []string) {
+	units := make(map[string][]uint16, len(keys))
+	for _, k := range keys {
+		units[k] = utf16.Encode([]rune(k))
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ui := units[keys[i]]
+		uj := units[keys[j]]
+		for n := 0; n < len(ui) && n < len(uj); n++ {
+			if ui[n] != uj[n] {
+				return ui[n] < uj[n]
+			}
+		}
+		return len(ui) < len(uj)
+	})
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package oj_test
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/gen"
+	"github.com/khaf/ojg/oj"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestCanonicalNumbers(t *testing.T) {
+	// Test values from RFC 8785 Appendix B.
+	for _, d := range []struct {
+		bits   uint64
+		expect string
+	}{
+		{bits: 0x0000000000000000, expect: "0"},
+		{bits: 0x8000000000000000, expect: "0"},
+		{bits: 0x0000000000000001, expect: "5e-324"},
+		{bits: 0x8000000000000001, expect: "-5e-324"},
+		{bits: 0x7fefffffffffffff, expect: "1.7976931348623157e+308"},
+		{bits: 0xffefffffffffffff, expect: "-1.7976931348623157e+308"},
+		{bits: 0x4340000000000000, expect: "9007199254740992"},
+		{bits: 0xc340000000000000, expect: "-9007199254740992"},
+		{bits: 0x4430000000000000, expect: "295147905179352830000"},
+		{bits: 0x44b52d02c7e14af5, expect: "9.999999999999997e+22"},
+		{bits: 0x44b52d02c7e14af6, expect: "1e+23"},
+		{bits: 0x44b52d02c7e14af7, expect: "1.0000000000000001e+23"},
+		{bits: 0x444b1ae4d6e2ef4e, expect: "999999999999999700000"},
+		{bits: 0x444b1ae4d6e2ef4f, expect: "999999999999999900000"},
+		{bits: 0x444b1ae4d6e2ef50, expect: "1e+21"},
+		{bits: 0x3eb0c6f7a0b5ed8c, expect: "9.999999999999997e-7"},
+		{bits: 0x3eb0c6f7a0b5ed8d, expect: "0.000001"},
+		{bits: 0x41b3de4355555553, expect: "333333333.3333332"},
+		{bits: 0x41b3de4355555554, expect: "333333333.33333325"},
+		{bits: 0x41b3de4355555555, expect: "333333333.3333333"},
+		{bits: 0x41b3de4355555556, expect: "333333333.3333334"},
+		{bits: 0x41b3de4355555557, expect: "333333333.33333343"},
+		{bits: 0xbecbf647612f3696, expect: "-0.0000033333333333333333"},
+		{bits: 0x43143ff3c1cb0959, expect: "1424953923781206.2"},
+	} {
+		out, err := oj.Canonical(math.Float64frombits(d.bits))
+		tt.Nil(t, err, d.expect)
+		tt.Equal(t, d.expect, string(out))
+	}
+	for _, v := range []any{math.NaN(), math.Inf(1), json.Number("1e999")} {
+		_, err := oj.Canonical(v)
+		tt.NotNil(t, err, v)
+	}
+}
+
+func TestCanonicalRFC(t *testing.T) {
+	// The example from section 3.2.2 of RFC 8785.
+	src := `{
+  "numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+  "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+  "literals": [null, true, false]
+}`
+	out, err := oj.Canonical(oj.MustParseString(src))
+	tt.Nil(t, err)
+	tt.Equal(t,
+		`{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`,
+		string(out))
+
+	// The sorting example from section 3.2.3.
+	src = `{
+  "€": "Euro Sign",
+  "\r": "Carriage Return",
+  "דּ": "Hebrew Letter Dalet With Dagesh",
+  "1": "One",
+  "😀": "Emoji: Grinning Face",
+  "\u0080": "Control",
+  "ö": "Latin Small Letter O With Diaeresis"
+}`
+	out, err = oj.Canonical(oj.MustParseString(src))
+	tt.Nil(t, err)
+	var keys []string
+	for _, line := range strings.Split(string(out), `","`) {
+		if i := strings.Index(line, `":"`); 0 < i {
+			keys = append(keys, strings.TrimPrefix(line[:i], `{"`))
+		}
+	}
+	tt.Equal(t, []string{"\\r", "1", "\u0080", "ö", "€", "\U0001f600", "דּ"}, keys)
+}
+
+func TestCanonicalParsers(t *testing.T) {
+	src := `{"big": 123456789012345678901234567890, "dec": 0.12345678901234567890123, "u": 18446744073709551615, "f": 0.5}`
+	var gp gen.Parser
+	node, err := gp.Parse([]byte(src))
+	tt.Nil(t, err)
+	simple := oj.MustParseString(src)
+
+	out, err := oj.Canonical(node)
+	tt.Nil(t, err)
+	tt.Equal(t, `{"big":1.2345678901234568e+29,"dec":0.12345678901234568,"f":0.5,"u":18446744073709552000}`, string(out))
+
+	h0, err := oj.CanonicalHash(node, nil)
+	tt.Nil(t, err)
+	h1, err := oj.CanonicalHash(simple, nil)
+	tt.Nil(t, err)
+	tt.Equal(t, hex.EncodeToString(h1), hex.EncodeToString(h0))
+	h2, err := oj.CanonicalHash(gen.Persist(gen.Array{node}), nil)
+	tt.Nil(t, err)
+	h3, err := oj.CanonicalHash([]any{simple}, nil)
+	tt.Nil(t, err)
+	tt.Equal(t, hex.EncodeToString(h3), hex.EncodeToString(h2))
+}
+
+type canonicalSample struct {
+	Name  string  `json:"name"`
+	Count int     `json:"count"`
+	Ratio float32 `json:"ratio"`
+	Tags  []string
+	Skip  string `json:"-"`
+}
+
+func TestCanonicalValues(t *testing.T) {
+	sample := &canonicalSample{Name: "x<y>", Count: 3, Ratio: 0.1, Tags: []string{"a"}, Skip: "skip"}
+	opt := ojg.Options{UseTags: true, KeyExact: true}
+	out, err := oj.Canonical(sample, &opt)
+	tt.Nil(t, err)
+	tt.Equal(t, `{"Tags":["a"],"count":3,"name":"x<y>","ratio":0.1}`, string(out))
+
+	simple := map[string]any{"ratio": 0.1, "name": "x<y>", "count": 3, "Tags": []any{"a"}}
+	node := gen.Object{"ratio": gen.Float(0.1), "name": gen.String("x<y>"), "count": gen.Int(3), "Tags": gen.Array{gen.String("a")}}
+	h0, err := oj.CanonicalHash(sample, nil, &opt)
+	tt.Nil(t, err)
+	tt.Equal(t, 32, len(h0))
+	h1, _ := oj.CanonicalHash(simple, nil)
+	h2, _ := oj.CanonicalHash(node, sha256.New())
+	tt.Equal(t, hex.EncodeToString(h0), hex.EncodeToString(h1))
+	tt.Equal(t, hex.EncodeToString(h0), hex.EncodeToString(h2))
+	sum := sha256.Sum256(out)
+	tt.Equal(t, hex.EncodeToString(sum[:]), hex.EncodeToString(h0))
+
+	h3, err := oj.CanonicalHash(simple, sha512.New())
+	tt.Nil(t, err)
+	tt.Equal(t, 64, len(h3))
+	_, err = oj.CanonicalHash(math.NaN(), nil)
+	tt.NotNil(t, err)
+
+	out, err = oj.Canonical([]any{int8(1), int16(-2), int32(3), uint(4), uint8(5), uint16(6), uint32(7), uint64(1 << 60),
+		float32(1.5), json.Number("100"), gen.Decimal("0.5"), gen.Uint(2), "\t\b\f\x01\x7f"})
+	tt.Nil(t, err)
+	tt.Equal(t, `[1,-2,3,4,5,6,7,1152921504606847000,1.5,100,0.5,2,"\t\b\f\u0001`+"\x7f"+`"]`, string(out))
+
+	_, err = oj.Canonical("bad \xff utf-8")
+	tt.NotNil(t, err)
+	_, err = oj.Canonical(map[string]any{"\xff": 1})
+	tt.NotNil(t, err)
+	_, err = oj.Canonical(func() {})
+	tt.NotNil(t, err)
+}
+
+func TestCanonicalWriter(t *testing.T) {
+	wr := oj.Writer{Options: ojg.Options{Canonical: true, Indent: 2, Sort: false, Color: true, WriteLimit: 4}}
+	var b strings.Builder
+	err := wr.Write(&b, map[string]any{"b": []any{1.0, 2.5e-8}, "ab": 1, "a": nil})
+	tt.Nil(t, err)
+	tt.Equal(t, `{"a":null,"ab":1,"b":[1,2.5e-8]}`, b.String())
+	tt.Equal(t, `{"a":true}`, wr.JSON(map[string]any{"a": true}))
+
+	out, err := oj.Marshal(map[string]any{"x": false}, &ojg.Options{Canonical: true})
+	tt.Nil(t, err)
+	tt.Equal(t, `{"x":false}`, string(out))
+}
@@ -3,6 +3,7 @@
 package oj
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strconv"
@@ -13,6 +14,16 @@ import (
 )
 
 func (wr *Writer) colorJSON(data any, depth int) {
+	if wr.Converter != nil {
+		var converted bool
+		if data, converted = alt.ConvertValue(data, &wr.Options); converted {
+			wr.noConvert(func() { wr.colorJSON(data, depth) })
+			return
+		}
+	}
+	if enc := ojg.FindEncoder(data); enc != nil {
+		data = enc(data)
+	}
 	switch td := data.(type) {
 	case nil:
 		wr.buf = append(wr.buf, wr.NullColor...)
@@ -43,19 +54,22 @@ func (wr *Writer) colorJSON(data any, depth int) {
 		wr.buf = append(wr.buf, []byte(strconv.FormatInt(td, 10))...)
 	case uint:
 		wr.buf = append(wr.buf, wr.NumberColor...)
-		wr.buf = append(wr.buf, []byte(strconv.FormatInt(int64(td), 10))...)
+		wr.buf = strconv.AppendUint(wr.buf, uint64(td), 10)
 	case uint8:
 		wr.buf = append(wr.buf, wr.NumberColor...)
-		wr.buf = append(wr.buf, []byte(strconv.FormatInt(int64(td), 10))...)
+		wr.buf = strconv.AppendUint(wr.buf, uint64(td), 10)
 	case uint16:
 		wr.buf = append(wr.buf, wr.NumberColor...)
-		wr.buf = append(wr.buf, []byte(strconv.FormatInt(int64(td), 10))...)
+		wr.buf = strconv.AppendUint(wr.buf, uint64(td), 10)
 	case uint32:
 		wr.buf = append(wr.buf, wr.NumberColor...)
-		wr.buf = append(wr.buf, []byte(strconv.FormatInt(int64(td), 10))...)
+		wr.buf = strconv.AppendUint(wr.buf, uint64(td), 10)
 	case uint64:
 		wr.buf = append(wr.buf, wr.NumberColor...)
-		wr.buf = append(wr.buf, []byte(strconv.FormatInt(int64(td), 10))...)
+		wr.buf = strconv.AppendUint(wr.buf, td, 10)
+	case json.Number:
+		wr.buf = append(wr.buf, wr.NumberColor...)
+		wr.buf = append(wr.buf, td...)
 
 	case float32:
 		wr.buf = append(wr.buf, wr.NumberColor...)
@@ -3,7 +3,9 @@
 package oj_test
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
 	"strings"
 	"testing"
 	"time"
@@ -34,6 +36,9 @@ func TestColor(t *testing.T) {
 		{value: false, expect: "bfalsex"},
 		{value: "string", expect: `q"string"x`},
 		{value: gen.String("string"), expect: `q"string"x`},
+		{value: uint64(math.MaxUint64), expect: "018446744073709551615x"},
+		{value: json.Number("1.50"), expect: "01.50x"},
+		{value: gen.Decimal("1.50"), expect: "01.50x"},
 		{value: []any{true, false}, expect: "s[xbtruexs,xbfalsexs]x"},
 		{value: gen.Array{gen.Bool(true), gen.Bool(false)}, expect: "s[xbtruexs,xbfalsexs]x"},
 		{value: gen.Object{"f": gen.False}, expect: `s{xk"f"xs:xbfalsexs}x`},
@@ -0,0 +1,236 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package oj_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/alt"
+	"github.com/khaf/ojg/oj"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+type confInner struct {
+	A int
+	B string `json:"b"`
+}
+
+type confHidden struct {
+	Hidden int
+	X      int `json:"x"`
+}
+
+type confOther struct {
+	A int
+	Q int
+}
+
+type confAsString struct {
+	I    int64   `json:"i,string"`
+	U    uint    `json:"u,string"`
+	F    float64 `json:"f,string"`
+	B    bool    `json:"b,string"`
+	S    string  `json:"s,string"`
+	P    *int    `json:"p,string"`
+	Dash int     `json:"-,"`
+	Skip int     `json:"-"`
+}
+
+type confNumber struct {
+	N json.Number  `json:"n"`
+	S json.Number  `json:"s,string"`
+	P *json.Number `json:"p,string"`
+	O json.Number  `json:"o,omitempty"`
+	E json.Number  `json:"e"`
+}
+
+type confOmit struct {
+	B  bool           `json:"b,omitempty"`
+	I  int            `json:"i,omitempty"`
+	F  float64        `json:"f,omitempty"`
+	S  string         `json:"s,omitempty"`
+	P  *int           `json:"p,omitempty"`
+	A  any            `json:"a,omitempty"`
+	L  []int          `json:"l,omitempty"`
+	M  map[string]int `json:"m,omitempty"`
+	St confInner      `json:"st,omitempty"`
+	T  time.Time      `json:"t,omitempty"`
+	Z  confInner      `json:"z,omitzero"`
+	ZT time.Time      `json:"zt,omitzero"`
+	ZI int            `json:"zi,omitzero"`
+	ZP *confInner     `json:"zp,omitzero"`
+	ZL []int          `json:"zl,omitzero"`
+}
+
+type confEmbed struct {
+	confInner
+	confHidden
+	C int
+}
+
+type confEmbedPtr struct {
+	*confInner
+	C int
+}
+
+// ConfPoint is exported since the json package includes tagged embedded
+// structs of unexported types but they can not be accessed with reflection.
+type ConfPoint struct {
+	X int
+	Y int `json:"y"`
+}
+
+type confEmbedTagged struct {
+	ConfPoint `json:"pt"`
+	C         int
+}
+
+type confShadow struct {
+	confInner
+	A int
+}
+
+type confConflict struct {
+	confInner
+	confOther
+}
+
+type confDeep struct {
+	confEmbed
+	D int `json:"d"`
+}
+
+type confValues struct {
+	Bytes   []byte
+	Times   []time.Time
+	TimeMap map[string]time.Time
+	TimePtr *time.Time
+	Raw     []byte `json:"raw,omitempty"`
+}
+
+func TestEncodingJSONConformance(t *testing.T) {
+	n := 7
+	num := json.Number("14")
+	tm := time.Date(2023, 1, 2, 3, 4, 5, 6, time.UTC)
+	for _, sample := range []any{
+		&confAsString{I: 12345678901234, U: 18446744073709551615, F: 1.5, B: true, S: `a"b`, P: &n, Dash: 1, Skip: 2},
+		&confAsString{},
+		&confNumber{N: "1.5", S: "13", P: &num, O: "-2"},
+		&confNumber{},
+		&confOmit{},
+		&confOmit{B: true, I: 1, St: confInner{A: 1}, T: tm, Z: confInner{A: 2}, ZT: tm, ZI: 3, ZL: []int{}},
+		&confEmbed{confInner: confInner{A: 1, B: "b"}, confHidden: confHidden{Hidden: 2, X: 3}, C: 4},
+		&confEmbedPtr{C: 1},
+		&confEmbedPtr{confInner: &confInner{A: 1, B: "b"}, C: 2},
+		&confEmbedTagged{ConfPoint: ConfPoint{X: 1, Y: 2}, C: 3},
+		&confShadow{confInner: confInner{A: 1}, A: 2},
+		&confConflict{confInner: confInner{A: 1, B: "b"}, confOther: confOther{A: 2, Q: 3}},
+		&confDeep{confEmbed: confEmbed{confInner: confInner{A: 1}, C: 3}, D: 4},
+		&confValues{
+			Bytes:   []byte("hi"),
+			Times:   []time.Time{tm},
+			TimeMap: map[string]time.Time{"t": tm},
+			TimePtr: &tm,
+		},
+		&confValues{Bytes: []byte{}, Times: []time.Time{}, TimeMap: map[string]time.Time{}, Raw: []byte{0xff, 0}},
+	} {
+		name := fmt.Sprintf("%T", sample)
+		js, err := json.Marshal(sample)
+		tt.Nil(t, err, name)
+		var expect any
+		tt.Nil(t, json.Unmarshal(js, &expect), name)
+
+		out, err := oj.Marshal(sample, &ojg.GoOptions)
+		tt.Nil(t, err, name)
+		tt.Equal(t, sortedJSON(expect), sortedJSON(oj.MustParse(out)), name+" oj.Marshal")
+
+		s := sen.String(sample, &ojg.GoOptions)
+		tt.Equal(t, sortedJSON(expect), sortedJSON(sen.MustParse([]byte(s))), name+" sen.String")
+
+		dec := alt.Decompose(sample, &ojg.GoOptions)
+		tt.Equal(t, sortedJSON(expect), sortedJSON(dec), name+" alt.Decompose")
+
+		g := alt.Generify(sample, &ojg.GoOptions)
+		tt.Equal(t, sortedJSON(expect), sortedJSON(g), name+" alt.Generify")
+
+		// Recompose the encoding/json output and compare to what
+		// encoding/json unmarshals. Both fail when an unexported embedded
+		// struct pointer must be set.
+		rt := reflect.TypeOf(sample).Elem()
+		jv := reflect.New(rt)
+		jerr := json.Unmarshal(js, jv.Interface())
+		rv := reflect.New(rt)
+		_, err = alt.Recompose(oj.MustParse(js), rv.Interface())
+		if jerr != nil {
+			tt.NotNil(t, err, name+" alt.Recompose")
+			continue
+		}
+		tt.Nil(t, err, name)
+		j1, _ := json.Marshal(jv.Interface())
+		j2, _ := json.Marshal(rv.Interface())
+		tt.Equal(t, string(j1), string(j2), name+" alt.Recompose")
+	}
+}
+
+// ConfExtra is exported so it can be embedded in confEmbedded.
+type ConfExtra struct {
+	E int    `json:"e"`
+	F string `json:"f,omitempty"`
+}
+
+type confInline struct {
+	Point ConfPoint  `json:",inline"`
+	Extra *ConfExtra `json:"extra,inline"`
+	C     int
+}
+
+// confEmbedded is what the json package would write for confInline.
+type confEmbedded struct {
+	ConfPoint
+	*ConfExtra
+	C int
+}
+
+func TestInlineTag(t *testing.T) {
+	for _, pair := range [][2]any{
+		{
+			&confInline{Point: ConfPoint{X: 1, Y: 2}, Extra: &ConfExtra{E: 3, F: "f"}, C: 4},
+			&confEmbedded{ConfPoint: ConfPoint{X: 1, Y: 2}, ConfExtra: &ConfExtra{E: 3, F: "f"}, C: 4},
+		},
+		{
+			&confInline{Point: ConfPoint{X: 1}, C: 4},
+			&confEmbedded{ConfPoint: ConfPoint{X: 1}, C: 4},
+		},
+	} {
+		sample := pair[0]
+		js, err := json.Marshal(pair[1])
+		tt.Nil(t, err)
+		var expect any
+		tt.Nil(t, json.Unmarshal(js, &expect))
+
+		out, err := oj.Marshal(sample, &ojg.GoOptions)
+		tt.Nil(t, err)
+		tt.Equal(t, sortedJSON(expect), sortedJSON(oj.MustParse(out)), "oj.Marshal")
+		opt := ojg.GoOptions
+		opt.Indent = 2
+		tt.Equal(t, sortedJSON(expect), sortedJSON(oj.MustParse([]byte(oj.JSON(sample, &opt)))), "oj.JSON indented")
+		tt.Equal(t, sortedJSON(expect), sortedJSON(sen.MustParse([]byte(sen.String(sample, &ojg.GoOptions)))), "sen.String")
+		tt.Equal(t, sortedJSON(expect), sortedJSON(alt.Decompose(sample, &ojg.GoOptions)), "alt.Decompose")
+		tt.Equal(t, sortedJSON(expect), sortedJSON(alt.Generify(sample, &ojg.GoOptions)), "alt.Generify")
+
+		var ci confInline
+		_, err = alt.Recompose(oj.MustParse(js), &ci)
+		tt.Nil(t, err)
+		tt.Equal(t, oj.JSON(sample, &ojg.GoOptions), oj.JSON(&ci, &ojg.GoOptions), "alt.Recompose")
+	}
+}
+
+func sortedJSON(v any) string {
+	return oj.JSON(v, &ojg.Options{Sort: true, TimeFormat: time.RFC3339Nano, BytesAs: ojg.BytesAsBase64})
+}
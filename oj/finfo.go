@@ -6,6 +6,7 @@ import (
 	"encoding"
 	"encoding/json"
 	"reflect"
+	"strconv"
 	"unsafe"
 
 	"github.com/khaf/ojg"
@@ -38,6 +39,36 @@ type finfo struct {
 	jkey    []byte
 	index   []int
 	offset  uintptr
+	tagged  bool
+	// omit is true if the field can be left out of the output because of
+	// the omitempty or omitzero tag options.
+	omit     bool
+	asString bool
+	// zAppend and ziAppend are the append functions used when omitzero
+	// applies and the field is not zero.
+	zAppend  appendFunc
+	ziAppend appendFunc
+	// eAppend is the append function used once a field reached through an
+	// embedded struct pointer is known to not be behind a nil pointer.
+	eAppend appendFunc
+}
+
+// viaPtr is called when a field is reached through an embedded struct
+// pointer. The address based append function can no longer be used and a
+// nil embedded pointer must result in the field being skipped.
+func (fi *finfo) viaPtr() {
+	if fi.eAppend == nil {
+		fi.eAppend = fi.iAppend
+		fi.iAppend = appendEmbedded
+	}
+	fi.Append = fi.iAppend
+}
+
+func appendEmbedded(fi *finfo, buf []byte, rv reflect.Value, addr uintptr, safe bool) ([]byte, any, appendStatus) {
+	if _, err := rv.FieldByIndexErr(fi.index); err != nil {
+		return buf, nil, aSkip
+	}
+	return fi.eAppend(fi, buf, rv, addr, safe)
 }
 
 func (f *finfo) keyLen() int {
@@ -63,6 +94,145 @@ func appendStringNotEmpty(fi *finfo, buf []byte, rv reflect.Value, addr uintptr,
 	return buf, nil, aWrote
 }
 
+func appendStringAsString(fi *finfo, buf []byte, rv reflect.Value, addr uintptr, safe bool) ([]byte, any, appendStatus) {
+	v := rv.FieldByIndex(fi.index).String()
+	buf = append(buf, fi.jkey...)
+	buf = ojg.AppendJSONString(buf, string(ojg.AppendJSONString(nil, v, safe)), false)
+
+	return buf, nil, aWrote
+}
+
+func appendStringNotEmptyAsString(fi *finfo, buf []byte, rv reflect.Value, addr uintptr, safe bool) ([]byte, any, appendStatus) {
+	s := rv.FieldByIndex(fi.index).String()
+	if len(s) == 0 {
+		return buf, nil, aSkip
+	}
+	buf = append(buf, fi.jkey...)
+	buf = ojg.AppendJSONString(buf, string(ojg.AppendJSONString(nil, s, safe)), false)
+
+	return buf, nil, aWrote
+}
+
+// appendNumber is used for json.Number fields which are written as numbers
+// as the json package does. An empty json.Number is written as 0.
+func appendNumber(fi *finfo, buf []byte, rv reflect.Value, addr uintptr, safe bool) ([]byte, any, appendStatus) {
+	buf = append(buf, fi.jkey...)
+	return appendNumberValue(buf, rv.FieldByIndex(fi.index).String(), false), nil, aWrote
+}
+
+func appendNumberNotEmpty(fi *finfo, buf []byte, rv reflect.Value, addr uintptr, safe bool) ([]byte, any, appendStatus) {
+	s := rv.FieldByIndex(fi.index).String()
+	if len(s) == 0 {
+		return buf, nil, aSkip
+	}
+	buf = append(buf, fi.jkey...)
+	return appendNumberValue(buf, s, false), nil, aWrote
+}
+
+func appendNumberAsString(fi *finfo, buf []byte, rv reflect.Value, addr uintptr, safe bool) ([]byte, any, appendStatus) {
+	buf = append(buf, fi.jkey...)
+	return appendNumberValue(buf, rv.FieldByIndex(fi.index).String(), true), nil, aWrote
+}
+
+func appendNumberNotEmptyAsString(fi *finfo, buf []byte, rv reflect.Value, addr uintptr, safe bool) ([]byte, any, appendStatus) {
+	s := rv.FieldByIndex(fi.index).String()
+	if len(s) == 0 {
+		return buf, nil, aSkip
+	}
+	buf = append(buf, fi.jkey...)
+	return appendNumberValue(buf, s, true), nil, aWrote
+}
+
+func appendNumberValue(buf []byte, s string, quote bool) []byte {
+	if len(s) == 0 {
+		s = "0"
+	}
+	if quote {
+		buf = append(buf, '"')
+		buf = append(buf, s...)
+		return append(buf, '"')
+	}
+	return append(buf, s...)
+}
+
+// appendPtrAsString is used for pointers to scalar types with the json
+// string option.
+func appendPtrAsString(fi *finfo, buf []byte, rv reflect.Value, addr uintptr, safe bool) ([]byte, any, appendStatus) {
+	fv := rv.FieldByIndex(fi.index)
+	buf = append(buf, fi.jkey...)
+	if fv.IsNil() {
+		return append(buf, "null"...), nil, aWrote
+	}
+	return appendScalarAsString(buf, fv.Elem(), safe), nil, aWrote
+}
+
+func appendPtrNotEmptyAsString(fi *finfo, buf []byte, rv reflect.Value, addr uintptr, safe bool) ([]byte, any, appendStatus) {
+	fv := rv.FieldByIndex(fi.index)
+	if fv.IsNil() {
+		return buf, nil, aSkip
+	}
+	buf = append(buf, fi.jkey...)
+	return appendScalarAsString(buf, fv.Elem(), safe), nil, aWrote
+}
+
+func appendScalarAsString(buf []byte, rv reflect.Value, safe bool) []byte {
+	if rv.Type() == jsonNumberType {
+		return appendNumberValue(buf, rv.String(), true)
+	}
+	if rv.Kind() == reflect.String {
+		return ojg.AppendJSONString(buf, string(ojg.AppendJSONString(nil, rv.String(), safe)), false)
+	}
+	buf = append(buf, '"')
+	switch rv.Kind() {
+	case reflect.Bool:
+		buf = strconv.AppendBool(buf, rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		buf = strconv.AppendInt(buf, rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		buf = strconv.AppendUint(buf, rv.Uint(), 10)
+	case reflect.Float32:
+		buf = strconv.AppendFloat(buf, rv.Float(), 'g', -1, 32)
+	case reflect.Float64:
+		buf = strconv.AppendFloat(buf, rv.Float(), 'g', -1, 64)
+	}
+	return append(buf, '"')
+}
+
+func appendOmitZero(fi *finfo, buf []byte, rv reflect.Value, addr uintptr, safe bool) ([]byte, any, appendStatus) {
+	if isZeroField(rv, fi.index) {
+		return buf, nil, aSkip
+	}
+	return fi.zAppend(fi, buf, rv, addr, safe)
+}
+
+func iappendOmitZero(fi *finfo, buf []byte, rv reflect.Value, addr uintptr, safe bool) ([]byte, any, appendStatus) {
+	if isZeroField(rv, fi.index) {
+		return buf, nil, aSkip
+	}
+	return fi.ziAppend(fi, buf, rv, addr, safe)
+}
+
+// isZeroField returns true if the field is the zero value or has an
+// IsZero() method that returns true as with the json omitzero option.
+func isZeroField(rv reflect.Value, index []int) bool {
+	fv, err := rv.FieldByIndexErr(index)
+	if err != nil {
+		return true
+	}
+	if fv.Kind() == reflect.Ptr && fv.IsNil() {
+		return true
+	}
+	if z, ok := fv.Interface().(interface{ IsZero() bool }); ok {
+		return z.IsZero()
+	}
+	if fv.CanAddr() {
+		if z, ok := fv.Addr().Interface().(interface{ IsZero() bool }); ok {
+			return z.IsZero()
+		}
+	}
+	return fv.IsZero()
+}
+
 func appendJustKey(fi *finfo, buf []byte, rv reflect.Value, addr uintptr, safe bool) ([]byte, any, appendStatus) {
 	v := rv.FieldByIndex(fi.index).Interface()
 	buf = append(buf, fi.jkey...)
@@ -88,6 +258,9 @@ func appendSliceNotEmpty(fi *finfo, buf []byte, rv reflect.Value, addr uintptr,
 }
 
 func whichAppend(rt reflect.Type, omitEmpty bool) (f appendFunc, af appendFunc) {
+	if f = codecAppend(rt, omitEmpty); f != nil {
+		return
+	}
 	v := reflect.New(rt).Elem().Interface()
 	switch v.(type) {
 	case json.Marshaler:
@@ -129,13 +302,26 @@ func whichAppend(rt reflect.Type, omitEmpty bool) (f appendFunc, af appendFunc)
 	return
 }
 
+func isScalarKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
 func newFinfo(f *reflect.StructField, key string, omitEmpty, asString, pretty, embedded bool) *finfo {
 	fi := finfo{
-		rt:     f.Type,
-		key:    key,
-		kind:   f.Type.Kind(),
-		index:  f.Index,
-		offset: f.Offset,
+		rt:       f.Type,
+		key:      key,
+		kind:     f.Type.Kind(),
+		index:    f.Index,
+		offset:   f.Offset,
+		omit:     omitEmpty,
+		asString: asString,
 	}
 	var fx byte
 	// Check for interfaces first since almost any type can implement one of
@@ -200,10 +386,34 @@ func newFinfo(f *reflect.StructField, key string, omitEmpty, asString, pretty, e
 		fi.iAppend = float64AppendFuncs[fx|embedMask]
 
 	case reflect.String:
-		if omitEmpty {
+		if fi.rt == jsonNumberType {
+			switch {
+			case omitEmpty && asString:
+				fi.Append = appendNumberNotEmptyAsString
+				fi.iAppend = appendNumberNotEmptyAsString
+			case omitEmpty:
+				fi.Append = appendNumberNotEmpty
+				fi.iAppend = appendNumberNotEmpty
+			case asString:
+				fi.Append = appendNumberAsString
+				fi.iAppend = appendNumberAsString
+			default:
+				fi.Append = appendNumber
+				fi.iAppend = appendNumber
+			}
+			break
+		}
+		switch {
+		case omitEmpty && asString:
+			fi.Append = appendStringNotEmptyAsString
+			fi.iAppend = appendStringNotEmptyAsString
+		case omitEmpty:
 			fi.Append = appendStringNotEmpty
 			fi.iAppend = appendStringNotEmpty
-		} else {
+		case asString:
+			fi.Append = appendStringAsString
+			fi.iAppend = appendStringAsString
+		default:
 			fi.Append = appendString
 			fi.iAppend = appendString
 		}
@@ -219,10 +429,19 @@ func newFinfo(f *reflect.StructField, key string, omitEmpty, asString, pretty, e
 		if et.Kind() == reflect.Struct {
 			fi.elem = getTypeStruct(et, false)
 		}
-		if omitEmpty {
+		switch {
+		case asString && isScalarKind(fi.rt.Elem().Kind()):
+			if omitEmpty {
+				fi.Append = appendPtrNotEmptyAsString
+				fi.iAppend = appendPtrNotEmptyAsString
+			} else {
+				fi.Append = appendPtrAsString
+				fi.iAppend = appendPtrAsString
+			}
+		case omitEmpty:
 			fi.Append = appendPtrNotEmpty
 			fi.iAppend = appendPtrNotEmpty
-		} else {
+		default:
 			fi.Append = appendJustKey
 			fi.iAppend = appendJustKey
 		}
@@ -0,0 +1,333 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package oj
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/alt"
+)
+
+// SchemaDraft is the $schema value included in generated JSON Schemas.
+const SchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	bytesType       = reflect.TypeOf([]byte{})
+	jsonMarshalType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	simplifierType  = reflect.TypeOf((*alt.Simplifier)(nil)).Elem()
+	genericerType   = reflect.TypeOf((*alt.Genericer)(nil)).Elem()
+)
+
+type schemaBuilder struct {
+	opts   *ojg.Options
+	findex byte
+	defs   map[string]any
+	names  map[reflect.Type]string
+}
+
+// JSONSchema returns a JSON Schema, as simple data, that describes the JSON
+// a Writer produces when writing a value of the provided type. The optional
+// arg can be a *ojg.Options or a *Writer. If not provided the
+// ojg.DefaultOptions are used. The UseTags, KeyExact, NestEmbed, CreateKey,
+// FullTypePath, OmitNil, BytesAs, TimeFormat, TimeWrap, and TimeMap options
+// are honored.
+//
+// Named struct types are placed in the $defs of the schema and referenced
+// so recursive types are supported. Pointers allow null values and fields
+// tagged with omitempty or omitzero are not required.
+func JSONSchema(rt reflect.Type, args ...any) map[string]any {
+	opts := &ojg.DefaultOptions
+	if 0 < len(args) {
+		switch ta := args[0].(type) {
+		case *ojg.Options:
+			opts = ta
+		case *Writer:
+			opts = &ta.Options
+		}
+	}
+	b := schemaBuilder{
+		opts:  opts,
+		defs:  map[string]any{},
+		names: map[reflect.Type]string{},
+	}
+	if opts.NestEmbed {
+		b.findex |= maskNested
+	}
+	if opts.UseTags {
+		b.findex |= maskByTag
+	} else if opts.KeyExact {
+		b.findex |= maskExact
+	}
+	var s map[string]any
+	switch {
+	case rt.Kind() == reflect.Struct && len(rt.Name()) != 0 && b.isPlain(rt):
+		// The root struct is not placed in the $defs and is referenced as
+		// "#" instead.
+		b.names[rt] = ""
+		s = b.structSchema(rt)
+	default:
+		s = b.schema(rt)
+	}
+	s["$schema"] = SchemaDraft
+	if 0 < len(b.defs) {
+		s["$defs"] = b.defs
+	}
+	return s
+}
+
+// isPlain returns true if the type is not encoded with a codec or one of the
+// marshal interfaces.
+func (b *schemaBuilder) isPlain(rt reflect.Type) bool {
+	if c := ojg.LookupCodec(rt); c != nil && c.Encode != nil {
+		return false
+	}
+	pt := reflect.PtrTo(rt)
+	for _, it := range []reflect.Type{jsonMarshalType, textMarshalType, simplifierType, genericerType} {
+		if rt.Implements(it) || pt.Implements(it) {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *schemaBuilder) schema(rt reflect.Type) map[string]any {
+	if rt.Kind() == reflect.Ptr {
+		return nullable(b.schema(rt.Elem()))
+	}
+	if c := ojg.LookupCodec(rt); c != nil && c.Encode != nil {
+		return map[string]any{}
+	}
+	// Same order of checks as the writer.
+	pt := reflect.PtrTo(rt)
+	switch {
+	case rt == timeType:
+		return b.timeSchema()
+	case rt == bytesType:
+		return b.bytesSchema()
+	case rt == jsonNumberType:
+		return map[string]any{"type": "number"}
+	case rt.Implements(jsonMarshalType) || pt.Implements(jsonMarshalType):
+		return map[string]any{}
+	case rt.Implements(textMarshalType) || pt.Implements(textMarshalType):
+		return map[string]any{"type": "string"}
+	case rt.Implements(simplifierType) || pt.Implements(simplifierType),
+		rt.Implements(genericerType) || pt.Implements(genericerType):
+		return map[string]any{}
+	}
+	switch rt.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return map[string]any{"type": "integer", "minimum": int64(0)}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": b.schema(rt.Elem())}
+	case reflect.Array:
+		return map[string]any{
+			"type":     "array",
+			"items":    b.schema(rt.Elem()),
+			"minItems": int64(rt.Len()),
+			"maxItems": int64(rt.Len()),
+		}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": b.schema(rt.Elem())}
+	case reflect.Struct:
+		if len(rt.Name()) == 0 {
+			return b.structSchema(rt)
+		}
+		return b.structRef(rt)
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return map[string]any{"type": "null"}
+	}
+	// Interfaces can be anything.
+	return map[string]any{}
+}
+
+func (b *schemaBuilder) structRef(rt reflect.Type) map[string]any {
+	name, has := b.names[rt]
+	if !has {
+		name = rt.Name()
+		if b.opts.FullTypePath {
+			name = rt.PkgPath() + "/" + name
+		}
+		base := name
+		for i := 2; b.defs[name] != nil; i++ {
+			name = fmt.Sprintf("%s%d", base, i)
+		}
+		b.names[rt] = name
+		// Reserve the name before building to support recursive types.
+		b.defs[name] = true
+		b.defs[name] = b.structSchema(rt)
+	}
+	if len(name) == 0 {
+		return map[string]any{"$ref": "#"}
+	}
+	return map[string]any{"$ref": "#/$defs/" + escapeSchemaKey(name)}
+}
+
+func (b *schemaBuilder) structSchema(rt reflect.Type) map[string]any {
+	structMut.Lock()
+	si := getTypeStruct(rt, false)
+	structMut.Unlock()
+
+	props := map[string]any{}
+	var required []any
+	if 0 < len(b.opts.CreateKey) {
+		name := rt.Name()
+		if b.opts.FullTypePath {
+			name = rt.PkgPath() + "/" + name
+		}
+		props[b.opts.CreateKey] = map[string]any{"const": name}
+		required = append(required, b.opts.CreateKey)
+	}
+	for _, fi := range si.fields[b.findex] {
+		var fs map[string]any
+		if fi.asString && isAsStringType(fi.rt) {
+			fs = map[string]any{"type": "string"}
+			if fi.kind == reflect.Ptr {
+				fs = nullable(fs)
+			}
+		} else {
+			fs = b.fieldSchema(fi.rt)
+		}
+		props[fi.key] = fs
+		if fi.omit || (b.opts.OmitNil && (fi.kind == reflect.Ptr || fi.kind == reflect.Interface)) {
+			continue
+		}
+		// Fields reached through an embedded pointer are skipped if the
+		// pointer is nil.
+		if fi.eAppend != nil {
+			continue
+		}
+		required = append(required, fi.key)
+	}
+	s := map[string]any{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+	if 0 < len(required) {
+		s["required"] = required
+	}
+	return s
+}
+
+// fieldSchema returns the schema for a struct field. A time.Time or
+// *time.Time field is written with the time.Time MarshalJSON method and not
+// according to the time options as a time.Time in a slice or map is.
+func (b *schemaBuilder) fieldSchema(rt reflect.Type) map[string]any {
+	switch {
+	case rt == timeType:
+		return dateTimeSchema()
+	case rt.Kind() == reflect.Ptr && rt.Elem() == timeType:
+		return nullable(dateTimeSchema())
+	}
+	return b.schema(rt)
+}
+
+func dateTimeSchema() map[string]any {
+	return map[string]any{"type": "string", "format": "date-time"}
+}
+
+func (b *schemaBuilder) timeSchema() (s map[string]any) {
+	switch b.opts.TimeFormat {
+	case "", "nano":
+		s = map[string]any{"type": "integer"}
+	case "second":
+		s = map[string]any{"type": "number"}
+	case time.RFC3339, time.RFC3339Nano:
+		s = map[string]any{"type": "string", "format": "date-time"}
+	default:
+		s = map[string]any{"type": "string"}
+	}
+	switch {
+	case b.opts.TimeMap:
+		name := "Time"
+		if b.opts.FullTypePath {
+			name = "time/Time"
+		}
+		s = map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				b.opts.CreateKey: map[string]any{"const": name},
+				"value":          s,
+			},
+			"required":             []any{b.opts.CreateKey, "value"},
+			"additionalProperties": false,
+		}
+	case 0 < len(b.opts.TimeWrap):
+		s = map[string]any{
+			"type":                 "object",
+			"properties":           map[string]any{b.opts.TimeWrap: s},
+			"required":             []any{b.opts.TimeWrap},
+			"additionalProperties": false,
+		}
+	}
+	return
+}
+
+func (b *schemaBuilder) bytesSchema() map[string]any {
+	if b.opts.BytesAs == ojg.BytesAsArray {
+		return map[string]any{
+			"type":  "array",
+			"items": map[string]any{"type": "integer", "minimum": int64(0), "maximum": int64(255)},
+		}
+	}
+	s := map[string]any{"type": "string"}
+	if b.opts.BytesAs == ojg.BytesAsBase64 {
+		s["contentEncoding"] = "base64"
+	}
+	return s
+}
+
+func isAsStringType(rt reflect.Type) bool {
+	if rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	return isScalarKind(rt.Kind())
+}
+
+// nullable returns a schema that allows null in addition to the values
+// allowed by the provided schema.
+func nullable(s map[string]any) map[string]any {
+	switch tv := s["type"].(type) {
+	case string:
+		if tv != "null" {
+			s["type"] = []any{tv, "null"}
+		}
+		return s
+	case []any:
+		return s
+	}
+	if len(s) == 0 { // already allows anything
+		return s
+	}
+	return map[string]any{"anyOf": []any{s, map[string]any{"type": "null"}}}
+}
+
+func escapeSchemaKey(s string) string {
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '~':
+			b = append(b, "~0"...)
+		case '/':
+			b = append(b, "~1"...)
+		default:
+			b = append(b, s[i])
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,198 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package oj_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/oj"
+	"github.com/khaf/ojg/pretty"
+	"github.com/khaf/ojg/schema"
+	"github.com/khaf/ojg/tt"
+)
+
+type SchemaBase struct {
+	ID   int64  `json:"id"`
+	Note string `json:"note,omitempty"`
+}
+
+type SchemaInner struct {
+	Size uint8
+}
+
+type SchemaNode struct {
+	SchemaBase
+	*SchemaInner
+	Name     string         `json:"name"`
+	Count    int            `json:"count,string"`
+	Ratio    *float64       `json:"ratio"`
+	When     time.Time      `json:"when"`
+	Tags     []string       `json:"tags"`
+	Fixed    [2]bool        `json:"fixed"`
+	Attrs    map[string]int `json:"attrs,omitzero"`
+	Any      any            `json:"any"`
+	Next     *SchemaNode    `json:"next"`
+	Skip     int            `json:"-"`
+	internal int
+}
+
+func TestJSONSchemaTags(t *testing.T) {
+	s := oj.JSONSchema(reflect.TypeOf(SchemaNode{}), &ojg.GoOptions)
+	tt.Equal(t, `{
+  $schema: "https://json-schema.org/draft/2020-12/schema"
+  additionalProperties: false
+  properties: {
+    Size: {minimum: 0 type: integer}
+    any: {}
+    attrs: {additionalProperties: {type: integer} type: object}
+    count: {type: string}
+    fixed: {items: {type: boolean} maxItems: 2 minItems: 2 type: array}
+    id: {type: integer}
+    name: {type: string}
+    next: {
+      anyOf: [{$ref: "#"} {type: null}]
+    }
+    note: {type: string}
+    ratio: {type: [number null]}
+    tags: {items: {type: string} type: array}
+    when: {format: date-time type: string}
+  }
+  required: [any count fixed id name next ratio tags when]
+  type: object
+}`, pretty.SEN(s, &ojg.Options{Sort: true}))
+
+	sch, err := schema.Compile(s)
+	tt.Nil(t, err)
+	ratio := 1.5
+	for _, v := range []*SchemaNode{
+		{Name: "a", When: time.Now(), Next: &SchemaNode{Name: "b", Ratio: &ratio, SchemaInner: &SchemaInner{Size: 3}}},
+		{Attrs: map[string]int{"x": 1}, Any: []any{true}, SchemaBase: SchemaBase{ID: 3, Note: "n"}},
+	} {
+		js := oj.JSON(v, &ojg.GoOptions)
+		tt.Nil(t, sch.Validate(oj.MustParseString(js)), js)
+	}
+}
+
+func TestJSONSchemaOptions(t *testing.T) {
+	opts := ojg.Options{KeyExact: true, CreateKey: "^", OmitNil: true}
+	s := oj.JSONSchema(reflect.TypeOf(&SchemaNode{}), &opts)
+	tt.Equal(t, `{
+  $defs: {
+    SchemaNode: {
+      additionalProperties: false
+      properties: {
+        Any: {}
+        Attrs: {additionalProperties: {type: integer} type: object}
+        Count: {type: integer}
+        Fixed: {items: {type: boolean} maxItems: 2 minItems: 2 type: array}
+        ID: {type: integer}
+        Name: {type: string}
+        Next: {
+          anyOf: [{$ref: "#/$defs/SchemaNode"} {type: null}]
+        }
+        Note: {type: string}
+        Ratio: {type: [number null]}
+        Size: {minimum: 0 type: integer}
+        Skip: {type: integer}
+        Tags: {items: {type: string} type: array}
+        When: {format: date-time type: string}
+        ^: {const: SchemaNode}
+      }
+      required: [^ Attrs Count Fixed ID Name Note Skip Tags When]
+      type: object
+    }
+  }
+  $schema: "https://json-schema.org/draft/2020-12/schema"
+  anyOf: [{$ref: "#/$defs/SchemaNode"} {type: null}]
+}`, pretty.SEN(s, &ojg.Options{Sort: true}))
+
+	sch, err := schema.Compile(s)
+	tt.Nil(t, err)
+	js := oj.JSON(&SchemaNode{Name: "a", Next: &SchemaNode{SchemaInner: &SchemaInner{}}}, &opts)
+	tt.Nil(t, sch.Validate(oj.MustParseString(js)), js)
+}
+
+type SchemaValues struct {
+	Bytes    []byte
+	Nested   [][]byte
+	ByteMap  map[string][]byte
+	When     time.Time
+	WhenPtr  *time.Time
+	Times    []time.Time
+	TimeMap  map[string]time.Time
+	TimePtrs []*time.Time
+}
+
+func TestJSONSchemaWriterOptions(t *testing.T) {
+	tm := time.Date(2023, 1, 2, 3, 4, 5, 6, time.UTC)
+	v := SchemaValues{
+		Bytes:    []byte("ab"),
+		Nested:   [][]byte{[]byte("cd")},
+		ByteMap:  map[string][]byte{"x": []byte("ef")},
+		When:     tm,
+		WhenPtr:  &tm,
+		Times:    []time.Time{tm},
+		TimeMap:  map[string]time.Time{"x": tm},
+		TimePtrs: []*time.Time{&tm},
+	}
+	for _, bytesAs := range []int{ojg.BytesAsString, ojg.BytesAsBase64, ojg.BytesAsArray} {
+		for _, format := range []string{"", "nano", "second", time.RFC3339, time.RFC3339Nano, time.Kitchen} {
+			for _, opts := range []ojg.Options{{}, {TimeWrap: "@"}, {TimeMap: true, CreateKey: "^"}} {
+				opts.BytesAs = bytesAs
+				opts.TimeFormat = format
+				sch, err := schema.Compile(oj.JSONSchema(reflect.TypeOf(v), &opts))
+				tt.Nil(t, err)
+				js := oj.JSON(&v, &opts)
+				tt.Nil(t, sch.Validate(oj.MustParseString(js)), js)
+			}
+		}
+	}
+}
+
+func TestJSONSchemaTopLevel(t *testing.T) {
+	for _, c := range []struct {
+		v      any
+		opts   *ojg.Options
+		expect string
+	}{
+		{v: time.Time{}, opts: &ojg.Options{}, expect: `{type: integer}`},
+		{v: time.Time{}, opts: &ojg.Options{TimeFormat: "second"}, expect: `{type: number}`},
+		{v: time.Time{}, opts: &ojg.Options{TimeFormat: time.RFC3339Nano}, expect: `{format: date-time type: string}`},
+		{v: time.Time{}, opts: &ojg.Options{TimeFormat: time.Kitchen}, expect: `{type: string}`},
+		{
+			v:      time.Time{},
+			opts:   &ojg.Options{TimeWrap: "@"},
+			expect: `{additionalProperties: false properties: {@: {type: integer}} required: [@] type: object}`,
+		},
+		{
+			v:    time.Time{},
+			opts: &ojg.Options{TimeMap: true, CreateKey: "^", TimeFormat: "second"},
+			expect: `{additionalProperties: false properties: {^: {const: Time} value: {type: number}} ` +
+				`required: [^ value] type: object}`,
+		},
+		{v: []byte{}, opts: &ojg.Options{BytesAs: ojg.BytesAsBase64}, expect: `{contentEncoding: base64 type: string}`},
+		{v: []byte{}, opts: &ojg.Options{BytesAs: ojg.BytesAsString}, expect: `{type: string}`},
+		{
+			v:      []byte{},
+			opts:   &ojg.Options{BytesAs: ojg.BytesAsArray},
+			expect: `{items: {maximum: 255 minimum: 0 type: integer} type: array}`,
+		},
+		{v: []json.Number{}, opts: nil, expect: `{items: {type: number} type: array}`},
+		{v: map[string][]*int{}, opts: nil, expect: `{additionalProperties: {items: {type: [integer null]} type: array} type: object}`},
+		{v: struct{ X int }{}, opts: nil, expect: `{additionalProperties: false properties: {x: {type: integer}} required: [x] type: object}`},
+	} {
+		var s map[string]any
+		if c.opts == nil {
+			s = oj.JSONSchema(reflect.TypeOf(c.v))
+		} else {
+			s = oj.JSONSchema(reflect.TypeOf(c.v), &oj.Writer{Options: *c.opts})
+		}
+		tt.Equal(t, oj.SchemaDraft, s["$schema"])
+		delete(s, "$schema")
+		tt.Equal(t, c.expect, pretty.SEN(s, 200.9, &ojg.Options{Sort: true}), c.v, " ", c.opts)
+	}
+}
@@ -61,7 +61,11 @@ func (p *Parser) Unmarshal(data []byte, vp any, recomposer ...alt.Recomposer) (e
 	orig := p.num.ForceFloat
 	p.num.ForceFloat = true
 	if v, err = p.Parse(data); err == nil {
-		_, err = alt.Recompose(v, vp)
+		if 0 < len(recomposer) {
+			_, err = recomposer[0].Recompose(v, vp)
+		} else {
+			_, err = alt.Recompose(v, vp)
+		}
 	}
 	p.num.ForceFloat = orig
 	return
@@ -352,7 +356,6 @@ func (p *Parser) parseBuffer(buf []byte, last bool) error {
 					break
 				}
 				if gen.BigLimit <= p.num.I {
-					p.num.FillBig()
 					p.num.AddDigit(b)
 					break
 				}
@@ -39,10 +39,12 @@ func TestParserParseString(t *testing.T) {
 		{src: "12.3e-05", value: 12.3e-5},
 		{src: "12.3e+5\n", value: 12.3e+5},
 		{src: `12345678901234567890`, value: "12345678901234567890"},
-		{src: `9223372036854775807`, value: "9223372036854775807"},   // max int
+		{src: `9223372036854775800`, value: 9223372036854775800},
+		{src: `9223372036854775807`, value: 9223372036854775807},     // max int
 		{src: `9223372036854775808`, value: "9223372036854775808"},   // max int + 1
-		{src: `-9223372036854775807`, value: -9223372036854775807},   // min int
-		{src: `-9223372036854775808`, value: "-9223372036854775808"}, // min int -1
+		{src: `-9223372036854775807`, value: -9223372036854775807},   // min int + 1
+		{src: `-9223372036854775808`, value: -9223372036854775808},   // min int
+		{src: `-9223372036854775809`, value: "-9223372036854775809"}, // min int - 1
 		{src: `0.9223372036854775808`, value: "0.9223372036854775808"},
 		{src: `-0.9223372036854775808`, value: "-0.9223372036854775808"},
 		{src: `0.000001234567890123456789`, value: "0.000001234567890123456789"},
@@ -119,11 +119,11 @@ func TestJSONTagAsString(t *testing.T) {
 
 	out := wr.MustJSON(&sample)
 	tt.Equal(t,
-		`{"a":"1","a16":"3","a32":"4","a64":"5","a8":"2","b":"6","b16":"8","b32":"9","b64":"10","b8":"7","f32":"11.5","f64":"12.5","no":"false","yes":"true","z":"abc"}`,
+		`{"a":"1","a16":"3","a32":"4","a64":"5","a8":"2","b":"6","b16":"8","b32":"9","b64":"10","b8":"7","f32":"11.5","f64":"12.5","no":"false","yes":"true","z":"\"abc\""}`,
 		string(out))
 	out = wr.MustJSON(sample)
 	tt.Equal(t,
-		`{"a":"1","a16":"3","a32":"4","a64":"5","a8":"2","b":"6","b16":"8","b32":"9","b64":"10","b8":"7","f32":"11.5","f64":"12.5","no":"false","yes":"true","z":"abc"}`,
+		`{"a":"1","a16":"3","a32":"4","a64":"5","a8":"2","b":"6","b16":"8","b32":"9","b64":"10","b8":"7","f32":"11.5","f64":"12.5","no":"false","yes":"true","z":"\"abc\""}`,
 		string(out))
 }
 
@@ -220,11 +220,11 @@ func TestJSONTagOmitEmptyAsString(t *testing.T) {
 
 	out := wr.MustJSON(&sample)
 	tt.Equal(t,
-		`{"a":"1","a16":"3","a32":"4","a64":"5","a8":"2","b":"6","b16":"8","b32":"9","b64":"10","b8":"7","f32":"11.5","f64":"12.5","yes":"true","z":"abc"}`,
+		`{"a":"1","a16":"3","a32":"4","a64":"5","a8":"2","b":"6","b16":"8","b32":"9","b64":"10","b8":"7","f32":"11.5","f64":"12.5","yes":"true","z":"\"abc\""}`,
 		string(out))
 	out = wr.MustJSON(sample)
 	tt.Equal(t,
-		`{"a":"1","a16":"3","a32":"4","a64":"5","a8":"2","b":"6","b16":"8","b32":"9","b64":"10","b8":"7","f32":"11.5","f64":"12.5","yes":"true","z":"abc"}`,
+		`{"a":"1","a16":"3","a32":"4","a64":"5","a8":"2","b":"6","b16":"8","b32":"9","b64":"10","b8":"7","f32":"11.5","f64":"12.5","yes":"true","z":"\"abc\""}`,
 		string(out))
 
 	out = wr.MustJSON(&Sample{})
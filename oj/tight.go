@@ -105,6 +105,14 @@ func tightSortObject(wr *Writer, n map[string]any, _ int) {
 }
 
 func (wr *Writer) tightStruct(rv reflect.Value, si *sinfo) {
+	if c := ojg.LookupCodec(rv.Type()); c != nil && c.Encode != nil {
+		wr.appendJSON(c.Encode(rv.Interface()), 0)
+		return
+	}
+	if marshals(rv.Type()) {
+		wr.appendJSON(rv.Interface(), 0)
+		return
+	}
 	if si == nil {
 		si = getSinfo(rv.Interface())
 	}
@@ -204,6 +212,10 @@ func (wr *Writer) tightStruct(rv reflect.Value, si *sinfo) {
 }
 
 func (wr *Writer) tightSlice(rv reflect.Value, si *sinfo) {
+	if isBytes(rv) {
+		wr.appendBytes(rv, 0)
+		return
+	}
 	end := rv.Len()
 	comma := false
 	wr.buf = append(wr.buf, '[')
@@ -65,7 +65,9 @@ func (wr *Writer) MustJSON(data any) []byte {
 		wr.buf = wr.buf[:0]
 	}
 	wr.calcFieldsIndex()
-	if wr.Color {
+	if wr.Canonical {
+		wr.appendCanonical(data)
+	} else if wr.Color {
 		wr.colorJSON(data, 0)
 	} else {
 		wr.appendString = ojg.AppendJSONString
@@ -119,7 +121,9 @@ func (wr *Writer) MustWrite(w io.Writer, data any) {
 		wr.buf = wr.buf[:0]
 	}
 	wr.calcFieldsIndex()
-	if wr.Color {
+	if wr.Canonical {
+		wr.appendCanonical(data)
+	} else if wr.Color {
 		wr.colorJSON(data, 0)
 	} else {
 		wr.appendString = ojg.AppendJSONString
@@ -164,7 +168,26 @@ func (wr *Writer) calcFieldsIndex() {
 	}
 }
 
+// noConvert calls the function with the Converter option cleared so that a
+// value returned by the Converter is not converted again.
+func (wr *Writer) noConvert(f func()) {
+	c := wr.Converter
+	wr.Converter = nil
+	defer func() { wr.Converter = c }()
+	f()
+}
+
 func (wr *Writer) appendJSON(data any, depth int) {
+	if wr.Converter != nil {
+		var converted bool
+		if data, converted = alt.ConvertValue(data, &wr.Options); converted {
+			wr.noConvert(func() { wr.appendJSON(data, depth) })
+			return
+		}
+	}
+	if enc := ojg.FindEncoder(data); enc != nil {
+		data = enc(data)
+	}
 	switch td := data.(type) {
 	case nil:
 		wr.buf = append(wr.buf, "null"...)
@@ -196,6 +219,8 @@ func (wr *Writer) appendJSON(data any, depth int) {
 		wr.buf = strconv.AppendUint(wr.buf, uint64(td), 10)
 	case uint64:
 		wr.buf = strconv.AppendUint(wr.buf, td, 10)
+	case json.Number:
+		wr.buf = append(wr.buf, td...)
 
 	case float32:
 		wr.buf = strconv.AppendFloat(wr.buf, float64(td), 'g', -1, 32)
@@ -262,6 +287,32 @@ func (wr *Writer) appendJSON(data any, depth int) {
 	}
 }
 
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonNumberType    = reflect.TypeOf(json.Number(""))
+)
+
+// marshals returns true if the struct type encodes itself, as time.Time
+// does, so that it is written by appendJSON instead of by field.
+func marshals(rt reflect.Type) bool {
+	return rt.Implements(jsonMarshalerType) || rt.Implements(textMarshalerType)
+}
+
+// isBytes returns true if the value is a slice of bytes which is written
+// according to the BytesAs option as the json package writes it as base64.
+func isBytes(rv reflect.Value) bool {
+	return rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8
+}
+
+func (wr *Writer) appendBytes(rv reflect.Value, depth int) {
+	if wr.strict && rv.IsNil() {
+		wr.buf = append(wr.buf, "null"...)
+		return
+	}
+	wr.appendJSON(rv.Bytes(), depth)
+}
+
 func appendDefault(wr *Writer, data any, depth int) {
 	switch {
 	case !wr.NoReflect:
@@ -439,6 +490,14 @@ func appendSortObject(wr *Writer, n map[string]any, depth int) {
 }
 
 func (wr *Writer) appendStruct(rv reflect.Value, depth int, si *sinfo) {
+	if c := ojg.LookupCodec(rv.Type()); c != nil && c.Encode != nil {
+		wr.appendJSON(c.Encode(rv.Interface()), depth)
+		return
+	}
+	if marshals(rv.Type()) {
+		wr.appendJSON(rv.Interface(), depth)
+		return
+	}
 	if si == nil {
 		si = getSinfo(rv.Interface())
 	}
@@ -579,6 +638,10 @@ func (wr *Writer) appendStruct(rv reflect.Value, depth int, si *sinfo) {
 }
 
 func (wr *Writer) appendSlice(rv reflect.Value, depth int, si *sinfo) {
+	if isBytes(rv) {
+		wr.appendBytes(rv, depth)
+		return
+	}
 	end := rv.Len()
 	if end == 0 {
 		wr.buf = append(wr.buf, "[]"...)
@@ -3,7 +3,9 @@
 package oj_test
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
 	"strings"
 	"testing"
 	"time"
@@ -110,6 +112,9 @@ func TestString(t *testing.T) {
 		{value: []any{-1, int8(2), int16(-3), int32(4), int64(-5)}, expect: "[-1,2,-3,4,-5]"},
 		{value: []any{uint(1), 'A', uint8(2), uint16(3), uint32(4), uint64(5)}, expect: "[1,65,2,3,4,5]"},
 		{value: gen.Array{gen.Int(1), gen.Float(1.2)}, expect: "[1,1.2]"},
+		{value: []any{uint64(math.MaxUint64), json.Number("1.50e-3")}, expect: "[18446744073709551615,1.50e-3]"},
+		{value: gen.Array{gen.Uint(math.MaxUint64), gen.Decimal("0.10"), gen.Null{}}, expect: "[18446744073709551615,0.10,null]"},
+		{value: gen.Bytes("abc"), expect: `"YWJj"`, options: &oj.Options{BytesAs: ojg.BytesAsBase64}},
 		{value: []any{float32(1.2), float64(2.1)}, expect: "[1.2,2.1]"},
 		{value: []any{tm}, expect: "[1588879759123456789]"},
 		{value: []any{tm}, expect: `[{"^":"Time","value":"2020-05-07T19:29:19.123456789Z"}]`,
@@ -978,3 +983,113 @@ func BenchmarkMarshalMap(b *testing.B) {
 		}
 	}
 }
+
+func TestWriteConverter(t *testing.T) {
+	c := ojg.Converter{
+		String: []func(val string) (any, bool){
+			func(val string) (any, bool) {
+				if strings.HasPrefix(val, "secret") {
+					return "****", true
+				}
+				return val, false
+			},
+		},
+		Time: []func(val time.Time) (any, bool){
+			func(val time.Time) (any, bool) { return val.Format("2006-01-02"), true },
+		},
+		Key: []func(key string) (string, bool){
+			func(key string) (string, bool) { return strings.ToUpper(key), true },
+		},
+	}
+	src := map[string]any{
+		"pw":   "secret-123",
+		"when": time.Date(2021, time.March, 5, 10, 11, 12, 0, time.UTC),
+		"list": []any{"a", "secret"},
+	}
+	opt := ojg.Options{Sort: true, Converter: &c}
+	tt.Equal(t, `{"LIST":["a","****"],"PW":"****","WHEN":"2021-03-05"}`, oj.JSON(src, &opt))
+	tt.Equal(t, "secret-123", src["pw"])
+
+	opt.Indent = 2
+	tt.Equal(t, `{
+  "LIST": [
+    "a",
+    "****"
+  ],
+  "PW": "****",
+  "WHEN": "2021-03-05"
+}`, oj.JSON(src, &opt))
+
+	var b strings.Builder
+	wr := oj.Writer{Options: ojg.Options{Sort: true, Converter: &c}}
+	tt.Nil(t, wr.Write(&b, src))
+	tt.Equal(t, `{"LIST":["a","****"],"PW":"****","WHEN":"2021-03-05"}`, b.String())
+
+	opt.Indent = 0
+	opt.Canonical = true
+	tt.Equal(t, `{"LIST":["a","****"],"PW":"****","WHEN":"2021-03-05"}`, oj.JSON(src, &opt))
+
+	opt.Canonical = false
+	opt.Color = true
+	opt.SyntaxColor = ""
+	opt.KeyColor = ""
+	opt.NullColor = ""
+	opt.BoolColor = ""
+	opt.NumberColor = ""
+	opt.StringColor = ""
+	opt.TimeColor = ""
+	opt.NoColor = ""
+	tt.Equal(t, `{"LIST":["a","****"],"PW":"****","WHEN":"2021-03-05"}`, oj.JSON(src, &opt))
+
+	type Login struct {
+		User string
+		Pw   string
+		Tags []string
+	}
+	opt = ojg.Options{Sort: true, Converter: &c}
+	tt.Equal(t, `{"PW":"****","TAGS":["x","****"],"USER":"ann"}`,
+		oj.JSON(&Login{User: "ann", Pw: "secret", Tags: []string{"x", "secret-1"}}, &opt))
+
+	nested := map[string]any{"a": map[string]any{"b": "secret"}}
+	tt.Equal(t, `{"A":{"B":"****"}}`, oj.JSON(nested, &opt))
+	tt.Equal(t, map[string]any{"a": map[string]any{"b": "secret"}}, nested)
+}
+
+func benchmarkWriteData() any {
+	list := make([]any, 100)
+	for i := range list {
+		list[i] = map[string]any{
+			"id":    int64(i),
+			"name":  "name",
+			"score": 1.5,
+			"tags":  []any{"a", "b", "c"},
+		}
+	}
+	return map[string]any{"list": list}
+}
+
+func BenchmarkWriteNoConverter(b *testing.B) {
+	data := benchmarkWriteData()
+	wr := oj.Writer{}
+	for i := 0; i < b.N; i++ {
+		_ = wr.MustJSON(data)
+	}
+}
+
+func BenchmarkWriteConverter(b *testing.B) {
+	data := benchmarkWriteData()
+	c := ojg.Converter{
+		String: []func(val string) (any, bool){
+			func(val string) (any, bool) {
+				if val == "name" {
+					return "****", true
+				}
+				return val, false
+			},
+		},
+	}
+	wr := oj.Writer{Options: ojg.Options{Converter: &c}}
+	for i := 0; i < b.N; i++ {
+		_ = wr.MustJSON(data)
+	}
+}
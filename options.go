@@ -50,6 +50,10 @@ const (
 	BytesAsBase64
 	// BytesAsArray indicates []byte should be encoded as an array if integers.
 	BytesAsArray
+	// BytesAsBytes indicates []byte should be left as a []byte when
+	// decomposing. The binary encoders use it to write byte strings. The
+	// JSON writers encode the bytes as a string.
+	BytesAsBytes
 
 	// MaskByTag is the mask for byTag fields.
 	MaskByTag = byte(0x10)
@@ -112,6 +116,7 @@ var (
 		KeyExact:     true,
 		NestEmbed:    false,
 		BytesAs:      BytesAsBase64,
+		TimeFormat:   time.RFC3339Nano,
 		WriteLimit:   1024,
 	}
 
@@ -217,7 +222,8 @@ type Options struct {
 
 	// UseTags if true will use the json annotation tags when marhsalling,
 	// writing, or decomposing an struct. If no tag is present then the
-	// KeyExact flag is referenced to determine the key.
+	// KeyExact flag is referenced to determine the key. The string,
+	// omitempty, omitzero, and inline tag options are supported.
 	UseTags bool
 
 	// KeyExact if true will use the exact field name for an encoded struct
@@ -228,17 +234,24 @@ type Options struct {
 	// HTMLUnsafe if true turns off escaping of &, <, and >.
 	HTMLUnsafe bool
 
+	// Canonical if true writes JSON in the RFC 8785 (JCS) canonical form.
+	// The Indent, Tab, Sort, Color, and HTMLUnsafe options are ignored. Only
+	// the oj package writer supports canonical output.
+	Canonical bool
+
 	// NestEmbed if true will generate an element for each anonymous embedded
 	// field.
 	NestEmbed bool
 
 	// BytesAs indicates how []byte fields should be encoded. Choices are
-	// BytesAsString, BytesAsBase64 (the go json package default), or
-	// BytesAsArray.
+	// BytesAsString, BytesAsBase64 (the go json package default),
+	// BytesAsArray, or BytesAsBytes.
 	BytesAs int
 
-	// Converter to use when decomposing or altering if non nil. The Converter
-	// type includes more details.
+	// Converter to use when decomposing, altering, or writing if non nil.
+	// When writing each value is converted as it is written so the
+	// original data is not modified. Values such as structs are decomposed
+	// before being converted. The Converter type includes more details.
 	Converter *Converter
 }
 
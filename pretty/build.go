@@ -4,6 +4,7 @@ package pretty
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"sort"
 	"strconv"
 	"time"
@@ -14,8 +15,17 @@ import (
 )
 
 func (w *Writer) build(data any) (n *node) {
+	if w.Converter != nil {
+		var converted bool
+		if data, converted = alt.ConvertValue(data, &w.Options); converted {
+			c := w.Converter
+			w.Converter = nil
+			defer func() { w.Converter = c }()
+			return w.build(data)
+		}
+	}
 	switch td := data.(type) {
-	case nil:
+	case nil, gen.Null:
 		n = w.buildNull()
 	case bool:
 		n = w.buildBool(td)
@@ -32,7 +42,7 @@ func (w *Writer) build(data any) (n *node) {
 	case int64:
 		n = w.buildInt(td)
 	case uint:
-		n = w.buildInt(int64(td))
+		n = w.buildNumber(strconv.FormatUint(uint64(td), 10))
 	case uint8:
 		n = w.buildInt(int64(td))
 	case uint16:
@@ -40,9 +50,15 @@ func (w *Writer) build(data any) (n *node) {
 	case uint32:
 		n = w.buildInt(int64(td))
 	case uint64:
-		n = w.buildInt(int64(td))
+		n = w.buildNumber(strconv.FormatUint(td, 10))
 	case gen.Int:
 		n = w.buildInt(int64(td))
+	case gen.Uint:
+		n = w.buildNumber(strconv.FormatUint(uint64(td), 10))
+	case json.Number:
+		n = w.buildNumber(string(td))
+	case gen.Decimal:
+		n = w.buildNumber(string(td))
 	case float32:
 		n = w.buildFloat32(td)
 	case float64:
@@ -66,6 +82,8 @@ func (w *Writer) build(data any) (n *node) {
 		default:
 			n = w.buildStringNode(string(td))
 		}
+	case gen.Bytes:
+		n = w.build([]byte(td))
 	case time.Time:
 		n = w.buildTimeNode(td)
 	case gen.Time:
@@ -123,8 +141,12 @@ func (w *Writer) buildBool(v bool) (n *node) {
 }
 
 func (w *Writer) buildInt(v int64) (n *node) {
+	return w.buildNumber(strconv.FormatInt(v, 10))
+}
+
+func (w *Writer) buildNumber(v string) (n *node) {
 	n = &node{
-		buf:  []byte(strconv.FormatInt(v, 10)),
+		buf:  []byte(v),
 		kind: numNode,
 	}
 	n.size = len(n.buf)
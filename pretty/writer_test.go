@@ -3,7 +3,9 @@
 package pretty_test
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
 	"strings"
 	"testing"
 	"time"
@@ -242,17 +244,21 @@ func TestByteSlice(t *testing.T) {
 	tt.Equal(t, `"YWJj"`, s)
 	s = pretty.JSON(val, &ojg.Options{BytesAs: ojg.BytesAsArray})
 	tt.Equal(t, "[97, 98, 99]", s)
+	s = pretty.JSON(gen.Bytes(val), &ojg.Options{BytesAs: ojg.BytesAsBase64})
+	tt.Equal(t, `"YWJj"`, s)
 }
 
 func TestIntTypes(t *testing.T) {
 	val := []any{
 		[]any{int8(-8), int16(-16), int32(-32), int64(-64), int(-1)},
 		[]any{uint8(8), uint16(16), uint32(32), uint64(64), uint(1)},
+		[]any{uint64(math.MaxUint64), json.Number("1.50e-3")},
 	}
 	s := pretty.JSON(val, 80.2)
 	tt.Equal(t, `[
   [-8, -16, -32, -64, -1],
-  [8, 16, 32, 64, 1]
+  [8, 16, 32, 64, 1],
+  [18446744073709551615, 1.50e-3]
 ]`, s)
 }
 
@@ -265,6 +271,9 @@ func TestGen(t *testing.T) {
 		gen.String("abc"),
 		gen.Object{"x": nil, "y": gen.False},
 		gen.Time(when),
+		gen.Uint(math.MaxUint64),
+		gen.Decimal("0.10"),
+		gen.Null{},
 	}
 	opt := ojg.DefaultOptions
 	opt.TimeFormat = time.RFC3339Nano
@@ -275,7 +284,10 @@ func TestGen(t *testing.T) {
   1.5,
   "abc",
   {"x": null, "y": false},
-  "2021-02-09T10:11:12.000000111Z"
+  "2021-02-09T10:11:12.000000111Z",
+  18446744073709551615,
+  0.10,
+  null
 ]`, s)
 }
 
@@ -462,3 +474,21 @@ func TestAlignMap(t *testing.T) {
   "short":      3
 }`, out)
 }
+
+func TestWriteConverter(t *testing.T) {
+	c := ojg.Converter{
+		Int: []func(val int64) (any, bool){
+			func(val int64) (any, bool) { return val * 2, true },
+		},
+	}
+	src := map[string]any{"a": 1, "b": []any{2, 3}}
+	tt.Equal(t, `{"a": 2, "b": [4, 6]}`, pretty.JSON(src, &ojg.Options{Sort: true, Converter: &c}))
+	tt.Equal(t, `{a: 2 b: [4 6]}`, pretty.SEN(src, &ojg.Options{Sort: true, Converter: &c}))
+	tt.Equal(t, 1, src["a"])
+
+	c.Key = []func(key string) (string, bool){
+		func(key string) (string, bool) { return strings.ToUpper(key), true },
+	}
+	tt.Equal(t, `{"A": 2, "B": [4, 6]}`, pretty.JSON(src, &ojg.Options{Sort: true, Converter: &c}))
+	tt.Equal(t, []any{2, 3}, src["b"])
+}
@@ -0,0 +1,479 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package schema
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/khaf/ojg/jp"
+)
+
+var rootPath = jp.R()
+
+type patternNode struct {
+	rx *regexp.Regexp
+	n  *node
+}
+
+type node struct {
+	path    jp.Expr
+	isBool  bool
+	boolVal bool
+
+	ref     string
+	base    string
+	refNode *node
+
+	types    []string
+	enum     []any
+	hasConst bool
+	constVal any
+
+	multipleOf       *float64
+	maximum          *float64
+	exclusiveMaximum *float64
+	minimum          *float64
+	exclusiveMinimum *float64
+
+	maxLength *int
+	minLength *int
+	pattern   *regexp.Regexp
+	format    string
+	formatFun func(s string) bool
+
+	prefixItems      []*node
+	items            *node
+	contains         *node
+	minContains      *int
+	maxContains      *int
+	maxItems         *int
+	minItems         *int
+	uniqueItems      bool
+	unevaluatedItems *node
+
+	properties            map[string]*node
+	propKeys              []string
+	patternProperties     []*patternNode
+	additionalProperties  *node
+	propertyNames         *node
+	maxProperties         *int
+	minProperties         *int
+	required              []string
+	dependentRequired     map[string][]string
+	dependentSchemas      map[string]*node
+	unevaluatedProperties *node
+
+	allOf    []*node
+	anyOf    []*node
+	oneOf    []*node
+	not      *node
+	ifNode   *node
+	thenNode *node
+	elseNode *node
+}
+
+type resource struct {
+	nodes   map[string]*node
+	anchors map[string]*node
+}
+
+type compiler struct {
+	opts      *Compiler
+	resources map[string]*resource
+	refs      []*node
+	nodes     []*node
+}
+
+func (c *compiler) compileDoc(v any) *node {
+	res := &resource{nodes: map[string]*node{}, anchors: map[string]*node{}}
+	c.resources[""] = res
+
+	return c.compile(v, rootPath, "", "", res)
+}
+
+func (c *compiler) resolve() {
+	for _, n := range c.refs {
+		n.refNode = c.lookup(n.base, n.ref)
+		if n.refNode == nil {
+			panic(fmt.Errorf("%s: $ref %q could not be resolved", childPath(n.path, "$ref"), n.ref))
+		}
+	}
+}
+
+// checkCycles panics if a $ref leads back to a schema without moving to a
+// different location in the instance as validating with such a schema would
+// never end. Only the in-place applicators are followed since the others
+// always validate a member or item of the instance.
+func (c *compiler) checkCycles() {
+	done := map[*node]bool{}
+	onStack := map[*node]bool{}
+	var stack []*node
+	var visit func(n *node)
+	visit = func(n *node) {
+		if done[n] {
+			return
+		}
+		if onStack[n] {
+			for i := len(stack) - 1; 0 <= i; i-- {
+				if r := stack[i]; r.refNode != nil {
+					panic(fmt.Errorf("%s: $ref %q is a cycle that does not advance in the instance",
+						childPath(r.path, "$ref"), r.ref))
+				}
+			}
+		}
+		onStack[n] = true
+		stack = append(stack, n)
+		if n.refNode != nil {
+			visit(n.refNode)
+		}
+		for _, list := range [][]*node{n.allOf, n.anyOf, n.oneOf} {
+			for _, m := range list {
+				visit(m)
+			}
+		}
+		for _, m := range []*node{n.not, n.ifNode, n.thenNode, n.elseNode} {
+			if m != nil {
+				visit(m)
+			}
+		}
+		for _, k := range sortedNodeKeys(n.dependentSchemas) {
+			visit(n.dependentSchemas[k])
+		}
+		stack = stack[:len(stack)-1]
+		onStack[n] = false
+		done[n] = true
+	}
+	for _, n := range c.nodes {
+		visit(n)
+	}
+}
+
+func sortedNodeKeys(m map[string]*node) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (c *compiler) lookup(base, ref string) *node {
+	u, err := resolveURI(base, ref)
+	if err != nil {
+		return nil
+	}
+	frag := u.Fragment
+	u.Fragment = ""
+	u.RawFragment = ""
+	res := c.resources[u.String()]
+	if res == nil {
+		return nil
+	}
+	if len(frag) == 0 || frag[0] == '/' {
+		return res.nodes[frag]
+	}
+	return res.anchors[frag]
+}
+
+func resolveURI(base, ref string) (*url.URL, error) {
+	r, err := url.Parse(ref)
+	if err != nil {
+		return nil, err
+	}
+	b, err := url.Parse(base)
+	if err != nil {
+		return nil, err
+	}
+	return b.ResolveReference(r), nil
+}
+
+func (c *compiler) compile(v any, path jp.Expr, base, ptr string, res *resource) *node {
+	n := &node{path: path, base: base}
+	res.nodes[ptr] = n
+	c.nodes = append(c.nodes, n)
+	switch tv := v.(type) {
+	case bool:
+		n.isBool = true
+		n.boolVal = tv
+		return n
+	case map[string]any:
+		if id, ok := tv["$id"].(string); ok {
+			u, err := resolveURI(base, id)
+			if err != nil {
+				panic(fmt.Errorf("%s: %s", childPath(path, "$id"), err))
+			}
+			u.Fragment = ""
+			u.RawFragment = ""
+			base = u.String()
+			n.base = base
+			res = c.resources[base]
+			if res == nil {
+				res = &resource{nodes: map[string]*node{}, anchors: map[string]*node{}}
+				c.resources[base] = res
+			}
+			ptr = ""
+			res.nodes[ptr] = n
+		}
+		c.compileObject(n, tv, base, ptr, res)
+	default:
+		panic(fmt.Errorf("%s: a schema must be an object or a boolean not a %T", path, v))
+	}
+	return n
+}
+
+func (c *compiler) compileObject(n *node, obj map[string]any, base, ptr string, res *resource) {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	sub := func(key string, v any) *node {
+		return c.compile(v, childPath(n.path, key), base, ptr+"/"+escapePointer(key), res)
+	}
+	for _, k := range keys {
+		v := obj[k]
+		kpath := childPath(n.path, k)
+		switch k {
+		case "$ref":
+			n.ref = asString(kpath, v)
+			c.refs = append(c.refs, n)
+		case "$anchor":
+			res.anchors[asString(kpath, v)] = n
+		case "$dynamicRef", "$dynamicAnchor":
+			panic(fmt.Errorf("%s: %s is not supported", kpath, k))
+		case "$defs", "definitions":
+			for _, dk := range sortedKeys(kpath, v) {
+				dv := v.(map[string]any)[dk]
+				c.compile(dv, childPath(kpath, dk), base, ptr+"/"+escapePointer(k)+"/"+escapePointer(dk), res)
+			}
+		case "type":
+			switch tv := v.(type) {
+			case string:
+				n.types = []string{checkType(kpath, tv)}
+			case []any:
+				for i, t := range tv {
+					n.types = append(n.types, checkType(childPath(kpath, i), asString(childPath(kpath, i), t)))
+				}
+			default:
+				panic(fmt.Errorf("%s: type must be a string or an array of strings", kpath))
+			}
+		case "enum":
+			list, ok := v.([]any)
+			if !ok {
+				panic(fmt.Errorf("%s: enum must be an array", kpath))
+			}
+			n.enum = list
+		case "const":
+			n.hasConst = true
+			n.constVal = v
+		case "multipleOf":
+			n.multipleOf = asNumber(kpath, v)
+			if *n.multipleOf <= 0.0 {
+				panic(fmt.Errorf("%s: multipleOf must be greater than zero", kpath))
+			}
+		case "maximum":
+			n.maximum = asNumber(kpath, v)
+		case "exclusiveMaximum":
+			n.exclusiveMaximum = asNumber(kpath, v)
+		case "minimum":
+			n.minimum = asNumber(kpath, v)
+		case "exclusiveMinimum":
+			n.exclusiveMinimum = asNumber(kpath, v)
+		case "maxLength":
+			n.maxLength = asCount(kpath, v)
+		case "minLength":
+			n.minLength = asCount(kpath, v)
+		case "pattern":
+			rx, err := regexp.Compile(asString(kpath, v))
+			if err != nil {
+				panic(fmt.Errorf("%s: %s", kpath, err))
+			}
+			n.pattern = rx
+		case "format":
+			n.format = asString(kpath, v)
+			if !c.opts.IgnoreFormat {
+				if n.formatFun = c.opts.Formats[n.format]; n.formatFun == nil {
+					n.formatFun = formats[n.format]
+				}
+			}
+		case "prefixItems":
+			for i, iv := range asArray(kpath, v) {
+				n.prefixItems = append(n.prefixItems,
+					c.compile(iv, childPath(kpath, i), base, fmt.Sprintf("%s/%s/%d", ptr, k, i), res))
+			}
+		case "items":
+			n.items = sub(k, v)
+		case "contains":
+			n.contains = sub(k, v)
+		case "minContains":
+			n.minContains = asCount(kpath, v)
+		case "maxContains":
+			n.maxContains = asCount(kpath, v)
+		case "maxItems":
+			n.maxItems = asCount(kpath, v)
+		case "minItems":
+			n.minItems = asCount(kpath, v)
+		case "uniqueItems":
+			b, ok := v.(bool)
+			if !ok {
+				panic(fmt.Errorf("%s: uniqueItems must be a boolean", kpath))
+			}
+			n.uniqueItems = b
+		case "unevaluatedItems":
+			n.unevaluatedItems = sub(k, v)
+		case "properties":
+			n.propKeys = sortedKeys(kpath, v)
+			n.properties = map[string]*node{}
+			for _, pk := range n.propKeys {
+				n.properties[pk] = c.compile(v.(map[string]any)[pk], childPath(kpath, pk), base,
+					ptr+"/"+k+"/"+escapePointer(pk), res)
+			}
+		case "patternProperties":
+			for _, pk := range sortedKeys(kpath, v) {
+				rx, err := regexp.Compile(pk)
+				if err != nil {
+					panic(fmt.Errorf("%s: %s", childPath(kpath, pk), err))
+				}
+				n.patternProperties = append(n.patternProperties, &patternNode{
+					rx: rx,
+					n: c.compile(v.(map[string]any)[pk], childPath(kpath, pk), base,
+						ptr+"/"+k+"/"+escapePointer(pk), res),
+				})
+			}
+		case "additionalProperties":
+			n.additionalProperties = sub(k, v)
+		case "propertyNames":
+			n.propertyNames = sub(k, v)
+		case "maxProperties":
+			n.maxProperties = asCount(kpath, v)
+		case "minProperties":
+			n.minProperties = asCount(kpath, v)
+		case "required":
+			n.required = asStrings(kpath, v)
+		case "dependentRequired":
+			n.dependentRequired = map[string][]string{}
+			for _, dk := range sortedKeys(kpath, v) {
+				n.dependentRequired[dk] = asStrings(childPath(kpath, dk), v.(map[string]any)[dk])
+			}
+		case "dependentSchemas":
+			n.dependentSchemas = map[string]*node{}
+			for _, dk := range sortedKeys(kpath, v) {
+				n.dependentSchemas[dk] = c.compile(v.(map[string]any)[dk], childPath(kpath, dk), base,
+					ptr+"/"+k+"/"+escapePointer(dk), res)
+			}
+		case "unevaluatedProperties":
+			n.unevaluatedProperties = sub(k, v)
+		case "allOf", "anyOf", "oneOf":
+			list := asArray(kpath, v)
+			if len(list) == 0 {
+				panic(fmt.Errorf("%s: %s must not be empty", kpath, k))
+			}
+			nodes := make([]*node, len(list))
+			for i, iv := range list {
+				nodes[i] = c.compile(iv, childPath(kpath, i), base, fmt.Sprintf("%s/%s/%d", ptr, k, i), res)
+			}
+			switch k {
+			case "allOf":
+				n.allOf = nodes
+			case "anyOf":
+				n.anyOf = nodes
+			default:
+				n.oneOf = nodes
+			}
+		case "not":
+			n.not = sub(k, v)
+		case "if":
+			n.ifNode = sub(k, v)
+		case "then":
+			n.thenNode = sub(k, v)
+		case "else":
+			n.elseNode = sub(k, v)
+		}
+	}
+}
+
+func childPath(path jp.Expr, key any) jp.Expr {
+	p := make(jp.Expr, len(path), len(path)+1)
+	copy(p, path)
+	switch tk := key.(type) {
+	case int:
+		p = append(p, jp.Nth(tk))
+	case string:
+		p = append(p, jp.Child(tk))
+	}
+	return p
+}
+
+func escapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+func checkType(path jp.Expr, t string) string {
+	switch t {
+	case "null", "boolean", "integer", "number", "string", "array", "object":
+		return t
+	}
+	panic(fmt.Errorf("%s: %q is not a valid type", path, t))
+}
+
+func asString(path jp.Expr, v any) string {
+	s, ok := v.(string)
+	if !ok {
+		panic(fmt.Errorf("%s: expected a string not a %T", path, v))
+	}
+	return s
+}
+
+func asStrings(path jp.Expr, v any) []string {
+	list := asArray(path, v)
+	strs := make([]string, len(list))
+	for i, m := range list {
+		strs[i] = asString(childPath(path, i), m)
+	}
+	return strs
+}
+
+func asArray(path jp.Expr, v any) []any {
+	list, ok := v.([]any)
+	if !ok {
+		panic(fmt.Errorf("%s: expected an array not a %T", path, v))
+	}
+	return list
+}
+
+func sortedKeys(path jp.Expr, v any) []string {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		panic(fmt.Errorf("%s: expected an object not a %T", path, v))
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func asNumber(path jp.Expr, v any) *float64 {
+	f, ok := number(v)
+	if !ok {
+		panic(fmt.Errorf("%s: expected a number not a %T", path, v))
+	}
+	return &f
+}
+
+func asCount(path jp.Expr, v any) *int {
+	f, ok := number(v)
+	if !ok || f < 0.0 || f != math.Trunc(f) {
+		panic(fmt.Errorf("%s: expected a non-negative integer", path))
+	}
+	i := int(f)
+	return &i
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+/*
+Package schema validates data against JSON Schema draft 2020-12 schemas.
+
+A schema is parsed from JSON or SEN or compiled from data that has already
+been parsed. The compiled Schema is reusable and safe for concurrent use.
+Data can be simple types as returned by the oj and sen parsers or a
+gen.Node.
+
+	s, err := schema.Parse([]byte(`{
+	  type: object
+	  properties: {
+	    name: {type: string minLength: 1}
+	    age: {type: integer minimum: 0}
+	  }
+	  required: [name]
+	}`))
+	err = s.Validate(oj.MustParseString(`{"age": -1}`))
+	// $: name is required ($.required)
+	// $.age: -1 is less than 0 ($.properties.age.minimum)
+
+Each Error includes a jp.Expr path to the invalid value in the data and a
+jp.Expr path to the failed keyword in the schema document.
+
+All the validation and applicator keywords are supported including
+unevaluatedProperties and unevaluatedItems. References with $ref are
+resolved within the schema document using JSON pointers, $anchor, and $id
+values. A $ref cycle that does not advance in the instance, such as a
+schema of {"$ref": "#"}, is reported as a compile error. The $dynamicRef and
+$dynamicAnchor keywords are not supported and are reported as compile
+errors. Patterns use the Go regexp syntax.
+
+Recognized formats are asserted unless the Compiler IgnoreFormat option is
+set. The recognized formats are date-time, date, time, duration, email,
+idn-email, hostname, idn-hostname, ipv4, ipv6, uri, uri-reference, iri,
+iri-reference, uri-template, uuid, regex, json-pointer, and
+relative-json-pointer. Additional formats can be added to a Compiler.
+
+An Inferrer describes the structure of a set of sample documents. Each
+document added is merged into a Shape that tracks the types, optional
+members, numeric ranges, and string formats observed at each path. The
+Add method can be used as a parser callback for streams of documents.
+
+	var inf schema.Inferrer
+	_ = inf.Read(file)
+	s := inf.Schema()
+*/
+package schema
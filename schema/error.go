@@ -0,0 +1,45 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package schema
+
+import (
+	"strings"
+
+	"github.com/khaf/ojg/jp"
+)
+
+// Error describes a single validation failure.
+type Error struct {
+	// InstancePath is the path to the invalid value in the data.
+	InstancePath jp.Expr
+
+	// SchemaPath is the path to the keyword in the schema document that
+	// the value failed.
+	SchemaPath jp.Expr
+
+	// Keyword that the value failed such as type or required.
+	Keyword string
+
+	// Message describes the failure.
+	Message string
+}
+
+// Error returns a description of the failure that includes the paths.
+func (e *Error) Error() string {
+	return e.InstancePath.String() + ": " + e.Message + " (" + e.SchemaPath.String() + ")"
+}
+
+// Errors is the error returned by Schema.Validate() when validation fails.
+type Errors []*Error
+
+// Error returns the descriptions of all the errors, one per line.
+func (errs Errors) Error() string {
+	var b strings.Builder
+	for i, e := range errs {
+		if 0 < i {
+			b.WriteByte('\n')
+		}
+		b.WriteString(e.Error())
+	}
+	return b.String()
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package schema_test
+
+import (
+	"fmt"
+
+	"github.com/khaf/ojg/oj"
+	"github.com/khaf/ojg/schema"
+)
+
+func ExampleSchema_Validate() {
+	s := schema.MustParse([]byte(`{
+  type: object
+  properties: {
+    name: {type: string minLength: 1}
+    age: {type: integer minimum: 0}
+  }
+  required: [name]
+}`))
+	err := s.Validate(oj.MustParseString(`{"age": -1}`))
+	fmt.Println(err)
+
+	for _, e := range err.(schema.Errors) {
+		fmt.Printf("%s %s\n", e.InstancePath, e.Keyword)
+	}
+	fmt.Println(s.Valid(oj.MustParseString(`{"name": "Ann", "age": 27}`)))
+
+	// Output:
+	// $.age: -1 is less than 0 ($.properties.age.minimum)
+	// $: name is required ($.required)
+	// $.age minimum
+	// $ required
+	// true
+}
@@ -0,0 +1,221 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package schema
+
+import (
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	durationRx = regexp.MustCompile(`^P(?:\d+W|(?:\d+Y(?:\d+M)?(?:\d+D)?|\d+M(?:\d+D)?|\d+D)?` +
+		`(?:T(?:\d+H(?:\d+M)?(?:\d+S)?|\d+M(?:\d+S)?|\d+S))?)$`)
+	uuidRx    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	relPtrRx  = regexp.MustCompile(`^(?:0|[1-9][0-9]*)(?:#|(?:/(?:[^~/]|~[01])*)*)$`)
+	timeRx    = regexp.MustCompile(`^(\d{2}):(\d{2}):(\d{2})(?:\.\d+)?(?:[zZ]|([+-])(\d{2}):(\d{2}))$`)
+	hostLabel = regexp.MustCompile(`^[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+)
+
+// formats are the built in format checkers. Formats not in the map are
+// treated as annotations only.
+var formats = map[string]func(s string) bool{
+	"date-time":             isDateTime,
+	"date":                  isDate,
+	"time":                  isTime,
+	"duration":              isDuration,
+	"email":                 isEmail,
+	"idn-email":             isEmail,
+	"hostname":              isHostname,
+	"idn-hostname":          isIDNHostname,
+	"ipv4":                  isIPv4,
+	"ipv6":                  isIPv6,
+	"uri":                   isURI,
+	"uri-reference":         isURIReference,
+	"iri":                   isURI,
+	"iri-reference":         isURIReference,
+	"uri-template":          isURITemplate,
+	"uuid":                  uuidRx.MatchString,
+	"regex":                 isRegex,
+	"json-pointer":          isJSONPointer,
+	"relative-json-pointer": relPtrRx.MatchString,
+}
+
+func isDateTime(s string) bool {
+	i := strings.IndexAny(s, "Tt")
+	if i < 0 {
+		return false
+	}
+	return isDate(s[:i]) && isTime(s[i+1:])
+}
+
+func isDate(s string) bool {
+	if len(s) != 10 {
+		return false
+	}
+	_, err := time.Parse("2006-01-02", s)
+
+	return err == nil
+}
+
+func isTime(s string) bool {
+	m := timeRx.FindStringSubmatch(s)
+	if m == nil {
+		return false
+	}
+	hour, _ := strconv.Atoi(m[1])
+	minute, _ := strconv.Atoi(m[2])
+	second, _ := strconv.Atoi(m[3])
+	if 23 < hour || 59 < minute || 60 < second {
+		return false
+	}
+	if 0 < len(m[4]) {
+		oh, _ := strconv.Atoi(m[5])
+		om, _ := strconv.Atoi(m[6])
+		if 23 < oh || 59 < om {
+			return false
+		}
+		if second == 60 {
+			// A leap second must be at 23:59:60 UTC.
+			offset := oh*60 + om
+			if m[4] == "+" {
+				offset = -offset
+			}
+			utc := ((hour*60+minute+offset)%1440 + 1440) % 1440
+			return utc == 23*60+59
+		}
+	} else if second == 60 {
+		return hour == 23 && minute == 59
+	}
+	return true
+}
+
+func isDuration(s string) bool {
+	return durationRx.MatchString(s) && !strings.HasSuffix(s, "T") && s != "P"
+}
+
+func isEmail(s string) bool {
+	at := strings.LastIndexByte(s, '@')
+	if at <= 0 || at == len(s)-1 {
+		return false
+	}
+	addr, err := mail.ParseAddress(s)
+
+	return err == nil && addr.Address == s && len(addr.Name) == 0
+}
+
+func isHostname(s string) bool {
+	s = strings.TrimSuffix(s, ".")
+	if len(s) == 0 || 253 < len(s) {
+		return false
+	}
+	for _, label := range strings.Split(s, ".") {
+		if !hostLabel.MatchString(label) {
+			return false
+		}
+	}
+	return true
+}
+
+func isIDNHostname(s string) bool {
+	s = strings.TrimSuffix(s, ".")
+	if len(s) == 0 {
+		return false
+	}
+	for _, label := range strings.Split(s, ".") {
+		if len(label) == 0 || 63 < len(label) || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if !(r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isIPv4(s string) bool {
+	parts := strings.Split(s, ".")
+	if len(parts) != 4 {
+		return false
+	}
+	for _, p := range parts {
+		if len(p) == 0 || 3 < len(p) || (1 < len(p) && p[0] == '0') {
+			return false
+		}
+		for _, b := range []byte(p) {
+			if b < '0' || '9' < b {
+				return false
+			}
+		}
+		if i, _ := strconv.Atoi(p); 255 < i {
+			return false
+		}
+	}
+	return true
+}
+
+func isIPv6(s string) bool {
+	if !strings.Contains(s, ":") || strings.ContainsAny(s, "%/") {
+		return false
+	}
+	return net.ParseIP(s) != nil
+}
+
+func isURI(s string) bool {
+	u, err := url.Parse(s)
+
+	return err == nil && u.IsAbs() && !strings.ContainsAny(s, " \\")
+}
+
+func isURIReference(s string) bool {
+	_, err := url.Parse(s)
+
+	return err == nil && !strings.ContainsAny(s, " \\")
+}
+
+func isURITemplate(s string) bool {
+	depth := 0
+	for _, b := range []byte(s) {
+		switch b {
+		case '{':
+			if depth != 0 {
+				return false
+			}
+			depth++
+		case '}':
+			if depth != 1 {
+				return false
+			}
+			depth--
+		}
+	}
+	return depth == 0
+}
+
+func isRegex(s string) bool {
+	_, err := regexp.Compile(s)
+
+	return err == nil
+}
+
+func isJSONPointer(s string) bool {
+	if len(s) == 0 {
+		return true
+	}
+	if s[0] != '/' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] == '~' && (i == len(s)-1 || (s[i+1] != '0' && s[i+1] != '1')) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package schema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/khaf/ojg/schema"
+	"github.com/khaf/ojg/tt"
+)
+
+func TestSchemaFormat(t *testing.T) {
+	for _, c := range []struct {
+		format string
+		value  string
+		valid  bool
+	}{
+		{format: "date-time", value: "1963-06-19T08:30:06.283185Z", valid: true},
+		{format: "date-time", value: "1990-12-31T15:59:60-08:00", valid: true},
+		{format: "date-time", value: "1990-12-31T15:59:60Z", valid: false},
+		{format: "date-time", value: "1963-06-19 08:30:06Z", valid: false},
+		{format: "date", value: "2020-02-29", valid: true},
+		{format: "date", value: "2021-02-29", valid: false},
+		{format: "time", value: "23:59:60Z", valid: true},
+		{format: "time", value: "08:30:06", valid: false},
+		{format: "time", value: "24:00:00Z", valid: false},
+		{format: "duration", value: "P4DT12H30M5S", valid: true},
+		{format: "duration", value: "P2W", valid: true},
+		{format: "duration", value: "PT", valid: false},
+		{format: "duration", value: "P1D2H", valid: false},
+		{format: "email", value: "joe@example.com", valid: true},
+		{format: "email", value: "joe.example.com", valid: false},
+		{format: "idn-email", value: "실례@실례.테스트", valid: true},
+		{format: "hostname", value: "www.example.com", valid: true},
+		{format: "hostname", value: "-bad.example.com", valid: false},
+		{format: "hostname", value: strings.Repeat("a", 64) + ".com", valid: false},
+		{format: "idn-hostname", value: "실례.테스트", valid: true},
+		{format: "idn-hostname", value: "a..b", valid: false},
+		{format: "ipv4", value: "192.168.0.1", valid: true},
+		{format: "ipv4", value: "192.168.0.01", valid: false},
+		{format: "ipv4", value: "256.1.1.1", valid: false},
+		{format: "ipv6", value: "::1", valid: true},
+		{format: "ipv6", value: "12345::", valid: false},
+		{format: "ipv6", value: "127.0.0.1", valid: false},
+		{format: "uri", value: "http://example.com/a?b=c#d", valid: true},
+		{format: "uri", value: "/relative", valid: false},
+		{format: "uri-reference", value: "/relative#frag", valid: true},
+		{format: "uri-reference", value: "\\\\WINDOWS\\share", valid: false},
+		{format: "iri", value: "http://ƒøø.ßår/?∂éœ=πîx#πîüx", valid: true},
+		{format: "uri-template", value: "http://example.com/{id}", valid: true},
+		{format: "uri-template", value: "http://example.com/{id", valid: false},
+		{format: "uuid", value: "2eb8aa08-aa98-11ea-b4aa-73b441d16380", valid: true},
+		{format: "uuid", value: "2eb8aa08-aa98-11ea-b4aa-73b441d1638", valid: false},
+		{format: "regex", value: "^[a-z]+$", valid: true},
+		{format: "regex", value: "[", valid: false},
+		{format: "json-pointer", value: "/a~1b/0", valid: true},
+		{format: "json-pointer", value: "a/b", valid: false},
+		{format: "json-pointer", value: "/a~2", valid: false},
+		{format: "relative-json-pointer", value: "1/a", valid: true},
+		{format: "relative-json-pointer", value: "0#", valid: true},
+		{format: "relative-json-pointer", value: "/a", valid: false},
+		{format: "unknown", value: "anything", valid: true},
+	} {
+		s := schema.MustCompile(map[string]any{"format": c.format})
+		tt.Equal(t, c.valid, s.Valid(c.value), c.format, " ", c.value)
+	}
+}
+
+func TestSchemaFormatOptions(t *testing.T) {
+	src := map[string]any{"format": "ipv4"}
+	c := schema.Compiler{IgnoreFormat: true}
+	s := c.MustCompile(src)
+	tt.Equal(t, true, s.Valid("not an address"))
+
+	c = schema.Compiler{Formats: map[string]func(s string) bool{
+		"ipv4": func(s string) bool { return s == "localhost" },
+		"even": func(s string) bool { return len(s)%2 == 0 },
+	}}
+	s = c.MustCompile(src)
+	tt.Equal(t, true, s.Valid("localhost"))
+	tt.Equal(t, false, s.Valid("127.0.0.1"))
+
+	s = c.MustCompile(map[string]any{"format": "even"})
+	err := s.Validate("abc")
+	tt.Equal(t, `$: "abc" is not a valid even ($.format)`, err.Error())
+	tt.Equal(t, true, s.Valid(12))
+}
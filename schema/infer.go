@@ -0,0 +1,398 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package schema
+
+import (
+	"encoding/json"
+	"io"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/khaf/ojg/gen"
+	"github.com/khaf/ojg/jp"
+	"github.com/khaf/ojg/sen"
+)
+
+// inferFormats are the formats checked for when inferring string values in
+// the order of preference.
+var inferFormats = []string{"date-time", "date", "time", "uuid", "email", "ipv4", "ipv6", "uri"}
+
+// Shape describes the values observed at one location in a set of sample
+// documents. Object members and array elements are described by child
+// shapes. The elements of all the arrays at a location are merged into a
+// single Items shape.
+type Shape struct {
+	// Path is the location of the values in the sample documents. Array
+	// elements are represented by a wildcard.
+	Path jp.Expr
+
+	// Count is the number of values observed.
+	Count int
+
+	// Nulls, Bools, Ints, Floats, Strings, Arrays, and Objects are the
+	// number of values observed of each type. A time.Time is counted as a
+	// string.
+	Nulls   int
+	Bools   int
+	Ints    int
+	Floats  int
+	Strings int
+	Arrays  int
+	Objects int
+
+	// Times is the number of time.Time values observed. Times are also
+	// included in the Strings count.
+	Times int
+
+	// Min and Max are the range of the numbers observed.
+	Min float64
+	Max float64
+
+	// IntMin and IntMax are the exact range of the integers observed. They
+	// are nil if no integers were observed.
+	IntMin *big.Int
+	IntMax *big.Int
+
+	// MinLen and MaxLen are the range of string lengths observed.
+	MinLen int
+	MaxLen int
+
+	// MinItems and MaxItems are the range of array lengths observed.
+	MinItems int
+	MaxItems int
+
+	// Formats is the number of strings that matched each of the recognized
+	// formats.
+	Formats map[string]int
+
+	// Items describes the elements of all the arrays observed.
+	Items *Shape
+
+	// Props describes the object members observed. A member is required
+	// if the Count of the member is the same as the Objects count.
+	Props map[string]*Shape
+}
+
+// Inferrer infers the structure of a set of sample documents. Documents
+// are added one at a time so the Add method can be used as a parser
+// callback when reading a stream of documents. The zero value is ready to
+// use.
+type Inferrer struct {
+	root *Shape
+}
+
+// Add a sample document. The document can be simple data or a gen.Node.
+func (inf *Inferrer) Add(v any) {
+	if n, ok := v.(gen.Node); ok {
+		v = n.Simplify()
+	}
+	if inf.root == nil {
+		inf.root = &Shape{Path: rootPath}
+	}
+	inf.root.add(v)
+}
+
+// Read and add all the JSON or SEN documents in a stream.
+func (inf *Inferrer) Read(r io.Reader) (err error) {
+	_, err = sen.ParseReader(r, inf.Add)
+
+	return
+}
+
+// Shape returns the shape of all the documents added or nil if no
+// documents have been added.
+func (inf *Inferrer) Shape() *Shape {
+	return inf.root
+}
+
+// Paths returns all the shapes ordered by path with object members sorted
+// by key.
+func (inf *Inferrer) Paths() (shapes []*Shape) {
+	if inf.root != nil {
+		shapes = inf.root.walk(shapes)
+	}
+	return
+}
+
+// Schema returns a JSON Schema, as simple data, that all the documents
+// added are valid against.
+func (inf *Inferrer) Schema() map[string]any {
+	if inf.root == nil {
+		return map[string]any{"$schema": Draft}
+	}
+	s := inf.root.Schema()
+	s["$schema"] = Draft
+
+	return s
+}
+
+// Types returns the JSON Schema type names of the values observed. If both
+// integers and floats were observed only number is included.
+func (s *Shape) Types() (types []string) {
+	if 0 < s.Nulls {
+		types = append(types, "null")
+	}
+	if 0 < s.Bools {
+		types = append(types, "boolean")
+	}
+	switch {
+	case 0 < s.Floats:
+		types = append(types, "number")
+	case 0 < s.Ints:
+		types = append(types, "integer")
+	}
+	if 0 < s.Strings {
+		types = append(types, "string")
+	}
+	if 0 < s.Arrays {
+		types = append(types, "array")
+	}
+	if 0 < s.Objects {
+		types = append(types, "object")
+	}
+	return
+}
+
+// Required returns the sorted keys of the members that were present in
+// every object observed.
+func (s *Shape) Required() (keys []string) {
+	for k, p := range s.Props {
+		if p.Count == s.Objects {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	return
+}
+
+// Format returns the format all the strings observed matched or an empty
+// string if there is no such format.
+func (s *Shape) Format() string {
+	if 0 < s.Strings {
+		for _, f := range inferFormats {
+			if s.Formats[f] == s.Strings {
+				return f
+			}
+		}
+	}
+	return ""
+}
+
+// Schema returns a JSON Schema, as simple data, that describes the shape.
+func (s *Shape) Schema() map[string]any {
+	schema := map[string]any{}
+	types := s.Types()
+	switch len(types) {
+	case 0:
+		return schema
+	case 1:
+		schema["type"] = types[0]
+	default:
+		list := make([]any, len(types))
+		for i, t := range types {
+			list[i] = t
+		}
+		schema["type"] = list
+	}
+	if 0 < s.Ints+s.Floats {
+		if s.Floats == 0 {
+			// Integer bounds are only included if they can be represented
+			// exactly.
+			if v := exactInt(s.IntMin); v != nil {
+				schema["minimum"] = v
+			}
+			if v := exactInt(s.IntMax); v != nil {
+				schema["maximum"] = v
+			}
+		} else {
+			schema["minimum"] = s.Min
+			schema["maximum"] = s.Max
+		}
+	}
+	if f := s.Format(); 0 < len(f) {
+		schema["format"] = f
+	}
+	if 0 < s.Arrays && s.Items != nil {
+		schema["items"] = s.Items.Schema()
+	}
+	if 0 < s.Objects {
+		props := map[string]any{}
+		for k, p := range s.Props {
+			props[k] = p.Schema()
+		}
+		schema["properties"] = props
+		if req := s.Required(); 0 < len(req) {
+			list := make([]any, len(req))
+			for i, k := range req {
+				list[i] = k
+			}
+			schema["required"] = list
+		}
+	}
+	return schema
+}
+
+func (s *Shape) add(v any) {
+	s.Count++
+	switch tv := v.(type) {
+	case nil:
+		s.Nulls++
+	case bool:
+		s.Bools++
+	case string:
+		s.addString(tv)
+		for _, f := range inferFormats {
+			if formats[f](tv) {
+				s.Formats[f]++
+			}
+		}
+	case time.Time:
+		s.addString(tv.Format(time.RFC3339Nano))
+		s.Formats["date-time"]++
+		s.Times++
+	case float32, float64:
+		f, _ := number(v)
+		s.addNumber(f)
+		s.Floats++
+	case []any:
+		if s.Arrays == 0 || len(tv) < s.MinItems {
+			s.MinItems = len(tv)
+		}
+		if s.MaxItems < len(tv) {
+			s.MaxItems = len(tv)
+		}
+		s.Arrays++
+		if s.Items == nil && 0 < len(tv) {
+			s.Items = &Shape{Path: append(append(jp.Expr{}, s.Path...), jp.Wildcard('*'))}
+		}
+		for _, m := range tv {
+			s.Items.add(m)
+		}
+	case map[string]any:
+		s.Objects++
+		if s.Props == nil {
+			s.Props = map[string]*Shape{}
+		}
+		for k, m := range tv {
+			p := s.Props[k]
+			if p == nil {
+				p = &Shape{Path: childPath(s.Path, k)}
+				s.Props[k] = p
+			}
+			p.add(m)
+		}
+	default:
+		f, ok := number(v)
+		switch {
+		case !ok:
+			s.Count--
+		case s.addInt(v):
+			s.addNumber(f)
+			s.Ints++
+		default:
+			s.addNumber(f)
+			s.Floats++
+		}
+	}
+}
+
+// addInt updates the integer range if v is an integer and returns false
+// if it is not, as with a json.Number that has a fraction or exponent.
+func (s *Shape) addInt(v any) bool {
+	var i big.Int
+	switch tv := v.(type) {
+	case int:
+		i.SetInt64(int64(tv))
+	case int8:
+		i.SetInt64(int64(tv))
+	case int16:
+		i.SetInt64(int64(tv))
+	case int32:
+		i.SetInt64(int64(tv))
+	case int64:
+		i.SetInt64(tv)
+	case uint:
+		i.SetUint64(uint64(tv))
+	case uint8:
+		i.SetUint64(uint64(tv))
+	case uint16:
+		i.SetUint64(uint64(tv))
+	case uint32:
+		i.SetUint64(uint64(tv))
+	case uint64:
+		i.SetUint64(tv)
+	case json.Number:
+		if _, ok := i.SetString(string(tv), 10); !ok {
+			return false
+		}
+	default:
+		return false
+	}
+	if s.IntMin == nil || i.Cmp(s.IntMin) < 0 {
+		s.IntMin = new(big.Int).Set(&i)
+	}
+	if s.IntMax == nil || 0 < i.Cmp(s.IntMax) {
+		s.IntMax = new(big.Int).Set(&i)
+	}
+	return true
+}
+
+// exactInt returns i as an int64 or uint64 or nil if i is nil or does not
+// fit in either.
+func exactInt(i *big.Int) any {
+	switch {
+	case i == nil:
+		return nil
+	case i.IsInt64():
+		return i.Int64()
+	case i.IsUint64():
+		return i.Uint64()
+	}
+	return nil
+}
+
+func (s *Shape) addString(str string) {
+	n := len([]rune(str))
+	if s.Strings == 0 || n < s.MinLen {
+		s.MinLen = n
+	}
+	if s.MaxLen < n {
+		s.MaxLen = n
+	}
+	s.Strings++
+	if s.Formats == nil {
+		s.Formats = map[string]int{}
+	}
+}
+
+func (s *Shape) addNumber(f float64) {
+	if s.Ints+s.Floats == 0 {
+		s.Min = f
+		s.Max = f
+		return
+	}
+	if f < s.Min {
+		s.Min = f
+	}
+	if s.Max < f {
+		s.Max = f
+	}
+}
+
+func (s *Shape) walk(shapes []*Shape) []*Shape {
+	shapes = append(shapes, s)
+	if s.Items != nil {
+		shapes = s.Items.walk(shapes)
+	}
+	keys := make([]string, 0, len(s.Props))
+	for k := range s.Props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		shapes = s.Props[k].walk(shapes)
+	}
+	return shapes
+}
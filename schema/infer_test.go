@@ -0,0 +1,134 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package schema_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/gen"
+	"github.com/khaf/ojg/oj"
+	"github.com/khaf/ojg/pretty"
+	"github.com/khaf/ojg/schema"
+	"github.com/khaf/ojg/tt"
+)
+
+const inferSamples = `
+{"id": "2eb8aa08-aa98-11ea-b4aa-73b441d16380", "n": 3, "tags": ["a", 1], "at": "2023-01-02T03:04:05Z"}
+{"id": "0c9b1a6e-aa99-11ea-9d51-0f2d5c2f4c11", "n": 7.5, "tags": [], "extra": null, "sub": {"x": true}}
+{"id": "a0b1c2d3-aa99-11ea-9d51-0f2d5c2f4c11", "n": -1, "sub": {"x": false, "y": "a@b.com"}}
+`
+
+func TestInferSchema(t *testing.T) {
+	var inf schema.Inferrer
+	tt.Nil(t, inf.Schema()["type"])
+	tt.Nil(t, inf.Shape())
+
+	var p oj.Parser
+	_, err := p.Parse([]byte(inferSamples), inf.Add)
+	tt.Nil(t, err)
+
+	s := inf.Schema()
+	tt.Equal(t, `{
+  $schema: "https://json-schema.org/draft/2020-12/schema"
+  properties: {
+    at: {format: date-time type: string}
+    extra: {type: null}
+    id: {format: uuid type: string}
+    n: {maximum: 7.5 minimum: -1 type: number}
+    sub: {
+      properties: {x: {type: boolean} y: {format: email type: string}}
+      required: [x]
+      type: object
+    }
+    tags: {
+      items: {maximum: 1 minimum: 1 type: [integer string]}
+      type: array
+    }
+  }
+  required: [id n]
+  type: object
+}`, pretty.SEN(s, &ojg.Options{Sort: true}))
+
+	// All the samples must be valid against the inferred schema.
+	sch := schema.MustCompile(s)
+	for _, line := range strings.Split(strings.TrimSpace(inferSamples), "\n") {
+		tt.Nil(t, sch.Validate(oj.MustParseString(line)), line)
+	}
+	tt.NotNil(t, sch.Validate(map[string]any{"n": 1}))
+}
+
+func TestInferPaths(t *testing.T) {
+	var inf schema.Inferrer
+	tt.Nil(t, inf.Read(strings.NewReader(`{a: [1 {b: x}]} {a: [] c: "2020-01-02"}`)))
+	inf.Add(gen.Object{"a": gen.Array{gen.Object{"b": gen.String("yy")}}, "d": gen.Time(time.Unix(0, 0))})
+	inf.Add(map[string]any{"c": int8(3)})
+
+	var lines []string
+	for _, s := range inf.Paths() {
+		lines = append(lines, s.Path.String()+" "+strings.Join(s.Types(), "|"))
+	}
+	tt.Equal(t, `$ object
+$.a array
+$.a.* integer|object
+$.a.*.b string
+$.c integer|string
+$.d string`, strings.Join(lines, "\n"))
+
+	root := inf.Shape()
+	tt.Equal(t, 4, root.Count)
+	tt.Equal(t, 0, len(root.Required()))
+	a := root.Props["a"]
+	tt.Equal(t, 0, a.MinItems)
+	tt.Equal(t, 2, a.MaxItems)
+	b := a.Items.Props["b"]
+	tt.Equal(t, 1, b.MinLen)
+	tt.Equal(t, 2, b.MaxLen)
+	tt.Equal(t, "", b.Format())
+	tt.Equal(t, "date-time", root.Props["d"].Format())
+	tt.Equal(t, "date", root.Props["c"].Format())
+	tt.Equal(t, 1, root.Props["c"].Formats["date"])
+}
+
+func TestInferIntegerBounds(t *testing.T) {
+	samples := []string{
+		`{"u": 18446744073709551615, "i": -9223372036854775808, "p": 9007199254740993}`,
+		`{"u": 0, "i": 9223372036854775807, "p": 9007199254740992}`,
+	}
+	var inf schema.Inferrer
+	for _, str := range samples {
+		inf.Add(oj.MustParseString(str))
+	}
+	s := inf.Schema()
+	tt.Equal(t, `{
+  $schema: "https://json-schema.org/draft/2020-12/schema"
+  properties: {
+    i: {maximum: 9223372036854775807 minimum: -9223372036854775808 type: integer}
+    p: {maximum: 9007199254740993 minimum: 9007199254740992 type: integer}
+    u: {maximum: 18446744073709551615 minimum: 0 type: integer}
+  }
+  required: [i p u]
+  type: object
+}`, pretty.SEN(s, &ojg.Options{Sort: true}))
+
+	sch := schema.MustCompile(s)
+	for _, str := range samples {
+		tt.Nil(t, sch.Validate(oj.MustParseString(str)), str)
+	}
+
+	// Bounds that do not fit in an int64 or uint64 are left out.
+	inf = schema.Inferrer{}
+	inf.Add(json.Number("-100000000000000000000"))
+	inf.Add(json.Number("3"))
+	inf.Add(json.Number("1.5"))
+	tt.Equal(t, "{maximum: 3 minimum: -1e+20 type: number}",
+		pretty.SEN(inf.Shape().Schema(), &ojg.Options{Sort: true}))
+
+	inf = schema.Inferrer{}
+	inf.Add(json.Number("-100000000000000000000"))
+	inf.Add(json.Number("3"))
+	tt.Equal(t, "{maximum: 3 type: integer}", pretty.SEN(inf.Shape().Schema(), &ojg.Options{Sort: true}))
+}
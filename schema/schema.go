@@ -0,0 +1,126 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package schema
+
+import (
+	"fmt"
+
+	"github.com/khaf/ojg/gen"
+	"github.com/khaf/ojg/sen"
+)
+
+// Draft is the $schema value for the JSON Schema draft supported.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a compiled JSON Schema that can be used to validate any number
+// of values. A Schema is safe for concurrent use.
+type Schema struct {
+	root *node
+}
+
+// Compiler compiles JSON Schema documents. The zero value is ready to use.
+type Compiler struct {
+	// IgnoreFormat if true treats the format keyword as an annotation only
+	// as described in the specification. The default is to assert the
+	// formats that are recognized.
+	IgnoreFormat bool
+
+	// Formats are additional format checkers keyed by the format name. An
+	// entry with the same name as a built in format replaces the built in
+	// format.
+	Formats map[string]func(s string) bool
+}
+
+// DefaultCompiler is the compiler used by the package functions.
+var DefaultCompiler = Compiler{}
+
+// Compile a schema that is either a map[string]any, a bool, or a gen.Node
+// with the DefaultCompiler.
+func Compile(v any) (*Schema, error) {
+	return DefaultCompiler.Compile(v)
+}
+
+// MustCompile compiles a schema with the DefaultCompiler and panics on
+// error.
+func MustCompile(v any) *Schema {
+	return DefaultCompiler.MustCompile(v)
+}
+
+// Parse a JSON or SEN schema document and compile it with the
+// DefaultCompiler.
+func Parse(data []byte) (*Schema, error) {
+	return DefaultCompiler.Parse(data)
+}
+
+// MustParse parses and compiles a JSON or SEN schema document with the
+// DefaultCompiler and panics on error.
+func MustParse(data []byte) *Schema {
+	s, err := DefaultCompiler.Parse(data)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Parse a JSON or SEN schema document and compile it.
+func (c *Compiler) Parse(data []byte) (*Schema, error) {
+	v, err := sen.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return c.Compile(v)
+}
+
+// Compile a schema that is either a map[string]any, a bool, or a gen.Node.
+// All $ref values must refer to schemas in the same document either by a
+// JSON pointer, an $anchor, or an $id. A $ref that leads back to the same
+// schema without advancing in the instance is an error.
+func (c *Compiler) Compile(v any) (s *Schema, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if err, _ = r.(error); err == nil {
+				err = fmt.Errorf("%v", r)
+			}
+			s = nil
+		}
+	}()
+	s = c.MustCompile(v)
+
+	return
+}
+
+// MustCompile compiles a schema and panics on error.
+func (c *Compiler) MustCompile(v any) *Schema {
+	if n, ok := v.(gen.Node); ok {
+		v = n.Simplify()
+	}
+	cp := compiler{
+		opts:      c,
+		resources: map[string]*resource{},
+	}
+	root := cp.compileDoc(v)
+	cp.resolve()
+	cp.checkCycles()
+
+	return &Schema{root: root}
+}
+
+// Validate data which can be simple data or a gen.Node. If the data is
+// valid nil is returned otherwise an Errors that describes each problem
+// is returned.
+func (s *Schema) Validate(v any) error {
+	if n, ok := v.(gen.Node); ok {
+		v = n.Simplify()
+	}
+	var vr validator
+	vr.validate(s.root, v, rootPath, &evaluated{})
+	if 0 < len(vr.errs) {
+		return vr.errs
+	}
+	return nil
+}
+
+// Valid returns true if the data is valid according to the schema.
+func (s *Schema) Valid(v any) bool {
+	return s.Validate(v) == nil
+}
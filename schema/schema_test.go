@@ -0,0 +1,340 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package schema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/khaf/ojg/gen"
+	"github.com/khaf/ojg/oj"
+	"github.com/khaf/ojg/schema"
+	"github.com/khaf/ojg/sen"
+	"github.com/khaf/ojg/tt"
+)
+
+type vcase struct {
+	data  string
+	valid bool
+}
+
+func checkCases(t *testing.T, src string, cases []vcase) {
+	t.Helper()
+	s, err := schema.Parse([]byte(src))
+	tt.Nil(t, err, src)
+	for _, c := range cases {
+		err = s.Validate(sen.MustParse([]byte(c.data)))
+		tt.Equal(t, c.valid, err == nil, src, " with ", c.data, " ", err)
+	}
+}
+
+func TestSchemaType(t *testing.T) {
+	checkCases(t, `{type: integer}`, []vcase{
+		{data: `1`, valid: true},
+		{data: `1.0`, valid: true},
+		{data: `1.5`, valid: false},
+		{data: `"1"`, valid: false},
+	})
+	checkCases(t, `{type: [string "null"]}`, []vcase{
+		{data: `null`, valid: true},
+		{data: `abc`, valid: true},
+		{data: `true`, valid: false},
+	})
+	checkCases(t, `{type: number}`, []vcase{
+		{data: `1`, valid: true},
+		{data: `1.5`, valid: true},
+		{data: `[]`, valid: false},
+	})
+	checkCases(t, `true`, []vcase{{data: `1`, valid: true}})
+	checkCases(t, `false`, []vcase{{data: `1`, valid: false}})
+	checkCases(t, `{enum: [1 a {x: [1]}]}`, []vcase{
+		{data: `1.0`, valid: true},
+		{data: `a`, valid: true},
+		{data: `{x: [1]}`, valid: true},
+		{data: `{x: [2]}`, valid: false},
+	})
+	checkCases(t, `{const: {a: [true null]}}`, []vcase{
+		{data: `{a: [true null]}`, valid: true},
+		{data: `{a: [true]}`, valid: false},
+	})
+}
+
+func TestSchemaNumber(t *testing.T) {
+	checkCases(t, `{minimum: 1 maximum: 10 multipleOf: 0.5}`, []vcase{
+		{data: `1`, valid: true},
+		{data: `10`, valid: true},
+		{data: `2.5`, valid: true},
+		{data: `2.3`, valid: false},
+		{data: `0`, valid: false},
+		{data: `11`, valid: false},
+		{data: `"20"`, valid: true},
+	})
+	checkCases(t, `{exclusiveMinimum: 1 exclusiveMaximum: 10}`, []vcase{
+		{data: `1`, valid: false},
+		{data: `10`, valid: false},
+		{data: `5`, valid: true},
+	})
+	checkCases(t, `{multipleOf: 0.01}`, []vcase{
+		{data: `19.99`, valid: true},
+		{data: `19.995`, valid: false},
+	})
+}
+
+func TestSchemaString(t *testing.T) {
+	checkCases(t, `{minLength: 2 maxLength: 3 pattern: "^[a-zé]+$"}`, []vcase{
+		{data: `ab`, valid: true},
+		{data: `"éé"`, valid: true},
+		{data: `a`, valid: false},
+		{data: `abcd`, valid: false},
+		{data: `AB`, valid: false},
+	})
+}
+
+func TestSchemaArray(t *testing.T) {
+	checkCases(t, `{prefixItems: [{type: string}] items: {type: integer} minItems: 1 maxItems: 3 uniqueItems: true}`, []vcase{
+		{data: `[a 1 2]`, valid: true},
+		{data: `[1]`, valid: false},
+		{data: `[a x]`, valid: false},
+		{data: `[]`, valid: false},
+		{data: `[a 1 2 3]`, valid: false},
+		{data: `[a 1 1.0]`, valid: false},
+	})
+	checkCases(t, `{prefixItems: [{} {}] items: false}`, []vcase{
+		{data: `[1 2]`, valid: true},
+		{data: `[1 2 3]`, valid: false},
+	})
+	checkCases(t, `{contains: {type: string} minContains: 2 maxContains: 3}`, []vcase{
+		{data: `[a 1 b]`, valid: true},
+		{data: `[a 1]`, valid: false},
+		{data: `[a b c d]`, valid: false},
+	})
+	checkCases(t, `{contains: {const: 5}}`, []vcase{
+		{data: `[1 5]`, valid: true},
+		{data: `[1 2]`, valid: false},
+		{data: `{}`, valid: true},
+	})
+	checkCases(t, `{contains: {const: 5} minContains: 0}`, []vcase{
+		{data: `[]`, valid: true},
+	})
+}
+
+func TestSchemaObject(t *testing.T) {
+	checkCases(t, `{
+  properties: {a: {type: integer} b: {type: string}}
+  patternProperties: {"^x-": {type: boolean}}
+  additionalProperties: false
+  required: [a]
+  minProperties: 1
+  maxProperties: 3
+  propertyNames: {maxLength: 4}
+}`, []vcase{
+		{data: `{a: 1 b: x x-y: true}`, valid: true},
+		{data: `{a: 1 x-y: 3}`, valid: false},
+		{data: `{a: 1 c: 3}`, valid: false},
+		{data: `{b: x}`, valid: false},
+		{data: `{a: 1 b: x x-y: true x-z: false}`, valid: false},
+		{data: `{a: 1 x-long: true}`, valid: false},
+	})
+	checkCases(t, `{dependentRequired: {card: [address]} dependentSchemas: {name: {required: [id]}}}`, []vcase{
+		{data: `{card: 1 address: x}`, valid: true},
+		{data: `{card: 1}`, valid: false},
+		{data: `{name: a id: 1}`, valid: true},
+		{data: `{name: a}`, valid: false},
+	})
+}
+
+func TestSchemaApplicators(t *testing.T) {
+	checkCases(t, `{allOf: [{type: integer} {minimum: 3}]}`, []vcase{
+		{data: `3`, valid: true},
+		{data: `2`, valid: false},
+	})
+	checkCases(t, `{anyOf: [{type: string} {minimum: 3}]}`, []vcase{
+		{data: `x`, valid: true},
+		{data: `4`, valid: true},
+		{data: `2`, valid: false},
+	})
+	checkCases(t, `{oneOf: [{type: integer} {minimum: 3}]}`, []vcase{
+		{data: `1`, valid: true},
+		{data: `3.5`, valid: true},
+		{data: `4`, valid: false},
+		{data: `x`, valid: true},
+	})
+	checkCases(t, `{not: {type: string}}`, []vcase{
+		{data: `1`, valid: true},
+		{data: `x`, valid: false},
+	})
+	checkCases(t, `{if: {properties: {kind: {const: a}}} then: {required: [x]} else: {required: [y]}}`, []vcase{
+		{data: `{kind: a x: 1}`, valid: true},
+		{data: `{kind: a y: 1}`, valid: false},
+		{data: `{kind: b y: 1}`, valid: true},
+		{data: `{kind: b x: 1}`, valid: false},
+	})
+}
+
+func TestSchemaUnevaluated(t *testing.T) {
+	checkCases(t, `{
+  properties: {a: true}
+  allOf: [{properties: {b: true}}]
+  anyOf: [{properties: {c: true}} {properties: {d: true}}]
+  if: {properties: {kind: {const: x}} required: [kind]}
+  then: {properties: {e: true}}
+  unevaluatedProperties: false
+}`, []vcase{
+		{data: `{a: 1 b: 2 c: 3}`, valid: true},
+		{data: `{a: 1 d: 2}`, valid: true},
+		{data: `{kind: x e: 1}`, valid: true},
+		{data: `{kind: y e: 1}`, valid: false},
+		{data: `{a: 1 z: 2}`, valid: false},
+	})
+	checkCases(t, `{
+  prefixItems: [{type: string}]
+  allOf: [{prefixItems: [true {type: integer}]}]
+  contains: {type: boolean}
+  unevaluatedItems: false
+}`, []vcase{
+		{data: `[a 1 true]`, valid: true},
+		{data: `[a 1 true false]`, valid: true},
+		{data: `[a 1 true null]`, valid: false},
+	})
+	// Annotations from failed subschemas are not used.
+	checkCases(t, `{anyOf: [{properties: {a: {type: string}}} {properties: {b: true}}] unevaluatedProperties: false}`, []vcase{
+		{data: `{a: 1 b: 2}`, valid: false},
+		{data: `{a: x b: 2}`, valid: true},
+	})
+}
+
+func TestSchemaRef(t *testing.T) {
+	checkCases(t, `{
+  $defs: {
+    pos: {type: integer minimum: 0}
+    node: {
+      type: object
+      properties: {value: {$ref: "#/$defs/pos"} next: {$ref: "#/$defs/node"}}
+    }
+    "a/b": {$anchor: slashed type: string}
+  }
+  properties: {
+    list: {$ref: "#/$defs/node"}
+    name: {$ref: "#slashed"}
+    alt: {$ref: "#/$defs/a~1b"}
+  }
+}`, []vcase{
+		{data: `{list: {value: 1 next: {value: 2}}}`, valid: true},
+		{data: `{list: {value: 1 next: {value: -2}}}`, valid: false},
+		{data: `{name: x alt: y}`, valid: true},
+		{data: `{name: 1}`, valid: false},
+		{data: `{alt: 1}`, valid: false},
+	})
+	checkCases(t, `{
+  $id: "https://example.com/root.json"
+  $defs: {
+    item: {$id: "item.json" type: string $defs: {inner: {type: integer}}}
+  }
+  properties: {
+    a: {$ref: "item.json"}
+    b: {$ref: "https://example.com/item.json#/$defs/inner"}
+  }
+}`, []vcase{
+		{data: `{a: x b: 1}`, valid: true},
+		{data: `{a: 1}`, valid: false},
+		{data: `{b: x}`, valid: false},
+	})
+	checkCases(t, `{$ref: "#/$defs/a" $defs: {a: {minimum: 2}} maximum: 4}`, []vcase{
+		{data: `3`, valid: true},
+		{data: `1`, valid: false},
+		{data: `5`, valid: false},
+	})
+}
+
+func TestSchemaErrors(t *testing.T) {
+	s := schema.MustParse([]byte(`{
+  type: object
+  properties: {
+    name: {type: string}
+    tags: {type: array items: {$ref: "#/$defs/tag"}}
+  }
+  required: [name id]
+  $defs: {tag: {type: string maxLength: 3}}
+}`))
+	err := s.Validate(oj.MustParseString(`{"name": 3, "id": 1, "tags": ["ab", "abcd", 7]}`))
+	tt.NotNil(t, err)
+	errs, ok := err.(schema.Errors)
+	tt.Equal(t, true, ok)
+	tt.Equal(t, 3, len(errs))
+	tt.Equal(t, "$.name", errs[0].InstancePath.String())
+	tt.Equal(t, "$.properties.name.type", errs[0].SchemaPath.String())
+	tt.Equal(t, "type", errs[0].Keyword)
+	tt.Equal(t, "expected string but was integer", errs[0].Message)
+	tt.Equal(t, `$.tags[1]: length 4 is longer than 3 ($['$defs'].tag.maxLength)
+$.tags[2]: expected string but was integer ($['$defs'].tag.type)`, strings.Join([]string{errs[1].Error(), errs[2].Error()}, "\n"))
+
+	err = s.Validate(map[string]any{"id": 1})
+	tt.Equal(t, "$: name is required ($.required)", err.Error())
+	tt.Equal(t, true, s.Valid(map[string]any{"name": "x", "id": 2}))
+}
+
+func TestSchemaGen(t *testing.T) {
+	s := schema.MustCompile(gen.Object{
+		"type":  gen.String("array"),
+		"items": gen.Object{"type": gen.String("integer")},
+	})
+	tt.Nil(t, s.Validate(gen.Array{gen.Int(1), gen.Int(2)}))
+	tt.NotNil(t, s.Validate(gen.Array{gen.Int(1), gen.String("x")}))
+	tt.Nil(t, s.Validate([]any{1, int8(2), uint64(3)}))
+	tt.Nil(t, s.Validate(gen.Array{gen.Uint(18446744073709551615), gen.Decimal("2.0")}))
+	tt.NotNil(t, s.Validate(gen.Array{gen.Decimal("2.5")}))
+}
+
+func TestSchemaCompileErrors(t *testing.T) {
+	for _, src := range []string{
+		`3`,
+		`{type: what}`,
+		`{type: 3}`,
+		`{minLength: -1}`,
+		`{minLength: 1.5}`,
+		`{maximum: x}`,
+		`{pattern: "["}`,
+		`{patternProperties: {"[": true}}`,
+		`{enum: 3}`,
+		`{allOf: []}`,
+		`{required: [1]}`,
+		`{properties: []}`,
+		`{$ref: "#/$defs/missing"}`,
+		`{$ref: "http://other.com/schema.json"}`,
+		`{multipleOf: 0}`,
+		`{uniqueItems: 1}`,
+		`{items: 3}`,
+		`{$dynamicRef: "#node"}`,
+		`{$dynamicAnchor: node}`,
+	} {
+		_, err := schema.Parse([]byte(src))
+		tt.NotNil(t, err, src)
+	}
+	_, err := schema.Parse([]byte(`{`))
+	tt.NotNil(t, err)
+	tt.Panic(t, func() { _ = schema.MustParse([]byte(`{type: what}`)) })
+	tt.Panic(t, func() { _ = schema.MustCompile(3) })
+}
+
+func TestSchemaRefCycle(t *testing.T) {
+	for _, src := range []string{
+		`{$ref: "#"}`,
+		`{$ref: "#/$defs/a" $defs: {a: {$ref: "#/$defs/b"} b: {$ref: "#/$defs/a"}}}`,
+		`{$defs: {a: {allOf: [{$ref: "#/$defs/b"}]} b: {anyOf: [{type: string} {$ref: "#/$defs/a"}]}}}`,
+		`{not: {$ref: "#"}}`,
+		`{if: {type: string} then: {$ref: "#"}}`,
+		`{dependentSchemas: {a: {$ref: "#"}}}`,
+	} {
+		_, err := schema.Parse([]byte(src))
+		tt.NotNil(t, err, src)
+	}
+	_, err := schema.Parse([]byte(`{$ref: "#"}`))
+	tt.Equal(t, `$['$ref']: $ref "#" is a cycle that does not advance in the instance`, err.Error())
+
+	// A cycle through a keyword that moves into the instance is fine.
+	s := schema.MustParse([]byte(`{
+  anyOf: [{type: integer} {type: array items: {$ref: "#"}} {type: object additionalProperties: {$ref: "#"}}]
+}`))
+	tt.Nil(t, s.Validate([]any{1, []any{2, map[string]any{"x": 3}}}))
+	tt.NotNil(t, s.Validate([]any{1, []any{"x"}}))
+}
@@ -0,0 +1,498 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/khaf/ojg/jp"
+)
+
+// evaluated tracks the properties and items evaluated at an instance
+// location for the unevaluatedProperties and unevaluatedItems keywords.
+type evaluated struct {
+	props map[string]bool
+	items map[int]bool
+}
+
+func (ev *evaluated) addProp(k string) {
+	if ev.props == nil {
+		ev.props = map[string]bool{}
+	}
+	ev.props[k] = true
+}
+
+func (ev *evaluated) addItem(i int) {
+	if ev.items == nil {
+		ev.items = map[int]bool{}
+	}
+	ev.items[i] = true
+}
+
+func (ev *evaluated) merge(other *evaluated) {
+	for k := range other.props {
+		ev.addProp(k)
+	}
+	for i := range other.items {
+		ev.addItem(i)
+	}
+}
+
+type validator struct {
+	errs Errors
+}
+
+func (vr *validator) fail(n *node, keyword string, ipath jp.Expr, format string, args ...any) {
+	vr.errs = append(vr.errs, &Error{
+		InstancePath: append(jp.Expr{}, ipath...),
+		SchemaPath:   childPath(n.path, keyword),
+		Keyword:      keyword,
+		Message:      fmt.Sprintf(format, args...),
+	})
+}
+
+// check validates without recording errors and returns true if valid.
+func (vr *validator) check(n *node, v any, ipath jp.Expr, ev *evaluated) bool {
+	start := len(vr.errs)
+	vr.validate(n, v, ipath, ev)
+	ok := len(vr.errs) == start
+	vr.errs = vr.errs[:start]
+
+	return ok
+}
+
+// inPlace validates a subschema that applies to the same instance location
+// and merges the evaluated annotations if valid.
+func (vr *validator) inPlace(n *node, v any, ipath jp.Expr, ev *evaluated) bool {
+	sev := &evaluated{}
+	start := len(vr.errs)
+	vr.validate(n, v, ipath, sev)
+	if len(vr.errs) == start {
+		ev.merge(sev)
+		return true
+	}
+	return false
+}
+
+func (vr *validator) validate(n *node, v any, ipath jp.Expr, ev *evaluated) {
+	if n.isBool {
+		if !n.boolVal {
+			vr.errs = append(vr.errs, &Error{
+				InstancePath: append(jp.Expr{}, ipath...),
+				SchemaPath:   n.path,
+				Keyword:      "false",
+				Message:      "no value is allowed",
+			})
+		}
+		return
+	}
+	if n.refNode != nil {
+		vr.inPlace(n.refNode, v, ipath, ev)
+	}
+	vr.validateGeneral(n, v, ipath)
+	switch tv := v.(type) {
+	case string:
+		vr.validateString(n, tv, ipath)
+	case []any:
+		vr.validateArray(n, tv, ipath, ev)
+	case map[string]any:
+		vr.validateObject(n, tv, ipath, ev)
+	default:
+		if f, ok := number(v); ok {
+			vr.validateNumber(n, f, ipath)
+		}
+	}
+	vr.validateApplicators(n, v, ipath, ev)
+
+	// The unevaluated keywords must be last so that all the other
+	// evaluations are included.
+	if n.unevaluatedItems != nil {
+		if list, ok := v.([]any); ok {
+			for i, m := range list {
+				if !ev.items[i] {
+					vr.validate(n.unevaluatedItems, m, childPath(ipath, i), &evaluated{})
+					ev.addItem(i)
+				}
+			}
+		}
+	}
+	if n.unevaluatedProperties != nil {
+		if obj, ok := v.(map[string]any); ok {
+			for _, k := range sortedMapKeys(obj) {
+				if !ev.props[k] {
+					vr.validate(n.unevaluatedProperties, obj[k], childPath(ipath, k), &evaluated{})
+					ev.addProp(k)
+				}
+			}
+		}
+	}
+}
+
+func (vr *validator) validateGeneral(n *node, v any, ipath jp.Expr) {
+	if 0 < len(n.types) {
+		vt := typeName(v)
+		match := false
+		for _, t := range n.types {
+			if t == vt || (t == "number" && vt == "integer") {
+				match = true
+				break
+			}
+		}
+		if !match {
+			if len(n.types) == 1 {
+				vr.fail(n, "type", ipath, "expected %s but was %s", n.types[0], vt)
+			} else {
+				vr.fail(n, "type", ipath, "expected one of %s but was %s", strings.Join(n.types, ", "), vt)
+			}
+		}
+	}
+	if n.enum != nil {
+		found := false
+		for _, e := range n.enum {
+			if equal(e, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			vr.fail(n, "enum", ipath, "value is not one of the enum values")
+		}
+	}
+	if n.hasConst && !equal(n.constVal, v) {
+		vr.fail(n, "const", ipath, "value does not equal the const value")
+	}
+}
+
+func (vr *validator) validateNumber(n *node, f float64, ipath jp.Expr) {
+	if n.multipleOf != nil && !isMultiple(f, *n.multipleOf) {
+		vr.fail(n, "multipleOf", ipath, "%s is not a multiple of %s", fmtNum(f), fmtNum(*n.multipleOf))
+	}
+	if n.maximum != nil && *n.maximum < f {
+		vr.fail(n, "maximum", ipath, "%s is greater than %s", fmtNum(f), fmtNum(*n.maximum))
+	}
+	if n.exclusiveMaximum != nil && *n.exclusiveMaximum <= f {
+		vr.fail(n, "exclusiveMaximum", ipath, "%s is not less than %s", fmtNum(f), fmtNum(*n.exclusiveMaximum))
+	}
+	if n.minimum != nil && f < *n.minimum {
+		vr.fail(n, "minimum", ipath, "%s is less than %s", fmtNum(f), fmtNum(*n.minimum))
+	}
+	if n.exclusiveMinimum != nil && f <= *n.exclusiveMinimum {
+		vr.fail(n, "exclusiveMinimum", ipath, "%s is not greater than %s", fmtNum(f), fmtNum(*n.exclusiveMinimum))
+	}
+}
+
+func (vr *validator) validateString(n *node, s string, ipath jp.Expr) {
+	if n.maxLength != nil || n.minLength != nil {
+		cnt := utf8.RuneCountInString(s)
+		if n.maxLength != nil && *n.maxLength < cnt {
+			vr.fail(n, "maxLength", ipath, "length %d is longer than %d", cnt, *n.maxLength)
+		}
+		if n.minLength != nil && cnt < *n.minLength {
+			vr.fail(n, "minLength", ipath, "length %d is shorter than %d", cnt, *n.minLength)
+		}
+	}
+	if n.pattern != nil && !n.pattern.MatchString(s) {
+		vr.fail(n, "pattern", ipath, "%q does not match /%s/", s, n.pattern)
+	}
+	if n.formatFun != nil && !n.formatFun(s) {
+		vr.fail(n, "format", ipath, "%q is not a valid %s", s, n.format)
+	}
+}
+
+func (vr *validator) validateArray(n *node, list []any, ipath jp.Expr, ev *evaluated) {
+	for i, m := range list {
+		switch {
+		case i < len(n.prefixItems):
+			vr.validate(n.prefixItems[i], m, childPath(ipath, i), &evaluated{})
+		case n.items != nil:
+			vr.validate(n.items, m, childPath(ipath, i), &evaluated{})
+		default:
+			continue
+		}
+		ev.addItem(i)
+	}
+	if n.contains != nil {
+		cnt := 0
+		for i, m := range list {
+			if vr.check(n.contains, m, childPath(ipath, i), &evaluated{}) {
+				cnt++
+				ev.addItem(i)
+			}
+		}
+		least := 1
+		if n.minContains != nil {
+			least = *n.minContains
+		}
+		if cnt < least {
+			if least == 1 {
+				vr.fail(n, "contains", ipath, "no items match the contains schema")
+			} else {
+				vr.fail(n, "minContains", ipath, "%d items match the contains schema, fewer than %d", cnt, least)
+			}
+		}
+		if n.maxContains != nil && *n.maxContains < cnt {
+			vr.fail(n, "maxContains", ipath, "%d items match the contains schema, more than %d", cnt, *n.maxContains)
+		}
+	}
+	if n.maxItems != nil && *n.maxItems < len(list) {
+		vr.fail(n, "maxItems", ipath, "%d items is more than %d", len(list), *n.maxItems)
+	}
+	if n.minItems != nil && len(list) < *n.minItems {
+		vr.fail(n, "minItems", ipath, "%d items is fewer than %d", len(list), *n.minItems)
+	}
+	if n.uniqueItems {
+	dup:
+		for i := 1; i < len(list); i++ {
+			for j := 0; j < i; j++ {
+				if equal(list[i], list[j]) {
+					vr.fail(n, "uniqueItems", ipath, "items %d and %d are equal", j, i)
+					break dup
+				}
+			}
+		}
+	}
+}
+
+func (vr *validator) validateObject(n *node, obj map[string]any, ipath jp.Expr, ev *evaluated) {
+	keys := sortedMapKeys(obj)
+	for _, k := range keys {
+		m := obj[k]
+		done := false
+		if pn := n.properties[k]; pn != nil {
+			vr.validate(pn, m, childPath(ipath, k), &evaluated{})
+			done = true
+		}
+		for _, pp := range n.patternProperties {
+			if pp.rx.MatchString(k) {
+				vr.validate(pp.n, m, childPath(ipath, k), &evaluated{})
+				done = true
+			}
+		}
+		if !done && n.additionalProperties != nil {
+			vr.validate(n.additionalProperties, m, childPath(ipath, k), &evaluated{})
+			done = true
+		}
+		if done {
+			ev.addProp(k)
+		}
+		if n.propertyNames != nil && !vr.check(n.propertyNames, k, childPath(ipath, k), &evaluated{}) {
+			vr.fail(n, "propertyNames", ipath, "property name %q is not valid", k)
+		}
+	}
+	if n.maxProperties != nil && *n.maxProperties < len(obj) {
+		vr.fail(n, "maxProperties", ipath, "%d properties is more than %d", len(obj), *n.maxProperties)
+	}
+	if n.minProperties != nil && len(obj) < *n.minProperties {
+		vr.fail(n, "minProperties", ipath, "%d properties is fewer than %d", len(obj), *n.minProperties)
+	}
+	for _, k := range n.required {
+		if _, has := obj[k]; !has {
+			vr.fail(n, "required", ipath, "%s is required", k)
+		}
+	}
+	for _, k := range keys {
+		for _, dk := range n.dependentRequired[k] {
+			if _, has := obj[dk]; !has {
+				vr.fail(n, "dependentRequired", ipath, "%s is required when %s is present", dk, k)
+			}
+		}
+		if dn := n.dependentSchemas[k]; dn != nil {
+			vr.inPlace(dn, obj, ipath, ev)
+		}
+	}
+}
+
+func (vr *validator) validateApplicators(n *node, v any, ipath jp.Expr, ev *evaluated) {
+	for _, an := range n.allOf {
+		vr.inPlace(an, v, ipath, ev)
+	}
+	if 0 < len(n.anyOf) {
+		match := false
+		for _, an := range n.anyOf {
+			sev := &evaluated{}
+			if vr.check(an, v, ipath, sev) {
+				ev.merge(sev)
+				match = true
+			}
+		}
+		if !match {
+			vr.fail(n, "anyOf", ipath, "value does not match any of the anyOf schemas")
+		}
+	}
+	if 0 < len(n.oneOf) {
+		var matches []int
+		for i, on := range n.oneOf {
+			sev := &evaluated{}
+			if vr.check(on, v, ipath, sev) {
+				if len(matches) == 0 {
+					ev.merge(sev)
+				}
+				matches = append(matches, i)
+			}
+		}
+		switch len(matches) {
+		case 1:
+			// valid
+		case 0:
+			vr.fail(n, "oneOf", ipath, "value does not match any of the oneOf schemas")
+		default:
+			vr.fail(n, "oneOf", ipath, "value matches more than one of the oneOf schemas %v", matches)
+		}
+	}
+	if n.not != nil && vr.check(n.not, v, ipath, &evaluated{}) {
+		vr.fail(n, "not", ipath, "value must not match the not schema")
+	}
+	if n.ifNode != nil {
+		sev := &evaluated{}
+		if vr.check(n.ifNode, v, ipath, sev) {
+			ev.merge(sev)
+			if n.thenNode != nil {
+				vr.inPlace(n.thenNode, v, ipath, ev)
+			}
+		} else if n.elseNode != nil {
+			vr.inPlace(n.elseNode, v, ipath, ev)
+		}
+	}
+}
+
+func typeName(v any) string {
+	switch tv := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	case float32:
+		if float32(math.Trunc(float64(tv))) == tv {
+			return "integer"
+		}
+		return "number"
+	case float64:
+		if math.Trunc(tv) == tv && !math.IsInf(tv, 0) {
+			return "integer"
+		}
+		return "number"
+	case json.Number:
+		if f, err := tv.Float64(); err == nil {
+			if math.Trunc(f) == f && !math.IsInf(f, 0) {
+				return "integer"
+			}
+			return "number"
+		}
+	}
+	if _, ok := number(v); ok {
+		return "integer"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func number(v any) (f float64, ok bool) {
+	ok = true
+	switch tv := v.(type) {
+	case int64:
+		f = float64(tv)
+	case float64:
+		f = tv
+	case int:
+		f = float64(tv)
+	case int8:
+		f = float64(tv)
+	case int16:
+		f = float64(tv)
+	case int32:
+		f = float64(tv)
+	case uint:
+		f = float64(tv)
+	case uint8:
+		f = float64(tv)
+	case uint16:
+		f = float64(tv)
+	case uint32:
+		f = float64(tv)
+	case uint64:
+		f = float64(tv)
+	case float32:
+		f = float64(tv)
+	case json.Number:
+		var err error
+		f, err = tv.Float64()
+		ok = err == nil
+	default:
+		ok = false
+	}
+	return
+}
+
+func isMultiple(f, m float64) bool {
+	q := f / m
+	if math.IsInf(q, 0) || math.IsNaN(q) {
+		return false
+	}
+	return math.Abs(q-math.Round(q)) < 1e-9
+}
+
+func fmtNum(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// equal compares values as JSON values so that 1 and 1.0 are equal.
+func equal(v0, v1 any) bool {
+	switch t0 := v0.(type) {
+	case nil:
+		return v1 == nil
+	case bool:
+		t1, ok := v1.(bool)
+		return ok && t0 == t1
+	case string:
+		t1, ok := v1.(string)
+		return ok && t0 == t1
+	case []any:
+		t1, ok := v1.([]any)
+		if !ok || len(t0) != len(t1) {
+			return false
+		}
+		for i, m := range t0 {
+			if !equal(m, t1[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		t1, ok := v1.(map[string]any)
+		if !ok || len(t0) != len(t1) {
+			return false
+		}
+		for k, m := range t0 {
+			if m1, has := t1[k]; !has || !equal(m, m1) {
+				return false
+			}
+		}
+		return true
+	}
+	if f0, ok := number(v0); ok {
+		f1, ok := number(v1)
+		return ok && f0 == f1
+	}
+	return false
+}
+
+func sortedMapKeys(obj map[string]any) []string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
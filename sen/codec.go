@@ -0,0 +1,53 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package sen
+
+import (
+	"reflect"
+	"unsafe"
+
+	"github.com/khaf/ojg"
+)
+
+// codecAppend returns the append function for a field with a type that has
+// a registered codec or nil if there is no codec.
+func codecAppend(rt reflect.Type, omitEmpty bool) appendFunc {
+	if ojg.LookupEncoder(rt) == nil {
+		return nil
+	}
+	if omitEmpty {
+		return appendCodecNotEmpty
+	}
+	return appendCodec
+}
+
+func appendCodec(fi *finfo, buf []byte, rv reflect.Value, addr uintptr, safe bool) ([]byte, any, appendStatus) {
+	v := rv.FieldByIndex(fi.index).Interface()
+	buf = append(buf, fi.jkey...)
+
+	return buf, encodeField(fi, v), aChanged
+}
+
+func appendCodecNotEmpty(fi *finfo, buf []byte, rv reflect.Value, addr uintptr, safe bool) ([]byte, any, appendStatus) {
+	fv := rv.FieldByIndex(fi.index)
+	if fv.IsZero() {
+		return buf, nil, aSkip
+	}
+	v := fv.Interface()
+	buf = append(buf, fi.jkey...)
+	v = encodeField(fi, v)
+	if (*[2]uintptr)(unsafe.Pointer(&v))[1] == 0 { // real nil check
+		return buf[:len(buf)-len(fi.jkey)], nil, aSkip
+	}
+	return buf, v, aChanged
+}
+
+// encodeField encodes a field value with the codec for the field type. If
+// the codec has been removed since the field information was cached then the
+// value is returned as is.
+func encodeField(fi *finfo, v any) any {
+	if encode := ojg.LookupEncoder(fi.rt); encode != nil {
+		return encode(v)
+	}
+	return v
+}
@@ -3,6 +3,7 @@
 package sen
 
 import (
+	"encoding/json"
 	"sort"
 	"strconv"
 	"time"
@@ -12,6 +13,16 @@ import (
 )
 
 func (wr *Writer) colorSEN(data any, depth int) {
+	if wr.Converter != nil {
+		var converted bool
+		if data, converted = alt.ConvertValue(data, &wr.Options); converted {
+			wr.noConvert(func() { wr.colorSEN(data, depth) })
+			return
+		}
+	}
+	if enc := ojg.FindEncoder(data); enc != nil {
+		data = enc(data)
+	}
 	switch td := data.(type) {
 	case nil:
 		wr.buf = append(wr.buf, wr.NullColor...)
@@ -55,6 +66,9 @@ func (wr *Writer) colorSEN(data any, depth int) {
 	case uint64:
 		wr.buf = append(wr.buf, wr.NumberColor...)
 		wr.buf = strconv.AppendUint(wr.buf, td, 10)
+	case json.Number:
+		wr.buf = append(wr.buf, wr.NumberColor...)
+		wr.buf = append(wr.buf, td...)
 
 	case float32:
 		wr.buf = append(wr.buf, wr.NumberColor...)
@@ -3,6 +3,7 @@
 package sen_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"testing"
@@ -45,6 +46,7 @@ func TestColor(t *testing.T) {
 		{value: []any{-1, int8(2), int16(-3), int32(4), int64(-5)}, expect: "s[x0-1x 02x 0-3x 04x 0-5xs]x"},
 		{value: []any{uint(1), 'A', uint8(2), uint16(3), uint32(4), uint64(5)}, expect: "s[x01x 065x 02x 03x 04x 05xs]x"},
 		{value: gen.Array{gen.Int(1), gen.Float(1.2)}, expect: "s[x01x 01.2xs]x"},
+		{value: []any{uint64(18446744073709551615), json.Number("1.50")}, expect: "s[x018446744073709551615x 01.50xs]x"},
 		{value: []any{float32(1.2), float64(2.1)}, expect: "s[x01.2x 02.1xs]x"},
 		{value: []any{tm}, expect: "s[xt1588879759123456789xs]x"},
 		{value: gen.Array{gen.Time(tm)}, expect: "s[xt1588879759123456789xs]x"},
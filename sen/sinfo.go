@@ -66,7 +66,7 @@ func buildStruct(rt reflect.Type, x uintptr, embedded bool) (st *sinfo) {
 func buildFields(rt reflect.Type, u byte, embedded bool) (fa []*finfo) {
 	switch {
 	case (maskByTag & u) != 0:
-		fa = buildTagFields(rt, (maskNested&u) != 0, (maskPretty&u) != 0, embedded)
+		fa = dominantFields(buildTagFields(rt, (maskNested&u) != 0, (maskPretty&u) != 0, embedded))
 	case (maskExact & u) != 0:
 		fa = buildExactFields(rt, (maskNested&u) != 0, (maskPretty&u) != 0, embedded)
 	default:
@@ -80,14 +80,28 @@ func buildTagFields(rt reflect.Type, out, pretty, embedded bool) (fa []*finfo) {
 	for i := rt.NumField() - 1; 0 <= i; i-- {
 		f := rt.Field(i)
 		name := []byte(f.Name)
-		if len(name) == 0 || 'a' <= name[0] {
+		tag, _ := f.Tag.Lookup("json")
+		parts := strings.Split(tag, ",")
+		if tag == "-" {
 			continue
 		}
-		if f.Anonymous && !out {
+		et := f.Type
+		if et.Kind() == reflect.Ptr {
+			et = et.Elem()
+		}
+		// As with the json package, the exported fields of embedded structs
+		// are included even if the embedded struct type is not exported. A
+		// tagged embedded struct of an unexported type is not included.
+		if len(name) == 0 || 'a' <= name[0] {
+			if !f.Anonymous || 0 < len(parts[0]) || out || et.Kind() != reflect.Struct {
+				continue
+			}
+		}
+		if et.Kind() == reflect.Struct && inlined(&f, parts, !out) {
 			if f.Type.Kind() == reflect.Ptr {
-				for _, fi := range buildTagFields(f.Type.Elem(), out, pretty, embedded) {
+				for _, fi := range buildTagFields(et, out, pretty, embedded) {
 					fi.index = append([]int{i}, fi.index...)
-					fi.Append = fi.iAppend
+					fi.viaPtr()
 					fa = append(fa, fi)
 				}
 			} else {
@@ -98,38 +112,100 @@ func buildTagFields(rt reflect.Type, out, pretty, embedded bool) (fa []*finfo) {
 				}
 			}
 		} else {
-			omitEmpty := false
-			asString := false
+			var opts tagOptions
 			key := f.Name
-			if tag, ok := f.Tag.Lookup("json"); ok && 0 < len(tag) {
-				parts := strings.Split(tag, ",")
-				switch parts[0] {
-				case "":
-					key = f.Name
-				case "-":
-					if 1 < len(parts) {
-						key = "-"
-					} else {
-						continue
-					}
-				default:
-					key = parts[0]
-				}
-				for _, p := range parts[1:] {
-					switch p {
-					case "omitempty":
-						omitEmpty = true
-					case "string":
-						asString = true
-					}
+			if 0 < len(parts[0]) {
+				key = parts[0]
+				opts.tagged = true
+			}
+			for _, p := range parts[1:] {
+				switch p {
+				case "omitempty":
+					opts.omitEmpty = true
+				case "omitzero":
+					opts.omitZero = true
+				case "string":
+					opts.asString = true
 				}
 			}
-			fa = append(fa, newFinfo(&f, key, omitEmpty, asString, pretty, embedded))
+			fi := newFinfo(&f, key, opts.omitEmpty, opts.asString, pretty, embedded)
+			if opts.omitZero {
+				fi.zAppend = fi.Append
+				fi.ziAppend = fi.iAppend
+				fi.Append = appendOmitZero
+				fi.iAppend = iappendOmitZero
+			}
+			fi.tagged = opts.tagged
+			fa = append(fa, fi)
 		}
 	}
 	return
 }
 
+// inlined returns true if the field is an embedded struct without a name in
+// the tag or if the tag includes the inline option. The fields of an
+// inlined struct are included as if they were fields of the parent.
+func inlined(f *reflect.StructField, parts []string, flatten bool) bool {
+	if f.Anonymous && flatten && len(parts[0]) == 0 {
+		return true
+	}
+	for _, p := range parts[1:] {
+		if p == "inline" {
+			return true
+		}
+	}
+	return false
+}
+
+type tagOptions struct {
+	tagged    bool
+	omitEmpty bool
+	omitZero  bool
+	asString  bool
+}
+
+// dominantFields removes fields with duplicate keys following the same rules
+// as the json package. The shallowest field wins. If there is more than one
+// at that depth then a single tagged field wins otherwise all are dropped.
+func dominantFields(fa []*finfo) []*finfo {
+	byKey := map[string][]*finfo{}
+	for _, fi := range fa {
+		byKey[fi.key] = append(byKey[fi.key], fi)
+	}
+	fa = fa[:0]
+	for _, list := range byKey {
+		if len(list) == 1 {
+			fa = append(fa, list[0])
+			continue
+		}
+		depth := len(list[0].index)
+		for _, fi := range list[1:] {
+			if len(fi.index) < depth {
+				depth = len(fi.index)
+			}
+		}
+		var dom *finfo
+		cnt := 0
+		tagged := 0
+		for _, fi := range list {
+			if len(fi.index) != depth {
+				continue
+			}
+			cnt++
+			if fi.tagged {
+				tagged++
+				dom = fi
+			} else if dom == nil {
+				dom = fi
+			}
+		}
+		if cnt == 1 || tagged == 1 {
+			fa = append(fa, dom)
+		}
+	}
+	return fa
+}
+
 func buildExactFields(rt reflect.Type, out, pretty, embedded bool) (fa []*finfo) {
 	for i := rt.NumField() - 1; 0 <= i; i-- {
 		f := rt.Field(i)
@@ -141,7 +217,7 @@ func buildExactFields(rt reflect.Type, out, pretty, embedded bool) (fa []*finfo)
 			if f.Type.Kind() == reflect.Ptr {
 				for _, fi := range buildExactFields(f.Type.Elem(), out, pretty, embedded) {
 					fi.index = append([]int{i}, fi.index...)
-					fi.Append = fi.iAppend
+					fi.viaPtr()
 					fa = append(fa, fi)
 				}
 			} else {
@@ -169,7 +245,7 @@ func buildLowFields(rt reflect.Type, out, pretty, embedded bool) (fa []*finfo) {
 			if f.Type.Kind() == reflect.Ptr {
 				for _, fi := range buildLowFields(f.Type.Elem(), out, pretty, embedded) {
 					fi.index = append([]int{i}, fi.index...)
-					fi.Append = fi.iAppend
+					fi.viaPtr()
 					fa = append(fa, fi)
 				}
 			} else {
@@ -119,11 +119,11 @@ func TestSENTagAsString(t *testing.T) {
 
 	out := wr.MustSEN(&sample)
 	tt.Equal(t,
-		`{a:"1" a16:"3" a32:"4" a64:"5" a8:"2" b:"6" b16:"8" b32:"9" b64:"10" b8:"7" f32:"11.5" f64:"12.5" no:"false" yes:"true" z:abc}`,
+		`{a:"1" a16:"3" a32:"4" a64:"5" a8:"2" b:"6" b16:"8" b32:"9" b64:"10" b8:"7" f32:"11.5" f64:"12.5" no:"false" yes:"true" z:"\"abc\""}`,
 		string(out))
 	out = wr.MustSEN(sample)
 	tt.Equal(t,
-		`{a:"1" a16:"3" a32:"4" a64:"5" a8:"2" b:"6" b16:"8" b32:"9" b64:"10" b8:"7" f32:"11.5" f64:"12.5" no:"false" yes:"true" z:abc}`,
+		`{a:"1" a16:"3" a32:"4" a64:"5" a8:"2" b:"6" b16:"8" b32:"9" b64:"10" b8:"7" f32:"11.5" f64:"12.5" no:"false" yes:"true" z:"\"abc\""}`,
 		string(out))
 }
 
@@ -220,11 +220,11 @@ func TestSENTagOmitEmptyAsString(t *testing.T) {
 
 	out := wr.MustSEN(&sample)
 	tt.Equal(t,
-		`{a:"1" a16:"3" a32:"4" a64:"5" a8:"2" b:"6" b16:"8" b32:"9" b64:"10" b8:"7" f32:"11.5" f64:"12.5" yes:"true" z:abc}`,
+		`{a:"1" a16:"3" a32:"4" a64:"5" a8:"2" b:"6" b16:"8" b32:"9" b64:"10" b8:"7" f32:"11.5" f64:"12.5" yes:"true" z:"\"abc\""}`,
 		string(out))
 	out = wr.MustSEN(sample)
 	tt.Equal(t,
-		`{a:"1" a16:"3" a32:"4" a64:"5" a8:"2" b:"6" b16:"8" b32:"9" b64:"10" b8:"7" f32:"11.5" f64:"12.5" yes:"true" z:abc}`,
+		`{a:"1" a16:"3" a32:"4" a64:"5" a8:"2" b:"6" b16:"8" b32:"9" b64:"10" b8:"7" f32:"11.5" f64:"12.5" yes:"true" z:"\"abc\""}`,
 		string(out))
 
 	out = wr.MustSEN(&Sample{})
@@ -320,13 +320,13 @@ func TestSENTagOther(t *testing.T) {
 
 	out := wr.MustSEN(&sample)
 	tt.Equal(t, `{
-  -: 2
+  "-": 2
   AsIs: 1
 }`, string(out))
 
 	wr.Indent = 0
 	out = wr.MustSEN(&sample)
-	tt.Equal(t, `{-:2 AsIs:1}`, string(out))
+	tt.Equal(t, `{"-":2 AsIs:1}`, string(out))
 }
 
 type Decimal struct {
@@ -110,6 +110,14 @@ func tightSortObject(wr *Writer, n map[string]any, _ int) {
 }
 
 func (wr *Writer) tightStruct(rv reflect.Value, si *sinfo) {
+	if c := ojg.LookupCodec(rv.Type()); c != nil && c.Encode != nil {
+		wr.appendSEN(c.Encode(rv.Interface()), 0)
+		return
+	}
+	if marshals(rv.Type()) {
+		wr.appendSEN(rv.Interface(), 0)
+		return
+	}
 	if si == nil {
 		si = getSinfo(rv.Interface())
 	}
@@ -211,6 +219,10 @@ func (wr *Writer) tightStruct(rv reflect.Value, si *sinfo) {
 }
 
 func (wr *Writer) tightSlice(rv reflect.Value, si *sinfo) {
+	if isBytes(rv) {
+		wr.appendSEN(rv.Bytes(), 0)
+		return
+	}
 	end := rv.Len()
 	comma := false
 	wr.buf = append(wr.buf, '[')
@@ -164,7 +164,26 @@ func (wr *Writer) calcFieldsIndex() {
 	}
 }
 
+// noConvert calls the function with the Converter option cleared so that a
+// value returned by the Converter is not converted again.
+func (wr *Writer) noConvert(f func()) {
+	c := wr.Converter
+	wr.Converter = nil
+	defer func() { wr.Converter = c }()
+	f()
+}
+
 func (wr *Writer) appendSEN(data any, depth int) {
+	if wr.Converter != nil {
+		var converted bool
+		if data, converted = alt.ConvertValue(data, &wr.Options); converted {
+			wr.noConvert(func() { wr.appendSEN(data, depth) })
+			return
+		}
+	}
+	if enc := ojg.FindEncoder(data); enc != nil {
+		data = enc(data)
+	}
 	wr.needSep = true
 	switch td := data.(type) {
 	case nil:
@@ -197,6 +216,8 @@ func (wr *Writer) appendSEN(data any, depth int) {
 		wr.buf = strconv.AppendUint(wr.buf, uint64(td), 10)
 	case uint64:
 		wr.buf = strconv.AppendUint(wr.buf, td, 10)
+	case json.Number:
+		wr.buf = append(wr.buf, td...)
 
 	case float32:
 		wr.buf = strconv.AppendFloat(wr.buf, float64(td), 'g', -1, 32)
@@ -266,6 +287,24 @@ func (wr *Writer) appendSEN(data any, depth int) {
 	}
 }
 
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonNumberType    = reflect.TypeOf(json.Number(""))
+)
+
+// marshals returns true if the struct type encodes itself, as time.Time
+// does, so that it is written by appendSEN instead of by field.
+func marshals(rt reflect.Type) bool {
+	return rt.Implements(jsonMarshalerType) || rt.Implements(textMarshalerType)
+}
+
+// isBytes returns true if the value is a slice of bytes which is written
+// according to the BytesAs option.
+func isBytes(rv reflect.Value) bool {
+	return rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8
+}
+
 func appendDefault(wr *Writer, data any, depth int) {
 	if !wr.NoReflect {
 		rv := reflect.ValueOf(data)
@@ -422,6 +461,14 @@ func appendSortObject(wr *Writer, n map[string]any, depth int) {
 }
 
 func (wr *Writer) appendStruct(rv reflect.Value, depth int, si *sinfo) {
+	if c := ojg.LookupCodec(rv.Type()); c != nil && c.Encode != nil {
+		wr.appendSEN(c.Encode(rv.Interface()), depth)
+		return
+	}
+	if marshals(rv.Type()) {
+		wr.appendSEN(rv.Interface(), depth)
+		return
+	}
 	if si == nil {
 		si = getSinfo(rv.Interface())
 	}
@@ -557,6 +604,10 @@ func (wr *Writer) appendStruct(rv reflect.Value, depth int, si *sinfo) {
 }
 
 func (wr *Writer) appendSlice(rv reflect.Value, depth int, si *sinfo) {
+	if isBytes(rv) {
+		wr.appendSEN(rv.Bytes(), depth)
+		return
+	}
 	end := rv.Len()
 	if end == 0 {
 		wr.buf = append(wr.buf, "[]"...)
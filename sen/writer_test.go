@@ -3,6 +3,7 @@
 package sen_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"testing"
@@ -106,6 +107,8 @@ func TestString(t *testing.T) {
 		{value: []any{-1, int8(2), int16(-3), int32(4), int64(-5)}, expect: "[-1 2 -3 4 -5]"},
 		{value: []any{uint(1), 'A', uint8(2), uint16(3), uint32(4), uint64(5)}, expect: "[1 65 2 3 4 5]"},
 		{value: gen.Array{gen.Int(1), gen.Float(1.2)}, expect: "[1 1.2]"},
+		{value: gen.Array{gen.Uint(18446744073709551615), gen.Decimal("0.10"), gen.Null{}}, expect: "[18446744073709551615 0.10 null]"},
+		{value: []any{uint64(18446744073709551615), json.Number("1e400")}, expect: "[18446744073709551615 1e400]"},
 		{value: []any{float32(1.2), float64(2.1)}, expect: "[1.2 2.1]"},
 		{value: []any{tm}, expect: "[1588879759123456789]"},
 		{value: []any{tm}, expect: `[{^:Time value:"2020-05-07T19:29:19.123456789Z"}]`,
@@ -721,3 +724,33 @@ func TestMarshalTextMarshaler(t *testing.T) {
 
 	tt.Panic(t, func() { _ = sen.Bytes(&TM{val: 5}) })
 }
+
+func TestWriteConverter(t *testing.T) {
+	c := ojg.Converter{
+		Float: []func(val float64) (any, bool){
+			func(val float64) (any, bool) { return float64(int64(val*10)) / 10, true },
+		},
+	}
+	type point struct {
+		X float64
+		Y float64
+	}
+	src := []any{1.234, &point{X: 2.345, Y: 3.456}}
+	tt.Equal(t, "[1.2 {x:2.3 y:3.4}]", sen.String(src, &ojg.Options{Sort: true, Converter: &c}))
+	tt.Equal(t, 1.234, src[0])
+
+	var b strings.Builder
+	wr := sen.Writer{Options: ojg.Options{Sort: true, Converter: &c}}
+	tt.Nil(t, wr.Write(&b, src))
+	tt.Equal(t, "[1.2 {x:2.3 y:3.4}]", b.String())
+
+	wr.Indent = 2
+	tt.Equal(t, `[
+  1.2
+  {
+    x: 2.3
+    y: 3.4
+  }
+]`, wr.SEN(src))
+	tt.Equal(t, 1.234, src[0])
+}
@@ -120,7 +120,8 @@ func AppendSENString(buf []byte, s string, htmlSafe bool) []byte {
 	}
 	b0 := len(buf)
 	m := senMap[s[0]]
-	quote := maxTokenLen < len(s) || (m != 'o' && m != '8' && !(!htmlSafe && m == 'h'))
+	// A leading '-' is read as the start of a number so must be quoted.
+	quote := maxTokenLen < len(s) || (m != 'o' && m != '8' && !(!htmlSafe && m == 'h')) || s[0] == '-'
 	buf = append(buf, '"')
 	start := 0
 	skip := 0
@@ -48,6 +48,9 @@ func TestStringSEN(t *testing.T) {
 		{src: "a\u001ec", expect: `"a\u001ec"`},
 		{src: "a\u2028b\u2029c", expect: `"a\u2028b\u2029c"`},
 		{src: "abc\ufffd", expect: `"abc\ufffd"`},
+		{src: "-", expect: `"-"`},
+		{src: "-1h30m0s", expect: `"-1h30m0s"`},
+		{src: "a-b", expect: `a-b`},
 	} {
 		var buf []byte
 		buf = ojg.AppendSENString(buf, td.src, td.htmlSafe)
@@ -0,0 +1,35 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+/*
+Package yaml reads and writes YAML with the same options and much the same
+API as the oj package. YAML documents are parsed into simple types or into
+gen.Node so they can be used with the jp and asm packages just like JSON.
+
+	v, err := yaml.Parse([]byte("a: [1, 2.5, x]\nb:\n  c: true\n"))
+	b, err := yaml.Marshal(v, &ojg.Options{Indent: 2, Sort: true})
+
+The parser supports the JSON compatible subset of YAML 1.2. Block and flow
+styles, quoted and block scalars, comments, and multiple document streams
+are supported. Plain scalars are resolved with the core schema so null,
+booleans, integers, and floats are recognized. The standard !!str,
+!!null, !!bool, !!int, and !!float tags are honored and other tags are
+ignored. Anchors and aliases are resolved with an alias replaced by a copy
+of the anchored node. The total expansion of aliases in a document is
+limited to a million values. Mapping keys must be scalars and are always
+returned as strings. Tabs are not allowed for indentation, including on
+the first line.
+
+The following are not supported and are reported as parse errors:
+
+  - explicit keys that start with the '?' indicator such as "? a\n: b" or
+    "{? a: b}"
+  - complex keys such as a sequence or mapping used as a key
+
+The writer uses the Indent, Sort, and OmitNil options. An Indent of zero
+writes flow style on a single line. Every document written ends with a
+newline. Strings are written as plain scalars
+when they would be read back unchanged, as literal block scalars when
+they span lines, and as double quoted scalars otherwise. Values other than
+simple types and gen.Node are decomposed as they are for the alt package.
+*/
+package yaml
@@ -0,0 +1,1259 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package yaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/alt"
+	"github.com/khaf/ojg/gen"
+	"github.com/khaf/ojg/oj"
+)
+
+// maxDepth limits the nesting of collections so that a small malicious
+// input can not exhaust the stack.
+const maxDepth = 10000
+
+// maxAliasValues limits the number of values copied when expanding the
+// aliases in a document so that a small malicious input such as the
+// billion laughs attack can not exhaust memory.
+const maxAliasValues = 1000000
+
+var bom = []byte{0xef, 0xbb, 0xbf}
+
+// Parser is a reusable YAML parser. A Parser is not safe for concurrent
+// use.
+type Parser struct {
+	buf        []byte
+	pos        int
+	line       int
+	lineStart  int
+	anchors    map[string]any
+	aliased    int
+	node       bool
+	cb         func(any)
+	resultChan chan any
+}
+
+// Parse YAML into simple types. Arguments are optional and can be a
+// func(any) bool or func(any) for callbacks, or a chan any for chan based
+// result delivery. If a callback or chan is provided each document in the
+// stream is delivered, otherwise the stream must not contain more than one
+// document.
+//
+// Plain scalars are resolved according to the YAML 1.2 core schema.
+// Integers are returned as int64 unless too large in which case they are
+// returned as a json.Number. Floats are returned as float64. Mapping keys
+// are always strings. An alias is replaced by a copy of the anchored value
+// so modifying one does not modify the other. An error is returned if the
+// aliases in a document expand to more than a million values.
+func (p *Parser) Parse(buf []byte, args ...any) (result any, err error) {
+	p.cb = nil
+	p.resultChan = nil
+	for _, a := range args {
+		switch ta := a.(type) {
+		case func(any) bool:
+			p.cb = func(x any) { _ = ta(x) }
+		case func(any):
+			p.cb = ta
+		case chan any:
+			p.resultChan = ta
+		default:
+			return nil, fmt.Errorf("a %T is not a valid option type", a)
+		}
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			result = nil
+			err = parseError(r)
+		}
+	}()
+	p.node = false
+	if p.cb == nil && p.resultChan == nil {
+		result = p.single(buf)
+		return
+	}
+	p.stream(buf, func(v any) {
+		if p.cb != nil {
+			p.cb(v)
+		}
+		if p.resultChan != nil {
+			p.resultChan <- v
+		}
+	})
+	return
+}
+
+// ParseReader reads YAML from an io.Reader. All the data is read before
+// parsing. The arguments are the same as for Parse.
+func (p *Parser) ParseReader(r io.Reader, args ...any) (any, error) {
+	buf, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return p.Parse(buf, args...)
+}
+
+// ParseNode parses a single YAML document into a gen.Node. Integers too
+// large for an int64 are returned as a gen.Big.
+func (p *Parser) ParseNode(buf []byte) (result gen.Node, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = nil
+			err = parseError(r)
+		}
+	}()
+	p.node = true
+	result, _ = p.single(buf).(gen.Node)
+
+	return
+}
+
+// Unmarshal parses the provided YAML and stores the result in the value
+// pointed to by vp.
+func (p *Parser) Unmarshal(data []byte, vp any, recomposer ...*alt.Recomposer) (err error) {
+	var v any
+	if v, err = p.Parse(data); err == nil {
+		if 0 < len(recomposer) {
+			_, err = recomposer[0].Recompose(v, vp)
+		} else {
+			_, err = alt.Recompose(v, vp)
+		}
+	}
+	return
+}
+
+func parseError(r any) error {
+	if err, ok := r.(*oj.ParseError); ok {
+		return err
+	}
+	return ojg.NewError(r)
+}
+
+func (p *Parser) single(buf []byte) (result any) {
+	cnt := 0
+	p.stream(buf, func(v any) {
+		if 0 < cnt {
+			panic(p.newError("a callback or chan is required for more than one document"))
+		}
+		result = v
+		cnt++
+	})
+	return
+}
+
+func (p *Parser) stream(buf []byte, each func(any)) {
+	p.buf = buf
+	p.pos = 0
+	p.line = 1
+	p.lineStart = 0
+	if bytes.HasPrefix(buf, bom) {
+		p.pos = len(bom)
+		p.lineStart = p.pos
+	}
+	for {
+		p.skipBlank()
+		directive := false
+		for p.pos < len(p.buf) && p.pos == p.lineStart && p.buf[p.pos] == '%' {
+			p.skipLine()
+			p.skipBlank()
+			directive = true
+		}
+		if len(p.buf) <= p.pos {
+			if directive {
+				panic(p.newError("directive without a document"))
+			}
+			return
+		}
+		p.anchors = map[string]any{}
+		p.aliased = 0
+		var v any
+		switch {
+		case p.atMarker("---"):
+			p.pos += 3
+			v = p.value(-1, false, false, 0)
+		case p.atMarker("..."):
+			p.pos += 3
+			p.endLine()
+			continue
+		default:
+			if directive {
+				panic(p.newError("expected a document start after a directive"))
+			}
+			v = p.value(-1, true, false, 0)
+		}
+		p.skipBlank()
+		if p.pos < len(p.buf) {
+			switch {
+			case p.atMarker("..."):
+				p.pos += 3
+				p.endLine()
+			case !p.atMarker("---"):
+				panic(p.newError("unexpected character %q", p.buf[p.pos]))
+			}
+		}
+		each(v)
+	}
+}
+
+// value parses a block node. The indent is the column of the parent
+// collection. If compact is true a block sequence or mapping can start on
+// the current line as it can after a sequence entry indicator. If inMap is
+// true the node is a mapping value so a block sequence can be at the same
+// indentation as the mapping.
+func (p *Parser) value(indent int, compact, inMap bool, depth int) (v any) {
+	p.checkDepth(depth)
+	p.skipSpace()
+	anchor, tag := p.properties()
+	p.skipComment()
+	if p.atEOL() {
+		p.skipBlank()
+		c := p.col()
+		if p.pos < len(p.buf) && !p.atDocMarker() && (indent < c || (inMap && c == indent && p.atEntry())) {
+			v = p.inline(indent, true, tag, depth)
+		} else {
+			v = p.scalar("", true, tag)
+		}
+	} else {
+		v = p.inline(indent, compact, tag, depth)
+	}
+	if 0 < len(anchor) {
+		p.anchors[anchor] = v
+	}
+	return
+}
+
+// inline parses a block node that starts at the current position.
+func (p *Parser) inline(indent int, compact bool, tag string, depth int) (v any) {
+	col := p.col()
+	b := p.buf[p.pos]
+	switch {
+	case b == '-' && compact && p.blankAt(p.pos+1):
+		return p.blockSeq(col, depth)
+	case b == '?' && p.blankAt(p.pos+1):
+		panic(p.newError("explicit keys are not supported"))
+	case b == '|' || b == '>':
+		return p.blockScalar(indent, tag)
+	case b == '[' || b == '{':
+		v = p.flowValue(depth)
+		p.endLine()
+		return
+	case b == '*':
+		v = p.alias()
+		p.endLine()
+		return
+	}
+	if compact && p.isKey() {
+		return p.blockMap(col, depth)
+	}
+	switch b {
+	case '\'':
+		v = p.scalar(p.singleQuoted(), false, tag)
+	case '"':
+		v = p.scalar(p.doubleQuoted(), false, tag)
+	default:
+		v = p.scalar(p.plain(indent, false), true, tag)
+	}
+	p.endLine()
+
+	return
+}
+
+func (p *Parser) blockSeq(col, depth int) any {
+	list := []any{}
+	for {
+		p.pos++ // past the -
+		list = append(list, p.value(col, true, false, depth+1))
+		p.skipBlank()
+		if len(p.buf) <= p.pos || p.atDocMarker() {
+			break
+		}
+		if c := p.col(); c != col || !p.atEntry() {
+			if col < c {
+				panic(p.newError("bad indentation of a sequence entry"))
+			}
+			break
+		}
+	}
+	return p.array(list)
+}
+
+func (p *Parser) blockMap(col, depth int) any {
+	obj := map[string]any{}
+	for {
+		k := p.key()
+		if _, has := obj[k]; has {
+			panic(p.newError("duplicate key %q", k))
+		}
+		p.pos++ // past the :
+		obj[k] = p.value(col, false, true, depth+1)
+		p.skipBlank()
+		if len(p.buf) <= p.pos || p.atDocMarker() {
+			break
+		}
+		c := p.col()
+		if c < col {
+			break
+		}
+		if col < c {
+			panic(p.newError("bad indentation of a mapping entry"))
+		}
+		if !p.isKey() {
+			panic(p.newError("expected a mapping key"))
+		}
+	}
+	return p.object(obj)
+}
+
+// key reads a block mapping key and leaves the position on the :
+// indicator.
+func (p *Parser) key() (k string) {
+	anchor, _ := p.properties()
+	switch p.buf[p.pos] {
+	case '\'':
+		k = p.singleQuoted()
+	case '"':
+		k = p.doubleQuoted()
+	default:
+		p.checkPlainStart(false)
+		k = string(p.plainLine(false))
+	}
+	p.skipSpace()
+	if len(p.buf) <= p.pos || p.buf[p.pos] != ':' {
+		panic(p.newError("expected a ':' after a mapping key"))
+	}
+	if 0 < len(anchor) {
+		p.anchors[anchor] = p.scalar(k, false, "")
+	}
+	return
+}
+
+// isKey returns true if the current line starts with an implicit mapping
+// key.
+func (p *Parser) isKey() bool {
+	i := p.pos
+	if q := p.buf[i]; q == '\'' || q == '"' {
+		for i++; i < len(p.buf); i++ {
+			switch p.buf[i] {
+			case '\n', '\r':
+				return false
+			case '\\':
+				if q == '"' {
+					i++
+				}
+			case q:
+				if q == '\'' && i+1 < len(p.buf) && p.buf[i+1] == '\'' {
+					i++
+					continue
+				}
+				for i++; i < len(p.buf) && (p.buf[i] == ' ' || p.buf[i] == '\t'); i++ {
+				}
+				return i < len(p.buf) && p.buf[i] == ':' && p.blankAt(i+1)
+			}
+		}
+		return false
+	}
+	for ; i < len(p.buf); i++ {
+		switch p.buf[i] {
+		case '\n', '\r':
+			return false
+		case ':':
+			if p.blankAt(i + 1) {
+				return true
+			}
+		case '#':
+			if p.pos < i && (p.buf[i-1] == ' ' || p.buf[i-1] == '\t') {
+				return false
+			}
+		}
+	}
+	return false
+}
+
+// properties reads the optional anchor and tag of a node.
+func (p *Parser) properties() (anchor, tag string) {
+	for p.pos < len(p.buf) {
+		switch p.buf[p.pos] {
+		case '&':
+			if 0 < len(anchor) {
+				panic(p.newError("a node can only have one anchor"))
+			}
+			p.pos++
+			anchor = p.name()
+		case '!':
+			if 0 < len(tag) {
+				panic(p.newError("a node can only have one tag"))
+			}
+			tag = p.tag()
+		default:
+			return
+		}
+		p.skipSpace()
+	}
+	return
+}
+
+// name reads an anchor or alias name.
+func (p *Parser) name() string {
+	start := p.pos
+	for p.pos < len(p.buf) && !p.blankAt(p.pos) && !p.flowIndicatorAt(p.pos) {
+		p.pos++
+	}
+	if start == p.pos {
+		panic(p.newError("missing anchor name"))
+	}
+	return string(p.buf[start:p.pos])
+}
+
+// tag reads a tag. The verbatim form of the standard tags is returned in
+// the shorthand form so that !<tag:yaml.org,2002:str> is returned as
+// !!str.
+func (p *Parser) tag() string {
+	start := p.pos
+	if p.pos+1 < len(p.buf) && p.buf[p.pos+1] == '<' {
+		end := bytes.IndexByte(p.buf[p.pos:], '>')
+		if end < 0 {
+			panic(p.newError("unterminated verbatim tag"))
+		}
+		p.pos += end + 1
+		t := string(p.buf[start+2 : p.pos-1])
+		if strings.HasPrefix(t, "tag:yaml.org,2002:") {
+			return "!!" + t[18:]
+		}
+		return t
+	}
+	for p.pos < len(p.buf) && !p.blankAt(p.pos) && !p.flowIndicatorAt(p.pos) {
+		p.pos++
+	}
+	return string(p.buf[start:p.pos])
+}
+
+func (p *Parser) alias() any {
+	p.pos++ // past the *
+	name := p.name()
+	v, ok := p.anchors[name]
+	if !ok {
+		panic(p.newError("unknown alias %q", name))
+	}
+	return p.copyAlias(v)
+}
+
+// copyAlias returns a copy of an anchored value and counts the values
+// copied against the maxAliasValues limit.
+func (p *Parser) copyAlias(v any) any {
+	if p.aliased++; maxAliasValues < p.aliased {
+		panic(p.newError("aliases expand to more than %d values", maxAliasValues))
+	}
+	switch tv := v.(type) {
+	case []any:
+		a := make([]any, len(tv))
+		for i, m := range tv {
+			a[i] = p.copyAlias(m)
+		}
+		return a
+	case map[string]any:
+		o := make(map[string]any, len(tv))
+		for k, m := range tv {
+			o[k] = p.copyAlias(m)
+		}
+		return o
+	case gen.Array:
+		a := make(gen.Array, len(tv))
+		for i, m := range tv {
+			a[i], _ = p.copyAlias(m).(gen.Node)
+		}
+		return a
+	case gen.Object:
+		o := make(gen.Object, len(tv))
+		for k, m := range tv {
+			o[k], _ = p.copyAlias(m).(gen.Node)
+		}
+		return o
+	}
+	return v
+}
+
+func (p *Parser) checkPlainStart(flow bool) {
+	if len(p.buf) <= p.pos {
+		panic(p.newError("unexpected end of input"))
+	}
+	switch b := p.buf[p.pos]; b {
+	case ',', '[', ']', '{', '}', '#', '&', '*', '!', '|', '>', '\'', '"', '%', '@', '`':
+		panic(p.newError("unexpected character %q", b))
+	case '-', '?', ':':
+		if p.blankAt(p.pos+1) || (flow && p.flowIndicatorAt(p.pos+1)) {
+			panic(p.newError("unexpected character %q", b))
+		}
+	}
+}
+
+// plain reads a plain scalar that may continue on following lines that
+// are indented more than the indent. Line breaks are folded.
+func (p *Parser) plain(indent int, flow bool) string {
+	p.checkPlainStart(flow)
+	text := append([]byte{}, p.plainLine(flow)...)
+	for {
+		pos, line, lineStart := p.pos, p.line, p.lineStart
+		p.skipSpace()
+		if len(p.buf) <= p.pos || !p.atEOL() {
+			break
+		}
+		breaks := 0
+		for p.pos < len(p.buf) && p.atEOL() {
+			p.newline()
+			breaks++
+			p.skipSpace()
+		}
+		if len(p.buf) <= p.pos || p.atDocMarker() || p.buf[p.pos] == '#' ||
+			(flow && p.flowIndicatorAt(p.pos)) || (!flow && p.col() <= indent) {
+			p.pos, p.line, p.lineStart = pos, line, lineStart
+			break
+		}
+		if breaks == 1 {
+			text = append(text, ' ')
+		} else {
+			for ; 1 < breaks; breaks-- {
+				text = append(text, '\n')
+			}
+		}
+		text = append(text, p.plainLine(flow)...)
+	}
+	return string(text)
+}
+
+// plainLine reads the part of a plain scalar on the current line and
+// returns it without trailing white space.
+func (p *Parser) plainLine(flow bool) []byte {
+	start := p.pos
+	end := p.pos
+Loop:
+	for p.pos < len(p.buf) {
+		switch p.buf[p.pos] {
+		case '\n', '\r':
+			break Loop
+		case ' ', '\t':
+			p.pos++
+			continue
+		case ':':
+			if p.blankAt(p.pos+1) || (flow && p.flowIndicatorAt(p.pos+1)) {
+				break Loop
+			}
+		case '#':
+			if start < p.pos && (p.buf[p.pos-1] == ' ' || p.buf[p.pos-1] == '\t') {
+				break Loop
+			}
+		case ',', '[', ']', '{', '}':
+			if flow {
+				break Loop
+			}
+		}
+		p.pos++
+		end = p.pos
+	}
+	p.pos = end
+
+	return p.buf[start:end]
+}
+
+func (p *Parser) singleQuoted() string {
+	p.pos++ // past the opening quote
+	var text []byte
+	for {
+		if len(p.buf) <= p.pos {
+			panic(p.newError("unterminated quoted scalar"))
+		}
+		switch b := p.buf[p.pos]; b {
+		case '\'':
+			p.pos++
+			if p.pos < len(p.buf) && p.buf[p.pos] == '\'' {
+				text = append(text, '\'')
+				p.pos++
+				continue
+			}
+			return string(text)
+		case '\n', '\r':
+			text = p.fold(text, 0)
+		default:
+			text = append(text, b)
+			p.pos++
+		}
+	}
+}
+
+func (p *Parser) doubleQuoted() string {
+	p.pos++ // past the opening quote
+	var text []byte
+	keep := 0
+	for {
+		if len(p.buf) <= p.pos {
+			panic(p.newError("unterminated quoted scalar"))
+		}
+		b := p.buf[p.pos]
+		switch b {
+		case '"':
+			p.pos++
+			return string(text)
+		case '\n', '\r':
+			text = p.fold(text, keep)
+			continue
+		case '\\':
+			// handled below
+		default:
+			text = append(text, b)
+			p.pos++
+			continue
+		}
+		p.pos++
+		if len(p.buf) <= p.pos {
+			panic(p.newError("unterminated quoted scalar"))
+		}
+		e := p.buf[p.pos]
+		p.pos++
+		switch e {
+		case '\n', '\r':
+			// An escaped line break is removed along with the leading white
+			// space of the next line.
+			p.pos--
+			p.newline()
+			p.skipSpace()
+		case '0':
+			text = append(text, 0)
+		case 'a':
+			text = append(text, '\a')
+		case 'b':
+			text = append(text, '\b')
+		case 't', '\t':
+			text = append(text, '\t')
+		case 'n':
+			text = append(text, '\n')
+		case 'v':
+			text = append(text, '\v')
+		case 'f':
+			text = append(text, '\f')
+		case 'r':
+			text = append(text, '\r')
+		case 'e':
+			text = append(text, 0x1b)
+		case ' ', '"', '/', '\\':
+			text = append(text, e)
+		case 'N':
+			text = utf8.AppendRune(text, 0x85)
+		case '_':
+			text = utf8.AppendRune(text, 0xa0)
+		case 'L':
+			text = utf8.AppendRune(text, 0x2028)
+		case 'P':
+			text = utf8.AppendRune(text, 0x2029)
+		case 'x':
+			text = utf8.AppendRune(text, p.hexRune(2))
+		case 'u':
+			text = utf8.AppendRune(text, p.hexRune(4))
+		case 'U':
+			text = utf8.AppendRune(text, p.hexRune(8))
+		default:
+			p.pos--
+			panic(p.newError("invalid escape character %q", e))
+		}
+		keep = len(text)
+	}
+}
+
+func (p *Parser) hexRune(n int) rune {
+	if len(p.buf) < p.pos+n {
+		panic(p.newError("invalid escape sequence"))
+	}
+	r, err := strconv.ParseUint(string(p.buf[p.pos:p.pos+n]), 16, 32)
+	if err != nil {
+		panic(p.newError("invalid escape sequence"))
+	}
+	p.pos += n
+
+	return rune(r)
+}
+
+// fold replaces the line breaks in a quoted scalar, starting at the
+// current position, with a space if there is only one or with one less
+// newline if there are more. Trailing white space, other than that
+// preserved by an escape which ends at keep, is removed as is the leading
+// white space on the following lines.
+func (p *Parser) fold(text []byte, keep int) []byte {
+	for keep < len(text) && (text[len(text)-1] == ' ' || text[len(text)-1] == '\t') {
+		text = text[:len(text)-1]
+	}
+	breaks := 0
+	for p.pos < len(p.buf) && (p.buf[p.pos] == '\n' || p.buf[p.pos] == '\r') {
+		p.newline()
+		breaks++
+		p.skipSpace()
+	}
+	if p.atDocMarker() {
+		panic(p.newError("unterminated quoted scalar"))
+	}
+	if breaks == 1 {
+		return append(text, ' ')
+	}
+	for ; 1 < breaks; breaks-- {
+		text = append(text, '\n')
+	}
+	return text
+}
+
+// blockScalar reads a literal (|) or folded (>) block scalar. The content
+// must be indented more than the indent.
+func (p *Parser) blockScalar(indent int, tag string) any {
+	folded := p.buf[p.pos] == '>'
+	p.pos++
+	var chomp byte
+	ci := -1
+	for i := 0; i < 2 && p.pos < len(p.buf); i++ {
+		switch b := p.buf[p.pos]; {
+		case (b == '-' || b == '+') && chomp == 0:
+			chomp = b
+			p.pos++
+		case '1' <= b && b <= '9' && ci < 0:
+			ci = indent + int(b-'0')
+			p.pos++
+		}
+	}
+	p.endLine()
+	if p.pos < len(p.buf) {
+		p.newline()
+	}
+	var text []byte
+	breaks := 0
+	started := false
+	prevMore := false
+	for p.pos < len(p.buf) {
+		start := p.pos
+		n := 0
+		for p.pos < len(p.buf) && p.buf[p.pos] == ' ' {
+			p.pos++
+			n++
+		}
+		if p.atEOL() && (ci < 0 || n <= ci) {
+			if len(p.buf) <= p.pos {
+				break
+			}
+			p.newline()
+			breaks++
+			continue
+		}
+		if ci < 0 {
+			if n <= indent {
+				p.pos = start
+				break
+			}
+			ci = n
+		}
+		if n < ci || (n == 0 && p.atDocMarker()) {
+			p.pos = start
+			break
+		}
+		p.pos = start + ci
+		for !p.atEOL() {
+			p.pos++
+		}
+		content := p.buf[start+ci : p.pos]
+		more := 0 < len(content) && (content[0] == ' ' || content[0] == '\t')
+		switch {
+		case !started:
+			text = appendBreaks(text, breaks)
+		case folded && !more && !prevMore && breaks == 1:
+			text = append(text, ' ')
+		case folded && !more && !prevMore:
+			text = appendBreaks(text, breaks-1)
+		default:
+			text = appendBreaks(text, breaks)
+		}
+		text = append(text, content...)
+		started = true
+		prevMore = more
+		// The end of the input is taken as the line break of the last line.
+		breaks = 1
+		if p.pos < len(p.buf) {
+			p.newline()
+		}
+	}
+	switch chomp {
+	case '-':
+		// strip the final line break and trailing empty lines
+	case '+':
+		text = appendBreaks(text, breaks)
+	default:
+		if started && 0 < breaks {
+			text = append(text, '\n')
+		}
+	}
+	return p.scalar(string(text), false, tag)
+}
+
+func appendBreaks(text []byte, n int) []byte {
+	for ; 0 < n; n-- {
+		text = append(text, '\n')
+	}
+	return text
+}
+
+func (p *Parser) flowValue(depth int) (v any) {
+	p.checkDepth(depth)
+	p.skipFlowBlank()
+	anchor, tag := p.properties()
+	p.skipFlowBlank()
+	if len(p.buf) <= p.pos {
+		panic(p.newError("unterminated flow collection"))
+	}
+	switch p.buf[p.pos] {
+	case '[':
+		v = p.flowSeq(depth)
+	case '{':
+		v = p.flowMap(depth)
+	case '*':
+		v = p.alias()
+	case '\'':
+		v = p.scalar(p.singleQuoted(), false, tag)
+	case '"':
+		v = p.scalar(p.doubleQuoted(), false, tag)
+	case ',', ']', '}':
+		v = p.scalar("", true, tag)
+	default:
+		v = p.scalar(p.plain(-1, true), true, tag)
+	}
+	if 0 < len(anchor) {
+		p.anchors[anchor] = v
+	}
+	return
+}
+
+func (p *Parser) flowSeq(depth int) any {
+	p.pos++ // past the [
+	list := []any{}
+	for {
+		p.skipFlowBlank()
+		if len(p.buf) <= p.pos {
+			panic(p.newError("unterminated flow sequence"))
+		}
+		switch p.buf[p.pos] {
+		case ']':
+			p.pos++
+			return p.array(list)
+		case ',':
+			panic(p.newError("unexpected ','"))
+		}
+		list = append(list, p.flowValue(depth+1))
+		p.skipFlowBlank()
+		if len(p.buf) <= p.pos {
+			panic(p.newError("unterminated flow sequence"))
+		}
+		switch p.buf[p.pos] {
+		case ',':
+			p.pos++
+		case ']':
+		default:
+			panic(p.newError("expected a ',' or ']'"))
+		}
+	}
+}
+
+func (p *Parser) flowMap(depth int) any {
+	p.pos++ // past the {
+	obj := map[string]any{}
+	for {
+		p.skipFlowBlank()
+		if len(p.buf) <= p.pos {
+			panic(p.newError("unterminated flow mapping"))
+		}
+		if p.buf[p.pos] == '}' {
+			p.pos++
+			return p.object(obj)
+		}
+		k := p.flowKey()
+		if _, has := obj[k]; has {
+			panic(p.newError("duplicate key %q", k))
+		}
+		p.skipFlowBlank()
+		var v any
+		if p.pos < len(p.buf) && p.buf[p.pos] == ':' {
+			p.pos++
+			v = p.flowValue(depth + 1)
+			p.skipFlowBlank()
+		}
+		obj[k] = v
+		if len(p.buf) <= p.pos {
+			panic(p.newError("unterminated flow mapping"))
+		}
+		switch p.buf[p.pos] {
+		case ',':
+			p.pos++
+		case '}':
+		default:
+			panic(p.newError("expected a ',' or '}'"))
+		}
+	}
+}
+
+func (p *Parser) flowKey() (k string) {
+	anchor, _ := p.properties()
+	switch p.buf[p.pos] {
+	case '\'':
+		k = p.singleQuoted()
+	case '"':
+		k = p.doubleQuoted()
+	case '[', '{':
+		panic(p.newError("only scalar keys are supported"))
+	case '?':
+		panic(p.newError("explicit keys are not supported"))
+	default:
+		p.checkPlainStart(true)
+		k = string(p.plainLine(true))
+	}
+	if 0 < len(anchor) {
+		p.anchors[anchor] = p.scalar(k, false, "")
+	}
+	return
+}
+
+// scalar returns the value of a scalar. Plain scalars are resolved
+// according to the core schema unless a standard tag is provided.
+func (p *Parser) scalar(s string, plain bool, tag string) (v any) {
+	switch tag {
+	case "!!str":
+		v = s
+	case "!!null":
+		if v = resolve(s); v != nil {
+			panic(p.newError("%q is not a valid %s", s, tag))
+		}
+	case "!!bool":
+		if v = resolve(s); !isBool(v) {
+			panic(p.newError("%q is not a valid %s", s, tag))
+		}
+	case "!!int":
+		switch v = resolve(s); v.(type) {
+		case int64, json.Number:
+		default:
+			panic(p.newError("%q is not a valid %s", s, tag))
+		}
+	case "!!float":
+		switch tv := resolve(s).(type) {
+		case int64:
+			v = float64(tv)
+		case float64:
+			v = tv
+		case json.Number:
+			v, _ = strconv.ParseFloat(string(tv), 64)
+		default:
+			panic(p.newError("%q is not a valid %s", s, tag))
+		}
+	default:
+		if plain {
+			v = resolve(s)
+		} else {
+			v = s
+		}
+	}
+	if p.node {
+		switch tv := v.(type) {
+		case bool:
+			v = gen.Bool(tv)
+		case int64:
+			v = gen.Int(tv)
+		case float64:
+			v = gen.Float(tv)
+		case json.Number:
+			v = gen.Big(tv)
+		case string:
+			v = gen.String(tv)
+		}
+	}
+	return
+}
+
+func isBool(v any) bool {
+	_, ok := v.(bool)
+	return ok
+}
+
+// resolve a plain scalar according to the YAML 1.2 core schema.
+func resolve(s string) any {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	case ".inf", ".Inf", ".INF", "+.inf", "+.Inf", "+.INF":
+		return math.Inf(1)
+	case "-.inf", "-.Inf", "-.INF":
+		return math.Inf(-1)
+	case ".nan", ".NaN", ".NAN":
+		return math.NaN()
+	}
+	if b := s[0]; b == '-' || b == '+' || b == '.' || ('0' <= b && b <= '9') {
+		if v := resolveNumber(s); v != nil {
+			return v
+		}
+	}
+	return s
+}
+
+func resolveNumber(s string) any {
+	if 2 < len(s) && s[0] == '0' {
+		switch s[1] {
+		case 'o':
+			return parseBase(s[2:], 8)
+		case 'x':
+			return parseBase(s[2:], 16)
+		}
+	}
+	digits := s
+	if digits[0] == '-' || digits[0] == '+' {
+		digits = digits[1:]
+	}
+	if isDigits(digits, 10) {
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i
+		}
+		return json.Number(strings.TrimPrefix(s, "+"))
+	}
+	if isFloat(digits) {
+		f, _ := strconv.ParseFloat(s, 64)
+		return f
+	}
+	return nil
+}
+
+func parseBase(digits string, base int) any {
+	if !isDigits(digits, base) {
+		return nil
+	}
+	if i, err := strconv.ParseInt(digits, base, 64); err == nil {
+		return i
+	}
+	var bi big.Int
+	bi.SetString(digits, base)
+
+	return json.Number(bi.String())
+}
+
+func isDigits(s string, base int) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for _, b := range []byte(s) {
+		switch {
+		case '0' <= b && b <= '7':
+		case '8' <= b && b <= '9':
+			if base < 10 {
+				return false
+			}
+		case ('a' <= b && b <= 'f') || ('A' <= b && b <= 'F'):
+			if base < 16 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isFloat returns true if the unsigned s matches the core schema float
+// pattern of (\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?.
+func isFloat(s string) bool {
+	i := skipDigits(s, 0)
+	whole := i
+	if i < len(s) && s[i] == '.' {
+		i++
+		start := i
+		if i = skipDigits(s, i); whole == 0 && i == start {
+			return false
+		}
+	} else if whole == 0 {
+		return false
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '-' || s[i] == '+') {
+			i++
+		}
+		start := i
+		if i = skipDigits(s, i); i == start {
+			return false
+		}
+	}
+	return i == len(s)
+}
+
+func skipDigits(s string, i int) int {
+	for i < len(s) && '0' <= s[i] && s[i] <= '9' {
+		i++
+	}
+	return i
+}
+
+func (p *Parser) array(list []any) any {
+	if !p.node {
+		return list
+	}
+	a := make(gen.Array, len(list))
+	for i, v := range list {
+		a[i], _ = v.(gen.Node)
+	}
+	return a
+}
+
+func (p *Parser) object(obj map[string]any) any {
+	if !p.node {
+		return obj
+	}
+	o := make(gen.Object, len(obj))
+	for k, v := range obj {
+		o[k], _ = v.(gen.Node)
+	}
+	return o
+}
+
+func (p *Parser) col() int {
+	return p.pos - p.lineStart
+}
+
+func (p *Parser) newline() {
+	if p.buf[p.pos] == '\r' {
+		p.pos++
+		if p.pos < len(p.buf) && p.buf[p.pos] == '\n' {
+			p.pos++
+		}
+	} else {
+		p.pos++
+	}
+	p.line++
+	p.lineStart = p.pos
+}
+
+func (p *Parser) atEOL() bool {
+	return len(p.buf) <= p.pos || p.buf[p.pos] == '\n' || p.buf[p.pos] == '\r'
+}
+
+func (p *Parser) blankAt(i int) bool {
+	if len(p.buf) <= i {
+		return true
+	}
+	switch p.buf[i] {
+	case ' ', '\t', '\n', '\r':
+		return true
+	}
+	return false
+}
+
+func (p *Parser) flowIndicatorAt(i int) bool {
+	if len(p.buf) <= i {
+		return false
+	}
+	switch p.buf[i] {
+	case ',', '[', ']', '{', '}':
+		return true
+	}
+	return false
+}
+
+// atEntry returns true if at a block sequence entry indicator.
+func (p *Parser) atEntry() bool {
+	return p.pos < len(p.buf) && p.buf[p.pos] == '-' && p.blankAt(p.pos+1)
+}
+
+func (p *Parser) atMarker(marker string) bool {
+	return p.pos == p.lineStart && bytes.HasPrefix(p.buf[p.pos:], []byte(marker)) && p.blankAt(p.pos+3)
+}
+
+func (p *Parser) atDocMarker() bool {
+	return p.atMarker("---") || p.atMarker("...")
+}
+
+func (p *Parser) skipSpace() {
+	for p.pos < len(p.buf) && (p.buf[p.pos] == ' ' || p.buf[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *Parser) skipComment() {
+	if p.pos < len(p.buf) && p.buf[p.pos] == '#' {
+		p.skipLine()
+	}
+}
+
+func (p *Parser) skipLine() {
+	for !p.atEOL() {
+		p.pos++
+	}
+}
+
+// endLine verifies only white space and a comment remain on the line.
+func (p *Parser) endLine() {
+	p.skipSpace()
+	p.skipComment()
+	if !p.atEOL() {
+		panic(p.newError("unexpected character %q", p.buf[p.pos]))
+	}
+}
+
+// skipBlank skips white space, comments, and line breaks and leaves the
+// position on the next content character.
+func (p *Parser) skipBlank() {
+	crossed := p.pos == p.lineStart
+	for p.pos < len(p.buf) {
+		switch p.buf[p.pos] {
+		case ' ', '\t':
+			p.pos++
+		case '\n', '\r':
+			p.newline()
+			crossed = true
+		case '#':
+			p.skipLine()
+		default:
+			if crossed && 0 <= bytes.IndexByte(p.buf[p.lineStart:p.pos], '\t') {
+				panic(p.newError("tabs are not allowed for indentation"))
+			}
+			return
+		}
+	}
+}
+
+// skipFlowBlank skips white space, comments, and line breaks in a flow
+// collection.
+func (p *Parser) skipFlowBlank() {
+	for p.pos < len(p.buf) {
+		switch p.buf[p.pos] {
+		case ' ', '\t':
+			p.pos++
+		case '\n', '\r':
+			p.newline()
+		case '#':
+			p.skipLine()
+		default:
+			return
+		}
+	}
+}
+
+func (p *Parser) checkDepth(depth int) {
+	if maxDepth <= depth {
+		panic(p.newError("too deeply nested"))
+	}
+}
+
+func (p *Parser) newError(format string, args ...any) error {
+	return &oj.ParseError{
+		Message: fmt.Sprintf(format, args...),
+		Line:    p.line,
+		Column:  p.pos - p.lineStart + 1,
+	}
+}
@@ -0,0 +1,386 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package yaml_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khaf/ojg/alt"
+	"github.com/khaf/ojg/gen"
+	"github.com/khaf/ojg/oj"
+	"github.com/khaf/ojg/tt"
+	"github.com/khaf/ojg/yaml"
+)
+
+type errReader struct{}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, errors.New("failed")
+}
+
+func TestParseScalars(t *testing.T) {
+	for _, d := range []struct {
+		src    string
+		expect any
+	}{
+		{src: "", expect: nil},
+		{src: "~", expect: nil},
+		{src: "Null", expect: nil},
+		{src: "TRUE", expect: true},
+		{src: "false", expect: false},
+		{src: "123", expect: int64(123)},
+		{src: "-17", expect: int64(-17)},
+		{src: "+7", expect: int64(7)},
+		{src: "0o17", expect: int64(15)},
+		{src: "0x1F", expect: int64(31)},
+		{src: "0xffffffffffffffff", expect: json.Number("18446744073709551615")},
+		{src: "123456789012345678901234", expect: json.Number("123456789012345678901234")},
+		{src: "1.5", expect: 1.5},
+		{src: "-.5", expect: -0.5},
+		{src: "1.", expect: 1.0},
+		{src: "1e3", expect: 1000.0},
+		{src: "2.5E-1", expect: 0.25},
+		{src: ".inf", expect: math.Inf(1)},
+		{src: "-.Inf", expect: math.Inf(-1)},
+		{src: "abc", expect: "abc"},
+		{src: "yes", expect: "yes"},
+		{src: "0x", expect: "0x"},
+		{src: "0o8", expect: "0o8"},
+		{src: "1_000", expect: "1_000"},
+		{src: "1.2.3", expect: "1.2.3"},
+		{src: "e3", expect: "e3"},
+		{src: "1e", expect: "1e"},
+		{src: "-", expect: []any{nil}},
+		{src: "http://example.com/a#b", expect: "http://example.com/a#b"},
+		{src: "a long\n  plain\n\n  scalar", expect: "a long plain\nscalar"},
+		{src: "'it''s'", expect: "it's"},
+		{src: "'a\n  b\n\n  c '", expect: "a b\nc "},
+		{src: `"\t\n\\\"\/\x41\u00e9\U0001F600\0\a\b\v\f\r\e\ \N\_\L\P"`,
+			expect: "\t\n\\\"/Aé😀\x00\a\b\v\f\r\x1b \u0085\u00a0\u2028\u2029"},
+		{src: "\"a \\\n  b\"", expect: "a b"},
+		{src: "\"a\\t\n  b\"", expect: "a\t b"},
+		{src: "!!str 123", expect: "123"},
+		{src: "!!str", expect: ""},
+		{src: "!!int \"12\"", expect: int64(12)},
+		{src: "!!float 3", expect: 3.0},
+		{src: "!!float 1.5", expect: 1.5},
+		{src: "!!float 123456789012345678901234", expect: 1.2345678901234568e+23},
+		{src: "!!bool 'true'", expect: true},
+		{src: "!!null ''", expect: nil},
+		{src: "!<tag:yaml.org,2002:str> 1", expect: "1"},
+		{src: "!custom 1", expect: int64(1)},
+		{src: "\xef\xbb\xbfx", expect: "x"},
+	} {
+		v, err := yaml.Parse([]byte(d.src))
+		tt.Nil(t, err, d.src)
+		tt.Equal(t, d.expect, v, d.src)
+	}
+	v, err := yaml.Parse([]byte(".nan"))
+	tt.Nil(t, err)
+	f, _ := v.(float64)
+	tt.Equal(t, true, math.IsNaN(f))
+}
+
+func TestParseBlock(t *testing.T) {
+	src := `# config
+name: sample   # trailing comment
+count: 3
+tags:
+- a
+- b
+nested:
+  list:
+    - x: 1
+      y: [1, 2]
+    - - deep
+      - er
+    -
+      z: null
+  empty:
+  "quoted key": 'v'
+  1: one
+`
+	v, err := yaml.Parse([]byte(src))
+	tt.Nil(t, err)
+	tt.Equal(t, map[string]any{
+		"name":  "sample",
+		"count": int64(3),
+		"tags":  []any{"a", "b"},
+		"nested": map[string]any{
+			"list": []any{
+				map[string]any{"x": int64(1), "y": []any{int64(1), int64(2)}},
+				[]any{"deep", "er"},
+				map[string]any{"z": nil},
+			},
+			"empty":      nil,
+			"quoted key": "v",
+			"1":          "one",
+		},
+	}, v)
+
+	v, err = yaml.Parse([]byte("- a\n-\n- - b\n  - c\n-   d: 1\n    e: 2\n"))
+	tt.Nil(t, err)
+	tt.Equal(t, []any{"a", nil, []any{"b", "c"}, map[string]any{"d": int64(1), "e": int64(2)}}, v)
+}
+
+func TestParseBlockScalar(t *testing.T) {
+	for _, d := range []struct {
+		src    string
+		expect string
+	}{
+		{src: "x: |\n  a\n   b\n\n  c\ny: 1\n", expect: "a\n b\n\nc\n"},
+		{src: "x: |-\n  a\n  b\n\n", expect: "a\nb"},
+		{src: "x: |+\n  a\n\n\ny: 1\n", expect: "a\n\n\n"},
+		{src: "x: |2\n    a\n  b\n", expect: "  a\nb\n"},
+		{src: "x: |-2\n    a\n", expect: "  a"},
+		{src: "x: >\n  a\n  b\n\n  c\n    d\n  e\n", expect: "a b\nc\n  d\ne\n"},
+		{src: "x: >-\n\n  a\n  b\n", expect: "\na b"},
+		{src: "x: |\n  a", expect: "a\n"},
+		{src: "x: |\n  a\n     \n  b\n", expect: "a\n   \nb\n"},
+		{src: "x: |\ny: 1\n", expect: ""},
+		{src: "x: | # comment\n  a\n# other\n", expect: "a\n"},
+	} {
+		v, err := yaml.Parse([]byte(d.src))
+		tt.Nil(t, err, d.src)
+		tt.Equal(t, d.expect, v.(map[string]any)["x"], d.src)
+	}
+	v, err := yaml.Parse([]byte("--- |\n  top\n...\n"))
+	tt.Nil(t, err)
+	tt.Equal(t, "top\n", v)
+}
+
+func TestParseFlow(t *testing.T) {
+	v, err := yaml.Parse([]byte(`{"a":1,"b":[true,null,{"c":"d"}],"e":-2.5}`))
+	tt.Nil(t, err)
+	tt.Equal(t, map[string]any{"a": int64(1), "b": []any{true, nil, map[string]any{"c": "d"}}, "e": -2.5}, v)
+
+	v, err = yaml.Parse([]byte("{a: [x, y z], b, c: , 'd': \"e\", # comment\n  f:\n   g\n}"))
+	tt.Nil(t, err)
+	tt.Equal(t, map[string]any{"a": []any{"x", "y z"}, "b": nil, "c": nil, "d": "e", "f": "g"}, v)
+
+	v, err = yaml.Parse([]byte("[a:b, http://x, [], {}, ]"))
+	tt.Nil(t, err)
+	tt.Equal(t, []any{"a:b", "http://x", []any{}, map[string]any{}}, v)
+}
+
+func TestParseAnchors(t *testing.T) {
+	src := `base: &base
+  x: 1
+copy: *base
+list: [&v 3, *v]
+&k key: *k
+`
+	v, err := yaml.Parse([]byte(src))
+	tt.Nil(t, err)
+	tt.Equal(t, map[string]any{
+		"base": map[string]any{"x": int64(1)},
+		"copy": map[string]any{"x": int64(1)},
+		"list": []any{int64(3), int64(3)},
+		"key":  "key",
+	}, v)
+
+	// Aliases are copies so changing one does not change the anchor.
+	m := v.(map[string]any)
+	m["copy"].(map[string]any)["x"] = int64(2)
+	tt.Equal(t, int64(1), m["base"].(map[string]any)["x"])
+
+	// Anchors do not carry across documents.
+	_, err = yaml.Parse([]byte("--- &a 1\n--- *a\n"), func(any) {})
+	tt.NotNil(t, err)
+}
+
+func TestParseAliasLimit(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("a: &a [x, x, x, x, x, x, x, x, x, x]\n")
+	prev := "a"
+	for _, name := range []string{"b", "c", "d", "e", "f", "g", "h"} {
+		fmt.Fprintf(&b, "%s: &%s [*%s, *%s, *%s, *%s, *%s, *%s, *%s, *%s, *%s, *%s]\n",
+			name, name, prev, prev, prev, prev, prev, prev, prev, prev, prev, prev)
+		prev = name
+	}
+	_, err := yaml.Parse([]byte(b.String()))
+	tt.NotNil(t, err)
+	tt.Equal(t, true, strings.Contains(err.Error(), "aliases expand to more than"), err.Error())
+
+	_, err = yaml.ParseNode([]byte(b.String()))
+	tt.NotNil(t, err)
+
+	// Each document has its own limit.
+	doc := "--- {a: &a [1, 2], b: *a, c: *a}\n"
+	var docs []any
+	_, err = yaml.Parse([]byte(strings.Repeat(doc, 3)), func(v any) { docs = append(docs, v) })
+	tt.Nil(t, err)
+	tt.Equal(t, 3, len(docs))
+}
+
+func TestParseStream(t *testing.T) {
+	src := "%YAML 1.2\n---\na: 1\n...\nb\n--- [c]\n---\n--- |\n  d\n...\n"
+	var docs []any
+	_, err := yaml.Parse([]byte(src), func(v any) bool { docs = append(docs, v); return false })
+	tt.Nil(t, err)
+	tt.Equal(t, []any{map[string]any{"a": int64(1)}, "b", []any{"c"}, nil, "d\n"}, docs)
+
+	docs = docs[:0]
+	_, err = yaml.Load(bytes.NewReader([]byte(src)), func(v any) { docs = append(docs, v) })
+	tt.Nil(t, err)
+	tt.Equal(t, 5, len(docs))
+
+	ch := make(chan any, 5)
+	_, err = yaml.Parse([]byte(src), ch)
+	tt.Nil(t, err)
+	tt.Equal(t, map[string]any{"a": int64(1)}, <-ch)
+
+	v, err := yaml.Load(bytes.NewReader([]byte("- 1\n")))
+	tt.Nil(t, err)
+	tt.Equal(t, []any{int64(1)}, v)
+	tt.Equal(t, []any{int64(1)}, yaml.MustLoad(bytes.NewReader([]byte("- 1\n"))))
+	tt.Equal(t, []any{int64(1)}, yaml.MustParse([]byte("- 1\n")))
+
+	// Without a callback only one document is allowed.
+	_, err = yaml.Parse([]byte(src))
+	tt.NotNil(t, err)
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, d := range []struct {
+		src    string
+		expect string
+	}{
+		{src: "a: b: c", expect: "unexpected character ':' at 1:5"},
+		{src: "a: 1\na: 2", expect: "duplicate key \"a\" at 2:2"},
+		{src: "{a: 1, a: 2}", expect: "duplicate key"},
+		{src: "a:\n\t- b", expect: "tabs are not allowed"},
+		{src: "\ta: 1", expect: "tabs are not allowed for indentation at 1:2"},
+		{src: "\t- a", expect: "tabs are not allowed for indentation at 1:2"},
+		{src: "# c\n\ta: 1", expect: "tabs are not allowed for indentation at 2:2"},
+		{src: "a: 1\n  b: 2", expect: "unexpected character ':'"},
+		{src: "a:\n  b: 1\n c: 2", expect: "bad indentation"},
+		{src: "- a\n  - b: c", expect: "unexpected character ':'"},
+		{src: "- [a]\n  - b", expect: "bad indentation"},
+		{src: "a: 1\n- b", expect: "expected a mapping key"},
+		{src: "- a\nb", expect: "unexpected character 'b'"},
+		{src: "? a\n: b", expect: "explicit keys"},
+		{src: "{? a}", expect: "explicit keys"},
+		{src: "{[a]: b}", expect: "scalar keys"},
+		{src: "[a]: b", expect: "unexpected character ':'"},
+		{src: "? [a]\n: b", expect: "explicit keys"},
+		{src: "[a, b", expect: "unterminated flow sequence"},
+		{src: "[a,, b]", expect: "unexpected ','"},
+		{src: "[a b: c]", expect: "expected a ',' or ']'"},
+		{src: "{a: b", expect: "unterminated flow mapping"},
+		{src: "{a: b c: d}", expect: "expected a ',' or '}'"},
+		{src: "{a: [", expect: "unterminated flow"},
+		{src: "'abc", expect: "unterminated quoted scalar"},
+		{src: "\"abc", expect: "unterminated quoted scalar"},
+		{src: "\"abc\\", expect: "unterminated quoted scalar"},
+		{src: "\"a\n---\nb\"", expect: "unterminated quoted scalar"},
+		{src: `"\q"`, expect: "invalid escape character"},
+		{src: `"\u12"`, expect: "invalid escape sequence"},
+		{src: `"\uxyz1"`, expect: "invalid escape sequence"},
+		{src: "*a", expect: "unknown alias \"a\""},
+		{src: "&", expect: "missing anchor name"},
+		{src: "&a &b x", expect: "one anchor"},
+		{src: "!a !b x", expect: "one tag"},
+		{src: "!<abc x", expect: "unterminated verbatim tag"},
+		{src: "!!int x", expect: "not a valid !!int"},
+		{src: "!!float x", expect: "not a valid !!float"},
+		{src: "!!bool 1", expect: "not a valid !!bool"},
+		{src: "!!null x", expect: "not a valid !!null"},
+		{src: "@x", expect: "unexpected character '@'"},
+		{src: "a: - b", expect: "unexpected character '-'"},
+		{src: "a: |x\n  b", expect: "unexpected character 'x'"},
+		{src: "%YAML 1.2\n", expect: "directive without a document"},
+		{src: "%YAML 1.2\na: 1", expect: "expected a document start"},
+		{src: "a\n--- b", expect: "more than one document"},
+		{src: "--- a\n... b", expect: "unexpected character 'b'"},
+		{src: strings.Repeat("[", 10001), expect: "too deeply nested"},
+		{src: strings.Repeat("- ", 10001), expect: "too deeply nested"},
+	} {
+		_, err := yaml.Parse([]byte(d.src))
+		tt.NotNil(t, err, d.src)
+		if !strings.Contains(err.Error(), d.expect) {
+			t.Log(d.src, err)
+		}
+		tt.Equal(t, true, strings.Contains(err.Error(), d.expect), d.src, ": ", err)
+		_, err = yaml.ParseNode([]byte(d.src))
+		tt.NotNil(t, err, d.src)
+	}
+	_, err := yaml.Parse([]byte("a:\n  b: 1\n c: 2"))
+	var pe *oj.ParseError
+	tt.Equal(t, true, errors.As(err, &pe))
+	tt.Equal(t, 3, pe.Line)
+	tt.Equal(t, 2, pe.Column)
+
+	_, err = yaml.Parse([]byte("x"), 7)
+	tt.NotNil(t, err)
+	_, err = yaml.Load(errReader{})
+	tt.NotNil(t, err)
+	tt.Panic(t, func() { yaml.MustParse([]byte("[")) })
+	tt.Panic(t, func() { yaml.MustLoad(bytes.NewReader([]byte("["))) })
+}
+
+func TestParseNode(t *testing.T) {
+	src := `a: [-1, 123456789012345678901234, null]
+b: 1.5
+c: x
+d: {e: true}
+f: &f [1]
+g: *f
+`
+	n, err := yaml.ParseNode([]byte(src))
+	tt.Nil(t, err)
+	tt.Equal(t, gen.Object{
+		"a": gen.Array{gen.Int(-1), gen.Big("123456789012345678901234"), nil},
+		"b": gen.Float(1.5),
+		"c": gen.String("x"),
+		"d": gen.Object{"e": gen.Bool(true)},
+		"f": gen.Array{gen.Int(1)},
+		"g": gen.Array{gen.Int(1)},
+	}, n)
+
+	n, err = yaml.ParseNode([]byte(""))
+	tt.Nil(t, err)
+	tt.Nil(t, n)
+}
+
+type sample struct {
+	Name  string
+	Count int
+	When  time.Time
+	Tags  []string
+	Ptr   *sample
+	Other any
+}
+
+func TestUnmarshal(t *testing.T) {
+	when := time.Date(2023, 1, 2, 3, 4, 5, 6, time.UTC)
+	src := &sample{Name: "x", Count: 2, When: when, Tags: []string{"a"}, Ptr: &sample{Name: "y"}, Other: []any{1.5}}
+	out, err := yaml.Marshal(src)
+	tt.Nil(t, err)
+
+	var s sample
+	err = yaml.Unmarshal(out, &s)
+	tt.Nil(t, err)
+	tt.Equal(t, "x", s.Name)
+	tt.Equal(t, 2, s.Count)
+	tt.Equal(t, when, s.When)
+	tt.Equal(t, []string{"a"}, s.Tags)
+	tt.Equal(t, "y", s.Ptr.Name)
+	tt.Equal(t, []any{1.5}, s.Other)
+
+	var p yaml.Parser
+	var s2 sample
+	err = p.Unmarshal(out, &s2, &alt.DefaultRecomposer)
+	tt.Nil(t, err)
+	tt.Equal(t, "x", s2.Name)
+
+	err = yaml.Unmarshal([]byte("["), &s)
+	tt.NotNil(t, err)
+}
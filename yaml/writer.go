@@ -0,0 +1,519 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package yaml
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/alt"
+	"github.com/khaf/ojg/gen"
+)
+
+// Writer is a YAML writer that includes a reused buffer for reduced
+// allocations for repeated encoding calls.
+//
+// An Indent of zero writes the data in flow style on a single line.
+// Otherwise block style is written with at least two spaces of
+// indentation. Every document written ends with a newline. The Tab and
+// color options are ignored.
+type Writer struct {
+	ojg.Options
+	buf    []byte
+	w      io.Writer
+	dopt   ojg.Options
+	indent int
+}
+
+// Marshal data as YAML. The returned slice is a copy and is not reused.
+func (wr *Writer) Marshal(data any) (out []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			wr.buf = wr.buf[:0]
+			err = ojg.NewError(r)
+		}
+	}()
+	wr.MustMarshal(data)
+	out = make([]byte, len(wr.buf))
+	copy(out, wr.buf)
+
+	return
+}
+
+// MustMarshal data as YAML. On error a panic is called with the error. The
+// returned buffer is the Writer buffer and is reused on the next call to
+// write. If returned value is to be preserved past a second invocation then
+// the buffer should be copied.
+func (wr *Writer) MustMarshal(data any) []byte {
+	wr.w = nil
+	wr.prepare()
+	wr.appendDoc(alt.ConvertForWrite(data, &wr.Options))
+
+	return wr.buf
+}
+
+// Write YAML for the data provided.
+func (wr *Writer) Write(w io.Writer, data any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			wr.buf = wr.buf[:0]
+			err = ojg.NewError(r)
+		}
+	}()
+	wr.MustWrite(w, data)
+	return
+}
+
+// MustWrite YAML for the data provided. If an error occurs panic is called
+// with the error.
+func (wr *Writer) MustWrite(w io.Writer, data any) {
+	wr.w = w
+	if wr.WriteLimit <= 0 {
+		wr.WriteLimit = 1024
+	}
+	wr.prepare()
+	wr.appendDoc(alt.ConvertForWrite(data, &wr.Options))
+	if 0 < len(wr.buf) {
+		if _, err := wr.w.Write(wr.buf); err != nil {
+			panic(err)
+		}
+		wr.buf = wr.buf[:0]
+	}
+}
+
+func (wr *Writer) prepare() {
+	if wr.InitSize <= 0 {
+		wr.InitSize = 256
+	}
+	if cap(wr.buf) < wr.InitSize {
+		wr.buf = make([]byte, 0, wr.InitSize)
+	} else {
+		wr.buf = wr.buf[:0]
+	}
+	switch {
+	case wr.Indent <= 0:
+		wr.indent = 0
+	case wr.Indent < 2:
+		// A sequence entry indicator must be followed by a space so two is
+		// the minimum block indentation.
+		wr.indent = 2
+	default:
+		wr.indent = wr.Indent
+	}
+	wr.dopt = wr.Options
+	wr.dopt.Converter = nil
+}
+
+// appendDoc appends a document followed by a newline.
+func (wr *Writer) appendDoc(v any) {
+	if wr.indent == 0 {
+		wr.appendFlow(v)
+	} else {
+		wr.appendBlock(wr.normalize(v))
+	}
+	wr.buf = append(wr.buf, '\n')
+}
+
+func (wr *Writer) appendBlock(v any) {
+	if list, ok := wr.list(v); ok && 0 < len(list) {
+		wr.appendBlockSeq(list, 0, true)
+		return
+	}
+	if keys, vals, ok := wr.members(v); ok && 0 < len(keys) {
+		wr.appendBlockMap(keys, vals, 0, true)
+		return
+	}
+	if s, ok := asString(v); ok {
+		wr.appendString(s, false, 1)
+		return
+	}
+	wr.appendFlow(v)
+}
+
+// appendBlockMap appends the members of a mapping in block style. If inline
+// is true the first member continues the current line.
+func (wr *Writer) appendBlockMap(keys []string, vals []any, depth int, inline bool) {
+	for i, k := range keys {
+		if 0 < i || !inline {
+			wr.newline(depth)
+		}
+		wr.appendKey(k, false)
+		wr.buf = append(wr.buf, ':')
+		wr.appendMember(vals[i], depth+1, false)
+	}
+}
+
+// appendBlockSeq appends the entries of a sequence in block style. If
+// inline is true the first entry continues the current line.
+func (wr *Writer) appendBlockSeq(list []any, depth int, inline bool) {
+	for i, v := range list {
+		if 0 < i || !inline {
+			wr.newline(depth)
+		}
+		wr.buf = append(wr.buf, '-')
+		wr.appendMember(v, depth+1, true)
+	}
+}
+
+// appendMember appends a mapping value or a sequence entry. Non-empty
+// collections in a sequence entry start on the same line as the entry
+// indicator while those of a mapping value start on the next line.
+func (wr *Writer) appendMember(v any, depth int, entry bool) {
+	v = wr.normalize(v)
+	if list, ok := wr.list(v); ok && 0 < len(list) {
+		if entry {
+			wr.pad()
+		}
+		wr.appendBlockSeq(list, depth, entry)
+	} else if keys, vals, ok := wr.members(v); ok && 0 < len(keys) {
+		if entry {
+			wr.pad()
+		}
+		wr.appendBlockMap(keys, vals, depth, entry)
+	} else {
+		if entry {
+			wr.pad()
+		} else {
+			wr.buf = append(wr.buf, ' ')
+		}
+		if s, ok := asString(v); ok {
+			wr.appendString(s, false, depth)
+		} else {
+			wr.appendFlow(v)
+		}
+	}
+	if wr.w != nil && wr.WriteLimit < len(wr.buf) {
+		if _, err := wr.w.Write(wr.buf); err != nil {
+			panic(err)
+		}
+		wr.buf = wr.buf[:0]
+	}
+}
+
+// appendFlow appends a value in flow style.
+func (wr *Writer) appendFlow(v any) {
+	switch tv := wr.normalize(v).(type) {
+	case nil, gen.Null:
+		wr.buf = append(wr.buf, "null"...)
+	case bool:
+		wr.buf = strconv.AppendBool(wr.buf, tv)
+	case gen.Bool:
+		wr.buf = strconv.AppendBool(wr.buf, bool(tv))
+	case int:
+		wr.buf = strconv.AppendInt(wr.buf, int64(tv), 10)
+	case int8:
+		wr.buf = strconv.AppendInt(wr.buf, int64(tv), 10)
+	case int16:
+		wr.buf = strconv.AppendInt(wr.buf, int64(tv), 10)
+	case int32:
+		wr.buf = strconv.AppendInt(wr.buf, int64(tv), 10)
+	case int64:
+		wr.buf = strconv.AppendInt(wr.buf, tv, 10)
+	case gen.Int:
+		wr.buf = strconv.AppendInt(wr.buf, int64(tv), 10)
+	case uint:
+		wr.buf = strconv.AppendUint(wr.buf, uint64(tv), 10)
+	case uint8:
+		wr.buf = strconv.AppendUint(wr.buf, uint64(tv), 10)
+	case uint16:
+		wr.buf = strconv.AppendUint(wr.buf, uint64(tv), 10)
+	case uint32:
+		wr.buf = strconv.AppendUint(wr.buf, uint64(tv), 10)
+	case uint64:
+		wr.buf = strconv.AppendUint(wr.buf, tv, 10)
+	case gen.Uint:
+		wr.buf = strconv.AppendUint(wr.buf, uint64(tv), 10)
+	case float32:
+		wr.appendFloat(float64(tv), 32)
+	case float64:
+		wr.appendFloat(tv, 64)
+	case gen.Float:
+		wr.appendFloat(float64(tv), 64)
+	case json.Number:
+		wr.buf = append(wr.buf, tv...)
+	case gen.Big:
+		wr.buf = append(wr.buf, tv...)
+	case gen.Decimal:
+		wr.buf = append(wr.buf, tv...)
+	case string:
+		wr.appendString(tv, true, -1)
+	case gen.String:
+		wr.appendString(string(tv), true, -1)
+	default:
+		if list, ok := wr.list(tv); ok {
+			wr.buf = append(wr.buf, '[')
+			for i, m := range list {
+				if 0 < i {
+					wr.buf = append(wr.buf, ", "...)
+				}
+				wr.appendFlow(m)
+			}
+			wr.buf = append(wr.buf, ']')
+			return
+		}
+		keys, vals, _ := wr.members(tv)
+		wr.buf = append(wr.buf, '{')
+		for i, k := range keys {
+			if 0 < i {
+				wr.buf = append(wr.buf, ", "...)
+			}
+			wr.appendKey(k, true)
+			wr.buf = append(wr.buf, ": "...)
+			wr.appendFlow(vals[i])
+		}
+		wr.buf = append(wr.buf, '}')
+	}
+}
+
+// normalize returns a value that is either a scalar handled by appendFlow,
+// a slice or gen.Array, or a map[string]any or gen.Object. Times and
+// []byte values are converted according to the options and other values
+// are decomposed.
+func (wr *Writer) normalize(v any) any {
+	switch tv := v.(type) {
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64,
+		float32, float64, string, json.Number, []any, map[string]any,
+		gen.Null, gen.Bool, gen.Int, gen.Uint, gen.Float, gen.String, gen.Big, gen.Decimal,
+		gen.Array, gen.Object:
+		return v
+	case time.Time:
+		return wr.timeValue(tv)
+	case gen.Time:
+		return wr.timeValue(time.Time(tv))
+	case []byte:
+		return wr.bytesValue(tv)
+	case gen.Bytes:
+		return wr.bytesValue(tv)
+	case alt.Simplifier:
+		return wr.normalize(tv.Simplify())
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		panic(fmt.Errorf("%T can not be encoded as a YAML element", v))
+	}
+	return wr.normalize(alt.Decompose(v, &wr.dopt))
+}
+
+// timeValue returns a time encoded according to the TimeFormat option. A
+// TimeFormat of "time" writes times in RFC3339Nano format.
+func (wr *Writer) timeValue(t time.Time) any {
+	if wr.TimeFormat == "time" {
+		return t.Format(time.RFC3339Nano)
+	}
+	return wr.DecomposeTime(t)
+}
+
+func (wr *Writer) bytesValue(b []byte) any {
+	switch wr.BytesAs {
+	case ojg.BytesAsBase64:
+		return base64.StdEncoding.EncodeToString(b)
+	case ojg.BytesAsArray:
+		a := make([]any, len(b))
+		for i, x := range b {
+			a[i] = int64(x)
+		}
+		return a
+	}
+	return string(b)
+}
+
+func (wr *Writer) list(v any) ([]any, bool) {
+	switch tv := v.(type) {
+	case []any:
+		return tv, true
+	case gen.Array:
+		list := make([]any, len(tv))
+		for i, n := range tv {
+			list[i] = n
+		}
+		return list, true
+	}
+	return nil, false
+}
+
+// members returns the keys and values of a mapping in the order they are
+// written.
+func (wr *Writer) members(v any) (keys []string, vals []any, ok bool) {
+	switch tv := v.(type) {
+	case map[string]any:
+		keys = make([]string, 0, len(tv))
+		for k, m := range tv {
+			if m != nil || !wr.OmitNil {
+				keys = append(keys, k)
+			}
+		}
+		if wr.Sort {
+			sort.Strings(keys)
+		}
+		vals = make([]any, len(keys))
+		for i, k := range keys {
+			vals[i] = tv[k]
+		}
+	case gen.Object:
+		keys = make([]string, 0, len(tv))
+		for k, m := range tv {
+			if m != nil || !wr.OmitNil {
+				keys = append(keys, k)
+			}
+		}
+		if wr.Sort {
+			sort.Strings(keys)
+		}
+		vals = make([]any, len(keys))
+		for i, k := range keys {
+			vals[i] = tv[k]
+		}
+	default:
+		return nil, nil, false
+	}
+	return keys, vals, true
+}
+
+func asString(v any) (string, bool) {
+	switch tv := v.(type) {
+	case string:
+		return tv, true
+	case gen.String:
+		return string(tv), true
+	}
+	return "", false
+}
+
+func (wr *Writer) appendKey(k string, flow bool) {
+	if plainSafe(k, flow) {
+		wr.buf = append(wr.buf, k...)
+	} else {
+		wr.buf = ojg.AppendJSONString(wr.buf, k, false)
+	}
+}
+
+// appendString appends a string as a plain scalar if it would be read back
+// as the same string, as a literal block scalar indented to the depth if it
+// is a multiple line string in a block, and as a double quoted scalar
+// otherwise.
+func (wr *Writer) appendString(s string, flow bool, depth int) {
+	switch {
+	case plainSafe(s, flow):
+		wr.buf = append(wr.buf, s...)
+	case !flow && literalSafe(s):
+		wr.appendLiteral(s, depth)
+	default:
+		wr.buf = ojg.AppendJSONString(wr.buf, s, false)
+	}
+}
+
+func (wr *Writer) appendLiteral(s string, depth int) {
+	body := strings.TrimRight(s, "\n")
+	wr.buf = append(wr.buf, '|')
+	if len(s) == len(body) {
+		wr.buf = append(wr.buf, '-')
+	}
+	for _, line := range strings.Split(body, "\n") {
+		if 0 < len(line) {
+			wr.newline(depth)
+			wr.buf = append(wr.buf, line...)
+		} else {
+			wr.buf = append(wr.buf, '\n')
+		}
+	}
+}
+
+func (wr *Writer) appendFloat(f float64, bits int) {
+	switch {
+	case math.IsInf(f, 1):
+		wr.buf = append(wr.buf, ".inf"...)
+	case math.IsInf(f, -1):
+		wr.buf = append(wr.buf, "-.inf"...)
+	case math.IsNaN(f):
+		wr.buf = append(wr.buf, ".nan"...)
+	default:
+		start := len(wr.buf)
+		wr.buf = strconv.AppendFloat(wr.buf, f, 'g', -1, bits)
+		// Make sure the value is read back as a float and not an integer.
+		if bytes.IndexAny(wr.buf[start:], ".e") < 0 {
+			wr.buf = append(wr.buf, ".0"...)
+		}
+	}
+}
+
+func (wr *Writer) newline(depth int) {
+	wr.buf = append(wr.buf, '\n')
+	for i := depth * wr.indent; 0 < i; i-- {
+		wr.buf = append(wr.buf, ' ')
+	}
+}
+
+// pad appends the spaces after a sequence entry indicator.
+func (wr *Writer) pad() {
+	for i := wr.indent - 1; 0 < i; i-- {
+		wr.buf = append(wr.buf, ' ')
+	}
+}
+
+// plainSafe returns true if s can be written as a plain scalar and be read
+// back as the same string.
+func plainSafe(s string, flow bool) bool {
+	if len(s) == 0 || s[0] == ' ' || s[len(s)-1] == ' ' || s[len(s)-1] == ':' ||
+		strings.HasPrefix(s, "...") || !utf8.ValidString(s) {
+		return false
+	}
+	switch s[0] {
+	case ',', '[', ']', '{', '}', '#', '&', '*', '!', '|', '>', '\'', '"', '%', '@', '`', '-', '?', ':':
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		switch b := s[i]; b {
+		case ':':
+			if n := s[i+1]; n == ' ' || (flow && strings.IndexByte(",[]{}", n) != -1) {
+				return false
+			}
+		case '#':
+			if s[i-1] == ' ' {
+				return false
+			}
+		case ',', '[', ']', '{', '}':
+			if flow {
+				return false
+			}
+		default:
+			if b < ' ' || b == 0x7f {
+				return false
+			}
+		}
+	}
+	// YAML 1.1 booleans are quoted so older parsers read them as strings.
+	switch strings.ToLower(s) {
+	case "y", "n", "yes", "no", "on", "off":
+		return false
+	}
+	_, ok := resolve(s).(string)
+
+	return ok
+}
+
+// literalSafe returns true if s is a multiple line string that can be
+// written as a literal block scalar with strip or clip chomping.
+func literalSafe(s string) bool {
+	if strings.IndexByte(s, '\n') < 0 || s[0] == ' ' || s[0] == '\n' ||
+		strings.HasSuffix(s, "\n\n") || !utf8.ValidString(s) {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if b := s[i]; (b < ' ' && b != '\n' && b != '\t') || b == 0x7f {
+			return false
+		}
+	}
+	return true
+}
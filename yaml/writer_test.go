@@ -0,0 +1,253 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package yaml_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/gen"
+	"github.com/khaf/ojg/tt"
+	"github.com/khaf/ojg/yaml"
+)
+
+type failWriter struct{}
+
+func (w failWriter) Write([]byte) (int, error) {
+	return 0, errors.New("failed")
+}
+
+func TestMarshalBlock(t *testing.T) {
+	data := map[string]any{
+		"a": 1,
+		"b": []any{1, 2.5, "x"},
+		"c": map[string]any{"d": true, "e": nil, "s": "multi\nline\n"},
+		"f": []any{
+			[]any{2, 3},
+			map[string]any{"g": 4, "h": 5},
+			map[string]any{},
+			[]any{},
+		},
+		"i": "strip\n\nme",
+	}
+	out, err := yaml.Marshal(data, &ojg.Options{Indent: 2, Sort: true})
+	tt.Nil(t, err)
+	tt.Equal(t, `a: 1
+b:
+  - 1
+  - 2.5
+  - x
+c:
+  d: true
+  e: null
+  s: |
+    multi
+    line
+f:
+  - - 2
+    - 3
+  - g: 4
+    h: 5
+  - {}
+  - []
+i: |-
+  strip
+
+  me
+`, string(out))
+	tt.Equal(t, jsonify(data), jsonify(yaml.MustParse(out)))
+
+	out, err = yaml.Marshal([]any{map[string]any{"a": []any{1}}}, &ojg.Options{Indent: 4})
+	tt.Nil(t, err)
+	tt.Equal(t, "-   a:\n        -   1\n", string(out))
+
+	// An indent of one is increased to two.
+	out, err = yaml.Marshal([]any{[]any{1}}, &ojg.Options{Indent: 1})
+	tt.Nil(t, err)
+	tt.Equal(t, "- - 1\n", string(out))
+
+	// The package defaults use an indent of two.
+	tt.Equal(t, "a:\n  - 1\n", string(yaml.MustMarshal(map[string]any{"a": []any{1}})))
+}
+
+func TestMarshalFlow(t *testing.T) {
+	data := map[string]any{
+		"a": []any{1, "x y", "a,b", "multi\nline", map[string]any{}},
+		"b": map[string]any{"c": nil, "[d]": "e"},
+	}
+	out, err := yaml.Marshal(data, &ojg.Options{Sort: true})
+	tt.Nil(t, err)
+	tt.Equal(t, `{a: [1, x y, "a,b", "multi\nline", {}], b: {"[d]": e, c: null}}
+`, string(out))
+	tt.Equal(t, jsonify(data), jsonify(yaml.MustParse(out)))
+}
+
+func TestMarshalScalars(t *testing.T) {
+	opt := ojg.Options{Indent: 2}
+	for _, d := range []struct {
+		value  any
+		expect string
+	}{
+		{value: nil, expect: "null"},
+		{value: true, expect: "true"},
+		{value: int8(-8), expect: "-8"},
+		{value: int16(16), expect: "16"},
+		{value: int32(32), expect: "32"},
+		{value: int64(64), expect: "64"},
+		{value: uint(1), expect: "1"},
+		{value: uint8(8), expect: "8"},
+		{value: uint16(16), expect: "16"},
+		{value: uint32(32), expect: "32"},
+		{value: uint64(64), expect: "64"},
+		{value: 1.0, expect: "1.0"},
+		{value: 1e21, expect: "1e+21"},
+		{value: float32(1.5), expect: "1.5"},
+		{value: math.Inf(1), expect: ".inf"},
+		{value: math.Inf(-1), expect: "-.inf"},
+		{value: math.NaN(), expect: ".nan"},
+		{value: json.Number("123456789012345678901234"), expect: "123456789012345678901234"},
+		{value: "", expect: `""`},
+		{value: "abc", expect: "abc"},
+		{value: "a: b", expect: `"a: b"`},
+		{value: "a:b", expect: "a:b"},
+		{value: "a #b", expect: `"a #b"`},
+		{value: "a#b", expect: "a#b"},
+		{value: " a", expect: `" a"`},
+		{value: "a:", expect: `"a:"`},
+		{value: "- a", expect: `"- a"`},
+		{value: "...", expect: `"..."`},
+		{value: "123", expect: `"123"`},
+		{value: "1.5", expect: `"1.5"`},
+		{value: "true", expect: `"true"`},
+		{value: "null", expect: `"null"`},
+		{value: "~", expect: `"~"`},
+		{value: "Yes", expect: `"Yes"`},
+		{value: "off", expect: `"off"`},
+		{value: "tab\there", expect: `"tab\there"`},
+		{value: "a,b", expect: "a,b"},
+		{value: "<&>", expect: "<&>"},
+		{value: "é", expect: "é"},
+		{value: "\xff", expect: `"\ufffd"`},
+		{value: " lead\nline", expect: `" lead\nline"`},
+		{value: "a\r\nb", expect: `"a\r\nb"`},
+		{value: "two\n\n", expect: `"two\n\n"`},
+		{value: "top\nlevel", expect: "|-\n  top\n  level"},
+		{value: []byte("hi"), expect: "hi"},
+		{value: gen.Null{}, expect: "null"},
+		{value: gen.Bool(false), expect: "false"},
+		{value: gen.Int(-3), expect: "-3"},
+		{value: gen.Uint(3), expect: "3"},
+		{value: gen.Float(2), expect: "2.0"},
+		{value: gen.String("s"), expect: "s"},
+		{value: gen.Big("18446744073709551616"), expect: "18446744073709551616"},
+		{value: gen.Decimal("0.5"), expect: "0.5"},
+		{value: gen.Bytes("b"), expect: "b"},
+		{value: []any{}, expect: "[]"},
+		{value: map[string]any{}, expect: "{}"},
+	} {
+		out, err := yaml.Marshal(d.value, &opt)
+		tt.Nil(t, err, d.expect)
+		tt.Equal(t, d.expect+"\n", string(out), d.value)
+	}
+}
+
+func TestMarshalNode(t *testing.T) {
+	node := gen.Object{
+		"a": gen.Array{gen.Int(1), gen.String("x"), nil},
+		"b": gen.Object{"c": gen.Float(1.5)},
+		"d": nil,
+	}
+	out, err := yaml.Marshal(node, &ojg.Options{Indent: 2, Sort: true, OmitNil: true})
+	tt.Nil(t, err)
+	tt.Equal(t, "a:\n  - 1\n  - x\n  - null\nb:\n  c: 1.5\n", string(out))
+
+	out, err = yaml.Marshal(map[string]any{"a": nil, "b": 1}, &ojg.Options{OmitNil: true})
+	tt.Nil(t, err)
+	tt.Equal(t, "{b: 1}\n", string(out))
+
+	out, err = yaml.Marshal(gen.Persist(gen.Object{"a": gen.Array{gen.Int(1)}}), &ojg.Options{Indent: 2})
+	tt.Nil(t, err)
+	tt.Equal(t, "a:\n  - 1\n", string(out))
+}
+
+func TestMarshalOther(t *testing.T) {
+	when := time.Date(2023, 1, 2, 3, 4, 5, 6, time.UTC)
+	s := &sample{Name: "x", Count: 2, When: when, Ptr: &sample{Name: "y", When: when}}
+	out, err := yaml.Marshal(s, &ojg.Options{Indent: 2, Sort: true, OmitNil: true, CreateKey: "^", TimeFormat: time.RFC3339})
+	tt.Nil(t, err)
+	tt.Equal(t, `^: sample
+count: 2
+name: x
+ptr:
+  ^: sample
+  count: 0
+  name: "y"
+  tags: []
+  when: 2023-01-02T03:04:05Z
+tags: []
+when: 2023-01-02T03:04:05Z
+`, string(out))
+
+	for _, d := range []struct {
+		opt    ojg.Options
+		value  any
+		expect string
+	}{
+		{opt: ojg.Options{TimeFormat: "time"}, value: when, expect: "2023-01-02T03:04:05.000000006Z"},
+		{opt: ojg.Options{TimeFormat: "nano"}, value: gen.Time(when), expect: "1672628645000000006"},
+		{opt: ojg.Options{TimeFormat: time.RFC3339, TimeWrap: "@"}, value: when, expect: `{"@": 2023-01-02T03:04:05Z}`},
+		{opt: ojg.Options{BytesAs: ojg.BytesAsBase64}, value: []byte{1, 2}, expect: "AQI="},
+		{opt: ojg.Options{BytesAs: ojg.BytesAsArray}, value: []byte{1, 2}, expect: "[1, 2]"},
+		{opt: ojg.Options{}, value: []int{1, 2}, expect: "[1, 2]"},
+		{opt: ojg.Options{Indent: 2}, value: map[string]int{"a": 1}, expect: "a: 1"},
+	} {
+		out, err = yaml.Marshal(d.value, &d.opt)
+		tt.Nil(t, err, d.expect)
+		tt.Equal(t, d.expect+"\n", string(out))
+	}
+	// The package default times are RFC3339Nano.
+	tt.Equal(t, "2023-01-02T03:04:05.000000006Z\n", string(yaml.MustMarshal(when)))
+
+	_, err = yaml.Marshal(func() {})
+	tt.NotNil(t, err)
+	_, err = yaml.Marshal(map[string]any{"a": make(chan int)}, &ojg.Options{Indent: 2})
+	tt.NotNil(t, err)
+	tt.Panic(t, func() { yaml.MustMarshal(func() {}) })
+}
+
+func TestWrite(t *testing.T) {
+	var b strings.Builder
+	data := []any{strings.Repeat("x", 20), strings.Repeat("y", 20), map[string]any{"z": nil}}
+	wr := yaml.Writer{Options: ojg.Options{Indent: 2, WriteLimit: 8}}
+	err := wr.Write(&b, data)
+	tt.Nil(t, err)
+	tt.Equal(t, "- xxxxxxxxxxxxxxxxxxxx\n- yyyyyyyyyyyyyyyyyyyy\n- z: null\n", b.String())
+
+	b.Reset()
+	err = yaml.Write(&b, data, &wr)
+	tt.Nil(t, err)
+	tt.Equal(t, data, yaml.MustParse([]byte(b.String())))
+
+	b.Reset()
+	err = yaml.Write(&b, 1)
+	tt.Nil(t, err)
+	tt.Equal(t, "1\n", b.String())
+
+	err = yaml.Write(failWriter{}, data, &ojg.Options{Indent: 2, WriteLimit: 8})
+	tt.NotNil(t, err)
+	err = yaml.Write(failWriter{}, 1, &ojg.Options{})
+	tt.NotNil(t, err)
+
+	out := wr.MustMarshal(true)
+	tt.Equal(t, "true\n", string(out))
+}
+
+// jsonify normalizes data for comparison after a round trip.
+func jsonify(v any) string {
+	return string(yaml.MustMarshal(v, &ojg.Options{Sort: true}))
+}
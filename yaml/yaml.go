@@ -0,0 +1,141 @@
+// Copyright (c) 2023, Peter Ohler, All rights reserved.
+
+package yaml
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/khaf/ojg"
+	"github.com/khaf/ojg/alt"
+	"github.com/khaf/ojg/gen"
+)
+
+var (
+	// DefaultOptions are the default options for the this package. Unlike
+	// the JSON defaults, block style is written with an indentation of two
+	// and times are written in RFC3339Nano format.
+	DefaultOptions = ojg.DefaultOptions
+
+	writerPool = sync.Pool{
+		New: func() any {
+			return &Writer{Options: DefaultOptions, buf: make([]byte, 0, 1024)}
+		},
+	}
+	parserPool = sync.Pool{
+		New: func() any {
+			return &Parser{}
+		},
+	}
+)
+
+func init() {
+	DefaultOptions.Indent = 2
+	DefaultOptions.TimeFormat = time.RFC3339Nano
+}
+
+// Parse YAML into a simple type. Arguments are optional and can be a
+// func(any) bool or func(any) for callbacks, or a chan any for chan based
+// result delivery of each document when the stream holds more than one.
+func Parse(b []byte, args ...any) (n any, err error) {
+	p := parserPool.Get().(*Parser)
+	defer parserPool.Put(p)
+	return p.Parse(b, args...)
+}
+
+// MustParse YAML into a simple type. Panics on error.
+func MustParse(b []byte, args ...any) (n any) {
+	p := parserPool.Get().(*Parser)
+	defer parserPool.Put(p)
+	var err error
+	if n, err = p.Parse(b, args...); err != nil {
+		panic(err)
+	}
+	return
+}
+
+// ParseNode parses a YAML document into a gen.Node.
+func ParseNode(b []byte) (gen.Node, error) {
+	p := parserPool.Get().(*Parser)
+	defer parserPool.Put(p)
+	return p.ParseNode(b)
+}
+
+// Load YAML from a io.Reader into a simple type. An error is returned if
+// not valid YAML.
+func Load(r io.Reader, args ...any) (any, error) {
+	p := parserPool.Get().(*Parser)
+	defer parserPool.Put(p)
+	return p.ParseReader(r, args...)
+}
+
+// MustLoad YAML from a io.Reader into a simple type. Panics on error.
+func MustLoad(r io.Reader, args ...any) (n any) {
+	p := parserPool.Get().(*Parser)
+	defer parserPool.Put(p)
+	var err error
+	if n, err = p.ParseReader(r, args...); err != nil {
+		panic(err)
+	}
+	return
+}
+
+// Unmarshal parses the provided YAML and stores the result in the value
+// pointed to by vp.
+func Unmarshal(data []byte, vp any, recomposer ...*alt.Recomposer) error {
+	p := Parser{}
+	return p.Unmarshal(data, vp, recomposer...)
+}
+
+// Marshal returns the YAML encoding of the data provided. The data can be
+// a simple type, a gen.Node, or any other value which is decomposed with
+// the alt package. The args, if supplied can be a *ojg.Options or a
+// *Writer.
+func Marshal(data any, args ...any) (out []byte, err error) {
+	var wr *Writer
+	if 0 < len(args) {
+		wr = pickWriter(args[0])
+	}
+	if wr == nil {
+		wr, _ = writerPool.Get().(*Writer)
+		defer writerPool.Put(wr)
+	}
+	return wr.Marshal(data)
+}
+
+// MustMarshal is the same as Marshal except it panics on error.
+func MustMarshal(data any, args ...any) []byte {
+	out, err := Marshal(data, args...)
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
+
+// Write the YAML encoding of the data provided to w. The args, if supplied
+// can be a *ojg.Options or a *Writer.
+func Write(w io.Writer, data any, args ...any) (err error) {
+	var wr *Writer
+	if 0 < len(args) {
+		wr = pickWriter(args[0])
+	}
+	if wr == nil {
+		wr, _ = writerPool.Get().(*Writer)
+		defer writerPool.Put(wr)
+	}
+	return wr.Write(w, data)
+}
+
+func pickWriter(arg any) (wr *Writer) {
+	switch ta := arg.(type) {
+	case *ojg.Options:
+		wr = &Writer{
+			Options: *ta,
+			buf:     make([]byte, 0, 1024),
+		}
+	case *Writer:
+		wr = ta
+	}
+	return
+}